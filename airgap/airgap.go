@@ -0,0 +1,209 @@
+// Package airgap splits ceremony messages into short, checksummed text
+// frames that can be shown as QR codes and scanned on the other side of an
+// air gap, and reassembles them.
+//
+// An offline signer never connects to a network: the JSON files of the fy
+// command (broadcasts, shares, commitments, signature shares) are encoded
+// with [Encode], each frame is rendered as a QR code by any QR library or
+// tool, and the codes are scanned in any order on the other machine, where
+// a [Decoder] collects them until the message is complete.
+//
+// A frame looks like
+//
+//	FY1:2:5:3F2A9C01D4E5B6A7:MZXW6YTBOI...:1A2B3C4D
+//
+// with the fields separated by colons: the format version, the frame
+// number and the number of frames, a message ID, the frame's part of the
+// message in base32, and a CRC-32 of everything before it. The message ID
+// is the start of the message's SHA-256 digest, so frames of different
+// messages are never mixed and the reassembled message is checked as a
+// whole. Frames use only uppercase letters, digits and colons, which QR
+// codes store in their compact alphanumeric mode.
+//
+// Frames are not encrypted. Private shares must only be shown to the
+// participant they are addressed to.
+package airgap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// prefix starts every frame and names the format version.
+const prefix = "FY1"
+
+// DefaultChunkSize is the number of message bytes per frame used when
+// [Encode] is given a chunk size of zero. A frame then holds at most 460
+// characters and fits a QR code of version 12 at error correction level L,
+// which phone cameras scan reliably from a screen.
+const DefaultChunkSize = 256
+
+// MaxFrames is the largest number of frames a message may be split into.
+const MaxFrames = 9999
+
+// idLen is the length of the message ID in bytes.
+const idLen = 8
+
+// encoding is base32 without padding; its alphabet is a subset of the QR
+// alphanumeric mode.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Errors returned by [Decoder.Add] and [Decoder.Message].
+var (
+	ErrMalformed    = errors.New("airgap: malformed frame")
+	ErrChecksum     = errors.New("airgap: frame checksum mismatch")
+	ErrOtherMessage = errors.New("airgap: frame belongs to another message")
+	ErrIncomplete   = errors.New("airgap: message incomplete")
+)
+
+// Encode splits message into frames of at most chunkSize message bytes
+// each, or [DefaultChunkSize] if chunkSize is zero. All frames must be
+// transferred for the message to be reassembled; their order does not
+// matter.
+func Encode(message []byte, chunkSize int) ([]string, error) {
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize < 0 {
+		return nil, fmt.Errorf("airgap: invalid chunk size %d", chunkSize)
+	}
+	if len(message) == 0 {
+		return nil, errors.New("airgap: empty message")
+	}
+	total := (len(message) + chunkSize - 1) / chunkSize
+	if total > MaxFrames {
+		return nil, fmt.Errorf("airgap: message needs %d frames, more than %d", total, MaxFrames)
+	}
+	id := messageID(message)
+	frames := make([]string, total)
+	for i := range frames {
+		chunk := message[i*chunkSize : min((i+1)*chunkSize, len(message))]
+		body := fmt.Sprintf("%s:%d:%d:%s:%s", prefix, i+1, total, id, encoding.EncodeToString(chunk))
+		frames[i] = body + ":" + checksum(body)
+	}
+	return frames, nil
+}
+
+// Decoder reassembles a message from its frames. The zero value is ready
+// to use.
+type Decoder struct {
+	id     string
+	chunks [][]byte
+	have   int
+}
+
+// Add adds a scanned frame. Surrounding whitespace is ignored, and so is a
+// frame that was already added. The first frame fixes the message; frames
+// of any other message are rejected with [ErrOtherMessage] and leave the
+// decoder unchanged, as do malformed and corrupted frames.
+func (d *Decoder) Add(frame string) error {
+	frame = strings.TrimSpace(frame)
+	body, sum, ok := cutLast(frame, ":")
+	if !ok {
+		return ErrMalformed
+	}
+	if checksum(body) != strings.ToUpper(sum) {
+		return ErrChecksum
+	}
+	fields := strings.Split(body, ":")
+	if len(fields) != 5 || fields[0] != prefix {
+		return ErrMalformed
+	}
+	n, err1 := strconv.Atoi(fields[1])
+	total, err2 := strconv.Atoi(fields[2])
+	id := strings.ToUpper(fields[3])
+	chunk, err3 := encoding.DecodeString(fields[4])
+	if err1 != nil || err2 != nil || err3 != nil ||
+		total < 1 || total > MaxFrames || n < 1 || n > total ||
+		len(id) != 2*idLen || len(chunk) == 0 {
+		return ErrMalformed
+	}
+
+	if d.chunks == nil {
+		d.id = id
+		d.chunks = make([][]byte, total)
+	} else if id != d.id || total != len(d.chunks) {
+		return ErrOtherMessage
+	}
+	if d.chunks[n-1] == nil {
+		d.chunks[n-1] = chunk
+		d.have++
+	}
+	return nil
+}
+
+// Done reports whether every frame of the message has been added.
+func (d *Decoder) Done() bool {
+	return d.chunks != nil && d.have == len(d.chunks)
+}
+
+// Progress returns the number of distinct frames added and the number of
+// frames in the message, or zero before the first frame.
+func (d *Decoder) Progress() (have, total int) {
+	return d.have, len(d.chunks)
+}
+
+// Missing returns the numbers of the frames still to be scanned, in
+// ascending order. It returns nil before the first frame.
+func (d *Decoder) Missing() []int {
+	var missing []int
+	for i, c := range d.chunks {
+		if c == nil {
+			missing = append(missing, i+1)
+		}
+	}
+	return missing
+}
+
+// Message returns the reassembled message. It returns [ErrIncomplete]
+// until every frame has been added, and [ErrChecksum] if the message does
+// not match its ID, which means the frames were encoded inconsistently.
+func (d *Decoder) Message() ([]byte, error) {
+	if !d.Done() {
+		return nil, ErrIncomplete
+	}
+	message := bytes.Join(d.chunks, nil)
+	if messageID(message) != d.id {
+		return nil, ErrChecksum
+	}
+	return message, nil
+}
+
+// Decode reassembles a message from a complete set of frames, in any
+// order.
+func Decode(frames []string) ([]byte, error) {
+	var d Decoder
+	for _, f := range frames {
+		if err := d.Add(f); err != nil {
+			return nil, err
+		}
+	}
+	return d.Message()
+}
+
+// messageID returns the ID of message: the start of its SHA-256 digest in
+// uppercase hex.
+func messageID(message []byte) string {
+	digest := sha256.Sum256(message)
+	return strings.ToUpper(hex.EncodeToString(digest[:idLen]))
+}
+
+// checksum returns the CRC-32 of a frame body in uppercase hex.
+func checksum(body string) string {
+	return fmt.Sprintf("%08X", crc32.ChecksumIEEE([]byte(body)))
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
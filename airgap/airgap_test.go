@@ -0,0 +1,172 @@
+package airgap
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testMessage returns n bytes of deterministic test data.
+func testMessage(n int) []byte {
+	r := rand.NewChaCha8([32]byte{1})
+	message := make([]byte, n)
+	r.Read(message)
+	return message
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range []struct{ size, chunk, frames int }{
+		{1, 0, 1},
+		{256, 0, 1},
+		{257, 0, 2},
+		{1000, 100, 10},
+		{1001, 100, 11},
+		{5000, 1, 5000},
+	} {
+		message := testMessage(tt.size)
+		frames, err := Encode(message, tt.chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(frames) != tt.frames {
+			t.Errorf("%d bytes in chunks of %d: %d frames, want %d", tt.size, tt.chunk, len(frames), tt.frames)
+		}
+		// Scan in reverse, with a repeated frame
+		var d Decoder
+		for i := len(frames) - 1; i >= 0; i-- {
+			if d.Done() {
+				t.Fatal("done before the last frame")
+			}
+			if err := d.Add(frames[i]); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Add(frames[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := d.Message()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, message) {
+			t.Fatalf("%d bytes in chunks of %d: message changed", tt.size, tt.chunk)
+		}
+	}
+}
+
+func TestFrameFormat(t *testing.T) {
+	frames, err := Encode(testMessage(2000), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+	for _, f := range frames {
+		if len(f) > 460 {
+			t.Errorf("frame of %d characters", len(f))
+		}
+		if i := strings.IndexFunc(f, func(r rune) bool { return !strings.ContainsRune(alphanumeric, r) }); i >= 0 {
+			t.Fatalf("frame %q has a character outside the QR alphanumeric set", f)
+		}
+	}
+	if !strings.HasPrefix(frames[2], "FY1:3:8:") {
+		t.Errorf("frame 3 = %q", frames[2])
+	}
+}
+
+func TestProgress(t *testing.T) {
+	frames, err := Encode(testMessage(500), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decoder
+	if have, total := d.Progress(); have != 0 || total != 0 || d.Missing() != nil {
+		t.Fatal("empty decoder reports progress")
+	}
+	if _, err := d.Message(); !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("err = %v, want ErrIncomplete", err)
+	}
+	for _, i := range []int{1, 3, 1} {
+		if err := d.Add(frames[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if have, total := d.Progress(); have != 2 || total != 5 {
+		t.Errorf("progress %d/%d, want 2/5", have, total)
+	}
+	if got := d.Missing(); !reflect.DeepEqual(got, []int{1, 3, 5}) {
+		t.Errorf("missing %v, want [1 3 5]", got)
+	}
+	if _, err := d.Message(); !errors.Is(err, ErrIncomplete) {
+		t.Fatalf("err = %v, want ErrIncomplete", err)
+	}
+}
+
+func TestBadFrames(t *testing.T) {
+	frames, err := Encode(testMessage(300), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	others, err := Encode(testMessage(301), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reframe builds a frame with a valid checksum around body
+	reframe := func(body string) string { return body + ":" + checksum(body) }
+	body, _, _ := cutLast(frames[0], ":")
+	fields := strings.Split(body, ":")
+
+	tests := []struct {
+		name  string
+		frame string
+		want  error
+	}{
+		{"empty", "", ErrMalformed},
+		{"flipped character", strings.Replace(frames[1], "FY1:2", "FY1:3", 1), ErrChecksum},
+		{"truncated", frames[1][:len(frames[1])-20], ErrChecksum},
+		{"other version", reframe("FY2" + body[3:]), ErrMalformed},
+		{"frame zero", reframe(strings.Join([]string{fields[0], "0", fields[2], fields[3], fields[4]}, ":")), ErrMalformed},
+		{"frame past total", reframe(strings.Join([]string{fields[0], "4", fields[2], fields[3], fields[4]}, ":")), ErrMalformed},
+		{"bad base32", reframe(strings.Join([]string{fields[0], fields[1], fields[2], fields[3], "A1"}, ":")), ErrMalformed},
+		{"other message", others[1], ErrOtherMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Decoder
+			if err := d.Add(frames[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Add(tt.frame); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if have, _ := d.Progress(); have != 1 {
+				t.Error("rejected frame was added")
+			}
+		})
+	}
+
+	// Frames that are valid on their own but swap two chunks
+	renumber := func(frame, n string) string {
+		fields := strings.Split(frame, ":")
+		fields[1] = n
+		return reframe(strings.Join(fields[:5], ":"))
+	}
+	swapped := []string{frames[0], renumber(frames[2], "2"), renumber(frames[1], "3")}
+	if _, err := Decode(swapped); !errors.Is(err, ErrChecksum) {
+		t.Errorf("swapped chunks: err = %v, want ErrChecksum", err)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	if _, err := Encode(nil, 0); err == nil {
+		t.Error("empty message encoded")
+	}
+	if _, err := Encode([]byte("x"), -1); err == nil {
+		t.Error("negative chunk size accepted")
+	}
+	if _, err := Encode(testMessage(MaxFrames+1), 1); err == nil {
+		t.Error("too many frames accepted")
+	}
+}
@@ -0,0 +1,105 @@
+package bjj
+
+import (
+	"crypto/subtle"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/group"
+)
+
+// baseWindows is the number of 4-bit windows in a 256-bit scalar.
+const baseWindows = 64
+
+// baseTable holds j * 16^i * G for every window i and digit j, so that a
+// base-point multiplication needs one table lookup and addition per window
+// and no doublings.
+var baseTable struct {
+	once   sync.Once
+	points [baseWindows][16]twistededwards.PointExtended
+}
+
+// initBaseTable fills baseTable on first use.
+func initBaseTable() {
+	window := baseExtended
+
+	for i := range baseTable.points {
+		row := &baseTable.points[i]
+		row[0] = identityExtended()
+		for j := 1; j < 16; j++ {
+			row[j].Add(&row[j-1], &window)
+		}
+		// Move to the next window: 16^(i+1) * G
+		window.Double(&row[8])
+	}
+}
+
+// ScalarBaseMult returns s times the generator using a precomputed table
+// of generator multiples. Every window performs the same table scan and
+// addition regardless of the scalar's digits.
+func (g *BJJ) ScalarBaseMult(s group.Scalar) group.Point {
+	result := g.newPoint()
+	baseMult(&result.inner, toScalar(s))
+	return result
+}
+
+// baseMult sets p to s times the generator using baseTable.
+func baseMult(p *twistededwards.PointExtended, s *Scalar) {
+	baseTable.once.Do(initBaseTable)
+
+	k := s.inner.bytes()
+
+	acc := identityExtended()
+	var sel twistededwards.PointExtended
+	for i := range baseWindows {
+		// Window i is nibble i counted from the least significant end
+		b := k[31-i/2]
+		digit := int(b>>(4*(i%2))) & 0xf
+
+		row := &baseTable.points[i]
+		sel = row[0]
+		for j := 1; j < 16; j++ {
+			selectExtended(&sel, &row[j], subtle.ConstantTimeEq(int32(j), int32(digit)))
+		}
+		acc.Add(&acc, &sel)
+	}
+	*p = acc
+}
+
+// selectExtended sets p to q if c is 1 and leaves it unchanged if c is 0,
+// in constant time.
+func selectExtended(p, q *twistededwards.PointExtended, c int) {
+	p.X.Select(c, &p.X, &q.X)
+	p.Y.Select(c, &p.Y, &q.Y)
+	p.Z.Select(c, &p.Z, &q.Z)
+	p.T.Select(c, &p.T, &q.T)
+}
+
+// scalarMult sets p to k times q, where k is a 256-bit big-endian value,
+// with a fixed 4-bit window. It precomputes 0..15 times q and then does
+// four doublings, a full table scan and an addition per window whatever
+// the digits of k, so it runs in constant time and does not allocate.
+func scalarMult(p, q *twistededwards.PointExtended, k *[32]byte) {
+	var table [16]twistededwards.PointExtended
+	table[0] = identityExtended()
+	for j := 1; j < 16; j++ {
+		table[j].Add(&table[j-1], q)
+	}
+
+	acc := identityExtended()
+	var sel twistededwards.PointExtended
+	for i := baseWindows - 1; i >= 0; i-- {
+		for range 4 {
+			acc.Double(&acc)
+		}
+		b := k[31-i/2]
+		digit := int(b>>(4*(i%2))) & 0xf
+
+		sel = table[0]
+		for j := 1; j < 16; j++ {
+			selectExtended(&sel, &table[j], subtle.ConstantTimeEq(int32(j), int32(digit)))
+		}
+		acc.Add(&acc, &sel)
+	}
+	*p = acc
+}
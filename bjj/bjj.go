@@ -1,150 +1,248 @@
 package bjj
 
 import (
-	"crypto/sha256"
+	"crypto/sha512"
 	"errors"
+	"hash"
 	"io"
 	"math/big"
+	"sync"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
 )
 
+// name is the group name returned by [BJJ.Name].
+const name = "baby-jubjub"
+
 // curveOrder is the Baby Jubjub subgroup order.
 // This is distinct from the BN254 scalar field order (Fr).
 var curveOrder *big.Int
 
+// curveOrderBytes is curveOrder as a 32-byte big-endian value.
+var curveOrderBytes [32]byte
+
+// baseExtended is the generator in extended coordinates.
+var baseExtended twistededwards.PointExtended
+
 func init() {
 	curve := twistededwards.GetEdwardsCurve()
 	curveOrder = new(big.Int).Set(&curve.Order)
+	curveOrder.FillBytes(curveOrderBytes[:])
+	curveOrderNAF, curveOrderNAFLen = wnaf(&curveOrderBytes, wnafWidth)
+	baseExtended.FromAffine(&curve.Base)
+	initScalarField()
+
+	group.Register(name, func() group.Group { return &BJJ{} })
 }
 
 // Scalar represents an element of the Baby Jubjub scalar field.
-// It implements [group.Scalar] using big.Int with modular arithmetic
+// It implements [group.Scalar] with fixed-width Montgomery arithmetic
 // over the curve's subgroup order.
 //
-// All arithmetic operations automatically reduce results modulo the
-// curve order to maintain valid scalar values.
+// All arithmetic operations reduce results modulo the curve order and,
+// except for the big.Int conversions, run in constant time. The zero
+// value is the scalar 0.
 type Scalar struct {
-	inner *big.Int
+	inner fe
 }
 
 // newScalar creates a new scalar initialized to zero.
 func newScalar() *Scalar {
-	return &Scalar{inner: new(big.Int)}
+	return &Scalar{}
 }
 
-// reduce ensures the scalar is in the range [0, curveOrder).
-func (s *Scalar) reduce() {
-	s.inner.Mod(s.inner, curveOrder)
+// toScalar converts s to a Baby Jubjub scalar, panicking with a
+// *[group.MismatchError] if it belongs to another group.
+func toScalar(s group.Scalar) *Scalar {
+	v, ok := s.(*Scalar)
+	if !ok {
+		panic(&group.MismatchError{Group: name, Value: s})
+	}
+	return v
 }
 
 // Add sets s to a + b (mod curveOrder) and returns s.
 func (s *Scalar) Add(a, b group.Scalar) group.Scalar {
-	aScalar := a.(*Scalar)
-	bScalar := b.(*Scalar)
-	s.inner.Add(aScalar.inner, bScalar.inner)
-	s.reduce()
+	aScalar := toScalar(a)
+	bScalar := toScalar(b)
+	s.inner.add(&aScalar.inner, &bScalar.inner)
 	return s
 }
 
 // Sub sets s to a - b (mod curveOrder) and returns s.
 func (s *Scalar) Sub(a, b group.Scalar) group.Scalar {
-	aScalar := a.(*Scalar)
-	bScalar := b.(*Scalar)
-	s.inner.Sub(aScalar.inner, bScalar.inner)
-	s.reduce()
+	aScalar := toScalar(a)
+	bScalar := toScalar(b)
+	s.inner.sub(&aScalar.inner, &bScalar.inner)
 	return s
 }
 
 // Mul sets s to a * b (mod curveOrder) and returns s.
 func (s *Scalar) Mul(a, b group.Scalar) group.Scalar {
-	aScalar := a.(*Scalar)
-	bScalar := b.(*Scalar)
-	s.inner.Mul(aScalar.inner, bScalar.inner)
-	s.reduce()
+	aScalar := toScalar(a)
+	bScalar := toScalar(b)
+	s.inner.mul(&aScalar.inner, &bScalar.inner)
 	return s
 }
 
 // Negate sets s to -a (mod curveOrder) and returns s.
 func (s *Scalar) Negate(a group.Scalar) group.Scalar {
-	aScalar := a.(*Scalar)
-	s.inner.Neg(aScalar.inner)
-	s.reduce()
+	aScalar := toScalar(a)
+	s.inner.neg(&aScalar.inner)
 	return s
 }
 
 // Invert sets s to a^(-1) (mod curveOrder) and returns s.
 // Returns an error if a is zero, as zero has no multiplicative inverse.
 func (s *Scalar) Invert(a group.Scalar) (group.Scalar, error) {
-	aScalar := a.(*Scalar)
+	aScalar := toScalar(a)
 	if aScalar.IsZero() {
 		return nil, errors.New("cannot invert zero scalar")
 	}
-	s.inner.ModInverse(aScalar.inner, curveOrder)
+	s.inner.inverse(&aScalar.inner)
 	return s, nil
 }
 
 // Set copies the value of a into s and returns s.
 func (s *Scalar) Set(a group.Scalar) group.Scalar {
-	aScalar := a.(*Scalar)
-	s.inner.Set(aScalar.inner)
+	aScalar := toScalar(a)
+	s.inner = aScalar.inner
+	return s
+}
+
+// Clone returns a copy of s.
+func (s *Scalar) Clone() group.Scalar {
+	c := *s
+	return &c
+}
+
+// Select sets s to a if cond is 1 and to b if cond is 0, and returns s.
+// It runs in constant time.
+func (s *Scalar) Select(a, b group.Scalar, cond int) group.Scalar {
+	s.inner.sel(cond, &toScalar(a).inner, &toScalar(b).inner)
+	return s
+}
+
+// SetUint64 sets s to v and returns s.
+func (s *Scalar) SetUint64(v uint64) group.Scalar {
+	s.inner.setUint64(v)
+	return s
+}
+
+// BigInt returns the value of s as a new big.Int. Unlike the arithmetic
+// methods, it is not constant time.
+func (s *Scalar) BigInt() *big.Int {
+	b := s.inner.bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// SetBigInt sets s to v (mod curveOrder) and returns s. Unlike the
+// arithmetic methods, it is not constant time.
+func (s *Scalar) SetBigInt(v *big.Int) group.Scalar {
+	var b [32]byte
+	new(big.Int).Mod(v, curveOrder).FillBytes(b[:])
+	s.inner.setBytes32(&b)
 	return s
 }
 
 // Bytes returns the scalar as a 32-byte big-endian representation.
 func (s *Scalar) Bytes() []byte {
-	bytes := s.inner.Bytes()
-	if len(bytes) >= 32 {
-		return bytes[:32]
-	}
-	// Pad with leading zeros
-	padded := make([]byte, 32)
-	copy(padded[32-len(bytes):], bytes)
-	return padded
+	b := s.inner.bytes()
+	return b[:]
 }
 
-// SetBytes sets s from a big-endian byte slice and returns s.
-// The value is reduced modulo the curve order.
+// SetBytes sets s from a big-endian byte slice of any length and returns
+// s. The value is reduced modulo the curve order, so distinct inputs can
+// give the same scalar; use [Scalar.SetCanonicalBytes] for encodings
+// received from others.
 func (s *Scalar) SetBytes(data []byte) (group.Scalar, error) {
-	s.inner.SetBytes(data)
-	s.reduce()
+	s.inner.setBytes(data)
 	return s, nil
 }
 
-// Equal reports whether s and b represent the same scalar value.
+// SetUniformBytes sets s from 48 to 64 big-endian uniformly random bytes
+// reduced modulo the curve order, and returns s.
+func (s *Scalar) SetUniformBytes(data []byte) (group.Scalar, error) {
+	if len(data) < 48 || len(data) > 64 {
+		return nil, errors.New("uniform bytes must be 48 to 64 bytes long")
+	}
+	s.inner.setBytes(data)
+	return s, nil
+}
+
+// Equal reports whether s and b represent the same scalar value, in
+// constant time.
 func (s *Scalar) Equal(b group.Scalar) bool {
-	bScalar := b.(*Scalar)
-	return s.inner.Cmp(bScalar.inner) == 0
+	bScalar := toScalar(b)
+	return s.inner.equal(&bScalar.inner)
 }
 
-// IsZero reports whether s is the zero scalar.
+// IsZero reports whether s is the zero scalar, in constant time.
 func (s *Scalar) IsZero() bool {
-	return s.inner.Sign() == 0
+	return s.inner.isZero()
+}
+
+// Zeroize sets s to zero, overwriting its limbs.
+func (s *Scalar) Zeroize() {
+	s.inner = fe{}
 }
 
 // Point represents a point on the Baby Jubjub curve.
-// It implements [group.Point] by wrapping gnark-crypto's PointAffine.
+// It implements [group.Point] by wrapping gnark-crypto's PointExtended.
+//
+// Points are kept in extended coordinates (X:Y:T:Z), with x = X/Z,
+// y = Y/Z and T = XY/Z, so that arithmetic needs no field inversions.
+// They are converted to affine coordinates (x, y) only when encoded. The
+// identity element is (0, 1).
 //
-// Points are represented in affine coordinates (x, y) on the twisted
-// Edwards curve. The identity element is (0, 1).
+// Each point carries the [Encoding] of the [BJJ] instance that created
+// it; arithmetic results keep the encoding of the receiver. The zero
+// value uses [EncodingGnark] but is not a valid point; create points with
+// [BJJ.NewPoint].
 type Point struct {
-	inner twistededwards.PointAffine
+	inner    twistededwards.PointExtended
+	encoding Encoding
+}
+
+// toPoint converts p to a Baby Jubjub point, panicking with a
+// *[group.MismatchError] if it belongs to another group.
+func toPoint(p group.Point) *Point {
+	v, ok := p.(*Point)
+	if !ok {
+		panic(&group.MismatchError{Group: name, Value: p})
+	}
+	return v
+}
+
+// affine returns p in affine coordinates. It costs a field inversion
+// unless p has been normalized with [BJJ.NormalizePoints]; use that or
+// [BJJ.EncodePoints] to share one across many points.
+func (p *Point) affine() twistededwards.PointAffine {
+	var a twistededwards.PointAffine
+	if p.inner.Z.IsOne() {
+		a.X, a.Y = p.inner.X, p.inner.Y
+		return a
+	}
+	a.FromExtended(&p.inner)
+	return a
 }
 
 // Add sets p to a + b and returns p.
 func (p *Point) Add(a, b group.Point) group.Point {
-	aPoint := a.(*Point)
-	bPoint := b.(*Point)
+	aPoint := toPoint(a)
+	bPoint := toPoint(b)
 	p.inner.Add(&aPoint.inner, &bPoint.inner)
 	return p
 }
 
 // Sub sets p to a - b and returns p.
 func (p *Point) Sub(a, b group.Point) group.Point {
-	aPoint := a.(*Point)
-	bPoint := b.(*Point)
-	var negB twistededwards.PointAffine
+	aPoint := toPoint(a)
+	bPoint := toPoint(b)
+	var negB twistededwards.PointExtended
 	negB.Neg(&bPoint.inner)
 	p.inner.Add(&aPoint.inner, &negB)
 	return p
@@ -152,38 +250,68 @@ func (p *Point) Sub(a, b group.Point) group.Point {
 
 // Negate sets p to -a and returns p.
 func (p *Point) Negate(a group.Point) group.Point {
-	aPoint := a.(*Point)
+	aPoint := toPoint(a)
 	p.inner.Neg(&aPoint.inner)
 	return p
 }
 
-// ScalarMult sets p to s * q and returns p.
+// ScalarMult sets p to s * q and returns p, in constant time and without
+// allocating. When q is the generator it uses the precomputed table of
+// [BJJ.ScalarBaseMult].
 func (p *Point) ScalarMult(s group.Scalar, q group.Point) group.Point {
-	scalar := s.(*Scalar)
-	qPoint := q.(*Point)
-	p.inner.ScalarMultiplication(&qPoint.inner, scalar.inner)
+	scalar := toScalar(s)
+	qPoint := toPoint(q)
+	if equalExtended(&qPoint.inner, &baseExtended) {
+		baseMult(&p.inner, scalar)
+		return p
+	}
+	k := scalar.inner.bytes()
+	scalarMult(&p.inner, &qPoint.inner, &k)
 	return p
 }
 
 // Set copies the value of a into p and returns p.
 func (p *Point) Set(a group.Point) group.Point {
-	aPoint := a.(*Point)
+	aPoint := toPoint(a)
 	p.inner.Set(&aPoint.inner)
 	return p
 }
 
-// Bytes returns the compressed point encoding as a byte slice.
+// Clone returns a copy of p.
+func (p *Point) Clone() group.Point {
+	c := *p
+	return &c
+}
+
+// Select sets p to a if cond is 1 and to b if cond is 0, and returns p.
+// It runs in constant time.
+func (p *Point) Select(a, b group.Point, cond int) group.Point {
+	aPoint := toPoint(a)
+	bPoint := toPoint(b)
+	p.inner.X.Select(cond, &bPoint.inner.X, &aPoint.inner.X)
+	p.inner.Y.Select(cond, &bPoint.inner.Y, &aPoint.inner.Y)
+	p.inner.Z.Select(cond, &bPoint.inner.Z, &aPoint.inner.Z)
+	p.inner.T.Select(cond, &bPoint.inner.T, &aPoint.inner.T)
+	return p
+}
+
+// Bytes returns the encoding of p in the point's [Encoding], by default
+// the 32-byte compressed format of gnark-crypto.
 func (p *Point) Bytes() []byte {
-	bytes := p.inner.Bytes()
-	return bytes[:]
+	a := p.affine()
+	return encodePoint(&a, p.encoding)
 }
 
-// SetBytes sets p from a compressed point encoding and returns p.
-// Returns an error if the data does not represent a valid curve point.
+// SetBytes sets p from an encoding in the point's [Encoding] and returns
+// p. Returns an error if the data is not the canonical encoding of a
+// point in the prime-order subgroup. Use [BJJ.DecodePoints] to decode
+// many points at once.
 func (p *Point) SetBytes(data []byte) (group.Point, error) {
-	if err := p.inner.Unmarshal(data); err != nil {
+	points, _, err := decodePoints([][]byte{data}, p.encoding)
+	if err != nil {
 		return nil, err
 	}
+	p.inner = points[0].inner
 	return p, nil
 }
 
@@ -191,9 +319,10 @@ func (p *Point) SetBytes(data []byte) (group.Point, error) {
 // This format is compatible with iden3 and Ledger applications.
 // Each coordinate is encoded as a 32-byte big-endian integer.
 func (p *Point) UncompressedBytes() []byte {
+	a := p.affine()
 	result := make([]byte, 64)
-	xBytes := p.inner.X.Bytes()
-	yBytes := p.inner.Y.Bytes()
+	xBytes := a.X.Bytes()
+	yBytes := a.Y.Bytes()
 	copy(result[0:32], xBytes[:])
 	copy(result[32:64], yBytes[:])
 	return result
@@ -201,25 +330,51 @@ func (p *Point) UncompressedBytes() []byte {
 
 // SetUncompressedBytes sets p from a 64-byte uncompressed encoding (X || Y).
 // This format is compatible with iden3 and Ledger applications.
-// Returns an error if the data is not 64 bytes or does not represent a
-// valid curve point.
+// Returns an error if the data is not 64 bytes or is not the canonical
+// encoding of a point in the prime-order subgroup.
 func (p *Point) SetUncompressedBytes(data []byte) error {
 	if len(data) != 64 {
 		return errors.New("uncompressed point must be 64 bytes")
 	}
-	p.inner.X.SetBytes(data[0:32])
-	p.inner.Y.SetBytes(data[32:64])
-	// Verify the point is on the curve
-	if !p.inner.IsOnCurve() {
+	var a twistededwards.PointAffine
+	if a.X.SetBytesCanonical(data[0:32]) != nil || a.Y.SetBytesCanonical(data[32:64]) != nil {
+		return errors.New("non-canonical encoding")
+	}
+	// Verify the point is on the curve and in the prime-order subgroup
+	if !a.IsOnCurve() {
 		return errors.New("point is not on curve")
 	}
+	var e twistededwards.PointExtended
+	e.FromAffine(&a)
+	if !inSubgroup(&e) {
+		return errors.New("point is not in the prime-order subgroup")
+	}
+	p.inner = e
 	return nil
 }
 
 // Equal reports whether p and b represent the same curve point.
 func (p *Point) Equal(b group.Point) bool {
-	bPoint := b.(*Point)
-	return p.inner.Equal(&bPoint.inner)
+	bPoint := toPoint(b)
+	return equalExtended(&p.inner, &bPoint.inner)
+}
+
+// equalExtended reports whether p and q represent the same point, by
+// comparing X1*Z2 with X2*Z1 and Y1*Z2 with Y2*Z1 rather than inverting.
+// The zero value, with Z = 0, is equal to nothing.
+func equalExtended(p, q *twistededwards.PointExtended) bool {
+	if p.Z.IsZero() || q.Z.IsZero() {
+		return false
+	}
+	var a, b fr.Element
+	a.Mul(&p.X, &q.Z)
+	b.Mul(&q.X, &p.Z)
+	if !a.Equal(&b) {
+		return false
+	}
+	a.Mul(&p.Y, &q.Z)
+	b.Mul(&q.Y, &p.Z)
+	return a.Equal(&b)
 }
 
 // IsIdentity reports whether p is the identity element (0, 1).
@@ -227,11 +382,88 @@ func (p *Point) IsIdentity() bool {
 	return p.inner.IsZero()
 }
 
+// IsOnCurve reports whether p satisfies the curve equation.
+func (p *Point) IsOnCurve() bool {
+	a := p.affine()
+	return a.IsOnCurve()
+}
+
+// IsInPrimeSubgroup reports whether p is on the curve and in the
+// prime-order subgroup. Points decoded with [Point.SetBytes] always are.
+func (p *Point) IsInPrimeSubgroup() bool {
+	return p.IsOnCurve() && inSubgroup(&p.inner)
+}
+
+// ClearCofactor sets p to 8*a and returns p.
+func (p *Point) ClearCofactor(a group.Point) group.Point {
+	aPoint := toPoint(a)
+	p.inner.Double(&aPoint.inner)
+	p.inner.Double(&p.inner)
+	p.inner.Double(&p.inner)
+	return p
+}
+
+// Zeroize sets p to the identity element (0, 1).
+func (p *Point) Zeroize() {
+	p.inner = identityExtended()
+}
+
 // BJJ implements [group.Group] for the Baby Jubjub curve.
 //
-// BJJ is a zero-sized type that provides access to Baby Jubjub curve
-// operations. Create an instance with &BJJ{} or new(BJJ).
-type BJJ struct{}
+// Create an instance with &BJJ{} or new(BJJ). The zero value encodes
+// points in the compressed format of gnark-crypto and hashes to scalars
+// with SHA-512; set Encoding or ScalarHash to change either.
+type BJJ struct {
+	// Encoding is the format of the points created by this instance.
+	Encoding Encoding
+
+	// ScalarHash is the hash function used by HashToScalar.
+	ScalarHash ScalarHash
+}
+
+// Name returns "baby-jubjub", the name BJJ is registered under with
+// [group.Register].
+func (g *BJJ) Name() string {
+	return name
+}
+
+// Capabilities implements [group.CapabilityReporter]. Scalars use
+// fixed-width Montgomery arithmetic, and ScalarBaseMult and ScalarMult
+// use fixed windows with constant-time table lookups.
+func (g *BJJ) Capabilities() group.Capabilities {
+	return group.Capabilities{
+		ConstantTimeScalars:    true,
+		ConstantTimeScalarMult: true,
+	}
+}
+
+// ScalarSize returns 32, the length of [Scalar.Bytes].
+func (g *BJJ) ScalarSize() int {
+	return 32
+}
+
+// ElementSize returns the length of the encoding returned by
+// [Point.Bytes] for g's [Encoding]: 65 for [EncodingSEC1] and 32
+// otherwise.
+func (g *BJJ) ElementSize() int {
+	return g.Encoding.size()
+}
+
+// CheckScalar returns a *[group.MismatchError] if s is not a [Scalar].
+func (g *BJJ) CheckScalar(s group.Scalar) error {
+	if _, ok := s.(*Scalar); !ok {
+		return &group.MismatchError{Group: name, Value: s}
+	}
+	return nil
+}
+
+// CheckPoint returns a *[group.MismatchError] if p is not a [Point].
+func (g *BJJ) CheckPoint(p group.Point) error {
+	if _, ok := p.(*Point); !ok {
+		return &group.MismatchError{Group: name, Value: p}
+	}
+	return nil
+}
 
 // NewScalar returns a new scalar initialized to zero.
 func (g *BJJ) NewScalar() group.Scalar {
@@ -240,48 +472,63 @@ func (g *BJJ) NewScalar() group.Scalar {
 
 // NewPoint returns a new point initialized to the identity element (0, 1).
 func (g *BJJ) NewPoint() group.Point {
-	var p Point
-	p.inner.X.SetZero()
-	p.inner.Y.SetOne()
-	return &p
+	return g.newPoint()
+}
+
+// newPoint returns the identity element with g's encoding.
+func (g *BJJ) newPoint() *Point {
+	return &Point{inner: identityExtended(), encoding: g.Encoding}
 }
 
 // Generator returns the standard base point for the Baby Jubjub curve.
+//
+// It is the base point B8 of EIP-2494 and circomlib, which generates the
+// prime-order subgroup, expressed in gnark-crypto's coordinates: the two
+// share y, and their x-coordinates differ by a constant factor. With
+// [EncodingCircomlib], public keys and signature commitments therefore
+// encode exactly as the iden3 tooling encodes the same multiples of B8.
+// The EIP-2494 generator G of the full group, B8 = 8*G, is not offered,
+// as it lies outside the prime-order subgroup.
 func (g *BJJ) Generator() group.Point {
-	var p Point
-	p.inner = twistededwards.GetEdwardsCurve().Base
-	return &p
+	p := g.newPoint()
+	p.inner = baseExtended
+	return p
 }
 
 // RandomScalar generates a cryptographically random scalar using the
-// provided random source. The result is uniformly distributed in
-// [0, curveOrder).
+// provided random source. It reads 64 bytes and reduces them modulo the
+// curve order, so the result is uniformly distributed in [0, curveOrder)
+// up to a negligible bias.
 func (g *BJJ) RandomScalar(r io.Reader) (group.Scalar, error) {
-	var buf [32]byte
+	var buf [64]byte
+	defer memwipe.Bytes(buf[:])
 	if _, err := io.ReadFull(r, buf[:]); err != nil {
 		return nil, err
 	}
-	s := newScalar()
-	s.inner.SetBytes(buf[:])
-	s.reduce()
-	return s, nil
+	return newScalar().SetUniformBytes(buf[:])
 }
 
-// HashToScalar hashes the provided data to a scalar using SHA-256.
-// Multiple byte slices are concatenated before hashing.
+// HashToScalar hashes the provided data to a scalar with g's
+// [ScalarHash]. By default it uses SHA-512: multiple byte slices are
+// concatenated before hashing, and the 64-byte digest is reduced with
+// [Scalar.SetUniformBytes].
 func (g *BJJ) HashToScalar(data ...[]byte) (group.Scalar, error) {
-	h := sha256.New()
+	if g.ScalarHash == ScalarHashPoseidon2 {
+		return hashToScalarPoseidon2(data), nil
+	}
+	h := sha512Pool.Get().(hash.Hash)
+	defer sha512Pool.Put(h)
+	h.Reset()
 	for _, d := range data {
 		h.Write(d)
 	}
-	hash := h.Sum(nil)
-
-	s := newScalar()
-	s.inner.SetBytes(hash)
-	s.reduce()
-	return s, nil
+	var digest [sha512.Size]byte
+	return newScalar().SetUniformBytes(h.Sum(digest[:0]))
 }
 
+// sha512Pool holds SHA-512 states for reuse across HashToScalar calls.
+var sha512Pool = sync.Pool{New: func() any { return sha512.New() }}
+
 // Order returns the order of the Baby Jubjub curve's prime-order subgroup
 // as a big-endian byte slice.
 func (g *BJJ) Order() []byte {
@@ -1,12 +1,195 @@
 package bjj
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/frost"
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/group/grouptest"
 )
 
+// affinePoint returns the point (x, y) without checking that it is on
+// the curve.
+func affinePoint(x, y fr.Element) *Point {
+	p := &Point{}
+	p.inner.FromAffine(&twistededwards.PointAffine{X: x, Y: y})
+	return p
+}
+
+func TestGroupConformance(t *testing.T) {
+	for _, enc := range []Encoding{EncodingGnark, EncodingRFC8032, EncodingSEC1, EncodingCircomlib} {
+		t.Run(enc.String(), func(t *testing.T) {
+			grouptest.RunGroupTests(t, &BJJ{Encoding: enc})
+		})
+	}
+	t.Run("poseidon2", func(t *testing.T) {
+		grouptest.RunGroupTests(t, &BJJ{ScalarHash: ScalarHashPoseidon2})
+	})
+}
+
+func TestPoseidon2HashToScalar(t *testing.T) {
+	g := &BJJ{ScalarHash: ScalarHashPoseidon2}
+
+	// Recompute the digest with the bare compression function, absorbing
+	// the documented field elements for ("ab", 40 bytes of 0xff)
+	long := bytes.Repeat([]byte{0xff}, 40)
+	elems := make([][]byte, 5)
+	for i := range elems {
+		elems[i] = make([]byte, 32)
+	}
+	elems[0][31] = 2
+	elems[1][1], elems[1][2] = 'a', 'b'
+	elems[2][31] = 40
+	copy(elems[3][1:], long[:31])
+	copy(elems[4][1:], long[31:])
+
+	perm := poseidon2.NewPermutation(2, 6, 50)
+	state := make([]byte, 32)
+	for _, e := range elems {
+		var err error
+		if state, err = perm.Compress(state, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := g.NewScalar().(*Scalar)
+	want.inner.setBytes(state)
+
+	got, err := g.HashToScalar([]byte("ab"), long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Error("HashToScalar does not match the documented Poseidon2 absorption")
+	}
+
+	// Slice boundaries are part of the input
+	a, _ := g.HashToScalar([]byte("ab"), []byte("c"))
+	b, _ := g.HashToScalar([]byte("a"), []byte("bc"))
+	c, _ := g.HashToScalar([]byte("abc"))
+	if a.Equal(b) || a.Equal(c) || b.Equal(c) {
+		t.Error("different splits of the input hashed to the same scalar")
+	}
+	empty, _ := g.HashToScalar()
+	emptySlice, _ := g.HashToScalar(nil)
+	if empty.Equal(emptySlice) {
+		t.Error("no input and one empty input hashed to the same scalar")
+	}
+
+	sha, _ := (&BJJ{}).HashToScalar([]byte("abc"))
+	if sha.Equal(c) {
+		t.Error("Poseidon2 and SHA-512 hashed to the same scalar")
+	}
+	if ScalarHashPoseidon2.String() != "poseidon2" || ScalarHashSHA512.String() != "sha512" {
+		t.Error("unexpected ScalarHash names")
+	}
+}
+
+func TestEncodingProfiles(t *testing.T) {
+	gnark := &BJJ{}
+	rfc := &BJJ{Encoding: EncodingRFC8032}
+	sec1 := &BJJ{Encoding: EncodingSEC1}
+
+	// The compressed formats share y and differ only in the sign bit,
+	// which agrees for some points and not for others
+	var same, differ bool
+	for range 32 {
+		s, _ := gnark.RandomScalar(rand.Reader)
+		p := gnark.ScalarBaseMult(s).(*Point)
+		q := rfc.ScalarBaseMult(s).(*Point)
+		a, b := p.Bytes(), q.Bytes()
+		if !bytes.Equal(a[:31], b[:31]) || a[31]&0x7f != b[31]&0x7f {
+			t.Fatal("gnark and RFC 8032 encodings disagree on y")
+		}
+		if a[31] == b[31] {
+			same = true
+		} else {
+			differ = true
+		}
+		if qa := q.affine(); b[31]>>7 != byte(sgn0(&qa.X)) {
+			t.Error("RFC 8032 sign bit is not the parity of x")
+		}
+
+		u := sec1.ScalarBaseMult(s).Bytes()
+		pa := p.affine()
+		x, y := pa.X.Bytes(), pa.Y.Bytes()
+		if u[0] != 0x04 || !bytes.Equal(u[1:33], x[:]) || !bytes.Equal(u[33:], y[:]) {
+			t.Error("SEC1 encoding is not 0x04 || x || y")
+		}
+	}
+	if !same || !differ {
+		t.Error("expected the sign conventions to agree for some points and differ for others")
+	}
+
+	// Arithmetic results keep the receiver's encoding
+	sum := rfc.NewPoint().Add(gnark.Generator(), gnark.Generator())
+	if len(sec1.NewPoint().Set(sum).Bytes()) != 65 {
+		t.Error("Set changed the encoding of the receiver")
+	}
+	if _, err := sec1.NewPoint().SetBytes(sum.Bytes()); err == nil {
+		t.Error("SEC1 point accepted a compressed encoding")
+	}
+}
+
+func TestCircomlibEncoding(t *testing.T) {
+	// packPoint test vector from circomlibjs, in EIP-2494 coordinates
+	var x, y fr.Element
+	x.SetString("17777552123799933955779906779655732241715742912184938656739573121738514868268")
+	y.SetString("2626589144620713026669568689430873010625803728049924121243784502389097019475")
+	want, _ := hex.DecodeString("53b81ed5bffe9545b54016234682e7b2f699bd42a5e9eae27ff4051bc698ce85")
+
+	x.Mul(&x, &iden3Scale)
+	p := twistededwards.PointAffine{X: x, Y: y}
+	if !p.IsOnCurve() {
+		t.Fatal("scaled point is not on gnark-crypto's curve")
+	}
+	if got := encodePoint(&p, EncodingCircomlib); !bytes.Equal(got, want) {
+		t.Errorf("packPoint = %x, want %x", got, want)
+	}
+
+	// The EIP-2494 base point B8 is gnark-crypto's base point
+	var b8 fr.Element
+	b8.SetString("5299619240641551281634865583518297030282874472190772894086521144482721001553")
+	b8.Mul(&b8, &iden3Scale)
+	base := twistededwards.GetEdwardsCurve().Base
+	if !b8.Equal(&base.X) {
+		t.Error("iden3Scale does not map B8 to the base point")
+	}
+
+	// B8 packed by circomlibjs decodes to the generator
+	g := &BJJ{Encoding: EncodingCircomlib}
+	packedB8, _ := hex.DecodeString("8b7d2d877a253c4b7733e1b91f05e0fcedf96bd11c2e572549b2a0f703727925")
+	gen, err := g.NewPoint().SetBytes(packedB8)
+	if err != nil || !gen.Equal(g.Generator()) {
+		t.Errorf("packed B8 = %v, %v; want the generator", gen, err)
+	}
+
+	// Decoding inverts encoding, for both signs of x
+	for range 16 {
+		s, _ := g.RandomScalar(rand.Reader)
+		q := g.ScalarBaseMult(s)
+		for _, pt := range []group.Point{q, g.NewPoint().Negate(q)} {
+			dec, err := g.NewPoint().SetBytes(pt.Bytes())
+			if err != nil || !dec.Equal(pt) {
+				t.Fatalf("round trip failed: %v", err)
+			}
+		}
+	}
+}
+
 func TestScalar(t *testing.T) {
 	g := &BJJ{}
 
@@ -105,6 +288,506 @@ func TestScalar(t *testing.T) {
 	})
 }
 
+func TestSetUniformBytes(t *testing.T) {
+	g := &BJJ{}
+
+	for _, n := range []int{48, 64} {
+		data := make([]byte, n)
+		rand.Read(data)
+		s, err := g.NewScalar().SetUniformBytes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := new(big.Int).SetBytes(data)
+		want.Mod(want, curveOrder)
+		if s.(*Scalar).BigInt().Cmp(want) != 0 {
+			t.Errorf("%d bytes: not reduced modulo the order", n)
+		}
+	}
+
+	for _, n := range []int{0, 32, 47, 65} {
+		if _, err := g.NewScalar().SetUniformBytes(make([]byte, n)); err == nil {
+			t.Errorf("expected error for %d bytes", n)
+		}
+	}
+}
+
+func TestRandomScalar(t *testing.T) {
+	g := &BJJ{}
+
+	// RandomScalar reads 64 bytes and reduces them, so that every scalar
+	// is equally likely up to a bias of 2^-261; reducing 32 bytes would
+	// make scalars below 2^256 mod r about 2% more likely than the rest
+	data := bytes.Repeat([]byte{0xff}, 70)
+	r := bytes.NewReader(data)
+	s, err := g.RandomScalar(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 6 {
+		t.Errorf("RandomScalar read %d bytes, want 64", 70-r.Len())
+	}
+	want := new(big.Int).SetBytes(data[:64])
+	want.Mod(want, curveOrder)
+	if s.(*Scalar).BigInt().Cmp(want) != 0 {
+		t.Error("RandomScalar does not reduce 64 bytes modulo the order")
+	}
+
+	if _, err := g.RandomScalar(bytes.NewReader(make([]byte, 63))); err == nil {
+		t.Error("expected error from a short random source")
+	}
+}
+
+func TestScalarIntegers(t *testing.T) {
+	g := &BJJ{}
+
+	s := g.NewScalar().SetUint64(70000).(*Scalar)
+	if s.BigInt().Int64() != 70000 {
+		t.Errorf("SetUint64(70000) = %v", s.BigInt())
+	}
+
+	// SetBigInt reduces, and BigInt returns a copy
+	v := new(big.Int).Add(curveOrder, big.NewInt(5))
+	s.SetBigInt(v)
+	got := s.BigInt()
+	if got.Int64() != 5 {
+		t.Errorf("SetBigInt(order+5) = %v", got)
+	}
+	got.SetInt64(9)
+	if s.BigInt().Int64() != 5 {
+		t.Error("BigInt result aliases the scalar")
+	}
+
+	var _ group.BigIntScalar = s
+}
+
+func TestScalarField(t *testing.T) {
+	// Compare the fixed-width arithmetic against big.Int, including the
+	// edge values 0, 1 and order-1
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Sub(curveOrder, big.NewInt(1))}
+	for range 20 {
+		v, _ := rand.Int(rand.Reader, curveOrder)
+		values = append(values, v)
+	}
+
+	toFe := func(v *big.Int) *fe {
+		var b [32]byte
+		v.FillBytes(b[:])
+		var z fe
+		return z.setBytes32(&b)
+	}
+	toBig := func(z *fe) *big.Int {
+		b := z.bytes()
+		return new(big.Int).SetBytes(b[:])
+	}
+	mod := func(v *big.Int) *big.Int {
+		return v.Mod(v, curveOrder)
+	}
+
+	for _, a := range values {
+		x := toFe(a)
+		if toBig(x).Cmp(a) != 0 {
+			t.Fatalf("round trip of %v = %v", a, toBig(x))
+		}
+		var z fe
+		if want := mod(new(big.Int).Neg(a)); toBig(z.neg(x)).Cmp(want) != 0 {
+			t.Errorf("-%v = %v, want %v", a, toBig(&z), want)
+		}
+		want := new(big.Int).ModInverse(a, curveOrder)
+		if want == nil {
+			want = new(big.Int)
+		}
+		if toBig(z.inverse(x)).Cmp(want) != 0 {
+			t.Errorf("1/%v = %v, want %v", a, toBig(&z), want)
+		}
+
+		for _, b := range values {
+			y := toFe(b)
+			if want := mod(new(big.Int).Add(a, b)); toBig(z.add(x, y)).Cmp(want) != 0 {
+				t.Errorf("%v + %v = %v, want %v", a, b, toBig(&z), want)
+			}
+			if want := mod(new(big.Int).Sub(a, b)); toBig(z.sub(x, y)).Cmp(want) != 0 {
+				t.Errorf("%v - %v = %v, want %v", a, b, toBig(&z), want)
+			}
+			if want := mod(new(big.Int).Mul(a, b)); toBig(z.mul(x, y)).Cmp(want) != 0 {
+				t.Errorf("%v * %v = %v, want %v", a, b, toBig(&z), want)
+			}
+		}
+	}
+
+	// setBytes reduces inputs of any length, and isCanonical only accepts
+	// values below the order
+	for _, n := range []int{0, 1, 31, 32, 33, 48, 64, 100} {
+		data := make([]byte, n)
+		rand.Read(data)
+		var z fe
+		if want := mod(new(big.Int).SetBytes(data)); toBig(z.setBytes(data)).Cmp(want) != 0 {
+			t.Errorf("%d bytes: got %v, want %v", n, toBig(&z), want)
+		}
+	}
+	var b [32]byte
+	curveOrder.FillBytes(b[:])
+	if isCanonical(&b) {
+		t.Error("order accepted as canonical")
+	}
+	b[31]--
+	if !isCanonical(&b) {
+		t.Error("order-1 rejected as non-canonical")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	g := &BJJ{}
+	if g.Name() != "baby-jubjub" {
+		t.Errorf("Name() = %q", g.Name())
+	}
+	s, _ := g.RandomScalar(rand.Reader)
+	if got := len(s.Bytes()); got != g.ScalarSize() {
+		t.Errorf("len(Scalar.Bytes()) = %d, ScalarSize() = %d", got, g.ScalarSize())
+	}
+	if got := len(g.Generator().Bytes()); got != g.ElementSize() {
+		t.Errorf("len(Point.Bytes()) = %d, ElementSize() = %d", got, g.ElementSize())
+	}
+
+	registered, err := group.New(g.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := registered.(*BJJ); !ok {
+		t.Errorf("group.New(%q) returned %T", g.Name(), registered)
+	}
+	if !slices.Contains(group.Names(), g.Name()) {
+		t.Errorf("group.Names() = %v, missing %q", group.Names(), g.Name())
+	}
+	if _, err := group.New("no-such-group"); err == nil {
+		t.Error("expected error for an unregistered group")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	p := g.ScalarBaseMult(s)
+
+	type record struct {
+		S *Scalar
+		P *Point
+	}
+	in := record{S: s.(*Scalar), P: p.(*Point)}
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out record
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !out.S.Equal(s) || !out.P.Equal(p) {
+			t.Error("JSON round trip changed the values")
+		}
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+		var out record
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if !out.S.Equal(s) || !out.P.Equal(p) {
+			t.Error("gob round trip changed the values")
+		}
+	})
+
+	t.Run("Rejects", func(t *testing.T) {
+		var sc Scalar
+		if err := sc.UnmarshalBinary(make([]byte, 31)); err == nil {
+			t.Error("expected error for short scalar")
+		}
+		order := make([]byte, 32)
+		curveOrder.FillBytes(order)
+		if err := sc.UnmarshalBinary(order); err == nil {
+			t.Error("expected error for unreduced scalar")
+		}
+		if err := sc.UnmarshalText([]byte("zz")); err == nil {
+			t.Error("expected error for invalid hex")
+		}
+		var pt Point
+		if err := pt.UnmarshalBinary(make([]byte, 5)); err == nil {
+			t.Error("expected error for invalid point")
+		}
+	})
+}
+
+func TestSelect(t *testing.T) {
+	g := &BJJ{}
+	a, _ := g.RandomScalar(rand.Reader)
+	b, _ := g.RandomScalar(rand.Reader)
+	pa := g.ScalarBaseMult(a)
+	pb := g.ScalarBaseMult(b)
+
+	if !g.NewScalar().Select(a, b, 1).Equal(a) {
+		t.Error("Scalar.Select(a, b, 1) != a")
+	}
+	if !g.NewScalar().Select(a, b, 0).Equal(b) {
+		t.Error("Scalar.Select(a, b, 0) != b")
+	}
+	if !g.NewPoint().Select(pa, pb, 1).Equal(pa) {
+		t.Error("Point.Select(a, b, 1) != a")
+	}
+	if !g.NewPoint().Select(pa, pb, 0).Equal(pb) {
+		t.Error("Point.Select(a, b, 0) != b")
+	}
+
+	// The receiver may alias an argument
+	c := g.NewScalar().Set(a)
+	if !c.Select(b, c, 1).Equal(b) {
+		t.Error("aliased Scalar.Select did not pick b")
+	}
+}
+
+func TestClone(t *testing.T) {
+	g := &BJJ{}
+	a, _ := g.RandomScalar(rand.Reader)
+	c := a.Clone()
+	if !c.Equal(a) {
+		t.Fatal("Scalar.Clone() != original")
+	}
+	c.Add(c, c)
+	if c.Equal(a) {
+		t.Error("modifying a cloned scalar changed the original")
+	}
+
+	p := g.ScalarBaseMult(a)
+	q := p.Clone()
+	if !q.Equal(p) {
+		t.Fatal("Point.Clone() != original")
+	}
+	q.Add(q, q)
+	if q.Equal(p) {
+		t.Error("modifying a cloned point changed the original")
+	}
+}
+
+// foreignScalar and foreignPoint stand in for elements of another group.
+type foreignScalar struct{ group.Scalar }
+type foreignPoint struct{ group.Point }
+
+func TestMismatch(t *testing.T) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+
+	var mismatch *group.MismatchError
+	if err := g.CheckScalar(foreignScalar{}); !errors.As(err, &mismatch) {
+		t.Errorf("CheckScalar(foreign) = %v, want *group.MismatchError", err)
+	}
+	if err := g.CheckPoint(foreignPoint{}); !errors.As(err, &mismatch) {
+		t.Errorf("CheckPoint(foreign) = %v, want *group.MismatchError", err)
+	}
+	if err := g.CheckScalar(s); err != nil {
+		t.Errorf("CheckScalar(own) = %v", err)
+	}
+	if err := g.CheckPoint(g.Generator()); err != nil {
+		t.Errorf("CheckPoint(own) = %v", err)
+	}
+
+	defer func() {
+		if _, ok := recover().(*group.MismatchError); !ok {
+			t.Error("expected arithmetic on a foreign scalar to panic with *group.MismatchError")
+		}
+	}()
+	g.NewScalar().Add(s, foreignScalar{})
+}
+
+func TestDecodePoints(t *testing.T) {
+	g := &BJJ{}
+	encodings := [][]byte{g.NewPoint().Bytes(), g.Generator().Bytes()}
+	for range 5 {
+		s, _ := g.RandomScalar(rand.Reader)
+		encodings = append(encodings, g.ScalarBaseMult(s).Bytes())
+	}
+
+	points, err := g.DecodePoints(encodings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range points {
+		if !bytes.Equal(p.Bytes(), encodings[i]) {
+			t.Errorf("point %d did not round trip", i)
+		}
+	}
+	generic, err := group.DecodePoints(g, encodings)
+	if err != nil || len(generic) != len(encodings) {
+		t.Fatalf("group.DecodePoints = %d points, %v", len(generic), err)
+	}
+
+	// (0, -1) has order 2, so adding it leaves the prime-order subgroup
+	var minusOne fr.Element
+	minusOne.SetOne()
+	minusOne.Neg(&minusOne)
+	torsion := affinePoint(fr.Element{}, minusOne)
+	mixed := g.NewPoint().Add(g.Generator(), torsion)
+
+	// y = p + 1 reduces to the identity's y = 1 but is not canonical
+	nonCanonical := make([]byte, 32)
+	new(big.Int).Add(fr.Modulus(), big.NewInt(1)).FillBytes(nonCanonical)
+	slices.Reverse(nonCanonical)
+
+	// Find a y for which x^2 has no square root
+	var offCurve []byte
+	for y := uint64(2); offCurve == nil; y++ {
+		var e fr.Element
+		e.SetUint64(y)
+		b := e.Bytes()
+		slices.Reverse(b[:])
+		if _, err := g.NewPoint().SetBytes(b[:]); err != nil {
+			offCurve = b[:]
+		}
+	}
+
+	tests := map[string][]byte{
+		"Short":         encodings[0][:31],
+		"SmallOrder":    torsion.Bytes(),
+		"MixedOrder":    mixed.Bytes(),
+		"NonCanonical":  nonCanonical,
+		"NotOnCurve":    offCurve,
+		"NegativeXZero": append(slices.Clone(encodings[0][:31]), encodings[0][31]|0x80),
+	}
+	for name, enc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := g.NewPoint().SetBytes(enc); err == nil {
+				t.Error("SetBytes accepted an invalid encoding")
+			}
+			batch := append(slices.Clone(encodings), enc)
+			_, err := g.DecodePoints(batch)
+			if err == nil || !strings.HasPrefix(err.Error(), fmt.Sprintf("point %d:", len(encodings))) {
+				t.Errorf("DecodePoints error = %v, want one naming point %d", err, len(encodings))
+			}
+		})
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	s.Zeroize()
+	if !s.IsZero() {
+		t.Error("zeroized scalar is not zero")
+	}
+	if s.(*Scalar).inner != (fe{}) {
+		t.Fatal("zeroized scalar left its limbs in memory")
+	}
+
+	p := g.ScalarBaseMult(g.NewScalar().SetUint64(7))
+	p.Zeroize()
+	if !p.IsIdentity() {
+		t.Error("zeroized point is not the identity")
+	}
+}
+
+func TestExtendedCoordinates(t *testing.T) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	p := g.ScalarBaseMult(s).(*Point)
+
+	// Scaling every coordinate by the same factor leaves the point
+	// unchanged
+	var k fr.Element
+	k.SetUint64(12345)
+	scaled := p.Clone().(*Point)
+	scaled.inner.X.Mul(&scaled.inner.X, &k)
+	scaled.inner.Y.Mul(&scaled.inner.Y, &k)
+	scaled.inner.Z.Mul(&scaled.inner.Z, &k)
+	scaled.inner.T.Mul(&scaled.inner.T, &k)
+	if !scaled.Equal(p) || !p.Equal(scaled) {
+		t.Error("points with different Z are not equal")
+	}
+	if !bytes.Equal(scaled.Bytes(), p.Bytes()) {
+		t.Error("encoding depends on Z")
+	}
+	if !g.NewPoint().Add(scaled, g.NewPoint().Negate(p)).IsIdentity() {
+		t.Error("P - P is not the identity for a scaled P")
+	}
+
+	var zero Point
+	if zero.Equal(p) || p.Equal(&zero) || zero.Equal(&zero) {
+		t.Error("the zero Point is equal to a point")
+	}
+
+	// Each point is encoded in its own format
+	var _ group.PointEncoder = g
+	points := []group.Point{
+		p,
+		scaled,
+		(&BJJ{Encoding: EncodingRFC8032}).NewPoint().Set(scaled),
+		(&BJJ{Encoding: EncodingSEC1}).NewPoint().Set(scaled),
+		g.NewPoint(),
+	}
+	for i, e := range g.EncodePoints(points) {
+		if !bytes.Equal(e, points[i].Bytes()) {
+			t.Errorf("EncodePoints differs from Bytes for point %d", i)
+		}
+	}
+
+	// Normalizing sets Z = 1 and keeps the points and their encodings
+	encodings := g.EncodePoints(points)
+	normalized := make([]group.Point, len(points))
+	for i, q := range points {
+		normalized[i] = q.Clone()
+	}
+	normalized = append(normalized, &zero)
+	g.NormalizePoints(normalized)
+	for i, q := range normalized[:len(points)] {
+		if z := q.(*Point).inner.Z; !z.IsOne() {
+			t.Errorf("point %d has Z = %s after normalization", i, z.String())
+		}
+		if !q.Equal(points[i]) || !bytes.Equal(q.Bytes(), encodings[i]) {
+			t.Errorf("normalization changed point %d", i)
+		}
+		if a := q.(*Point).affine(); !a.IsOnCurve() {
+			t.Errorf("normalized point %d is not on the curve", i)
+		}
+	}
+	if zero != (Point{}) {
+		t.Error("normalization changed the zero Point")
+	}
+}
+
+func TestValidator(t *testing.T) {
+	g := &BJJ{}
+	var _ group.Validator = &Point{}
+
+	// (0, -1) has order 2
+	var minusOne fr.Element
+	minusOne.SetOne()
+	minusOne.Neg(&minusOne)
+	torsion := affinePoint(fr.Element{}, minusOne)
+	mixed := g.NewPoint().Add(g.Generator(), torsion).(*Point)
+
+	if !g.Generator().(*Point).IsInPrimeSubgroup() {
+		t.Error("generator is not in the prime-order subgroup")
+	}
+	if !mixed.IsOnCurve() || mixed.IsInPrimeSubgroup() {
+		t.Error("G + (0, -1) should be on the curve but outside the subgroup")
+	}
+	var one fr.Element
+	one.SetOne()
+	offCurve := affinePoint(one, one)
+	if offCurve.IsOnCurve() || offCurve.IsInPrimeSubgroup() {
+		t.Error("(1, 1) should not be on the curve")
+	}
+
+	cleared := g.NewPoint().(*Point).ClearCofactor(mixed)
+	want := g.ScalarBaseMult(g.NewScalar().SetUint64(8))
+	if !cleared.Equal(want) {
+		t.Error("ClearCofactor(G + T) != 8G")
+	}
+}
+
 func TestPoint(t *testing.T) {
 	g := &BJJ{}
 
@@ -277,9 +960,10 @@ func TestPoint(t *testing.T) {
 		yBytes := uncompressed[32:64]
 
 		// Create a new point and set coordinates manually
-		restored := &Point{}
-		restored.inner.X.SetBytes(xBytes)
-		restored.inner.Y.SetBytes(yBytes)
+		var x, y fr.Element
+		x.SetBytes(xBytes)
+		y.SetBytes(yBytes)
+		restored := affinePoint(x, y)
 
 		if !restored.Equal(P) {
 			t.Error("uncompressed format is not X || Y")
@@ -312,6 +996,28 @@ func TestPoint(t *testing.T) {
 		}
 	})
 
+	t.Run("UncompressedBytesSubgroup", func(t *testing.T) {
+		// (0, -1) has order 2, and G + (0, -1) has mixed order
+		var minusOne fr.Element
+		minusOne.SetOne()
+		minusOne.Neg(&minusOne)
+		torsion := affinePoint(fr.Element{}, minusOne)
+		mixed := g.NewPoint().Add(g.Generator(), torsion).(*Point)
+		for _, p := range []*Point{torsion, mixed} {
+			if err := new(Point).SetUncompressedBytes(p.UncompressedBytes()); err == nil {
+				t.Error("accepted a point outside the prime-order subgroup")
+			}
+		}
+
+		// x + p encodes the same point but is not canonical
+		enc := g.Generator().(*Point).UncompressedBytes()
+		x := new(big.Int).SetBytes(enc[:32])
+		x.Add(x, fr.Modulus()).FillBytes(enc[:32])
+		if err := new(Point).SetUncompressedBytes(enc); err == nil {
+			t.Error("accepted a non-canonical coordinate")
+		}
+	})
+
 	t.Run("UncompressedBytesGenerator", func(t *testing.T) {
 		gen := g.Generator().(*Point)
 		uncompressed := gen.UncompressedBytes()
@@ -341,3 +1047,662 @@ func TestPoint(t *testing.T) {
 		}
 	})
 }
+
+func TestMultiScalarMult(t *testing.T) {
+	g := &BJJ{}
+
+	// Up to maxStraus terms use Straus' method, then Pippenger's
+	for _, n := range []int{0, 1, 2, 7, 64, maxStraus, maxStraus + 1, 300} {
+		scalars := make([]group.Scalar, n)
+		points := make([]group.Point, n)
+		for i := range n {
+			scalars[i], _ = g.RandomScalar(rand.Reader)
+			s, _ := g.RandomScalar(rand.Reader)
+			points[i] = g.NewPoint().ScalarMult(s, g.Generator())
+		}
+		// Repeated points and special scalars exercise bucket doubling
+		if n > 2 {
+			points[1] = points[0]
+			scalars[1] = g.NewScalar().Set(scalars[0])
+			scalars[2] = g.NewScalar()
+			points[n-1] = g.NewPoint()
+		}
+
+		got := g.MultiScalarMult(scalars, points)
+		want := group.MultiScalarMult(g, scalars, points)
+		if !got.Equal(want) {
+			t.Errorf("n=%d: MultiScalarMult does not match the naive sum", n)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for mismatched lengths")
+		}
+	}()
+	g.MultiScalarMult(make([]group.Scalar, 1), nil)
+}
+
+func TestWNAF(t *testing.T) {
+	g := &BJJ{}
+	one := g.NewScalar().SetUint64(1)
+	values := []group.Scalar{g.NewScalar(), one, g.NewScalar().Negate(one), g.NewScalar().SetUint64(0xffff)}
+	for range 32 {
+		s, _ := g.RandomScalar(rand.Reader)
+		values = append(values, s)
+	}
+	for _, s := range values {
+		k := s.(*Scalar).inner.bytes()
+		naf, n := wnaf(&k, wnafWidth)
+		sum := new(big.Int)
+		last := -1
+		for i := len(naf) - 1; i >= 0; i-- {
+			d := int(naf[i])
+			sum.Lsh(sum, 1)
+			sum.Add(sum, big.NewInt(int64(d)))
+			if d == 0 {
+				continue
+			}
+			if d%2 == 0 || d >= 1<<(wnafWidth-1) || d <= -1<<(wnafWidth-1) {
+				t.Fatalf("%x: digit %d at %d", k, d, i)
+			}
+			if last >= 0 && last-i < wnafWidth {
+				t.Fatalf("%x: nonzero digits at %d and %d", k, last, i)
+			}
+			if last < 0 && n != i+1 {
+				t.Fatalf("%x: length %d, last nonzero digit at %d", k, n, i)
+			}
+			last = i
+		}
+		if sum.Cmp(new(big.Int).SetBytes(k[:])) != 0 {
+			t.Fatalf("%x: NAF sums to %x", k, sum)
+		}
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	g := &BJJ{}
+	const n = 64
+	scalars := make([]group.Scalar, n)
+	points := make([]group.Point, n)
+	for i := range n {
+		scalars[i], _ = g.RandomScalar(rand.Reader)
+		points[i] = g.NewPoint().ScalarMult(scalars[i], g.Generator())
+	}
+
+	b.Run("Pippenger", func(b *testing.B) {
+		for b.Loop() {
+			g.MultiScalarMult(scalars, points)
+		}
+	})
+	b.Run("Naive", func(b *testing.B) {
+		for b.Loop() {
+			group.MultiScalarMult(g, scalars, points)
+		}
+	})
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	g := &BJJ{}
+
+	one, _ := g.NewScalar().SetBytes([]byte{1})
+	minusOne := g.NewScalar().Negate(one)
+	scalars := []group.Scalar{g.NewScalar(), one, minusOne}
+	for range 16 {
+		s, _ := g.RandomScalar(rand.Reader)
+		scalars = append(scalars, s)
+	}
+
+	two := g.NewScalar().SetUint64(2)
+	twoG := g.NewPoint().Add(g.Generator(), g.Generator())
+	base := twistededwards.GetEdwardsCurve().Base
+	for _, s := range scalars {
+		// Reference result from the generic double-and-add
+		want := g.NewPoint().(*Point)
+		want.inner.FromAffine(new(twistededwards.PointAffine).ScalarMultiplication(&base, s.(*Scalar).BigInt()))
+
+		if !g.ScalarBaseMult(s).Equal(want) {
+			t.Errorf("ScalarBaseMult(%x) is wrong", s.Bytes())
+		}
+		if !g.NewPoint().ScalarMult(s, g.Generator()).Equal(want) {
+			t.Errorf("ScalarMult(%x, G) is wrong", s.Bytes())
+		}
+		// Other points take the generic path
+		s2 := g.NewScalar().Mul(s, two)
+		if !g.NewPoint().ScalarMult(s, twoG).Equal(g.ScalarBaseMult(s2)) {
+			t.Errorf("ScalarMult(%x, 2G) is wrong", s.Bytes())
+		}
+	}
+}
+
+func TestPointOpsDoNotAllocate(t *testing.T) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	q := g.ScalarBaseMult(s)
+	gen := g.Generator()
+	p := g.NewPoint()
+	r := g.NewScalar()
+	for name, op := range map[string]func(){
+		"ScalarMult":        func() { p.ScalarMult(s, q) },
+		"ScalarBaseMult":    func() { p.ScalarMult(s, gen) },
+		"Add":               func() { p.Add(p, q) },
+		"Mul":               func() { r.Mul(r, s) },
+		"IsInPrimeSubgroup": func() { q.(*Point).IsInPrimeSubgroup() },
+	} {
+		if n := testing.AllocsPerRun(10, op); n != 0 {
+			t.Errorf("%s: %v allocations, want 0", name, n)
+		}
+	}
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	q := g.ScalarBaseMult(s)
+	p := g.NewPoint()
+
+	b.Run("FixedWindow", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			p.ScalarMult(s, q)
+		}
+	})
+	b.Run("WNAF", func(b *testing.B) {
+		// MultiScalarMult with one term, for public scalars
+		scalars, points := []group.Scalar{s}, []group.Point{q}
+		for b.Loop() {
+			g.MultiScalarMult(scalars, points)
+		}
+	})
+	b.Run("Generic", func(b *testing.B) {
+		b.ReportAllocs()
+		k := s.(*Scalar).BigInt()
+		var e twistededwards.PointExtended
+		for b.Loop() {
+			e.ScalarMultiplication(&q.(*Point).inner, k)
+		}
+	})
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	g := &BJJ{}
+	s, _ := g.RandomScalar(rand.Reader)
+	g.ScalarBaseMult(s) // build the table outside the timed loop
+
+	b.Run("Table", func(b *testing.B) {
+		for b.Loop() {
+			g.ScalarBaseMult(s)
+		}
+	})
+	b.Run("Generic", func(b *testing.B) {
+		base := twistededwards.GetEdwardsCurve().Base
+		k := s.(*Scalar).BigInt()
+		var p twistededwards.PointAffine
+		for b.Loop() {
+			p.ScalarMultiplication(&base, k)
+		}
+	})
+}
+
+func TestHashToPoint(t *testing.T) {
+	g := &BJJ{}
+	seen := make(map[string]bool)
+	for _, msg := range []string{"", "abc", "abcdef0123456789", string(make([]byte, 200))} {
+		p, err := g.HashToPoint("FY-TEST-V01-CS01-with-BJJ_XMD:SHA-256_ELL2_RO_", []byte(msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.(*Point).IsOnCurve() {
+			t.Fatalf("%q: point is not on the curve", msg)
+		}
+		if p.IsIdentity() {
+			t.Fatalf("%q: hashed to the identity", msg)
+		}
+		// Multiplying by the group order must give the identity
+		if !inSubgroup(&p.(*Point).inner) {
+			t.Errorf("%q: point is not in the prime-order subgroup", msg)
+		}
+		seen[string(p.Bytes())] = true
+	}
+	if len(seen) != 4 {
+		t.Error("distinct messages hashed to the same point")
+	}
+
+	// Known answers, checked against an independent implementation of
+	// the RFC 9380 steps, so that the output cannot change silently
+	for msg, want := range map[string]string{
+		"":    "4facffb453b7bec4ef7ee4eb5fca195f165605ac71a6e1b774e679886ecd3e0c",
+		"abc": "64adcebf175571a7ddc4d0806922f720456554030a7a7854e9d4c5f046b19a0f",
+	} {
+		p, _ := g.HashToPoint("FY-TEST-V01-CS01-with-BJJ_XMD:SHA-256_ELL2_RO_", []byte(msg))
+		if got := hex.EncodeToString(p.Bytes()); got != want {
+			t.Errorf("HashToPoint(%q) = %s, want %s", msg, got, want)
+		}
+	}
+
+	a, _ := g.HashToPoint("dst-a", []byte("msg"))
+	again, _ := g.HashToPoint("dst-a", []byte("m"), []byte("sg"))
+	b, _ := g.HashToPoint("dst-b", []byte("msg"))
+	if !a.Equal(again) {
+		t.Error("HashToPoint is not deterministic")
+	}
+	if a.Equal(b) {
+		t.Error("different tags produced the same point")
+	}
+
+	if _, err := g.HashToPoint("", []byte("msg")); err == nil {
+		t.Error("expected error for empty tag")
+	}
+}
+
+func TestMapToCurve(t *testing.T) {
+	// Exceptional inputs of the map must still yield curve points
+	var zero, one fr.Element
+	one.SetOne()
+	for _, u := range []fr.Element{zero, one} {
+		p := mapToCurve(&u)
+		if !p.IsOnCurve() {
+			t.Errorf("mapToCurve(%s) is not on the curve", u.String())
+		}
+	}
+	for range 32 {
+		var u fr.Element
+		u.SetRandom()
+		p := mapToCurve(&u)
+		if !p.IsOnCurve() {
+			t.Fatalf("mapToCurve(%s) is not on the curve", u.String())
+		}
+	}
+}
+
+func TestCurveForms(t *testing.T) {
+	g := &BJJ{}
+	var one fr.Element
+	one.SetOne()
+	for range 16 {
+		s, _ := g.RandomScalar(rand.Reader)
+		p := g.ScalarBaseMult(s).(*Point)
+
+		u, v, err := p.Montgomery()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// v^2 = u^3 + 168698*u^2 + u
+		var mu, mv, lhs, rhs, t2 fr.Element
+		mu.SetBigInt(u)
+		mv.SetBigInt(v)
+		lhs.Square(&mv)
+		t2.SetUint64(168698).Add(&t2, &mu)
+		rhs.Square(&mu).Mul(&rhs, &t2).Add(&rhs, &mu)
+		if !lhs.Equal(&rhs) {
+			t.Fatal("Montgomery point is not on the EIP-2494 curve")
+		}
+		// u depends on y only, so it is shared with -p
+		nu, nv, _ := g.NewPoint().Negate(p).(*Point).Montgomery()
+		if nu.Cmp(u) != 0 || new(big.Int).Add(nv, v).Cmp(fr.Modulus()) != 0 {
+			t.Error("negation does not negate v")
+		}
+		q := g.NewPoint().(*Point)
+		if err := q.SetMontgomery(u, v); err != nil || !q.Equal(p) {
+			t.Fatalf("Montgomery round trip failed: %v", err)
+		}
+
+		x, y, err := p.Weierstrass()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// y^2 = x^3 + a*x + b
+		var wx, wy fr.Element
+		wx.SetBigInt(x)
+		wy.SetBigInt(y)
+		lhs.Square(&wy)
+		rhs.Square(&wx).Add(&rhs, &weierstrA).Mul(&rhs, &wx).Add(&rhs, &weierstrB)
+		if !lhs.Equal(&rhs) {
+			t.Fatal("Weierstrass point is not on the curve")
+		}
+		q = g.NewPoint().(*Point)
+		if err := q.SetWeierstrass(x, y); err != nil || !q.Equal(p) {
+			t.Fatalf("Weierstrass round trip failed: %v", err)
+		}
+	}
+
+	// The point at infinity and the point (0, 0) of order 2 are rejected
+	if _, _, err := g.NewPoint().(*Point).Montgomery(); err == nil {
+		t.Error("Montgomery accepted the identity")
+	}
+	if _, _, err := g.NewPoint().(*Point).Weierstrass(); err == nil {
+		t.Error("Weierstrass accepted the identity")
+	}
+	zero := new(big.Int)
+	if err := g.NewPoint().(*Point).SetMontgomery(zero, zero); err == nil {
+		t.Error("SetMontgomery accepted a point of order 2")
+	}
+	// The EIP-2494 generator G is on the curve but has order 8r
+	var gx, gy, gu, gv, den fr.Element
+	gx.SetString("995203441582195749578291179787384436505546430278305826713579947235728471134")
+	gy.SetString("5472060717959818805561601436314318772137091100104008585924551046643952123905")
+	gu.Add(&one, &gy)
+	gu.Div(&gu, den.Sub(&one, &gy))
+	gv.Div(&gu, &gx)
+	if err := g.NewPoint().(*Point).SetMontgomery(gu.BigInt(new(big.Int)), gv.BigInt(new(big.Int))); err == nil ||
+		!strings.Contains(err.Error(), "subgroup") {
+		t.Errorf("SetMontgomery(G) = %v, want a subgroup error", err)
+	}
+	u, v, _ := g.Generator().(*Point).Montgomery()
+	if err := g.NewPoint().(*Point).SetMontgomery(u, new(big.Int).Add(v, big.NewInt(1))); err == nil {
+		t.Error("SetMontgomery accepted a point off the curve")
+	}
+	if err := g.NewPoint().(*Point).SetMontgomery(new(big.Int).Add(u, fr.Modulus()), v); err == nil {
+		t.Error("SetMontgomery accepted a non-canonical coordinate")
+	}
+}
+
+func TestCurveParams(t *testing.T) {
+	g := &BJJ{}
+	params := g.Params()
+	if params.Gx.String() != "5299619240641551281634865583518297030282874472190772894086521144482721001553" ||
+		params.Gy.String() != "16950150798460657717958625567821834550301663161624707787222815936182638968203" {
+		t.Error("the generator is not B8")
+	}
+	if params.Order.Cmp(new(big.Int).SetBytes(g.Order())) != 0 || params.Cofactor.Int64() != 8 {
+		t.Error("unexpected order or cofactor")
+	}
+	params.Order.SetInt64(1)
+	if g.Params().Order.Cmp(curveOrder) != 0 || curveOrder.Cmp(big.NewInt(1)) == 0 {
+		t.Error("Params returned shared values")
+	}
+
+	// a*x^2 + y^2 = 1 + d*x^2*y^2
+	onCurve := func(x, y *big.Int) bool {
+		p := params.P
+		x2 := new(big.Int).Mul(x, x)
+		y2 := new(big.Int).Mul(y, y)
+		lhs := new(big.Int).Mul(params.A, x2)
+		lhs.Add(lhs, y2).Mod(lhs, p)
+		rhs := new(big.Int).Mul(params.D, x2)
+		rhs.Mul(rhs, y2).Add(rhs, big.NewInt(1)).Mod(rhs, p)
+		return lhs.Cmp(rhs) == 0
+	}
+	if !onCurve(params.Gx, params.Gy) {
+		t.Fatal("the generator is not on the EIP-2494 curve")
+	}
+
+	circom := &BJJ{Encoding: EncodingCircomlib}
+	for range 16 {
+		s, _ := g.RandomScalar(rand.Reader)
+		p := circom.ScalarBaseMult(s).(*Point)
+		x, y := p.X(), p.Y()
+		if !onCurve(x, y) {
+			t.Fatal("X and Y are not on the EIP-2494 curve")
+		}
+		q := g.NewPoint().(*Point)
+		if err := q.SetCoordinates(x, y); err != nil || !q.Equal(p) {
+			t.Fatalf("SetCoordinates round trip failed: %v", err)
+		}
+
+		le := p.LittleEndianBytes()
+		packed := p.Bytes()
+		if !bytes.Equal(le[32:63], packed[:31]) || le[63] != packed[31]&0x7f {
+			t.Error("LittleEndianBytes and packPoint disagree on y")
+		}
+		q = g.NewPoint().(*Point)
+		if err := q.SetLittleEndianBytes(le); err != nil || !q.Equal(p) {
+			t.Fatalf("LittleEndianBytes round trip failed: %v", err)
+		}
+	}
+
+	// The EIP-2494 generator G is on the curve but has order 8r
+	gx, _ := new(big.Int).SetString("995203441582195749578291179787384436505546430278305826713579947235728471134", 10)
+	gy, _ := new(big.Int).SetString("5472060717959818805561601436314318772137091100104008585924551046643952123905", 10)
+	if !onCurve(gx, gy) {
+		t.Fatal("G is not on the EIP-2494 curve")
+	}
+	if err := g.NewPoint().(*Point).SetCoordinates(gx, gy); err == nil {
+		t.Error("SetCoordinates accepted a point outside the subgroup")
+	}
+	if err := g.NewPoint().(*Point).SetCoordinates(params.Gx, new(big.Int).Add(params.Gy, params.P)); err == nil {
+		t.Error("SetCoordinates accepted a non-canonical coordinate")
+	}
+	if err := g.NewPoint().(*Point).SetCoordinates(params.Gx, big.NewInt(1)); err == nil {
+		t.Error("SetCoordinates accepted a point off the curve")
+	}
+	if err := g.NewPoint().(*Point).SetLittleEndianBytes(make([]byte, 63)); err == nil {
+		t.Error("SetLittleEndianBytes accepted 63 bytes")
+	}
+	bad := bytes.Repeat([]byte{0xff}, 64)
+	if err := g.NewPoint().(*Point).SetLittleEndianBytes(bad); err == nil {
+		t.Error("SetLittleEndianBytes accepted a non-canonical encoding")
+	}
+}
+
+func TestIden3Signatures(t *testing.T) {
+	// A stand-in for poseidon.Hash: any hash of field elements to a field
+	// element exercises the same conventions
+	hash := func(inputs []*big.Int) (*big.Int, error) {
+		h := sha256.New()
+		for _, in := range inputs {
+			h.Write(in.FillBytes(make([]byte, 32)))
+		}
+		v := new(big.Int).SetBytes(h.Sum(nil))
+		return v.Mod(v, fr.Modulus()), nil
+	}
+
+	g := &BJJ{Encoding: EncodingCircomlib}
+	f, err := frost.NewWithHasher(g, 2, 3, &Iden3Hasher{Hash: hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	participants := make([]*frost.Participant, 3)
+	broadcasts := make([]*frost.Round1Data, 3)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i != j {
+				f.Round2ReceiveShare(participants[j], f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments)
+			}
+		}
+	}
+	shares := make([]*frost.KeyShare, 3)
+	for i, p := range participants {
+		shares[i], _ = f.Finalize(p, broadcasts)
+	}
+
+	m := big.NewInt(1234)
+	message, err := Iden3Message(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := shares[1:]
+	nonces := make([]*frost.SigningNonce, 2)
+	commitments := make([]*frost.SigningCommitment, 2)
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+	sigShares := make([]*frost.SignatureShare, 2)
+	for i, ks := range signers {
+		if sigShares[i], err = f.SignRound2(ks, nonces[i], message, commitments); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sig, err := f.Aggregate(message, commitments, sigShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := shares[0].GroupKey
+	if !f.Verify(message, sig, groupKey) {
+		t.Fatal("FROST verification failed")
+	}
+
+	compressed := Iden3Signature(sig.R, sig.Z)
+	if !bytes.Equal(compressed[:32], sig.R.Bytes()) {
+		t.Error("R8 is not packed with the circomlib encoding")
+	}
+	if !VerifyIden3(hash, groupKey, m, compressed) {
+		t.Fatal("iden3 verification failed")
+	}
+	if VerifyIden3(hash, groupKey, big.NewInt(1235), compressed) {
+		t.Error("signature verified for another message")
+	}
+	tampered := compressed
+	tampered[40] ^= 1
+	if VerifyIden3(hash, groupKey, m, tampered) {
+		t.Error("tampered signature verified")
+	}
+
+	in, err := NewCircomEdDSAInputs(sig.R, sig.Z, groupKey, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r8x, r8y := iden3Coordinates(toPoint(sig.R))
+	if in.R8x.Cmp(r8x) != 0 || in.R8y.Cmp(r8y) != 0 {
+		t.Error("R8 does not match the signature")
+	}
+	le := slices.Clone(compressed[32:])
+	slices.Reverse(le)
+	if in.S.Cmp(new(big.Int).SetBytes(le)) != 0 {
+		t.Error("S does not match the compressed signature")
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signals map[string]string
+	if err := json.Unmarshal(data, &signals); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]*big.Int{
+		"enabled": big.NewInt(1), "Ax": in.Ax, "Ay": in.Ay,
+		"R8x": r8x, "R8y": r8y, "S": in.S, "M": m,
+	} {
+		if signals[name] != want.String() {
+			t.Errorf("signal %s = %q, want %s", name, signals[name], want)
+		}
+	}
+	if len(signals) != 7 {
+		t.Errorf("got %d signals, want 7", len(signals))
+	}
+	if _, err := NewCircomEdDSAInputs(sig.R, sig.Z, groupKey, fr.Modulus()); err == nil {
+		t.Error("NewCircomEdDSAInputs accepted the field modulus")
+	}
+
+	if _, err := Iden3Message(fr.Modulus()); err == nil {
+		t.Error("Iden3Message accepted the field modulus")
+	}
+
+	// Other messages are rejected with errors, never a panic
+	bad := []byte("not a field element")
+	if f.Verify(bad, sig, groupKey) {
+		t.Error("Verify accepted a message that is not a field element")
+	}
+	if ok, err := f.VerifyBatch(rand.Reader, []frost.BatchEntry{{Message: bad, Signature: sig, GroupKey: groupKey}}); ok || err != nil {
+		t.Errorf("VerifyBatch of a message that is not a field element = %v, %v", ok, err)
+	}
+	if _, err := f.Aggregate(bad, commitments, sigShares); err == nil {
+		t.Error("Aggregate accepted a message that is not a field element")
+	}
+	nonce, _, _ := f.SignRound1(rand.Reader, signers[0])
+	var inputErr *frost.InputError
+	if _, err := f.SignRound2(signers[0], nonce, fr.Modulus().FillBytes(make([]byte, 32)), commitments); !errors.As(err, &inputErr) {
+		t.Errorf("SignRound2 of the field modulus: got %v, want an InputError", err)
+	}
+	if err := (&Iden3Hasher{Hash: hash}).ValidateMessage(struct{ group.Group }{g}, message); err == nil {
+		t.Error("ValidateMessage accepted another group")
+	}
+	failing := &Iden3Hasher{Hash: func([]*big.Int) (*big.Int, error) { return nil, errors.New("hash failed") }}
+	if c := failing.H2(g, sig.R.Bytes(), groupKey.Bytes(), message); c != nil {
+		t.Error("H2 returned a challenge although Hash failed")
+	}
+	if _, err := frost.NewWithHasher(g, 2, 3, &Iden3Hasher{}); err == nil {
+		t.Error("NewWithHasher accepted an Iden3Hasher without Hash")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	caps := group.CapabilitiesOf(&BJJ{})
+	if !caps.ConstantTimeScalars || !caps.ConstantTimeScalarMult {
+		t.Errorf("CapabilitiesOf(BJJ) = %+v", caps)
+	}
+	if caps := group.CapabilitiesOf(struct{ group.Group }{&BJJ{}}); caps != (group.Capabilities{}) {
+		t.Errorf("wrapped group reports %+v", caps)
+	}
+}
+
+// encodings lists every point encoding.
+var encodings = []Encoding{EncodingGnark, EncodingRFC8032, EncodingSEC1, EncodingCircomlib}
+
+func FuzzPointSetBytes(f *testing.F) {
+	for _, enc := range encodings {
+		g := &BJJ{Encoding: enc}
+		f.Add(g.Generator().Bytes())
+		f.Add(g.NewPoint().Bytes())
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0x04})
+	f.Add(bytes.Repeat([]byte{0xff}, 32))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, enc := range encodings {
+			g := &BJJ{Encoding: enc}
+			p, err := g.NewPoint().SetBytes(data)
+			points, batchErr := g.DecodePoints([][]byte{data})
+			if (err == nil) != (batchErr == nil) {
+				t.Fatalf("%v: SetBytes err = %v, DecodePoints err = %v", enc, err, batchErr)
+			}
+			if err != nil {
+				continue
+			}
+			if !bytes.Equal(p.Bytes(), data) {
+				t.Fatalf("%v: %x decodes to a point encoded as %x", enc, data, p.Bytes())
+			}
+			if !points[0].Equal(p) {
+				t.Fatalf("%v: SetBytes and DecodePoints disagree on %x", enc, data)
+			}
+		}
+	})
+}
+
+func FuzzPointSetUncompressedBytes(f *testing.F) {
+	g := &BJJ{}
+	f.Add(g.Generator().(*Point).UncompressedBytes())
+	f.Add(g.NewPoint().(*Point).UncompressedBytes())
+	f.Add([]byte{})
+	f.Add(make([]byte, 63))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p := &Point{}
+		if err := p.SetUncompressedBytes(data); err != nil {
+			return
+		}
+		if !bytes.Equal(p.UncompressedBytes(), data) {
+			t.Fatalf("%x decodes to a point encoded as %x", data, p.UncompressedBytes())
+		}
+	})
+}
+
+func FuzzScalarSetBytes(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1})
+	f.Add(curveOrder.Bytes())
+	f.Add(bytes.Repeat([]byte{0xff}, 32))
+	f.Add(bytes.Repeat([]byte{0xff}, 100))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := new(Scalar).SetBytes(data)
+		if err != nil {
+			t.Fatalf("SetBytes(%x): %v", data, err)
+		}
+		want := new(big.Int).Mod(new(big.Int).SetBytes(data), curveOrder).FillBytes(make([]byte, 32))
+		if !bytes.Equal(s.Bytes(), want) {
+			t.Fatalf("SetBytes(%x) = %x, want %x", data, s.Bytes(), want)
+		}
+
+		canonical := len(data) == 32 && new(big.Int).SetBytes(data).Cmp(curveOrder) < 0
+		c, err := new(Scalar).SetCanonicalBytes(data)
+		if (err == nil) != canonical {
+			t.Fatalf("SetCanonicalBytes(%x) err = %v, canonical = %v", data, err, canonical)
+		}
+		if err == nil && !bytes.Equal(c.Bytes(), data) {
+			t.Fatalf("SetCanonicalBytes(%x) re-encodes as %x", data, c.Bytes())
+		}
+	})
+}
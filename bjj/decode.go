@@ -0,0 +1,144 @@
+package bjj
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/group"
+)
+
+// DecodePoints decodes and validates the encodings in data using g's
+// [Encoding], with the same checks as [Point.SetBytes]. Recovering the
+// x-coordinates of compressed points needs one field inversion per point,
+// and DecodePoints shares a single inversion across the batch.
+//
+// The subgroup check is still done point by point: with a cofactor of 8,
+// a random linear combination of the points only catches a small-order
+// component with probability 1/2, which is not enough to batch it.
+func (g *BJJ) DecodePoints(data [][]byte) ([]group.Point, error) {
+	points, bad, err := decodePoints(data, g.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("point %d: %w", bad, err)
+	}
+	result := make([]group.Point, len(points))
+	for i := range points {
+		result[i] = &points[i]
+	}
+	return result, nil
+}
+
+// decodePoints decodes encodings in format enc. It rejects encodings that
+// are not canonical or do not describe a point of the prime-order
+// subgroup, returning the index of the first invalid encoding with the
+// error.
+func decodePoints(data [][]byte, enc Encoding) ([]Point, int, error) {
+	var points []Point
+	var bad int
+	var err error
+	if enc == EncodingSEC1 {
+		points, bad, err = decodeUncompressed(data)
+	} else {
+		points, bad, err = decodeCompressed(data, enc)
+	}
+	if err != nil {
+		return nil, bad, err
+	}
+
+	for i := range points {
+		points[i].encoding = enc
+		if !inSubgroup(&points[i].inner) {
+			return nil, i, errors.New("point is not in the prime-order subgroup")
+		}
+	}
+	return points, 0, nil
+}
+
+// decodeCompressed decodes 32-byte encodings holding y in little-endian
+// order, with the top bit holding the sign of x as defined by enc.
+func decodeCompressed(data [][]byte, enc Encoding) ([]Point, int, error) {
+	curve := twistededwards.GetEdwardsCurve()
+	n := len(data)
+	ys := make([]fr.Element, n)
+	negative := make([]bool, n)
+	nums := make([]fr.Element, n)
+	dens := make([]fr.Element, n)
+
+	var one fr.Element
+	one.SetOne()
+	for i, d := range data {
+		if len(d) != 32 {
+			return nil, i, errors.New("compressed point must be 32 bytes")
+		}
+		var be [32]byte
+		for j := range be {
+			be[j] = d[31-j]
+		}
+		negative[i] = be[0]&0x80 != 0
+		be[0] &= 0x7f
+		if err := ys[i].SetBytesCanonical(be[:]); err != nil {
+			return nil, i, errors.New("non-canonical encoding")
+		}
+
+		// x^2 = (1 - y^2) / (a - d*y^2)
+		var y2 fr.Element
+		y2.Square(&ys[i])
+		nums[i].Sub(&one, &y2)
+		dens[i].Mul(&y2, &curve.D)
+		dens[i].Sub(&curve.A, &dens[i])
+	}
+
+	invs := fr.BatchInvert(dens)
+	points := make([]Point, n)
+	for i := range points {
+		var x fr.Element
+		x.Mul(&nums[i], &invs[i])
+		if x.Sqrt(&x) == nil {
+			return nil, i, errors.New("point is not on curve")
+		}
+		if x.IsZero() && negative[i] {
+			return nil, i, errors.New("non-canonical encoding")
+		}
+		if xSign(&x, enc) != negative[i] {
+			x.Neg(&x)
+		}
+		points[i].inner.FromAffine(&twistededwards.PointAffine{X: x, Y: ys[i]})
+	}
+	return points, 0, nil
+}
+
+// decodeUncompressed decodes 65-byte SEC1 uncompressed encodings.
+func decodeUncompressed(data [][]byte) ([]Point, int, error) {
+	points := make([]Point, len(data))
+	for i, d := range data {
+		if len(d) != 65 || d[0] != 0x04 {
+			return nil, i, errors.New("uncompressed point must be 0x04 followed by 64 bytes")
+		}
+		var p twistededwards.PointAffine
+		if p.X.SetBytesCanonical(d[1:33]) != nil || p.Y.SetBytesCanonical(d[33:]) != nil {
+			return nil, i, errors.New("non-canonical encoding")
+		}
+		if !p.IsOnCurve() {
+			return nil, i, errors.New("point is not on curve")
+		}
+		points[i].inner.FromAffine(&p)
+	}
+	return points, 0, nil
+}
+
+// curveOrderNAF is the width-5 NAF of curveOrder, with curveOrderNAFLen
+// digits, for inSubgroup.
+var (
+	curveOrderNAF    [257]int8
+	curveOrderNAFLen int
+)
+
+// inSubgroup reports whether p lies in the prime-order subgroup, that is
+// whether curveOrder * p is the identity. Both are public, so the product
+// is computed in variable time.
+func inSubgroup(p *twistededwards.PointExtended) bool {
+	var e twistededwards.PointExtended
+	wnafMult(&e, p, &curveOrderNAF, curveOrderNAFLen)
+	return e.IsZero()
+}
@@ -0,0 +1,247 @@
+package bjj
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/group"
+)
+
+// Encoding selects the byte format used by [Point.Bytes] and
+// [Point.SetBytes]. Set it with the Encoding field of [BJJ] to match the
+// format expected by another ecosystem; protocol code is unaffected, as
+// it only ever goes through Bytes and SetBytes.
+//
+// All encodings reject points outside the prime-order subgroup.
+type Encoding int
+
+const (
+	// EncodingGnark is the 32-byte compressed format of gnark-crypto: y
+	// in little-endian order, with the top bit set if x is
+	// lexicographically largest, that is greater than (p-1)/2. It is the
+	// default.
+	EncodingGnark Encoding = iota
+
+	// EncodingRFC8032 is the 32-byte compressed format of RFC 8032
+	// section 5.1.2: y in little-endian order, with the top bit holding
+	// the least significant bit of x.
+	EncodingRFC8032
+
+	// EncodingSEC1 is the 65-byte uncompressed format of SEC 1 section
+	// 2.3.3: the byte 0x04 followed by x and y in big-endian order.
+	EncodingSEC1
+
+	// EncodingCircomlib is the 32-byte format of packPoint in circomlibjs
+	// and of the iden3 tooling. It describes points in the coordinates of
+	// EIP-2494, where the curve is 168700x^2 + y^2 = 1 + 168696x^2y^2:
+	// y, which is the same in both coordinate systems, in little-endian
+	// order, with the top bit set if the EIP-2494 x is greater than
+	// (p-1)/2.
+	EncodingCircomlib
+)
+
+// iden3Scale maps EIP-2494 x-coordinates to those of gnark-crypto's curve
+// -x^2 + y^2 = 1 + dx^2y^2: x_gnark = iden3Scale * x_eip2494. It is the
+// square root of -168700 that maps the EIP-2494 base point B8 to
+// gnark-crypto's base point.
+var iden3Scale, iden3ScaleInv fr.Element
+
+func init() {
+	iden3Scale.SetString("15527681003928902128179717624703512672403908117992798440346960750464748824729")
+	iden3ScaleInv.Inverse(&iden3Scale)
+}
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingGnark:
+		return "gnark"
+	case EncodingRFC8032:
+		return "rfc8032"
+	case EncodingSEC1:
+		return "sec1"
+	case EncodingCircomlib:
+		return "circomlib"
+	default:
+		return "unknown"
+	}
+}
+
+// size returns the length of an encoded point.
+func (e Encoding) size() int {
+	if e == EncodingSEC1 {
+		return 65
+	}
+	return 32
+}
+
+// encodePoint encodes p in format enc.
+func encodePoint(p *twistededwards.PointAffine, enc Encoding) []byte {
+	switch enc {
+	case EncodingGnark:
+		b := p.Bytes()
+		return b[:]
+	case EncodingRFC8032, EncodingCircomlib:
+		y := p.Y.Bytes()
+		for i, j := 0, len(y)-1; i < j; i, j = i+1, j-1 {
+			y[i], y[j] = y[j], y[i]
+		}
+		if xSign(&p.X, enc) {
+			y[31] |= 0x80
+		}
+		return y[:]
+	case EncodingSEC1:
+		out := make([]byte, 65)
+		out[0] = 0x04
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		copy(out[1:33], x[:])
+		copy(out[33:], y[:])
+		return out
+	default:
+		panic("bjj: unknown point encoding")
+	}
+}
+
+// EncodePoints returns [Point.Bytes] of every point. Points are kept in
+// extended coordinates, and encoding needs a field inversion per point to
+// convert them to affine coordinates; EncodePoints shares a single
+// inversion across the batch.
+func (g *BJJ) EncodePoints(points []group.Point) [][]byte {
+	ps := make([]*Point, len(points))
+	for i, p := range points {
+		ps[i] = toPoint(p)
+	}
+	affine := batchAffine(ps)
+
+	out := make([][]byte, len(points))
+	for i, p := range ps {
+		out[i] = encodePoint(&affine[i], p.encoding)
+	}
+	return out
+}
+
+// NormalizePoints rescales the extended coordinates of every point in
+// place so that Z = 1, without changing the points they represent. It
+// shares a single field inversion across the batch, and normalized points
+// convert to affine coordinates for free, so that encoding them or
+// reading their coordinates later needs no inversion of its own.
+func (g *BJJ) NormalizePoints(points []group.Point) {
+	ps := make([]*Point, len(points))
+	for i, p := range points {
+		ps[i] = toPoint(p)
+	}
+	for i, a := range batchAffine(ps) {
+		// The zero value has no affine form and is left as it is
+		if !ps[i].inner.Z.IsZero() {
+			ps[i].inner.FromAffine(&a)
+		}
+	}
+}
+
+// batchAffine converts points to affine coordinates with Montgomery's
+// trick, which replaces the field inversion of each point by three
+// multiplications and one inversion for the whole batch.
+func batchAffine(points []*Point) []twistededwards.PointAffine {
+	zs := make([]fr.Element, len(points))
+	for i, p := range points {
+		zs[i] = p.inner.Z
+	}
+	invs := fr.BatchInvert(zs)
+
+	out := make([]twistededwards.PointAffine, len(points))
+	for i, p := range points {
+		out[i].X.Mul(&p.inner.X, &invs[i])
+		out[i].Y.Mul(&p.inner.Y, &invs[i])
+	}
+	return out
+}
+
+// xSign returns the sign bit of x stored in compressed encodings of
+// format enc.
+func xSign(x *fr.Element, enc Encoding) bool {
+	switch enc {
+	case EncodingRFC8032:
+		return sgn0(x) == 1
+	case EncodingCircomlib:
+		var xi fr.Element
+		xi.Mul(x, &iden3ScaleInv)
+		return xi.LexicographicallyLargest()
+	default:
+		return x.LexicographicallyLargest()
+	}
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It returns the
+// 32-byte encoding produced by [Scalar.Bytes].
+func (s *Scalar) MarshalBinary() ([]byte, error) {
+	return s.Bytes(), nil
+}
+
+// SetCanonicalBytes sets s from the 32-byte big-endian encoding returned
+// by [Scalar.Bytes] and returns s. Unlike [Scalar.SetBytes] it is strict:
+// data must be exactly 32 bytes and encode a value below the curve order.
+func (s *Scalar) SetCanonicalBytes(data []byte) (group.Scalar, error) {
+	if len(data) != 32 {
+		return nil, errors.New("scalar must be 32 bytes")
+	}
+	b := (*[32]byte)(data)
+	if !isCanonical(b) {
+		return nil, errors.New("scalar is not reduced modulo the curve order")
+	}
+	s.inner.setBytes32(b)
+	return s, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler] like
+// [Scalar.SetCanonicalBytes].
+func (s *Scalar) UnmarshalBinary(data []byte) error {
+	_, err := s.SetCanonicalBytes(data)
+	return err
+}
+
+// MarshalText implements [encoding.TextMarshaler]. It returns the binary
+// encoding in lowercase hex.
+func (s *Scalar) MarshalText() ([]byte, error) {
+	return hex.AppendEncode(nil, s.Bytes()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] for the hex
+// encoding produced by [Scalar.MarshalText].
+func (s *Scalar) UnmarshalText(text []byte) error {
+	data, err := hex.AppendDecode(nil, text)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(data)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It returns the
+// encoding produced by [Point.Bytes].
+func (p *Point) MarshalBinary() ([]byte, error) {
+	return p.Bytes(), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler] like
+// [Point.SetBytes].
+func (p *Point) UnmarshalBinary(data []byte) error {
+	_, err := p.SetBytes(data)
+	return err
+}
+
+// MarshalText implements [encoding.TextMarshaler]. It returns the
+// encoding produced by [Point.Bytes] in lowercase hex.
+func (p *Point) MarshalText() ([]byte, error) {
+	return hex.AppendEncode(nil, p.Bytes()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] for the hex
+// encoding produced by [Point.MarshalText].
+func (p *Point) UnmarshalText(text []byte) error {
+	data, err := hex.AppendDecode(nil, text)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(data)
+}
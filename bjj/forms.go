@@ -0,0 +1,148 @@
+package bjj
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Baby Jubjub is birationally equivalent to the Montgomery curve
+//
+//	v^2 = u^3 + 168698*u^2 + u
+//
+// of EIP-2494 and to the short Weierstrass curve
+//
+//	y^2 = x^3 + a*x + b, a = (3 - A^2)/3, b = (2*A^3 - 9*A)/27,
+//
+// with A = 168698. The conversions go through the EIP-2494 twisted Edwards
+// coordinates (x, y): u = (1+y)/(1-y), v = u/x on the Montgomery curve,
+// and x = u + A/3, y = v on the Weierstrass curve. The identity maps to
+// the point at infinity of both forms, which has no affine coordinates.
+var (
+	montA      fr.Element // A
+	montAThird fr.Element // A/3
+	weierstrA  fr.Element // a
+	weierstrB  fr.Element // b
+)
+
+func init() {
+	montA.SetUint64(168698)
+
+	var three, inv fr.Element
+	three.SetUint64(3)
+	inv.Inverse(&three)
+	montAThird.Mul(&montA, &inv)
+
+	var a2, a3 fr.Element
+	a2.Square(&montA)
+	weierstrA.Sub(&three, &a2).Mul(&weierstrA, &inv)
+
+	var nine fr.Element
+	nine.SetUint64(9)
+	a3.Mul(&a2, &montA).Double(&a3)
+	weierstrB.Mul(&nine, &montA)
+	weierstrB.Sub(&a3, &weierstrB)
+	inv.SetUint64(27).Inverse(&inv)
+	weierstrB.Mul(&weierstrB, &inv)
+}
+
+// Montgomery returns the coordinates (u, v) of p on the Montgomery curve
+// v^2 = u^3 + 168698*u^2 + u of EIP-2494. It returns an error for the
+// identity, which maps to the point at infinity.
+func (p *Point) Montgomery() (u, v *big.Int, err error) {
+	mu, mv, err := p.montgomery()
+	if err != nil {
+		return nil, nil, err
+	}
+	return mu.BigInt(new(big.Int)), mv.BigInt(new(big.Int)), nil
+}
+
+// montgomery implements [Point.Montgomery] on field elements.
+func (p *Point) montgomery() (u, v fr.Element, err error) {
+	if p.IsIdentity() {
+		return u, v, errors.New("the identity has no affine coordinates in this form")
+	}
+	a := p.affine()
+	var one, num, den fr.Element
+	one.SetOne()
+	num.Add(&one, &a.Y)
+	den.Sub(&one, &a.Y)
+	u.Div(&num, &den)
+
+	// v = u / x_eip2494 = u * iden3Scale / x
+	v.Div(&u, &a.X).Mul(&v, &iden3Scale)
+	return u, v, nil
+}
+
+// SetMontgomery sets p from its coordinates on the Montgomery curve of
+// EIP-2494, as returned by [Point.Montgomery]. It returns an error if the
+// coordinates are not canonical field elements or do not describe a point
+// of the prime-order subgroup.
+func (p *Point) SetMontgomery(u, v *big.Int) error {
+	var mu, mv fr.Element
+	if setCanonical(&mu, u) != nil || setCanonical(&mv, v) != nil {
+		return errors.New("non-canonical coordinate")
+	}
+	return p.setMontgomery(&mu, &mv)
+}
+
+// setMontgomery implements [Point.SetMontgomery] on field elements.
+func (p *Point) setMontgomery(u, v *fr.Element) error {
+	// v^2 = u^3 + A*u^2 + u
+	var lhs, rhs, t fr.Element
+	lhs.Square(v)
+	t.Add(u, &montA)
+	rhs.Mul(u, u).Mul(&rhs, &t).Add(&rhs, u)
+	if !lhs.Equal(&rhs) {
+		return errors.New("point is not on curve")
+	}
+	// v = 0 only at the points of order 2, and u = -1 is never on the
+	// curve; both are outside the subgroup and excluded by the map
+	var one, den fr.Element
+	one.SetOne()
+	den.Add(u, &one)
+	if v.IsZero() || den.IsZero() {
+		return errors.New("point is not in the prime-order subgroup")
+	}
+
+	// x = u/v, y = (u-1)/(u+1)
+	var x, y fr.Element
+	x.Div(u, v)
+	y.Sub(u, &one).Div(&y, &den)
+	return p.setCoordinates(&x, &y)
+}
+
+// Weierstrass returns the coordinates (x, y) of p on the short Weierstrass
+// curve y^2 = x^3 + a*x + b equivalent to Baby Jubjub. It returns an
+// error for the identity, which maps to the point at infinity.
+func (p *Point) Weierstrass() (x, y *big.Int, err error) {
+	u, v, err := p.montgomery()
+	if err != nil {
+		return nil, nil, err
+	}
+	u.Add(&u, &montAThird)
+	return u.BigInt(new(big.Int)), v.BigInt(new(big.Int)), nil
+}
+
+// SetWeierstrass sets p from its short Weierstrass coordinates, as
+// returned by [Point.Weierstrass]. It returns an error if the coordinates
+// are not canonical field elements or do not describe a point of the
+// prime-order subgroup.
+func (p *Point) SetWeierstrass(x, y *big.Int) error {
+	var u, v fr.Element
+	if setCanonical(&u, x) != nil || setCanonical(&v, y) != nil {
+		return errors.New("non-canonical coordinate")
+	}
+	u.Sub(&u, &montAThird)
+	return p.setMontgomery(&u, &v)
+}
+
+// setCanonical sets e to v, which must be in [0, p).
+func setCanonical(e *fr.Element, v *big.Int) error {
+	if v.Sign() < 0 || v.Cmp(fr.Modulus()) >= 0 {
+		return errors.New("value is not a field element")
+	}
+	e.SetBigInt(v)
+	return nil
+}
@@ -0,0 +1,166 @@
+package bjj
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/group"
+)
+
+// elligator holds the constants of the Elligator 2 map for the Montgomery
+// curve K*t^2 = s^3 + J*s^2 + s that is birationally equivalent to Baby
+// Jubjub, as described in RFC 9380 section 6.7.1.
+var elligator struct {
+	once  sync.Once
+	z     fr.Element // non-square used by the map
+	k     fr.Element // K
+	jOnK  fr.Element // J / K
+	invK2 fr.Element // 1 / K^2
+}
+
+// initElligator derives the Montgomery constants from the twisted Edwards
+// parameters: J = 2(a+d)/(a-d) and K = 4/(a-d).
+func initElligator() {
+	curve := twistededwards.GetEdwardsCurve()
+
+	var aMinusD, aPlusD, inv, j fr.Element
+	aMinusD.Sub(&curve.A, &curve.D)
+	aPlusD.Add(&curve.A, &curve.D)
+	inv.Inverse(&aMinusD)
+
+	j.Mul(&aPlusD, &inv).Double(&j)
+	elligator.k.Double(&inv).Double(&elligator.k)
+
+	var invK fr.Element
+	invK.Inverse(&elligator.k)
+	elligator.jOnK.Mul(&j, &invK)
+	elligator.invK2.Square(&invK)
+
+	// Z is the first non-square in the sequence 1, -1, 2, -2, ...
+	// (RFC 9380 appendix H.3)
+	for ctr := uint64(1); ; ctr++ {
+		elligator.z.SetUint64(ctr)
+		if elligator.z.Legendre() == -1 {
+			break
+		}
+		elligator.z.Neg(&elligator.z)
+		if elligator.z.Legendre() == -1 {
+			break
+		}
+	}
+}
+
+// HashToPoint hashes data to a point in the prime-order subgroup,
+// following the random-oracle construction hash_to_curve of RFC 9380:
+// expand_message_xmd with SHA-256, hash_to_field producing two field
+// elements, the Elligator 2 map through the birationally equivalent
+// Montgomery curve, and multiplication by the cofactor 8.
+//
+// dst is the domain separation tag and must be non-empty; distinct
+// applications must use distinct tags. The data slices are concatenated,
+// so callers combining several variable-length inputs must encode them
+// unambiguously.
+//
+// Nobody knows the discrete logarithm of the result with respect to the
+// generator, which makes HashToPoint suitable for deriving independent
+// generators.
+func (g *BJJ) HashToPoint(dst string, data ...[]byte) (group.Point, error) {
+	if dst == "" {
+		return nil, errors.New("domain separation tag must not be empty")
+	}
+
+	var msg []byte
+	for _, d := range data {
+		msg = append(msg, d...)
+	}
+	u, err := fr.Hash(msg, []byte(dst), 2)
+	if err != nil {
+		return nil, err
+	}
+
+	q0 := mapToCurve(&u[0])
+	q1 := mapToCurve(&u[1])
+	var e0, e1 twistededwards.PointExtended
+	e0.FromAffine(&q0)
+	e1.FromAffine(&q1)
+
+	p := g.newPoint()
+	p.inner.Add(&e0, &e1)
+	p.ClearCofactor(p)
+	return p, nil
+}
+
+// mapToCurve maps a field element to a curve point with Elligator 2
+// followed by the rational map to twisted Edwards form.
+func mapToCurve(u *fr.Element) twistededwards.PointAffine {
+	elligator.once.Do(initElligator)
+
+	// x1 = -(J/K) / (1 + Z*u^2), or -(J/K) if the denominator is zero
+	var tv, x1 fr.Element
+	tv.Square(u).Mul(&tv, &elligator.z)
+	tv.Add(&tv, new(fr.Element).SetOne())
+	x1.Inverse(&tv).Mul(&x1, &elligator.jOnK).Neg(&x1)
+	if x1.IsZero() {
+		x1.Neg(&elligator.jOnK)
+	}
+
+	// x2 = -x1 - J/K
+	var x2 fr.Element
+	x2.Add(&x1, &elligator.jOnK).Neg(&x2)
+
+	var x, y fr.Element
+	gx1 := montgomeryRHS(&x1)
+	if gx1.Legendre() != -1 {
+		x.Set(&x1)
+		y.Sqrt(&gx1)
+		if sgn0(&y) != 1 {
+			y.Neg(&y)
+		}
+	} else {
+		gx2 := montgomeryRHS(&x2)
+		x.Set(&x2)
+		y.Sqrt(&gx2)
+		if sgn0(&y) != 0 {
+			y.Neg(&y)
+		}
+	}
+
+	// (s, t) on the Montgomery curve
+	var s, t fr.Element
+	s.Mul(&x, &elligator.k)
+	t.Mul(&y, &elligator.k)
+
+	// Rational map (RFC 9380 section 6.8.2): v = s/t, w = (s-1)/(s+1),
+	// with the exceptional cases sent to the identity
+	var p twistededwards.PointAffine
+	var one, sPlusOne fr.Element
+	one.SetOne()
+	sPlusOne.Add(&s, &one)
+	if t.IsZero() || sPlusOne.IsZero() {
+		p.Y.SetOne()
+		return p
+	}
+	var inv fr.Element
+	p.X.Mul(&s, inv.Inverse(&t))
+	p.Y.Sub(&s, &one).Mul(&p.Y, inv.Inverse(&sPlusOne))
+	return p
+}
+
+// montgomeryRHS returns x^3 + (J/K)*x^2 + x/K^2.
+func montgomeryRHS(x *fr.Element) fr.Element {
+	var res, x2, tmp fr.Element
+	x2.Square(x)
+	res.Mul(&x2, x)
+	tmp.Mul(&x2, &elligator.jOnK)
+	res.Add(&res, &tmp)
+	tmp.Mul(x, &elligator.invK2)
+	return *res.Add(&res, &tmp)
+}
+
+// sgn0 returns the parity of the canonical representation of x.
+func sgn0(x *fr.Element) uint64 {
+	b := x.Bytes()
+	return uint64(b[len(b)-1] & 1)
+}
@@ -0,0 +1,271 @@
+package bjj
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/f3rmion/fy/group"
+)
+
+// Iden3Hasher is a FROST hasher, implementing frost.Hasher, whose
+// signatures verify with the Baby Jubjub EdDSA verifiers of
+// go-iden3-crypto and circomlib that hash field elements, such as their
+// Poseidon and MiMC7 variants. Encode signatures for them with
+// [Iden3Signature]. The older circomlib verifier built on the Pedersen
+// hash of packed points is not supported.
+//
+// Those verifiers check S*B8 == R8 + 8*hm*A, where hm hashes the
+// coordinates of R8 and the public key A with the message. FROST checks
+// z*G == R + c*Y with the same base point, so H2 returns c = 8*hm.
+//
+// iden3 signs a single field element rather than a byte string. Messages
+// must therefore be 32-byte big-endian encodings of an element of the
+// BN254 scalar field, such as those returned by [Iden3Message], and the
+// group must be [BJJ]. Iden3Hasher implements frost.MessageValidator, so
+// FROST rejects other messages with an error when signing, verifying or
+// aggregating.
+type Iden3Hasher struct {
+	// Hash is the field hash the verifier uses for hm. It is called with
+	// the EIP-2494 coordinates R8.x, R8.y, A.x and A.y followed by the
+	// message. poseidon.Hash from go-iden3-crypto can be used as is for
+	// the Poseidon verifiers.
+	//
+	// If Hash returns an error, so does Challenge, and H2 returns nil:
+	// any fixed challenge would make signatures forgeable.
+	Hash func(inputs []*big.Int) (*big.Int, error)
+
+	// Prefix is the domain separation prefix of the hashes other than
+	// H2, whose form the verifiers fix. Default: "FROST-IDEN3-BJJ-v1"
+	Prefix string
+}
+
+// prefix returns the domain separation prefix of h.
+func (h *Iden3Hasher) prefix() string {
+	return cmp.Or(h.Prefix, "FROST-IDEN3-BJJ-v1")
+}
+
+// Iden3Message returns the message encoding expected by [Iden3Hasher] for
+// the field element m. It returns an error if m is negative or not below
+// the BN254 scalar field modulus.
+func Iden3Message(m *big.Int) ([]byte, error) {
+	if m.Sign() < 0 || m.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("message is not a field element")
+	}
+	return m.FillBytes(make([]byte, fr.Bytes)), nil
+}
+
+// Validate implements frost.HasherValidator. It rejects a hasher without
+// Hash.
+func (h *Iden3Hasher) Validate() error {
+	if h.Hash == nil {
+		return errors.New("bjj: Iden3Hasher: Hash is not set")
+	}
+	return nil
+}
+
+// ValidateMessage implements frost.MessageValidator. It returns an error
+// unless g is [BJJ] and msg is a 32-byte big-endian element of the BN254
+// scalar field.
+func (h *Iden3Hasher) ValidateMessage(g group.Group, msg []byte) error {
+	_, err := iden3Inputs(g, msg)
+	return err
+}
+
+// iden3Inputs checks g and msg as ValidateMessage does and returns the
+// message as a field element.
+func iden3Inputs(g group.Group, msg []byte) (*big.Int, error) {
+	if _, ok := g.(*BJJ); !ok {
+		return nil, fmt.Errorf("bjj: Iden3Hasher: cannot hash in group %s", g.Name())
+	}
+	if len(msg) != fr.Bytes {
+		return nil, errors.New("bjj: Iden3Hasher: message must be a 32-byte field element")
+	}
+	m := new(big.Int).SetBytes(msg)
+	if m.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("bjj: Iden3Hasher: message is not a field element")
+	}
+	return m, nil
+}
+
+// decodeIden3 decodes a point encoded by g for [Iden3Hasher].
+func decodeIden3(g group.Group, enc []byte) (*Point, error) {
+	p, err := g.NewPoint().SetBytes(enc)
+	if err != nil {
+		return nil, fmt.Errorf("bjj: Iden3Hasher: %w", err)
+	}
+	return toPoint(p), nil
+}
+
+// iden3Coordinates returns the EIP-2494 coordinates of p.
+func iden3Coordinates(p *Point) (x, y *big.Int) {
+	a := p.affine()
+	var xi fr.Element
+	xi.Mul(&a.X, &iden3ScaleInv)
+	return xi.BigInt(new(big.Int)), a.Y.BigInt(new(big.Int))
+}
+
+// tagged hashes data to a scalar of g with a domain separation tag.
+func (h *Iden3Hasher) tagged(g group.Group, tag string, data ...[]byte) group.Scalar {
+	s, _ := g.HashToScalar(append([][]byte{[]byte(h.prefix() + tag)}, data...)...)
+	return s
+}
+
+// H1 implements frost.Hasher.H1.
+func (h *Iden3Hasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.tagged(g, "rho", msg, encCommitList, signerID)
+}
+
+// H2 implements frost.Hasher.H2, returning 8 times the iden3 challenge.
+// It returns nil where [Iden3Hasher.Challenge] returns an error; FROST
+// calls Challenge instead.
+func (h *Iden3Hasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	c, err := h.Challenge(g, R, Y, msg)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
+// Challenge implements frost.MessageValidator. It is H2, returning an
+// error if the message or points are invalid or Hash fails.
+func (h *Iden3Hasher) Challenge(g group.Group, R, Y, msg []byte) (group.Scalar, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	m, err := iden3Inputs(g, msg)
+	if err != nil {
+		return nil, err
+	}
+	r, err := decodeIden3(g, R)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeIden3(g, Y)
+	if err != nil {
+		return nil, err
+	}
+	rx, ry := iden3Coordinates(r)
+	ax, ay := iden3Coordinates(y)
+	hm, err := h.Hash([]*big.Int{rx, ry, ax, ay, m})
+	if err != nil {
+		return nil, fmt.Errorf("bjj: Iden3Hasher: %w", err)
+	}
+	if hm == nil {
+		return nil, errors.New("bjj: Iden3Hasher: Hash returned nil")
+	}
+	return newScalar().SetBigInt(new(big.Int).Lsh(hm, 3)), nil
+}
+
+// H3 implements frost.Hasher.H3.
+func (h *Iden3Hasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.tagged(g, "nonce", seed, rho, msg)
+}
+
+// H4 implements frost.Hasher.H4.
+func (h *Iden3Hasher) H4(g group.Group, msg []byte) []byte {
+	sum := sha256.Sum256(append([]byte(h.prefix()+"msg"), msg...))
+	return sum[:]
+}
+
+// H5 implements frost.Hasher.H5.
+func (h *Iden3Hasher) H5(g group.Group, encCommitList []byte) []byte {
+	sum := sha256.Sum256(append([]byte(h.prefix()+"com"), encCommitList...))
+	return sum[:]
+}
+
+// Iden3Signature returns the 64-byte compressed form of the signature
+// (R, z) used by go-iden3-crypto and circomlib: R8 in [EncodingCircomlib]
+// followed by S in little-endian order.
+func Iden3Signature(R group.Point, z group.Scalar) [64]byte {
+	var a [64]byte
+	r := toPoint(R).affine()
+	copy(a[:32], encodePoint(&r, EncodingCircomlib))
+	s := toScalar(z).inner.bytes()
+	for i := range s {
+		a[32+i] = s[31-i]
+	}
+	return a
+}
+
+// VerifyIden3 checks a compressed signature as the iden3 EdDSA verifiers
+// do, S*B8 == R8 + 8*hm*A with hm = hash(R8.x, R8.y, A.x, A.y, m). It
+// rejects a signature whose S is not below the curve order.
+func VerifyIden3(hash func([]*big.Int) (*big.Int, error), publicKey group.Point, m *big.Int, sig [64]byte) bool {
+	g := &BJJ{Encoding: EncodingCircomlib}
+	R, err := g.NewPoint().SetBytes(sig[:32])
+	if err != nil {
+		return false
+	}
+	var le [32]byte
+	for i := range le {
+		le[i] = sig[63-i]
+	}
+	S, err := g.NewScalar().SetCanonicalBytes(le[:])
+	if err != nil {
+		return false
+	}
+	if m.Sign() < 0 || m.Cmp(fr.Modulus()) >= 0 {
+		return false
+	}
+	A := toPoint(publicKey)
+
+	rx, ry := iden3Coordinates(toPoint(R))
+	ax, ay := iden3Coordinates(A)
+	hm, err := hash([]*big.Int{rx, ry, ax, ay, m})
+	if err != nil {
+		return false
+	}
+	k := newScalar().SetBigInt(new(big.Int).Lsh(hm, 3))
+
+	rhs := g.NewPoint().Add(R, g.NewPoint().ScalarMult(k, A))
+	return g.ScalarBaseMult(S).Equal(rhs)
+}
+
+// CircomEdDSAInputs holds the input signals of circomlib's EdDSA verifier
+// templates, such as EdDSAPoseidonVerifier, for a signature made with
+// [Iden3Hasher]. Coordinates are in the EIP-2494 form those templates use.
+// It marshals to JSON as the input file of snarkjs, with every signal a
+// decimal string.
+type CircomEdDSAInputs struct {
+	Enabled *big.Int // 1, so that the template enforces the check
+	Ax, Ay  *big.Int // the group public key
+	R8x     *big.Int // the signature's R
+	R8y     *big.Int
+	S       *big.Int // the signature's z
+	M       *big.Int // the message field element
+}
+
+// NewCircomEdDSAInputs returns the verifier inputs for the signature
+// (R, z) on the field element m under groupKey. It returns an error if m
+// is not a field element.
+func NewCircomEdDSAInputs(R group.Point, z group.Scalar, groupKey group.Point, m *big.Int) (*CircomEdDSAInputs, error) {
+	if _, err := Iden3Message(m); err != nil {
+		return nil, err
+	}
+	in := &CircomEdDSAInputs{
+		Enabled: big.NewInt(1),
+		S:       toScalar(z).BigInt(),
+		M:       new(big.Int).Set(m),
+	}
+	in.Ax, in.Ay = iden3Coordinates(toPoint(groupKey))
+	in.R8x, in.R8y = iden3Coordinates(toPoint(R))
+	return in, nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (in *CircomEdDSAInputs) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"enabled": in.Enabled.String(),
+		"Ax":      in.Ax.String(),
+		"Ay":      in.Ay.String(),
+		"R8x":     in.R8x.String(),
+		"R8y":     in.R8y.String(),
+		"S":       in.S.String(),
+		"M":       in.M.String(),
+	})
+}
@@ -0,0 +1,99 @@
+package bjj
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/f3rmion/fy/group"
+)
+
+// MultiScalarMult returns the sum of scalars[i]*points[i] using
+// Pippenger's bucket method, which needs far fewer point additions than
+// computing each product separately once more than a handful of terms are
+// involved. Up to maxStraus terms, including the single product of a
+// signature verification, it uses width-5 NAFs with shared doublings
+// instead, which measured faster, see strausMult. It panics if the slices
+// differ in length.
+//
+// MultiScalarMult runs in variable time and must only be used with public
+// scalars.
+func (g *BJJ) MultiScalarMult(scalars []group.Scalar, points []group.Point) group.Point {
+	if len(scalars) != len(points) {
+		panic("bjj: MultiScalarMult called with mismatched lengths")
+	}
+	n := len(scalars)
+	if n == 0 {
+		return g.NewPoint()
+	}
+
+	ks := make([][32]byte, n)
+	ps := make([]twistededwards.PointExtended, n)
+	for i := range n {
+		ks[i] = toScalar(scalars[i]).inner.bytes()
+		ps[i] = toPoint(points[i]).inner
+	}
+	if n <= maxStraus {
+		result := g.newPoint()
+		result.inner = strausMult(ks, ps)
+		return result
+	}
+
+	// Window size grows with log(n); small inputs use small windows
+	c := max(2, min(16, bits.Len(uint(n))-2))
+	windows := (curveOrder.BitLen() + c - 1) / c
+
+	identity := identityExtended()
+	buckets := make([]twistededwards.PointExtended, 1<<c-1)
+	acc := identity
+	for w := windows - 1; w >= 0; w-- {
+		for range c {
+			acc.Double(&acc)
+		}
+
+		for i := range buckets {
+			buckets[i] = identity
+		}
+		for i, k := range ks {
+			if d := window(&k, w*c, c); d != 0 {
+				// Add rather than MixedAdd: the latter mishandles
+				// doubling when the bucket is not normalized
+				buckets[d-1].Add(&buckets[d-1], &ps[i])
+			}
+		}
+
+		// sum_d d*bucket[d] via running sums from the top bucket down
+		running, sum := identity, identity
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running.Add(&running, &buckets[i])
+			sum.Add(&sum, &running)
+		}
+		acc.Add(&acc, &sum)
+	}
+
+	result := g.newPoint()
+	result.inner = acc
+	return result
+}
+
+// window returns the c bits of the big-endian value k starting at bit
+// offset. Bits beyond the end of k are zero.
+func window(k *[32]byte, offset, c int) uint {
+	var d uint
+	for i := offset + c - 1; i >= offset; i-- {
+		bit := uint(0)
+		if i < 256 {
+			bit = uint(k[31-i/8]>>(i%8)) & 1
+		}
+		d = d<<1 | bit
+	}
+	return d
+}
+
+// identityExtended returns the identity element in extended coordinates.
+func identityExtended() twistededwards.PointExtended {
+	var zero twistededwards.PointAffine
+	zero.Y.SetOne()
+	var p twistededwards.PointExtended
+	p.FromAffine(&zero)
+	return p
+}
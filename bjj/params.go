@@ -0,0 +1,112 @@
+package bjj
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// CurveParams holds the parameters of Baby Jubjub as specified by
+// EIP-2494: the twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2 over
+// the BN254 scalar field. [Point.X], [Point.Y] and [Point.SetCoordinates]
+// use the same coordinates.
+type CurveParams struct {
+	P        *big.Int // modulus of the base field
+	A, D     *big.Int // curve coefficients, 168700 and 168696
+	Order    *big.Int // order of the prime-order subgroup
+	Cofactor *big.Int // 8
+	Gx, Gy   *big.Int // the generator B8
+}
+
+// Params returns the curve parameters. The result is a fresh copy that the
+// caller may modify.
+func (g *BJJ) Params() *CurveParams {
+	gx, gy := iden3Coordinates(&Point{inner: baseExtended})
+	return &CurveParams{
+		P:        fr.Modulus(),
+		A:        big.NewInt(168700),
+		D:        big.NewInt(168696),
+		Order:    new(big.Int).Set(curveOrder),
+		Cofactor: big.NewInt(8),
+		Gx:       gx,
+		Gy:       gy,
+	}
+}
+
+// X returns the x-coordinate of p in the EIP-2494 coordinates of
+// [CurveParams]. Like [Point.Y], it costs a field inversion unless p has
+// been normalized with [BJJ.NormalizePoints].
+func (p *Point) X() *big.Int {
+	x, _ := iden3Coordinates(p)
+	return x
+}
+
+// Y returns the y-coordinate of p, which is the same in the EIP-2494
+// coordinates and in those of gnark-crypto.
+func (p *Point) Y() *big.Int {
+	a := p.affine()
+	return a.Y.BigInt(new(big.Int))
+}
+
+// SetCoordinates sets p to the point (x, y) in EIP-2494 coordinates. It
+// returns an error if a coordinate is not a canonical field element or
+// (x, y) is not a point of the prime-order subgroup.
+func (p *Point) SetCoordinates(x, y *big.Int) error {
+	var fx, fy fr.Element
+	if setCanonical(&fx, x) != nil || setCanonical(&fy, y) != nil {
+		return errors.New("non-canonical coordinate")
+	}
+	return p.setCoordinates(&fx, &fy)
+}
+
+// setCoordinates implements [Point.SetCoordinates] on field elements.
+func (p *Point) setCoordinates(x, y *fr.Element) error {
+	a := twistededwards.PointAffine{Y: *y}
+	a.X.Mul(x, &iden3Scale)
+	if !a.IsOnCurve() {
+		return errors.New("point is not on curve")
+	}
+	var e twistededwards.PointExtended
+	e.FromAffine(&a)
+	if !inSubgroup(&e) {
+		return errors.New("point is not in the prime-order subgroup")
+	}
+	p.inner = e
+	return nil
+}
+
+// LittleEndianBytes returns the 64-byte encoding x || y of p, with the
+// EIP-2494 coordinates each in 32 bytes of little-endian order, the byte
+// order circomlibjs uses for field elements. [Point.UncompressedBytes]
+// holds gnark-crypto's coordinates in big-endian order instead.
+func (p *Point) LittleEndianBytes() []byte {
+	x, y := iden3Coordinates(p)
+	out := make([]byte, 64)
+	x.FillBytes(out[:32])
+	y.FillBytes(out[32:])
+	slices.Reverse(out[:32])
+	slices.Reverse(out[32:])
+	return out
+}
+
+// SetLittleEndianBytes sets p from the encoding returned by
+// [Point.LittleEndianBytes]. It returns an error if the data is not 64
+// bytes or not the canonical encoding of a point in the prime-order
+// subgroup.
+func (p *Point) SetLittleEndianBytes(data []byte) error {
+	if len(data) != 64 {
+		return errors.New("little-endian point must be 64 bytes")
+	}
+	var be [64]byte
+	copy(be[:], data)
+	slices.Reverse(be[:32])
+	slices.Reverse(be[32:])
+	var x, y fr.Element
+	if x.SetBytesCanonical(be[:32]) != nil || y.SetBytesCanonical(be[32:]) != nil {
+		return errors.New("non-canonical encoding")
+	}
+	return p.setCoordinates(&x, &y)
+}
@@ -0,0 +1,80 @@
+package bjj
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+)
+
+// ScalarHash selects the hash function used by [BJJ.HashToScalar]. Set it
+// with the ScalarHash field of [BJJ].
+type ScalarHash int
+
+const (
+	// ScalarHashSHA512 reduces a SHA-512 digest of the input. It is the
+	// default.
+	ScalarHashSHA512 ScalarHash = iota
+
+	// ScalarHashPoseidon2 hashes with Poseidon2 over the BN254 scalar
+	// field, which is the field Baby Jubjub is defined over. Hashing
+	// field elements instead of bits makes the hash cheap to recompute in
+	// a SNARK circuit, so that challenges derived from it can be verified
+	// in-circuit.
+	//
+	// The input is absorbed by the Merkle-Damgård construction of
+	// gnark-crypto's poseidon2 package with its default parameters, which
+	// gnark's std/hash/poseidon2 gadget reproduces. It is not the original
+	// Poseidon used by circomlib. Each byte slice is absorbed as its
+	// length followed by its bytes in 31-byte big-endian chunks, the last
+	// one zero-padded on the right, each chunk making one field element.
+	// The digest is reduced modulo the subgroup order l. The field modulus
+	// r lies between 7l and 8l, 2^125.64 below 8l, so all but 2^125.64
+	// scalars have 8 preimages and those have 7. The statistical distance
+	// from uniform is (r-7l)(8l-r)/(rl), below 2^-127.9.
+	ScalarHashPoseidon2
+)
+
+// String returns the name of the hash function.
+func (h ScalarHash) String() string {
+	switch h {
+	case ScalarHashSHA512:
+		return "sha512"
+	case ScalarHashPoseidon2:
+		return "poseidon2"
+	default:
+		return "unknown"
+	}
+}
+
+// poseidon2Chunk is the number of input bytes packed into each field
+// element; 31 bytes always encode a value below the field modulus.
+const poseidon2Chunk = 31
+
+// poseidon2Elements returns the field element encodings absorbed by
+// [ScalarHashPoseidon2] for data, each 32 bytes in big-endian order.
+func poseidon2Elements(data [][]byte) [][32]byte {
+	var elems [][32]byte
+	for _, d := range data {
+		var length [32]byte
+		binary.BigEndian.PutUint64(length[24:], uint64(len(d)))
+		elems = append(elems, length)
+		for len(d) > 0 {
+			var e [32]byte
+			n := copy(e[1:], d)
+			d = d[n:]
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+// hashToScalarPoseidon2 implements [ScalarHashPoseidon2].
+func hashToScalarPoseidon2(data [][]byte) *Scalar {
+	h := poseidon2.NewMerkleDamgardHasher()
+	for _, e := range poseidon2Elements(data) {
+		h.Write(e[:])
+	}
+	s := newScalar()
+	s.inner.setBytes(h.Sum(nil))
+	return s
+}
@@ -0,0 +1,268 @@
+package bjj
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// fe is an element of the scalar field, the integers modulo curveOrder,
+// in Montgomery form: the value x is stored as x*2^256 mod curveOrder in
+// four 64-bit limbs, least significant first.
+//
+// All operations run in time independent of the values involved, unlike
+// big.Int arithmetic, so that secret key shares and nonces do not leak
+// through timing.
+type fe [4]uint64
+
+// Scalar field constants, derived from curveOrder by initScalarField.
+var (
+	feModulus fe     // curveOrder, not in Montgomery form
+	feNegInv  uint64 // -curveOrder^-1 mod 2^64
+	feOne     fe     // 1 in Montgomery form: 2^256 mod curveOrder
+	feR2      fe     // 2^512 mod curveOrder, converts into Montgomery form
+	feExpInv  fe     // curveOrder - 2, the exponent used for inversion
+)
+
+// initScalarField computes the scalar field constants. It must run after
+// curveOrder is set.
+func initScalarField() {
+	feModulus = feFromBig(curveOrder)
+
+	// Newton iteration for curveOrder^-1 mod 2^64. Each step doubles the
+	// number of correct low bits, starting from q itself, which is its own
+	// inverse modulo 8.
+	inv := feModulus[0]
+	for range 5 {
+		inv *= 2 - feModulus[0]*inv
+	}
+	feNegInv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	feOne = feFromBig(new(big.Int).Mod(r, curveOrder))
+	r2 := new(big.Int).Mul(r, r)
+	feR2 = feFromBig(r2.Mod(r2, curveOrder))
+	feExpInv = feFromBig(new(big.Int).Sub(curveOrder, big.NewInt(2)))
+}
+
+// feFromBig returns the limbs of v, which must be in [0, 2^256), without
+// converting to Montgomery form. It is only used on public constants.
+func feFromBig(v *big.Int) fe {
+	var b [32]byte
+	v.FillBytes(b[:])
+	return feFromBytes(&b)
+}
+
+// feFromBytes returns the limbs of the big-endian value b, without
+// converting to Montgomery form.
+func feFromBytes(b *[32]byte) fe {
+	var z fe
+	for i := range z {
+		for j := range 8 {
+			z[i] |= uint64(b[31-8*i-j]) << (8 * j)
+		}
+	}
+	return z
+}
+
+// isCanonical reports whether the big-endian value b is below curveOrder,
+// in constant time.
+func isCanonical(b *[32]byte) bool {
+	x := feFromBytes(b)
+	var borrow uint64
+	for i := range x {
+		_, borrow = bits.Sub64(x[i], feModulus[i], borrow)
+	}
+	return borrow == 1
+}
+
+// setBytes32 sets z to the big-endian value b reduced modulo curveOrder.
+// Any 256-bit value is accepted.
+func (z *fe) setBytes32(b *[32]byte) *fe {
+	x := feFromBytes(b)
+	// x * R^2 / R = x*R, which is below 2*curveOrder because x < R
+	return z.mul(&x, &feR2)
+}
+
+// setBytes sets z to the big-endian value b of any length reduced modulo
+// curveOrder, processing it in 32-byte chunks with Horner's rule.
+func (z *fe) setBytes(b []byte) *fe {
+	var acc fe
+	first := len(b) % 32
+	if first == 0 && len(b) > 0 {
+		first = 32
+	}
+	for len(b) > 0 {
+		var chunk [32]byte
+		copy(chunk[32-first:], b[:first])
+		b = b[first:]
+		first = 32
+
+		// acc = acc*2^256 + chunk
+		var c fe
+		c.setBytes32(&chunk)
+		acc.mul(&acc, &feR2)
+		acc.add(&acc, &c)
+	}
+	*z = acc
+	return z
+}
+
+// bytes returns the canonical big-endian encoding of z.
+func (z *fe) bytes() [32]byte {
+	var x fe
+	x.fromMont(z)
+	var b [32]byte
+	for i := range x {
+		for j := range 8 {
+			b[31-8*i-j] = byte(x[i] >> (8 * j))
+		}
+	}
+	return b
+}
+
+// setUint64 sets z to v.
+func (z *fe) setUint64(v uint64) *fe {
+	x := fe{v}
+	return z.mul(&x, &feR2)
+}
+
+// fromMont sets z to x converted out of Montgomery form.
+func (z *fe) fromMont(x *fe) *fe {
+	one := fe{1}
+	return z.mul(x, &one)
+}
+
+// add sets z to x + y.
+func (z *fe) add(x, y *fe) *fe {
+	var t fe
+	var c uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], _ = bits.Add64(x[3], y[3], c)
+	// curveOrder < 2^252, so the sum fits in four limbs
+	z.reduce(&t, 0)
+	return z
+}
+
+// sub sets z to x - y.
+func (z *fe) sub(x, y *fe) *fe {
+	var t fe
+	var b uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+
+	// Add the modulus back if the subtraction borrowed
+	mask := -b
+	var c uint64
+	t[0], c = bits.Add64(t[0], feModulus[0]&mask, 0)
+	t[1], c = bits.Add64(t[1], feModulus[1]&mask, c)
+	t[2], c = bits.Add64(t[2], feModulus[2]&mask, c)
+	t[3], _ = bits.Add64(t[3], feModulus[3]&mask, c)
+	*z = t
+	return z
+}
+
+// neg sets z to -x.
+func (z *fe) neg(x *fe) *fe {
+	var zero fe
+	return z.sub(&zero, x)
+}
+
+// mul sets z to x * y, using Montgomery multiplication with coarsely
+// integrated operand scanning. The result is fully reduced as long as
+// x*y < curveOrder*2^256.
+func (z *fe) mul(x, y *fe) *fe {
+	var t [6]uint64
+	for i := range 4 {
+		// t += x * y[i]
+		var c uint64
+		for j := range 4 {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		// t = (t + m*q) / 2^64 with m chosen to clear the low limb
+		m := t[0] * feNegInv
+		hi, lo := bits.Mul64(m, feModulus[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, feModulus[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+	z.reduce((*fe)(t[:4]), t[4])
+	return z
+}
+
+// reduce sets z to t - curveOrder if the five-limb value (hi, t) is at
+// least curveOrder, and to t otherwise. The value must be below
+// 2*curveOrder.
+func (z *fe) reduce(t *fe, hi uint64) {
+	var s fe
+	var b uint64
+	s[0], b = bits.Sub64(t[0], feModulus[0], 0)
+	s[1], b = bits.Sub64(t[1], feModulus[1], b)
+	s[2], b = bits.Sub64(t[2], feModulus[2], b)
+	s[3], b = bits.Sub64(t[3], feModulus[3], b)
+	_, b = bits.Sub64(hi, 0, b)
+
+	// Keep t if the subtraction borrowed
+	z.sel(int(b), t, &s)
+}
+
+// inverse sets z to x^-1 using Fermat's little theorem, x^(q-2). The
+// exponent is public, so branching on its bits leaks nothing about x. The
+// inverse of zero is zero.
+func (z *fe) inverse(x *fe) *fe {
+	base := *x
+	acc := feOne
+	for i := 255; i >= 0; i-- {
+		acc.mul(&acc, &acc)
+		if feExpInv[i/64]>>(i%64)&1 == 1 {
+			acc.mul(&acc, &base)
+		}
+	}
+	*z = acc
+	return z
+}
+
+// sel sets z to a if c is 1 and to b if c is 0.
+func (z *fe) sel(c int, a, b *fe) *fe {
+	mask := -uint64(c & 1)
+	for i := range z {
+		z[i] = b[i] ^ (mask & (a[i] ^ b[i]))
+	}
+	return z
+}
+
+// equal reports whether z and x are equal.
+func (z *fe) equal(x *fe) bool {
+	var d uint64
+	for i := range z {
+		d |= z[i] ^ x[i]
+	}
+	return d == 0
+}
+
+// isZero reports whether z is zero.
+func (z *fe) isZero() bool {
+	return (z[0] | z[1] | z[2] | z[3]) == 0
+}
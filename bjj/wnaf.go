@@ -0,0 +1,150 @@
+package bjj
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// wnafWidth is the window width of the non-adjacent forms used by
+// strausMult. Each point needs a table of 2^(w-2) odd multiples, and a
+// 251-bit scalar then has about 251/(w+1) nonzero digits.
+const wnafWidth = 5
+
+// maxStraus is the largest number of terms for which MultiScalarMult uses
+// strausMult; Pippenger's bucket method is faster from there on.
+const maxStraus = 256
+
+// wnaf returns the width-w non-adjacent form of the 256-bit big-endian
+// value k, least significant digit first: digits that are zero or odd
+// with absolute value below 2^(w-1), at most one nonzero digit in any w
+// consecutive ones, and k = sum(naf[i] * 2^i). It also returns the number
+// of digits up to the last nonzero one.
+//
+// wnaf runs in variable time and must only be used with public values.
+func wnaf(k *[32]byte, w uint) (naf [257]int8, n int) {
+	// Little-endian limbs, with one more for the carry of a negative digit
+	var limbs [5]uint64
+	for i := range 4 {
+		limbs[i] = binary.BigEndian.Uint64(k[24-8*i:])
+	}
+	mask := uint64(1)<<w - 1
+	for i := 0; limbs != [5]uint64{}; i++ {
+		if limbs[0]&1 == 1 {
+			d := int64(limbs[0] & mask)
+			if d >= 1<<(w-1) {
+				d -= 1 << w
+			}
+			naf[i] = int8(d)
+			// k -= d
+			if d > 0 {
+				subWord(&limbs, uint64(d))
+			} else {
+				addWord(&limbs, uint64(-d))
+			}
+			n = i + 1
+		}
+		// k >>= 1
+		for j := range 4 {
+			limbs[j] = limbs[j]>>1 | limbs[j+1]<<63
+		}
+		limbs[4] >>= 1
+	}
+	return naf, n
+}
+
+// addWord adds v to the multi-limb value x.
+func addWord(x *[5]uint64, v uint64) {
+	for i := range x {
+		x[i] += v
+		if x[i] >= v {
+			return
+		}
+		v = 1
+	}
+}
+
+// subWord subtracts v from the multi-limb value x, which must be at
+// least v.
+func subWord(x *[5]uint64, v uint64) {
+	for i := range x {
+		old := x[i]
+		x[i] -= v
+		if old >= v {
+			return
+		}
+		v = 1
+	}
+}
+
+// wnafTableSize is the number of odd multiples of a point needed for
+// width-5 NAF digits.
+const wnafTableSize = 1 << (wnafWidth - 2)
+
+// wnafTable returns q, 3q, 5q, ..., (2*wnafTableSize-1)q.
+func wnafTable(q *twistededwards.PointExtended) [wnafTableSize]twistededwards.PointExtended {
+	var table [wnafTableSize]twistededwards.PointExtended
+	var twice twistededwards.PointExtended
+	twice.Double(q)
+	table[0] = *q
+	for j := 1; j < wnafTableSize; j++ {
+		table[j].Add(&table[j-1], &twice)
+	}
+	return table
+}
+
+// addDigit adds d times the point of table to acc, for a NAF digit d.
+func addDigit(acc *twistededwards.PointExtended, table *[wnafTableSize]twistededwards.PointExtended, d int8) {
+	switch {
+	case d > 0:
+		acc.Add(acc, &table[d/2])
+	case d < 0:
+		var neg twistededwards.PointExtended
+		neg.Neg(&table[-d/2])
+		acc.Add(acc, &neg)
+	}
+}
+
+// wnafMult sets p to k times q, where naf and n are the width-5 NAF of k
+// as returned by wnaf. It does not allocate.
+//
+// wnafMult runs in variable time and must only be used with public
+// values.
+func wnafMult(p, q *twistededwards.PointExtended, naf *[257]int8, n int) {
+	table := wnafTable(q)
+	acc := identityExtended()
+	for bit := n - 1; bit >= 0; bit-- {
+		acc.Double(&acc)
+		addDigit(&acc, &table, naf[bit])
+	}
+	*p = acc
+}
+
+// strausMult returns sum(k[i] * p[i]) by Straus' method: the width-5 NAFs
+// of all scalars are processed together, so the doublings are shared and
+// each term costs only an addition per nonzero digit. For a few terms it
+// needs far fewer additions than the fixed windows of scalarMult and the
+// buckets of Pippenger's method.
+//
+// strausMult runs in variable time and must only be used with public
+// scalars.
+func strausMult(ks [][32]byte, ps []twistededwards.PointExtended) twistededwards.PointExtended {
+	nafs := make([][257]int8, len(ks))
+	tables := make([][wnafTableSize]twistededwards.PointExtended, len(ks))
+	top := 0
+	for i := range ks {
+		var n int
+		nafs[i], n = wnaf(&ks[i], wnafWidth)
+		top = max(top, n)
+		tables[i] = wnafTable(&ps[i])
+	}
+
+	acc := identityExtended()
+	for bit := top - 1; bit >= 0; bit-- {
+		acc.Double(&acc)
+		for i := range nafs {
+			addDigit(&acc, &tables[i], nafs[i][bit])
+		}
+	}
+	return acc
+}
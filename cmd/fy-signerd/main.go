@@ -0,0 +1,137 @@
+// Command fy-signerd is a signing daemon for one FROST participant. It
+// is a reference deployment of the session package: it loads a key share
+// encrypted with fy keyshare encrypt, approves signing requests against a
+// policy, and takes part in signing ceremonies over a JSON HTTP API.
+//
+//	fy-signerd -key keyshare.enc -passphrase-file pass.txt -policy policy.json
+//
+// The API has three endpoints:
+//
+//	GET  /v1/info         the participant's ID and public keys
+//	POST /v1/sign/commit  {"message": hex} → {"session", "from", "hiding", "binding"}
+//	POST /v1/sign/share   {"session", "commitments": [{"from", "hiding", "binding"}]} → {"from", "share"}
+//
+// A commit request is checked against the policy before any nonce is
+// generated. Each session signs at most once and is discarded when its
+// timeout passes, so a nonce is never used twice.
+//
+// The policy file is a JSON object with the optional fields
+// maxMessageBytes, allowedPrefixes (hex), maxPendingSessions and
+// sessionTimeout (for example "5m").
+//
+// The daemon does not authenticate clients. It listens on a loopback
+// address unless TLS is configured, and is meant to run behind a proxy
+// that authenticates the coordinator.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stderr)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "fy-signerd:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line and serves until interrupted.
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("fy-signerd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keyPath := fs.String("key", "", "encrypted key share file")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the passphrase (default $"+keyfile.PassphraseEnv+")")
+	policyPath := fs.String("policy", "", "approval policy file (default: approve messages up to 64 KiB)")
+	listen := fs.String("listen", "127.0.0.1:8750", "address to listen on")
+	certFile := fs.String("tls-cert", "", "TLS certificate file")
+	keyFile := fs.String("tls-key", "", "TLS private key file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*certFile == "") != (*keyFile == "") {
+		return errors.New("-tls-cert and -tls-key must be given together")
+	}
+	if *certFile == "" && !isLoopback(*listen) {
+		return fmt.Errorf("refusing to listen on %s without TLS", *listen)
+	}
+
+	pol, err := loadPolicy(*policyPath)
+	if err != nil {
+		return err
+	}
+	s, err := loadServer(*keyPath, *passphraseFile, pol)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(stderr, "participant %d of ceremony %s listening on %s\n", s.info.ID, s.info.Ceremony, *listen)
+	if *certFile != "" {
+		err = srv.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// loadServer decrypts the key share at path and returns its server.
+func loadServer(path, passphraseFile string, pol *policy) (*server, error) {
+	e, err := keyfile.LoadEncrypted(path)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := keyfile.ReadPassphrase(passphraseFile)
+	if err != nil {
+		return nil, err
+	}
+	defer memwipe.Bytes(passphrase)
+	ks, err := e.Decrypt(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newServer(ks, pol)
+}
+
+// isLoopback reports whether addr is a loopback host and port.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+)
+
+// newTestServers runs a 2-of-3 DKG and starts a daemon for each of the
+// first two participants, loaded from encrypted key share files.
+func newTestServers(t testing.TB, pol *policy) ([]*server, []*httptest.Server, *frost.FROST) {
+	t.Helper()
+	g := &bjj.BJJ{}
+	shares, _, err := session.QuickDKG(g, 2, 3, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &keyfile.Ceremony{Version: keyfile.Version, ID: "test", Group: "baby-jubjub", Hasher: "sha256", Threshold: 2, Total: 3}
+	t.Setenv(keyfile.PassphraseEnv, "daemon passphrase")
+
+	var servers []*server
+	var https []*httptest.Server
+	for id := 1; id <= 2; id++ {
+		e, err := keyfile.Encrypt(keyfile.New(c, id, shares[id-1], nil), []byte("daemon passphrase"), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(e)
+		path := filepath.Join(t.TempDir(), "keyshare.enc")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		s, err := loadServer(path, "", pol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		servers = append(servers, s)
+		h := httptest.NewServer(s.handler())
+		t.Cleanup(h.Close)
+		https = append(https, h)
+	}
+	f, _ := c.NewFROST()
+	return servers, https, f
+}
+
+// post sends a JSON request and decodes the response into resp.
+func post(t *testing.T, url string, req, resp any) int {
+	t.Helper()
+	body, _ := json.Marshal(req)
+	r, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Body.Close()
+	if resp != nil {
+		json.NewDecoder(r.Body).Decode(resp)
+	}
+	return r.StatusCode
+}
+
+func TestSigning(t *testing.T) {
+	pol, _ := loadPolicy("")
+	servers, https, f := newTestServers(t, pol)
+	message := []byte("pay 10 to alice")
+
+	var info infoResponse
+	r, err := http.Get(https[0].URL + "/v1/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewDecoder(r.Body).Decode(&info)
+	r.Body.Close()
+	if info.ID != 1 || info.Threshold != 2 || info.GroupKey == "" {
+		t.Errorf("info = %+v", info)
+	}
+
+	commits := make([]commitResponse, len(https))
+	for i, h := range https {
+		if code := post(t, h.URL+"/v1/sign/commit", commitRequest{Message: hex.EncodeToString(message)}, &commits[i]); code != http.StatusOK {
+			t.Fatalf("commit: status %d", code)
+		}
+	}
+	list := []commitment{commits[0].commitment, commits[1].commitment}
+	commitments, err := servers[0].decodeCommitments(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares := make([]*frost.SignatureShare, len(https))
+	for i, h := range https {
+		var resp signResponse
+		if code := post(t, h.URL+"/v1/sign/share", signRequest{Session: commits[i].Session, Commitments: list}, &resp); code != http.StatusOK {
+			t.Fatalf("share: status %d", code)
+		}
+		z, err := keyfile.DecodeScalar(servers[i].group, resp.Share)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares[i] = &frost.SignatureShare{ID: commitments[i].ID, Z: z}
+	}
+	sig, err := f.Aggregate(message, commitments, shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey, _ := keyfile.DecodePoint(servers[0].group, info.GroupKey)
+	if !f.Verify(message, sig, groupKey) {
+		t.Error("signature from the daemons does not verify")
+	}
+
+	// A session signs only once
+	if code := post(t, https[0].URL+"/v1/sign/share", signRequest{Session: commits[0].Session, Commitments: list}, nil); code != http.StatusNotFound {
+		t.Errorf("reused session: status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	pol := &policy{AllowedPrefixes: []string{hex.EncodeToString([]byte("pay "))}, MaxPendingSessions: 1, SessionTimeout: "1m"}
+	if err := pol.init(); err != nil {
+		t.Fatal(err)
+	}
+	servers, https, _ := newTestServers(t, pol)
+	url := https[0].URL + "/v1/sign/commit"
+
+	if code := post(t, url, commitRequest{Message: hex.EncodeToString([]byte("steal everything"))}, nil); code != http.StatusForbidden {
+		t.Errorf("message without an allowed prefix: status %d", code)
+	}
+	if code := post(t, url, commitRequest{Message: "not hex"}, nil); code != http.StatusBadRequest {
+		t.Errorf("malformed message: status %d", code)
+	}
+	var first commitResponse
+	if code := post(t, url, commitRequest{Message: hex.EncodeToString([]byte("pay 1"))}, &first); code != http.StatusOK {
+		t.Fatalf("allowed message: status %d", code)
+	}
+	if code := post(t, url, commitRequest{Message: hex.EncodeToString([]byte("pay 2"))}, nil); code != http.StatusServiceUnavailable {
+		t.Errorf("session over the limit: status %d", code)
+	}
+
+	// Expired sessions are discarded and free their slot
+	servers[0].now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if code := post(t, url, commitRequest{Message: hex.EncodeToString([]byte("pay 3"))}, nil); code != http.StatusOK {
+		t.Errorf("after expiry: status %d", code)
+	}
+	if code := post(t, https[0].URL+"/v1/sign/share", signRequest{Session: first.Session}, nil); code != http.StatusNotFound {
+		t.Errorf("expired session: status %d", code)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8750": true,
+		"[::1]:8750":     true,
+		"localhost:80":   true,
+		"0.0.0.0:8750":   false,
+		":8750":          false,
+		"10.0.0.1:8750":  false,
+	} {
+		if got := isLoopback(addr); got != want {
+			t.Errorf("isLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func FuzzDecodeCommitments(f *testing.F) {
+	servers, _, _ := newTestServers(f, &policy{})
+	s := servers[0]
+	g := s.group
+	valid, _ := json.Marshal([]commitment{
+		{From: 1, Hiding: keyfile.EncodeHex(g.Generator()), Binding: keyfile.EncodeHex(g.Generator())},
+		{From: 2, Hiding: keyfile.EncodeHex(g.Generator()), Binding: keyfile.EncodeHex(g.Generator())},
+	})
+	f.Add(valid)
+	f.Add([]byte(`[{"from":1,"hiding":"","binding":"00"}]`))
+	f.Add([]byte(`[{"from":0}]`))
+	f.Add([]byte(`[]`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var list []commitment
+		if err := json.Unmarshal(data, &list); err != nil {
+			return
+		}
+		commitments, err := s.decodeCommitments(list)
+		if err != nil {
+			return
+		}
+		// Signing with the decoded commitments must fail cleanly or succeed
+		sess, err := s.participant.NewSigningSession(rand.Reader, []byte("message"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sess.Sign(commitments)
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// policy decides which signing requests the daemon approves. The zero
+// policy approves every message of up to defaultMaxMessageBytes.
+type policy struct {
+	// MaxMessageBytes is the largest message the daemon signs.
+	MaxMessageBytes int `json:"maxMessageBytes"`
+
+	// AllowedPrefixes lists hex-encoded prefixes, one of which every
+	// message must start with. An empty list allows any message.
+	AllowedPrefixes []string `json:"allowedPrefixes"`
+
+	// MaxPendingSessions is the number of signing sessions that may wait
+	// for round 2 at the same time.
+	MaxPendingSessions int `json:"maxPendingSessions"`
+
+	// SessionTimeout is how long a session waits for round 2 before its
+	// nonces are discarded, as a time.ParseDuration string.
+	SessionTimeout string `json:"sessionTimeout"`
+
+	prefixes [][]byte
+	timeout  time.Duration
+}
+
+// Policy defaults.
+const (
+	defaultMaxMessageBytes    = 64 << 10
+	defaultMaxPendingSessions = 64
+	defaultSessionTimeout     = 5 * time.Minute
+)
+
+// errDenied wraps the reasons for rejecting a signing request.
+var errDenied = errors.New("denied by policy")
+
+// loadPolicy reads the policy file at path, or returns the default policy
+// if path is empty.
+func loadPolicy(path string) (*policy, error) {
+	p := &policy{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(p); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := p.init(); err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+	return p, nil
+}
+
+// init checks the policy and fills in defaults.
+func (p *policy) init() error {
+	if p.MaxMessageBytes == 0 {
+		p.MaxMessageBytes = defaultMaxMessageBytes
+	}
+	if p.MaxPendingSessions == 0 {
+		p.MaxPendingSessions = defaultMaxPendingSessions
+	}
+	if p.MaxMessageBytes < 0 || p.MaxPendingSessions < 0 {
+		return errors.New("limits must not be negative")
+	}
+	p.timeout = defaultSessionTimeout
+	if p.SessionTimeout != "" {
+		d, err := time.ParseDuration(p.SessionTimeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid session timeout %q", p.SessionTimeout)
+		}
+		p.timeout = d
+	}
+	p.prefixes = p.prefixes[:0]
+	for _, s := range p.AllowedPrefixes {
+		prefix, err := hex.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("invalid prefix %q", s)
+		}
+		p.prefixes = append(p.prefixes, prefix)
+	}
+	return nil
+}
+
+// approve returns an error wrapping errDenied if message may not be
+// signed.
+func (p *policy) approve(message []byte) error {
+	if len(message) > p.MaxMessageBytes {
+		return fmt.Errorf("%w: message has %d bytes, limit is %d", errDenied, len(message), p.MaxMessageBytes)
+	}
+	if len(p.prefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range p.prefixes {
+		if bytes.HasPrefix(message, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: message does not start with an allowed prefix", errDenied)
+}
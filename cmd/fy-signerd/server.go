@@ -0,0 +1,267 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+)
+
+// server serves the signing API for one participant.
+type server struct {
+	participant *session.Participant
+	group       group.Group
+	info        infoResponse
+	policy      *policy
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*pendingSession
+}
+
+// pendingSession is a signing session waiting for round 2.
+type pendingSession struct {
+	session *session.SigningSession
+	expires time.Time
+}
+
+// infoResponse describes the participant.
+type infoResponse struct {
+	Ceremony  string `json:"ceremony"`
+	ID        int    `json:"id"`
+	Threshold int    `json:"threshold"`
+	Total     int    `json:"total"`
+	GroupKey  string `json:"groupKey"`
+	PublicKey string `json:"publicKey"`
+}
+
+// commitRequest asks the signer to start a session for a message.
+type commitRequest struct {
+	Message string `json:"message"` // hex
+}
+
+// commitment is a signer's round 1 commitment.
+type commitment struct {
+	From    int    `json:"from"`
+	Hiding  string `json:"hiding"`
+	Binding string `json:"binding"`
+}
+
+// commitResponse returns the signer's commitment for a new session.
+type commitResponse struct {
+	Session string `json:"session"`
+	commitment
+}
+
+// signRequest asks the signer to complete a session.
+type signRequest struct {
+	Session     string       `json:"session"`
+	Commitments []commitment `json:"commitments"`
+}
+
+// signResponse returns the signer's signature share.
+type signResponse struct {
+	From  int    `json:"from"`
+	Share string `json:"share"`
+}
+
+// errorResponse reports a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newServer returns the server for the key share ks.
+func newServer(ks *keyfile.KeyShare, pol *policy) (*server, error) {
+	p, err := ks.Participant(nil)
+	if err != nil {
+		return nil, err
+	}
+	g, err := group.New(ks.Ceremony.Group)
+	if err != nil {
+		return nil, err
+	}
+	return &server{
+		participant: p,
+		group:       g,
+		info: infoResponse{
+			Ceremony:  ks.Ceremony.ID,
+			ID:        ks.ID,
+			Threshold: ks.Ceremony.Threshold,
+			Total:     ks.Ceremony.Total,
+			GroupKey:  ks.GroupKey,
+			PublicKey: ks.PublicKey,
+		},
+		policy:  pol,
+		now:     time.Now,
+		pending: make(map[string]*pendingSession),
+	}, nil
+}
+
+// handler returns the HTTP handler of the API.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/info", s.handleInfo)
+	mux.HandleFunc("POST /v1/sign/commit", s.handleCommit)
+	mux.HandleFunc("POST /v1/sign/share", s.handleShare)
+	return mux
+}
+
+// handleInfo returns the participant's public parameters.
+func (s *server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.info)
+}
+
+// handleCommit runs signing round 1 for an approved message.
+func (s *server) handleCommit(w http.ResponseWriter, r *http.Request) {
+	var req commitRequest
+	if !s.readRequest(w, r, &req) {
+		return
+	}
+	message, err := hex.DecodeString(req.Message)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("message is not hex-encoded"))
+		return
+	}
+	if err := s.policy.approve(message); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if len(s.pending) >= s.policy.MaxPendingSessions {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("%w: too many pending sessions", errDenied))
+		return
+	}
+	sess, err := s.participant.NewSigningSession(rand.Reader, message)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.pending[sess.ID()] = &pendingSession{session: sess, expires: s.now().Add(s.policy.timeout)}
+
+	c := sess.Commitment()
+	writeJSON(w, http.StatusOK, commitResponse{
+		Session: sess.ID(),
+		commitment: commitment{
+			From:    s.info.ID,
+			Hiding:  keyfile.EncodeHex(c.HidingPoint),
+			Binding: keyfile.EncodeHex(c.BindingPoint),
+		},
+	})
+}
+
+// handleShare runs signing round 2 for a pending session. The session is
+// consumed whether or not signing succeeds.
+func (s *server) handleShare(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if !s.readRequest(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	s.expire()
+	pending, ok := s.pending[req.Session]
+	delete(s.pending, req.Session)
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown or expired session"))
+		return
+	}
+	sess := pending.session
+
+	commitments, err := s.decodeCommitments(req.Commitments)
+	if err != nil {
+		sess.Zeroize()
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	share, err := sess.Sign(commitments)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, signResponse{From: s.info.ID, Share: keyfile.EncodeHex(share.Z)})
+}
+
+// decodeCommitments decodes the commitments of a signing request.
+func (s *server) decodeCommitments(list []commitment) ([]*frost.SigningCommitment, error) {
+	out := make([]*frost.SigningCommitment, len(list))
+	for i, c := range list {
+		if c.From < 1 || c.From > s.info.Total {
+			return nil, fmt.Errorf("commitments[%d]: invalid participant ID %d", i, c.From)
+		}
+		hiding, err := keyfile.DecodePoint(s.group, c.Hiding)
+		if err != nil {
+			return nil, fmt.Errorf("commitments[%d]: invalid hiding point: %w", i, err)
+		}
+		binding, err := keyfile.DecodePoint(s.group, c.Binding)
+		if err != nil {
+			return nil, fmt.Errorf("commitments[%d]: invalid binding point: %w", i, err)
+		}
+		out[i] = &frost.SigningCommitment{
+			ID:           s.group.NewScalar().SetUint64(uint64(c.From)),
+			HidingPoint:  hiding,
+			BindingPoint: binding,
+		}
+	}
+	return out, nil
+}
+
+// expire discards the sessions whose timeout has passed. s.mu must be
+// held.
+func (s *server) expire() {
+	now := s.now()
+	for id, p := range s.pending {
+		if now.After(p.expires) {
+			p.session.Zeroize()
+			delete(s.pending, id)
+		}
+	}
+}
+
+// close discards all pending sessions.
+func (s *server) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, p := range s.pending {
+		p.session.Zeroize()
+		delete(s.pending, id)
+	}
+}
+
+// readRequest decodes the JSON body of r into v, writing an error
+// response and returning false if it is malformed. Messages are
+// hex-encoded, so bodies may hold twice the largest permitted message.
+func (s *server) readRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	limit := 2*int64(s.policy.MaxMessageBytes) + 64<<10
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as the response with the given status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,40 @@
+// fy.js runs fy.wasm, the WebAssembly build of cmd/fy-wasm, and exposes
+// its ceremony API. Load the wasm_exec.js of the Go release that built
+// fy.wasm first; it defines the Go class used here.
+//
+//   import { load, toHex } from "./fy.js";
+//   const fy = await load("fy.wasm");
+//   const { handle } = fy.call("dkg.new", { ceremony, id: 2, echo: true });
+//
+// See package internal/jsbind for the methods and their parameters.
+
+// load instantiates fy.wasm from a URL or from its bytes and returns the
+// API.
+export async function load(source) {
+  const go = new Go();
+  const bytes = typeof source === "string"
+    ? await (await fetch(source)).arrayBuffer()
+    : source;
+  const { instance } = await WebAssembly.instantiate(bytes, go.importObject);
+  go.run(instance);
+  return { call };
+}
+
+// call runs method with params and returns its result, throwing an Error
+// if it fails.
+export function call(method, params) {
+  const r = globalThis.fy.call(method, JSON.stringify(params));
+  if (r.error !== undefined) {
+    throw new Error(r.error);
+  }
+  return JSON.parse(r.result);
+}
+
+// toHex encodes a message, given as a string or bytes, in hex as the
+// methods expect.
+export function toHex(message) {
+  const bytes = typeof message === "string"
+    ? new TextEncoder().encode(message)
+    : message;
+  return Array.from(bytes, (b) => b.toString(16).padStart(2, "0")).join("");
+}
@@ -0,0 +1,45 @@
+//go:build js && wasm
+
+// Command fy-wasm is the WebAssembly build of fy for browsers and other
+// JavaScript hosts. It exposes the ceremony API of package
+// internal/jsbind, so browser participants run the same DKG and signing
+// code as the fy command and exchange the same message files.
+//
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o fy.wasm ./cmd/fy-wasm
+//
+// and load fy.wasm with the wasm_exec.js of the same Go release, found in
+// $(go env GOROOT)/lib/wasm. Once running, it defines a global
+//
+//	fy.call(method, params) -> {result} or {error}
+//
+// taking the params and returning the result as JSON strings. fy.js in
+// this directory wraps it in a small module that throws on errors.
+package main
+
+import (
+	"crypto/rand"
+	"syscall/js"
+
+	"github.com/f3rmion/fy/internal/jsbind"
+)
+
+func main() {
+	api := jsbind.New(rand.Reader)
+	call := js.FuncOf(func(this js.Value, args []js.Value) any {
+		// A panic would end the program, so every failure is returned
+		if len(args) != 2 || args[0].Type() != js.TypeString || args[1].Type() != js.TypeString {
+			return map[string]any{"error": "fy.call takes a method name and a JSON string"}
+		}
+		result, err := api.Call(args[0].String(), []byte(args[1].String()))
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return map[string]any{"result": string(result)}
+	})
+	js.Global().Set("fy", js.ValueOf(map[string]any{"call": call}))
+
+	// Keep serving calls for the lifetime of the page
+	select {}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/f3rmion/fy/airgap"
+)
+
+// airgapEncode splits a message file into frames for QR codes, one per
+// line, to be rendered by a QR tool and scanned on an air-gapped machine.
+func airgapEncode(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("airgap encode", stderr)
+	in := fs.String("in", "", "message file to encode")
+	chunk := fs.Int("chunk", airgap.DefaultChunkSize, "message bytes per frame")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("no message file given: use -in")
+	}
+	message, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	frames, err := airgap.Encode(message, *chunk)
+	if err != nil {
+		return err
+	}
+	for _, f := range frames {
+		fmt.Fprintln(stdout, f)
+	}
+	return nil
+}
+
+// airgapDecode reassembles a message file from scanned frames, read one
+// per line from the given files in any order.
+func airgapDecode(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("airgap decode", stderr)
+	out := fs.String("o", "", "message file to write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("no output file given: use -o")
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no frame files given")
+	}
+	var d airgap.Decoder
+	for _, path := range fs.Args() {
+		if err := addFrames(&d, path); err != nil {
+			return err
+		}
+	}
+	message, err := d.Message()
+	if errors.Is(err, airgap.ErrIncomplete) {
+		return fmt.Errorf("%w: frames %s still missing", err, joinInts(d.Missing()))
+	}
+	if err != nil {
+		return err
+	}
+	// The message may be a private share, so it is written like one
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(message); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	_, total := d.Progress()
+	fmt.Fprintf(stdout, "wrote %d bytes from %d frames to %s\n", len(message), total, *out)
+	return nil
+}
+
+// addFrames adds the frames in the file at path, one per line, to d.
+// Blank lines are skipped.
+func addFrames(d *airgap.Decoder, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		if err := d.Add(sc.Text()); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+	}
+	return sc.Err()
+}
+
+// joinInts formats a list of numbers as "1, 2, 3".
+func joinInts(list []int) string {
+	s := make([]string, len(list))
+	for i, n := range list {
+		s[i] = fmt.Sprint(n)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/chacha20"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/internal/memwipe"
+	"github.com/f3rmion/fy/session"
+)
+
+// dkgInit creates a ceremony file.
+func dkgInit(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dkg init", stderr)
+	groupName := fs.String("group", "baby-jubjub", "group to generate the key in")
+	hasher := fs.String("hasher", "sha256", "hasher for signing, see frost.HasherNames")
+	threshold := fs.Int("threshold", 2, "number of signers needed to sign")
+	total := fs.Int("total", 3, "number of participants")
+	out := fs.String("o", "ceremony.json", "ceremony file to write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	c := &keyfile.Ceremony{
+		Version:   keyfile.Version,
+		ID:        hex.EncodeToString(id),
+		Group:     *groupName,
+		Hasher:    *hasher,
+		Threshold: *threshold,
+		Total:     *total,
+	}
+	// Check the parameters before anyone relies on the file
+	if _, err := c.NewParticipant(1, nil); err != nil {
+		return err
+	}
+	if err := writeJSON(*out, c, false); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "ceremony %s: %d-of-%d on %s, written to %s\n", c.ID, c.Threshold, c.Total, c.Group, *out)
+	return nil
+}
+
+// dkgRound1 generates a participant's round 1 messages.
+func dkgRound1(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dkg round1", stderr)
+	ceremonyPath := fs.String("ceremony", "ceremony.json", "ceremony file")
+	id := fs.Int("id", 0, "this participant's ID")
+	outDir := fs.String("out", ".", "directory for the messages to send")
+	statePath := fs.String("state", "", "secret state file to write (default fy-dkg-state-ID.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	c, err := keyfile.LoadCeremony(*ceremonyPath)
+	if err != nil {
+		return err
+	}
+	if err := c.CheckID(*id); err != nil {
+		return err
+	}
+	if *statePath == "" {
+		*statePath = defaultStateName(*id)
+	}
+
+	seed := make([]byte, chacha20.KeySize)
+	defer memwipe.Bytes(seed)
+	if _, err := rand.Read(seed); err != nil {
+		return err
+	}
+	p, err := c.NewParticipant(*id, nil)
+	if err != nil {
+		return err
+	}
+	rng, err := newSeedReader(seed)
+	if err != nil {
+		return err
+	}
+	output, err := p.GenerateRound1(rng, c.IDs())
+	if err != nil {
+		return err
+	}
+
+	// Write the state first: without it the messages are useless
+	state := &stateFile{Ceremony: c.ID, ID: *id, Seed: hex.EncodeToString(seed)}
+	if err := writeJSON(*statePath, state, true); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(*outDir, broadcastName(*id)), keyfile.NewBroadcast(c, *id, output.Broadcast), false); err != nil {
+		return err
+	}
+	for _, to := range c.IDs() {
+		share, ok := output.PrivateShares[to]
+		if !ok {
+			continue
+		}
+		f := &keyfile.Share{Ceremony: c.ID, From: *id, To: to, Share: keyfile.EncodeHex(share.Share)}
+		if err := writeJSON(filepath.Join(*outDir, shareName(*id, to)), f, true); err != nil {
+			return err
+		}
+		share.Zeroize()
+	}
+
+	fmt.Fprintf(stdout, "wrote %s: send it to every participant\n", filepath.Join(*outDir, broadcastName(*id)))
+	fmt.Fprintf(stdout, "wrote %d private shares to %s: send each only to its recipient over a secure channel\n",
+		len(output.PrivateShares), *outDir)
+	fmt.Fprintf(stdout, "kept secret state in %s for dkg finalize\n", *statePath)
+	return nil
+}
+
+// dkgFinalize verifies the round 1 messages and writes the key share.
+func dkgFinalize(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dkg finalize", stderr)
+	ceremonyPath := fs.String("ceremony", "ceremony.json", "ceremony file")
+	id := fs.Int("id", 0, "this participant's ID")
+	inDir := fs.String("in", ".", "directory holding the received messages")
+	statePath := fs.String("state", "", "secret state file from dkg round1 (default fy-dkg-state-ID.json)")
+	out := fs.String("o", "", "key share file to write (default fy-keyshare-ID.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	c, err := keyfile.LoadCeremony(*ceremonyPath)
+	if err != nil {
+		return err
+	}
+	if err := c.CheckID(*id); err != nil {
+		return err
+	}
+	if *statePath == "" {
+		*statePath = defaultStateName(*id)
+	}
+	if *out == "" {
+		*out = defaultKeyShareName(*id)
+	}
+
+	var state stateFile
+	if err := readJSON(*statePath, &state); err != nil {
+		return err
+	}
+	if err := checkCeremony(c, *statePath, state.Ceremony); err != nil {
+		return err
+	}
+	if state.ID != *id {
+		return fmt.Errorf("%s: state of participant %d, not %d", *statePath, state.ID, *id)
+	}
+	seed, err := hex.DecodeString(state.Seed)
+	if err != nil {
+		return fmt.Errorf("%s: invalid seed", *statePath)
+	}
+	defer memwipe.Bytes(seed)
+
+	// Rebuild the round 1 state from the seed
+	p, err := c.NewParticipant(*id, nil)
+	if err != nil {
+		return err
+	}
+	rng, err := newSeedReader(seed)
+	if err != nil {
+		return err
+	}
+	own, err := p.GenerateRound1(rng, c.IDs())
+	if err != nil {
+		return err
+	}
+	for _, share := range own.PrivateShares {
+		share.Zeroize()
+	}
+
+	input, err := readRound1(c, p, *id, *inDir)
+	if err != nil {
+		return err
+	}
+	if !sameCommitments(input.Broadcasts[*id-1], own.Broadcast) {
+		return fmt.Errorf("%s does not match the state in %s", broadcastName(*id), *statePath)
+	}
+	result, err := p.ProcessRound1(input)
+	if err != nil {
+		return err
+	}
+	defer result.KeyShare.Zeroize()
+
+	if err := writeJSON(*out, keyfile.New(c, *id, result.KeyShare, result.AllPublicKeys), true); err != nil {
+		return err
+	}
+	// The seed recreates the shares sent to everyone; it is not needed
+	// any more
+	if err := os.Remove(*statePath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote key share to %s\n", *out)
+	fmt.Fprintf(stdout, "group key:         %s\n", keyfile.EncodeHex(result.GroupKey))
+	fmt.Fprintf(stdout, "transcript digest: %x\n", result.TranscriptDigest)
+	fmt.Fprintln(stdout, "Compare the transcript digest with every other participant before signing.")
+	return nil
+}
+
+// readRound1 reads the broadcasts of all participants and the private
+// shares addressed to id from dir. Broadcasts are in ID order.
+func readRound1(c *keyfile.Ceremony, p *session.Participant, id int, dir string) (*session.Round1Input, error) {
+	g, err := group.New(c.Group)
+	if err != nil {
+		return nil, err
+	}
+	input := &session.Round1Input{}
+	for _, from := range c.IDs() {
+		path := filepath.Join(dir, broadcastName(from))
+		var b keyfile.Broadcast
+		if err := readJSON(path, &b); err != nil {
+			return nil, err
+		}
+		if err := checkCeremony(c, path, b.Ceremony); err != nil {
+			return nil, err
+		}
+		if b.From != from {
+			return nil, fmt.Errorf("%s: broadcast from participant %d", path, b.From)
+		}
+		broadcast, err := b.Decode(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		input.Broadcasts = append(input.Broadcasts, broadcast)
+
+		if from == id {
+			continue
+		}
+		path = filepath.Join(dir, shareName(from, id))
+		var s keyfile.Share
+		if err := readJSON(path, &s); err != nil {
+			return nil, err
+		}
+		if err := checkCeremony(c, path, s.Ceremony); err != nil {
+			return nil, err
+		}
+		if s.From != from || s.To != id {
+			return nil, fmt.Errorf("%s: share from %d to %d", path, s.From, s.To)
+		}
+		share, err := s.Decode(g)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		input.PrivateShares = append(input.PrivateShares, share)
+	}
+	return input, nil
+}
+
+// sameCommitments reports whether two broadcasts carry the same
+// commitments.
+func sameCommitments(a, b *frost.Round1Data) bool {
+	if len(a.Commitments) != len(b.Commitments) {
+		return false
+	}
+	for i := range a.Commitments {
+		if !bytes.Equal(a.Commitments[i].Bytes(), b.Commitments[i].Bytes()) {
+			return false
+		}
+	}
+	return true
+}
+
+// seedReader is a deterministic random stream derived from a secret seed
+// with ChaCha20. It lets dkg finalize rebuild the polynomial generated by
+// dkg round1 without storing the polynomial itself.
+type seedReader struct {
+	cipher *chacha20.Cipher
+}
+
+// newSeedReader returns the stream for seed.
+func newSeedReader(seed []byte) (*seedReader, error) {
+	if len(seed) != chacha20.KeySize {
+		return nil, errors.New("invalid seed length")
+	}
+	c, err := chacha20.NewUnauthenticatedCipher(seed, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		return nil, err
+	}
+	return &seedReader{cipher: c}, nil
+}
+
+// Read implements io.Reader.
+func (r *seedReader) Read(p []byte) (int, error) {
+	clear(p)
+	r.cipher.XORKeyStream(p, p)
+	return len(p), nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+)
+
+// stateFile holds a participant's secret state between round 1 and
+// finalize: the seed from which its polynomial is derived.
+type stateFile struct {
+	Ceremony string `json:"ceremony"`
+	ID       int    `json:"id"`
+	Seed     string `json:"seed"`
+}
+
+// signStateFile holds a signer's exported signing session, including its
+// secret nonces, between sign round1 and sign round2.
+type signStateFile struct {
+	Ceremony string `json:"ceremony"`
+	ID       int    `json:"id"`
+	Session  string `json:"session"`
+}
+
+// Names of the message files in a directory.
+func broadcastName(from int) string      { return fmt.Sprintf("round1-broadcast-%d.json", from) }
+func shareName(from, to int) string      { return fmt.Sprintf("round1-share-%d-to-%d.json", from, to) }
+func commitmentName(from int) string     { return fmt.Sprintf("sign-commitment-%d.json", from) }
+func signatureShareName(from int) string { return fmt.Sprintf("sign-share-%d.json", from) }
+func defaultStateName(id int) string     { return fmt.Sprintf("fy-dkg-state-%d.json", id) }
+func defaultSignStateName(id int) string { return fmt.Sprintf("fy-sign-state-%d.json", id) }
+func defaultKeyShareName(id int) string  { return fmt.Sprintf("fy-keyshare-%d.json", id) }
+
+// readJSON decodes the JSON file at path into v, rejecting unknown fields.
+func readJSON(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v to a new file at path. Secret files are readable by
+// the owner only. writeJSON does not overwrite existing files, so that a
+// repeated command cannot destroy a key share or state.
+func writeJSON(path string, v any, secret bool) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	perm := os.FileMode(0o644)
+	if secret {
+		perm = 0o600
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadKeyShare reads the key share file at path and returns the
+// participant it belongs to, ready to sign. The participant records its
+// signing sessions in a nonce store kept next to the key share, at
+// path.nonces; close the store when done.
+func loadKeyShare(path string) (*keyfile.Ceremony, *session.Participant, *session.FileNonceStore, error) {
+	f, err := keyfile.Load(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	store, err := session.OpenFileNonceStore(path + ".nonces")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p, err := f.Participant(&session.Config{NonceStore: store})
+	if err != nil {
+		store.Close()
+		return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f.Ceremony, p, store, nil
+}
+
+// checkCeremony returns an error if a message file belongs to another
+// ceremony than c.
+func checkCeremony(c *keyfile.Ceremony, path, id string) error {
+	if id != c.ID {
+		return fmt.Errorf("%s: belongs to ceremony %s, not %s", path, id, c.ID)
+	}
+	return nil
+}
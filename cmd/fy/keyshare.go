@@ -0,0 +1,78 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// keyshareEncrypt encrypts a key share file under a passphrase, for use
+// with fy-signerd.
+func keyshareEncrypt(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keyshare encrypt", stderr)
+	keyPath := fs.String("key", "", "key share file from dkg finalize")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the passphrase (default $"+keyfile.PassphraseEnv+")")
+	out := fs.String("o", "", "encrypted key share file to write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("no output file given: use -o")
+	}
+	ks, err := keyfile.Load(*keyPath)
+	if err != nil {
+		return err
+	}
+	// Refuse to encrypt a key share that would not load
+	if _, err := ks.Decode(); err != nil {
+		return fmt.Errorf("%s: %w", *keyPath, err)
+	}
+	passphrase, err := keyfile.ReadPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	defer memwipe.Bytes(passphrase)
+
+	e, err := keyfile.Encrypt(ks, passphrase, rand.Reader)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(*out, e, true); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote encrypted key share of participant %d to %s\n", ks.ID, *out)
+	return nil
+}
+
+// keyshareCheck checks that key share files belong to one threshold key,
+// as a dry run before the backups of a ceremony are destroyed.
+func keyshareCheck(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keyshare check", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no key share files given")
+	}
+	files := make([]*keyfile.KeyShare, fs.NArg())
+	ids := make([]string, fs.NArg())
+	for i, path := range fs.Args() {
+		ks, err := keyfile.Load(path)
+		if err != nil {
+			return err
+		}
+		files[i], ids[i] = ks, strconv.Itoa(ks.ID)
+	}
+	if err := keyfile.Check(files); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "key shares %s of ceremony %s are consistent with group key %s\n",
+		strings.Join(ids, ", "), files[0].Ceremony.ID, files[0].GroupKey)
+	return nil
+}
@@ -0,0 +1,145 @@
+// Command fy runs FROST key generation and signing ceremonies from the
+// command line.
+// Participants exchange JSON message files, so a ceremony can be run
+// over any file transport without writing Go code.
+//
+// A 3-of-5 distributed key generation runs in three steps:
+//
+//	fy dkg init -threshold 3 -total 5 -o ceremony.json
+//
+// creates the ceremony file, which every participant receives. Each
+// participant i then runs
+//
+//	fy dkg round1 -ceremony ceremony.json -id i -out outbox
+//
+// which writes a broadcast for everyone and a private share for each
+// other participant, and keeps the secret state in a state file. Once
+// every participant has collected the broadcasts and the shares addressed
+// to them in one directory, each runs
+//
+//	fy dkg finalize -ceremony ceremony.json -id i -in inbox
+//
+// which verifies the messages and writes the participant's key share. The
+// private shares, the state file and the key share are secret: move the
+// shares only over authenticated, confidential channels, and protect the
+// key share like any other private key.
+//
+// Signing takes two rounds. Each signer runs
+//
+//	fy sign round1 -key fy-keyshare-i.json -message "..." -out outbox
+//
+// and sends its commitment to the other signers and the aggregator. With
+// the commitments of all signers in one directory, each signer runs
+//
+//	fy sign round2 -key fy-keyshare-i.json -in inbox -out outbox
+//
+// and the aggregator, holding the commitments and signature shares, runs
+//
+//	fy aggregate -ceremony ceremony.json -group-key KEY -message "..." -in inbox
+//
+// Anyone can then check the signature with fy verify. The signer set is
+// the set of commitment files in the directory.
+//
+// Before the backups of a new group are destroyed, fy keyshare check
+// with at least threshold key share files confirms that they
+// interpolate to the group key.
+//
+// For an air-gapped participant, fy airgap encode splits any message
+// file into short frames to show as QR codes, and fy airgap decode
+// reassembles the file from the scanned frames.
+//
+// fy testvectors generate writes deterministic test vectors for other
+// implementations, and fy testvectors check validates vector files.
+//
+// Run fy help for the full list of commands and flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "fy:", err)
+		os.Exit(1)
+	}
+}
+
+// command is a subcommand of fy.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+// commands lists the subcommands in the order fy help prints them.
+var commands = []command{
+	{"dkg init", "create a ceremony file", dkgInit},
+	{"dkg round1", "generate this participant's round 1 messages", dkgRound1},
+	{"dkg finalize", "verify round 1 messages and write the key share", dkgFinalize},
+	{"sign round1", "generate a signer's nonce commitment for a message", signRound1},
+	{"sign round2", "produce a signer's signature share", signRound2},
+	{"aggregate", "combine signature shares into a signature", aggregate},
+	{"verify", "verify a signature against the group key", verify},
+	{"keyshare encrypt", "encrypt a key share under a passphrase", keyshareEncrypt},
+	{"keyshare check", "check that key shares belong to one group key", keyshareCheck},
+	{"airgap encode", "split a message file into frames for QR codes", airgapEncode},
+	{"airgap decode", "reassemble a message file from scanned frames", airgapDecode},
+	{"testvectors generate", "write deterministic test vectors for every group and hasher", testvectorsGenerate},
+	{"testvectors check", "recompute and check test vector files", testvectorsCheck},
+}
+
+// run executes the command line args, writing results to stdout and
+// diagnostics to stderr.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return errors.New("no command given")
+		}
+		return nil
+	}
+	for n := min(len(args), 2); n > 0; n-- {
+		for _, c := range commands {
+			if c.name == joinArgs(args[:n]) {
+				return c.run(args[n:], stdout, stderr)
+			}
+		}
+	}
+	usage(stderr)
+	return fmt.Errorf("unknown command %q", joinArgs(args[:min(len(args), 2)]))
+}
+
+// usage prints the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: fy <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-22s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run fy <command> -h for the flags of a command.")
+}
+
+// joinArgs joins command words with spaces.
+func joinArgs(args []string) string {
+	s := args[0]
+	for _, a := range args[1:] {
+		s += " " + a
+	}
+	return s
+}
+
+// newFlagSet returns a flag set for the named command that reports
+// errors instead of exiting.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("fy "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+	"github.com/f3rmion/fy/testvectors"
+)
+
+// runOK runs a command line and fails the test if it returns an error.
+func runOK(t *testing.T, args ...string) {
+	t.Helper()
+	if err := run(args, io.Discard, io.Discard); err != nil {
+		t.Fatalf("fy %v: %v", args, err)
+	}
+}
+
+// runCeremony runs a t-of-n DKG in dir and returns the key share paths.
+func runCeremony(t *testing.T, dir string, threshold, total int) []string {
+	t.Helper()
+	ceremony := filepath.Join(dir, "ceremony.json")
+	msgs := filepath.Join(dir, "msgs")
+	if err := os.Mkdir(msgs, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	runOK(t, "dkg", "init", "-threshold", strconv.Itoa(threshold), "-total", strconv.Itoa(total), "-o", ceremony)
+	for id := 1; id <= total; id++ {
+		runOK(t, "dkg", "round1", "-ceremony", ceremony, "-id", strconv.Itoa(id), "-out", msgs,
+			"-state", filepath.Join(dir, defaultStateName(id)))
+	}
+	paths := make([]string, total)
+	for id := 1; id <= total; id++ {
+		paths[id-1] = filepath.Join(dir, defaultKeyShareName(id))
+		runOK(t, "dkg", "finalize", "-ceremony", ceremony, "-id", strconv.Itoa(id), "-in", msgs,
+			"-state", filepath.Join(dir, defaultStateName(id)), "-o", paths[id-1])
+	}
+	return paths
+}
+
+func TestDKGCeremony(t *testing.T) {
+	dir := t.TempDir()
+	paths := runCeremony(t, dir, 3, 5)
+
+	var first keyfile.KeyShare
+	for i, path := range paths {
+		var ks keyfile.KeyShare
+		if err := readJSON(path, &ks); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = ks
+		}
+		if ks.GroupKey != first.GroupKey || ks.TranscriptDigest != first.TranscriptDigest {
+			t.Errorf("participant %d disagrees on the group key or transcript", ks.ID)
+		}
+		if ks.PublicKeys[ks.ID] != ks.PublicKey {
+			t.Errorf("participant %d: public key differs from its entry in publicKeys", ks.ID)
+		}
+		if _, err := ks.Decode(); err != nil {
+			t.Errorf("participant %d: %v", ks.ID, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, defaultStateName(ks.ID))); !os.IsNotExist(err) {
+			t.Errorf("state of participant %d was not removed", ks.ID)
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("key share %s is not private", path)
+		}
+	}
+}
+
+func TestSigning(t *testing.T) {
+	dir := t.TempDir()
+	paths := runCeremony(t, dir, 3, 5)
+	var ks keyfile.KeyShare
+	if err := readJSON(paths[0], &ks); err != nil {
+		t.Fatal(err)
+	}
+	ceremony := filepath.Join(dir, "ceremony.json")
+	signing := filepath.Join(dir, "signing")
+	os.Mkdir(signing, 0o700)
+	message := "signed from the terminal"
+
+	signers := []int{1, 3, 5}
+	for _, id := range signers {
+		runOK(t, "sign", "round1", "-key", paths[id-1], "-message", message, "-out", signing,
+			"-state", filepath.Join(dir, defaultSignStateName(id)))
+	}
+	state, err := os.ReadFile(filepath.Join(dir, defaultSignStateName(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range signers {
+		runOK(t, "sign", "round2", "-key", paths[id-1], "-in", signing, "-out", signing,
+			"-state", filepath.Join(dir, defaultSignStateName(id)))
+	}
+	sigPath := filepath.Join(dir, "signature.json")
+	runOK(t, "aggregate", "-ceremony", ceremony, "-group-key", ks.GroupKey, "-message", message,
+		"-in", signing, "-o", sigPath)
+	runOK(t, "verify", "-ceremony", ceremony, "-group-key", ks.GroupKey, "-message", message, "-signature", sigPath)
+
+	if err := run([]string{"verify", "-ceremony", ceremony, "-group-key", ks.GroupKey,
+		"-message", "another message", "-signature", sigPath}, io.Discard, io.Discard); err == nil {
+		t.Error("verify accepted a signature for another message")
+	}
+	if err := run([]string{"verify", "-ceremony", ceremony, "-group-key", ks.PublicKey,
+		"-message", message, "-signature", sigPath}, io.Discard, io.Discard); err == nil {
+		t.Error("verify accepted a signature under another key")
+	}
+
+	// The nonces are gone after round 2
+	os.Remove(filepath.Join(signing, signatureShareName(1)))
+	if err := run([]string{"sign", "round2", "-key", paths[0], "-in", signing, "-out", signing,
+		"-state", filepath.Join(dir, defaultSignStateName(1))}, io.Discard, io.Discard); err == nil {
+		t.Error("sign round2 ran twice with the same nonces")
+	}
+	// A copy of the state file is refused by the nonce store
+	if err := os.WriteFile(filepath.Join(dir, defaultSignStateName(1)), state, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err = run([]string{"sign", "round2", "-key", paths[0], "-in", signing, "-out", signing,
+		"-state", filepath.Join(dir, defaultSignStateName(1))}, io.Discard, io.Discard)
+	if !errors.Is(err, session.ErrNonceReused) {
+		t.Errorf("sign round2 with a restored state file: got %v, want ErrNonceReused", err)
+	}
+}
+
+func TestDKGCeremonyRejectsBadMessages(t *testing.T) {
+	dir := t.TempDir()
+	ceremony := filepath.Join(dir, "ceremony.json")
+	msgs := filepath.Join(dir, "msgs")
+	os.Mkdir(msgs, 0o700)
+	runOK(t, "dkg", "init", "-o", ceremony)
+	for id := 1; id <= 3; id++ {
+		runOK(t, "dkg", "round1", "-ceremony", ceremony, "-id", strconv.Itoa(id), "-out", msgs,
+			"-state", filepath.Join(dir, defaultStateName(id)))
+	}
+	finalize := func(id int) error {
+		return run([]string{"dkg", "finalize", "-ceremony", ceremony, "-id", strconv.Itoa(id), "-in", msgs,
+			"-state", filepath.Join(dir, defaultStateName(id)), "-o", filepath.Join(dir, defaultKeyShareName(id))},
+			io.Discard, io.Discard)
+	}
+
+	// Participant 2 sends participant 1 a share that does not match its
+	// commitments
+	path := filepath.Join(msgs, shareName(2, 1))
+	var s keyfile.Share
+	if err := readJSON(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	good := s.Share
+	g, _ := group.New("baby-jubjub")
+	bad, _ := keyfile.DecodeScalar(g, s.Share)
+	bad.Add(bad, g.NewScalar().SetUint64(1))
+	s.Share = keyfile.EncodeHex(bad)
+	rewriteJSON(t, path, &s)
+	if err := finalize(1); err == nil {
+		t.Error("finalize accepted a share that does not match its commitments")
+	}
+
+	// A share from another ceremony
+	s.Share, s.Ceremony = good, "00"
+	rewriteJSON(t, path, &s)
+	if err := finalize(1); err == nil {
+		t.Error("finalize accepted a share from another ceremony")
+	}
+
+	c, _ := keyfile.LoadCeremony(ceremony)
+	s.Ceremony = c.ID
+	rewriteJSON(t, path, &s)
+	if err := finalize(1); err != nil {
+		t.Errorf("finalize with restored messages: %v", err)
+	}
+	if err := finalize(1); err == nil {
+		t.Error("finalize overwrote an existing key share")
+	}
+
+	if err := run([]string{"dkg", "round1", "-ceremony", ceremony, "-id", "4"}, io.Discard, io.Discard); err == nil {
+		t.Error("round1 accepted an ID outside the ceremony")
+	}
+	if err := run([]string{"dkg", "bogus"}, io.Discard, io.Discard); err == nil {
+		t.Error("unknown command accepted")
+	}
+}
+
+func TestTestVectors(t *testing.T) {
+	dir := t.TempDir()
+	runOK(t, "testvectors", "generate", "-seed", "0102", "-out", dir)
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no vectors written")
+	}
+	runOK(t, append([]string{"testvectors", "check"}, paths...)...)
+
+	var v testvectors.Vector
+	if err := readJSON(paths[0], &v); err != nil {
+		t.Fatal(err)
+	}
+	v.Signing.Challenge = v.Signing.Shares[0]
+	rewriteJSON(t, paths[0], &v)
+	if err := run(append([]string{"testvectors", "check"}, paths...), io.Discard, io.Discard); err == nil {
+		t.Error("tampered vector accepted")
+	}
+}
+
+func TestKeyShareCheck(t *testing.T) {
+	dir := t.TempDir()
+	paths := runCeremony(t, dir, 2, 3)
+	runOK(t, append([]string{"keyshare", "check"}, paths...)...)
+	runOK(t, "keyshare", "check", paths[2], paths[0])
+	if err := run([]string{"keyshare", "check", paths[0]}, io.Discard, io.Discard); err == nil {
+		t.Error("a single key share of a 2-of-3 group accepted")
+	}
+
+	var ks keyfile.KeyShare
+	if err := readJSON(paths[1], &ks); err != nil {
+		t.Fatal(err)
+	}
+	other := runCeremony(t, t.TempDir(), 2, 3)
+	var foreign keyfile.KeyShare
+	if err := readJSON(other[1], &foreign); err != nil {
+		t.Fatal(err)
+	}
+	ks.SecretKey, ks.PublicKey = foreign.SecretKey, foreign.PublicKey
+	rewriteJSON(t, paths[1], &ks)
+	// Record the foreign public key everywhere, so that only the
+	// interpolation can catch it
+	for _, path := range paths {
+		var f keyfile.KeyShare
+		if err := readJSON(path, &f); err != nil {
+			t.Fatal(err)
+		}
+		f.PublicKeys[ks.ID] = ks.PublicKey
+		rewriteJSON(t, path, &f)
+	}
+	if err := run(append([]string{"keyshare", "check"}, paths...), io.Discard, io.Discard); err == nil {
+		t.Error("key share of another group accepted")
+	}
+}
+
+func TestAirgap(t *testing.T) {
+	dir := t.TempDir()
+	paths := runCeremony(t, dir, 2, 3)
+	var frames strings.Builder
+	if err := run([]string{"airgap", "encode", "-in", paths[0], "-chunk", "100"}, &frames, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(frames.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("key share encoded in %d frames", len(lines))
+	}
+	// Scanned in two batches, out of order
+	first := filepath.Join(dir, "frames-1.txt")
+	second := filepath.Join(dir, "frames-2.txt")
+	if err := os.WriteFile(first, []byte(strings.Join(lines[1:], "\n")+"\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(lines[0]+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := run([]string{"airgap", "decode", "-o", filepath.Join(dir, "partial.json"), first}, io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "frames 1 still missing") {
+		t.Errorf("err = %v, want frame 1 missing", err)
+	}
+	out := filepath.Join(dir, "decoded.json")
+	runOK(t, "airgap", "decode", "-o", out, first, second)
+	want, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("decoded file differs from the original")
+	}
+}
+
+// rewriteJSON replaces the JSON file at path with v.
+func rewriteJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSON(path, v, true); err != nil {
+		t.Fatal(err)
+	}
+}
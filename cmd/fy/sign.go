@@ -0,0 +1,313 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+)
+
+// messageFlags adds the flags selecting the message to sign or verify.
+func messageFlags(fs *flag.FlagSet) (text, file *string) {
+	text = fs.String("message", "", "message, as a string")
+	file = fs.String("message-file", "", "file holding the message")
+	return text, file
+}
+
+// readMessage returns the message selected by exactly one of the message
+// flags.
+func readMessage(text, file string) ([]byte, error) {
+	switch {
+	case text != "" && file != "":
+		return nil, errors.New("-message and -message-file are mutually exclusive")
+	case file != "":
+		return os.ReadFile(file)
+	case text != "":
+		return []byte(text), nil
+	}
+	return nil, errors.New("no message given: use -message or -message-file")
+}
+
+// signRound1 generates a signer's commitment for a message.
+func signRound1(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sign round1", stderr)
+	keyPath := fs.String("key", "", "key share file from dkg finalize")
+	text, file := messageFlags(fs)
+	outDir := fs.String("out", ".", "directory for the commitment to send")
+	statePath := fs.String("state", "", "secret state file to write (default fy-sign-state-ID.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	message, err := readMessage(*text, *file)
+	if err != nil {
+		return err
+	}
+	c, p, store, err := loadKeyShare(*keyPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if *statePath == "" {
+		*statePath = defaultSignStateName(p.ID())
+	}
+
+	s, err := p.NewSigningSession(rand.Reader, message)
+	if err != nil {
+		return err
+	}
+	commitment := s.Commitment()
+	exported, err := s.Export()
+	if err != nil {
+		return err
+	}
+	state := &signStateFile{Ceremony: c.ID, ID: p.ID(), Session: hex.EncodeToString(exported)}
+	clear(exported)
+	if err := writeJSON(*statePath, state, true); err != nil {
+		return err
+	}
+	out := filepath.Join(*outDir, commitmentName(p.ID()))
+	if err := writeJSON(out, &keyfile.Commitment{
+		Ceremony: c.ID,
+		Message:  keyfile.MessageDigest(message),
+		From:     p.ID(),
+		Hiding:   keyfile.EncodeHex(commitment.HidingPoint),
+		Binding:  keyfile.EncodeHex(commitment.BindingPoint),
+	}, false); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "wrote %s: send it to the other signers and the aggregator\n", out)
+	fmt.Fprintf(stdout, "kept secret nonces in %s for sign round2\n", *statePath)
+	return nil
+}
+
+// signRound2 produces a signer's signature share.
+func signRound2(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sign round2", stderr)
+	keyPath := fs.String("key", "", "key share file from dkg finalize")
+	inDir := fs.String("in", ".", "directory holding the commitments of all signers")
+	outDir := fs.String("out", ".", "directory for the signature share to send")
+	statePath := fs.String("state", "", "secret state file from sign round1 (default fy-sign-state-ID.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	c, p, store, err := loadKeyShare(*keyPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if *statePath == "" {
+		*statePath = defaultSignStateName(p.ID())
+	}
+
+	var state signStateFile
+	if err := readJSON(*statePath, &state); err != nil {
+		return err
+	}
+	if err := checkCeremony(c, *statePath, state.Ceremony); err != nil {
+		return err
+	}
+	exported, err := hex.DecodeString(state.Session)
+	if err != nil {
+		return fmt.Errorf("%s: invalid session", *statePath)
+	}
+	s, err := p.ImportSigningSession(exported)
+	clear(exported)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *statePath, err)
+	}
+	// The nonces must never be used twice: remove them before signing
+	if err := os.Remove(*statePath); err != nil {
+		s.Zeroize()
+		return err
+	}
+
+	digest := keyfile.MessageDigest(s.Message())
+	commitments, _, err := readCommitments(c, p.FROST(), digest, *inDir)
+	if err != nil {
+		s.Zeroize()
+		return err
+	}
+	share, err := s.Sign(commitments)
+	if err != nil {
+		return err
+	}
+	out := filepath.Join(*outDir, signatureShareName(p.ID()))
+	if err := writeJSON(out, &keyfile.SignatureShare{
+		Ceremony: c.ID,
+		Message:  digest,
+		From:     p.ID(),
+		Share:    keyfile.EncodeHex(share.Z),
+	}, false); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %s: send it to the aggregator\n", out)
+	return nil
+}
+
+// aggregate combines the signature shares into a signature.
+func aggregate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("aggregate", stderr)
+	ceremonyPath := fs.String("ceremony", "ceremony.json", "ceremony file")
+	groupKeyHex := fs.String("group-key", "", "group public key, as printed by dkg finalize")
+	text, file := messageFlags(fs)
+	inDir := fs.String("in", ".", "directory holding the commitments and signature shares")
+	out := fs.String("o", "signature.json", "signature file to write")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	message, err := readMessage(*text, *file)
+	if err != nil {
+		return err
+	}
+	c, err := keyfile.LoadCeremony(*ceremonyPath)
+	if err != nil {
+		return err
+	}
+	f, err := c.NewFROST()
+	if err != nil {
+		return err
+	}
+	g, _ := group.New(c.Group)
+	groupKey, err := keyfile.DecodePoint(g, *groupKeyHex)
+	if err != nil {
+		return fmt.Errorf("invalid group key: %w", err)
+	}
+
+	digest := keyfile.MessageDigest(message)
+	commitments, signers, err := readCommitments(c, f, digest, *inDir)
+	if err != nil {
+		return err
+	}
+	shares := make([]*frost.SignatureShare, len(commitments))
+	for i, from := range signers {
+		path := filepath.Join(*inDir, signatureShareName(from))
+		var sf keyfile.SignatureShare
+		if err := readJSON(path, &sf); err != nil {
+			return err
+		}
+		if err := checkCeremony(c, path, sf.Ceremony); err != nil {
+			return err
+		}
+		if sf.Message != digest || sf.From != from {
+			return fmt.Errorf("%s: share from %d for message %s", path, sf.From, sf.Message)
+		}
+		z, err := keyfile.DecodeScalar(g, sf.Share)
+		if err != nil {
+			return fmt.Errorf("%s: invalid share: %w", path, err)
+		}
+		shares[i] = &frost.SignatureShare{ID: commitments[i].ID, Z: z}
+	}
+
+	sig, err := session.Aggregate(f, message, commitments, shares)
+	if err != nil {
+		return err
+	}
+	if err := session.Verify(f, message, sig, groupKey); err != nil {
+		return fmt.Errorf("aggregated signature is invalid for the group key: %w", err)
+	}
+	if err := writeJSON(*out, &keyfile.Signature{
+		Ceremony:  c.ID,
+		Message:   digest,
+		Signature: hex.EncodeToString(sig.Bytes()),
+	}, false); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote signature of %d signers to %s\n", len(shares), *out)
+	return nil
+}
+
+// verify checks a signature file against a message and group key.
+func verify(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	ceremonyPath := fs.String("ceremony", "ceremony.json", "ceremony file")
+	groupKeyHex := fs.String("group-key", "", "group public key, as printed by dkg finalize")
+	text, file := messageFlags(fs)
+	sigPath := fs.String("signature", "signature.json", "signature file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	message, err := readMessage(*text, *file)
+	if err != nil {
+		return err
+	}
+	c, err := keyfile.LoadCeremony(*ceremonyPath)
+	if err != nil {
+		return err
+	}
+	f, err := c.NewFROST()
+	if err != nil {
+		return err
+	}
+	g, _ := group.New(c.Group)
+	groupKey, err := keyfile.DecodePoint(g, *groupKeyHex)
+	if err != nil {
+		return fmt.Errorf("invalid group key: %w", err)
+	}
+	var sf keyfile.Signature
+	if err := readJSON(*sigPath, &sf); err != nil {
+		return err
+	}
+	data, err := hex.DecodeString(sf.Signature)
+	if err != nil {
+		return fmt.Errorf("%s: invalid signature encoding", *sigPath)
+	}
+	sig, err := f.ParseSignature(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *sigPath, err)
+	}
+	if err := session.Verify(f, message, sig, groupKey); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "signature OK")
+	return nil
+}
+
+// readCommitments reads the commitments of all signers for the message
+// with the given digest from dir, in ID order, and returns them with the
+// signers' IDs.
+func readCommitments(c *keyfile.Ceremony, f *frost.FROST, digest, dir string) ([]*frost.SigningCommitment, []int, error) {
+	g, err := group.New(c.Group)
+	if err != nil {
+		return nil, nil, err
+	}
+	var commitments []*frost.SigningCommitment
+	var signers []int
+	for _, id := range c.IDs() {
+		path := filepath.Join(dir, commitmentName(id))
+		var cf keyfile.Commitment
+		if err := readJSON(path, &cf); errors.Is(err, os.ErrNotExist) {
+			continue // not a signer
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if err := checkCeremony(c, path, cf.Ceremony); err != nil {
+			return nil, nil, err
+		}
+		if cf.Message != digest {
+			return nil, nil, fmt.Errorf("%s: commitment for message %s, not %s", path, cf.Message, digest)
+		}
+		if cf.From != id {
+			return nil, nil, fmt.Errorf("%s: commitment from participant %d", path, cf.From)
+		}
+		commitment, err := cf.Decode(g)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		commitments = append(commitments, commitment)
+		signers = append(signers, id)
+	}
+	if err := f.CheckCommitments(commitments); err != nil {
+		return nil, nil, fmt.Errorf("commitments in %s: %w", dir, err)
+	}
+	return commitments, signers, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/f3rmion/fy/testvectors"
+)
+
+// testvectorsGenerate writes a deterministic test vector for every group
+// and hasher to a directory.
+func testvectorsGenerate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("testvectors generate", stderr)
+	threshold := fs.Int("threshold", 2, "number of signers")
+	total := fs.Int("total", 3, "number of participants")
+	seedHex := fs.String("seed", "", "hex-encoded seed (default: empty)")
+	out := fs.String("out", ".", "directory to write the vectors to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	seed, err := hex.DecodeString(*seedHex)
+	if err != nil {
+		return fmt.Errorf("invalid -seed: %w", err)
+	}
+	vectors, err := testvectors.GenerateAll(*threshold, *total, seed)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	for _, v := range vectors {
+		path := filepath.Join(*out, v.Name())
+		if err := writeJSON(path, v, false); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "wrote", path)
+	}
+	return nil
+}
+
+// testvectorsCheck recomputes the test vectors in the given files.
+func testvectorsCheck(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("testvectors check", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no vector files given")
+	}
+	failed := 0
+	for _, path := range fs.Args() {
+		v, err := testvectors.Load(path)
+		if err == nil {
+			err = v.Check()
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "FAIL %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "ok   %s\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d vector files failed", failed, fs.NArg())
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package frost
+
+import (
+	"hash"
+	"slices"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// BroadcastAccumulator folds round 1 broadcasts, one at a time, into the
+// values [FROST.Finalize] derives from them: the group key, the transcript
+// digest, and the sum of all commitment polynomials, from which every
+// verification share follows. It keeps threshold points however many
+// broadcasts it has seen, so that a DKG with thousands of participants
+// can stream the broadcasts from disk or the network instead of holding
+// all n·t commitments, and finish with [FROST.FinalizeAccumulated].
+//
+// Broadcasts must be added in strictly ascending ID order, the order in
+// which the transcript digest hashes them; the order also rules out
+// duplicate IDs without remembering earlier ones. Only an accumulator
+// created for a participant remembers the IDs, so that
+// FinalizeAccumulated can check them against the shares it received.
+type BroadcastAccumulator struct {
+	f   *FROST
+	own *Participant
+
+	last  group.Scalar
+	count int
+	ids   map[string]group.Scalar // IDs added, if own is set
+	// sum[k] is the sum of the k-th commitments of all broadcasts
+	sum        []group.Point
+	transcript hash.Hash
+}
+
+// NewBroadcastAccumulator returns an empty accumulator. For a participant
+// that will finalize with it, p is that participant, whose own broadcast
+// is then checked against its commitments as [FROST.Finalize] does; a
+// coordinator that only needs the group key, digest or verification
+// shares passes nil.
+func (f *FROST) NewBroadcastAccumulator(p *Participant) *BroadcastAccumulator {
+	sum := make([]group.Point, f.threshold)
+	for k := range sum {
+		sum[k] = f.group.NewPoint()
+	}
+	a := &BroadcastAccumulator{
+		f:          f,
+		own:        p,
+		sum:        sum,
+		transcript: f.newTranscript(),
+	}
+	if p != nil {
+		a.ids = make(map[string]group.Scalar)
+	}
+	return a
+}
+
+// Add adds the next broadcast. It returns an *[InputError] for a
+// malformed broadcast or one whose ID is below the previous one, and a
+// *[DuplicateIDError] for a repeated ID or a broadcast that claims the
+// participant's ID with other commitments. A rejected broadcast leaves
+// the accumulator unchanged. Add does not retain b.
+func (a *BroadcastAccumulator) Add(b *Round1Data) error {
+	if err := a.f.checkBroadcast("broadcast", b); err != nil {
+		return err
+	}
+	if a.last != nil {
+		switch c := compareIDs(a.last, b.ID); {
+		case c == 0:
+			return &DuplicateIDError{ID: b.ID.Clone()}
+		case c > 0:
+			return &InputError{Field: "broadcast.ID", Reason: "is below the previous ID " + idString(a.last)}
+		}
+	}
+	if a.own != nil && b.ID.Equal(a.own.id) && !slices.EqualFunc(b.Commitments, a.own.commitments, group.Point.Equal) {
+		return &DuplicateIDError{ID: b.ID.Clone()}
+	}
+	a.add(b)
+	return nil
+}
+
+// add adds a checked broadcast.
+func (a *BroadcastAccumulator) add(b *Round1Data) {
+	for k, c := range b.Commitments {
+		a.sum[k].Add(a.sum[k], c)
+	}
+	writeBroadcast(a.transcript, b)
+	a.last = b.ID.Clone()
+	a.count++
+	if a.ids != nil {
+		a.ids[string(b.ID.Bytes())] = a.last
+	}
+}
+
+// Len returns the number of broadcasts added.
+func (a *BroadcastAccumulator) Len() int {
+	return a.count
+}
+
+// GroupKey returns the group public key, the sum of the constant-term
+// commitments of the broadcasts added so far.
+func (a *BroadcastAccumulator) GroupKey() group.Point {
+	return a.sum[0].Clone()
+}
+
+// TranscriptDigest returns the digest [FROST.TranscriptDigest] computes
+// for the broadcasts added so far.
+func (a *BroadcastAccumulator) TranscriptDigest() []byte {
+	return a.transcript.Sum(nil)
+}
+
+// VerificationShare returns the verification share of participant id,
+// as [FROST.VerificationShare] computes it from the broadcasts added so
+// far, with threshold rather than n·threshold point multiplications.
+func (a *BroadcastAccumulator) VerificationShare(id int) group.Point {
+	return a.f.evalCommitments(a.sum, a.f.scalarFromInt(id))
+}
+
+// FinalizeAccumulated is [FROST.Finalize] with the broadcasts of all
+// participants fed through acc, which must have been created for p. As
+// with Finalize, they must be exactly p's own broadcast and those of the
+// participants whose shares p received.
+func (f *FROST) FinalizeAccumulated(p *Participant, acc *BroadcastAccumulator) (*KeyShare, error) {
+	if err := checkNotNil(field{"participant", p}, field{"acc", acc}); err != nil {
+		return nil, err
+	}
+	if p.destroyed() {
+		return nil, errDestroyed
+	}
+	if acc.own != p {
+		return nil, &InputError{Field: "acc", Reason: "was created for another participant"}
+	}
+	if acc.count == 0 {
+		return nil, &InputError{Field: "acc", Reason: "holds no broadcasts"}
+	}
+	if len(acc.sum) != f.threshold {
+		return nil, &InputError{Field: "acc", Reason: "was created for another threshold"}
+	}
+	if err := p.checkCoverage("acc", acc.ids); err != nil {
+		return nil, err
+	}
+	return f.finalize(p, acc), nil
+}
@@ -0,0 +1,87 @@
+package frost
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// HasherCapabilities describes the side-channel guarantees of a Hasher.
+// The zero value promises nothing.
+type HasherCapabilities struct {
+	// ConstantTimeNonces reports that H3, which hashes the secret key
+	// share in [FROST.SignRound1Hedged], runs in time independent of the
+	// values of its inputs, given a group with constant-time scalars. Only
+	// the input lengths may affect its running time.
+	ConstantTimeNonces bool
+}
+
+// HasherCapabilityReporter is implemented by hashers that declare their
+// side-channel guarantees. All hashers in this package implement it.
+type HasherCapabilityReporter interface {
+	Capabilities() HasherCapabilities
+}
+
+// HasherCapabilitiesOf returns the capabilities h declares, or the zero
+// value if it does not implement [HasherCapabilityReporter].
+func HasherCapabilitiesOf(h Hasher) HasherCapabilities {
+	if r, ok := h.(HasherCapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return HasherCapabilities{}
+}
+
+// CheckConstantTime reports whether f runs in constant-time mode, that is
+// whether every computation on secrets (key generation, key shares,
+// nonces and signature shares) runs in time independent of their values.
+// It returns an error naming the first missing guarantee otherwise.
+//
+// The protocol code itself does not branch on secrets or index memory
+// with them: secrets only enter scalar arithmetic, ScalarBaseMult, and H3
+// in hedged signing, while maps keyed by participant ID, big.Int
+// conversions and multi-scalar multiplications only handle public data.
+// Constant-time mode therefore holds when the group declares
+// constant-time scalars and scalar multiplication, see
+// [group.Capabilities], and the hasher declares constant-time nonces.
+func (f *FROST) CheckConstantTime() error {
+	caps := group.CapabilitiesOf(f.group)
+	if !caps.ConstantTimeScalars {
+		return fmt.Errorf("group %s does not declare constant-time scalar arithmetic", f.group.Name())
+	}
+	if !caps.ConstantTimeScalarMult {
+		return fmt.Errorf("group %s does not declare constant-time scalar multiplication", f.group.Name())
+	}
+	if !HasherCapabilitiesOf(f.hasher).ConstantTimeNonces {
+		return errors.New("hasher does not declare constant-time nonce derivation")
+	}
+	return nil
+}
+
+// constantTime is the capability of the hashers in this package, which
+// are built from constant-time hash functions and group scalar reduction.
+var constantTime = HasherCapabilities{ConstantTimeNonces: true}
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *SHA256Hasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *Blake2bHasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *Keccak256Hasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *SHA512Hasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *SHAKE256Hasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *PoseidonHasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *TaggedHasher) Capabilities() HasherCapabilities { return constantTime }
+
+// Capabilities implements HasherCapabilityReporter.
+func (h *HKDFNonceHasher) Capabilities() HasherCapabilities { return constantTime }
@@ -1,10 +1,18 @@
 package frost
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash"
 	"io"
+	"maps"
+	"slices"
 
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
 )
 
 // Round1Data contains the public data broadcast by a participant during
@@ -34,39 +42,122 @@ type Round1PrivateData struct {
 	Share group.Scalar
 }
 
+// Zeroize wipes the share once the recipient has processed it with
+// [FROST.Round2ReceiveShare], which keeps its own copy.
+func (d *Round1PrivateData) Zeroize() {
+	memwipe.Scalars(d.Share)
+}
+
 // Participant holds the state for a single participant during the DKG protocol.
 // Create instances using [FROST.NewParticipant].
 type Participant struct {
 	id             group.Scalar
 	coefficients   []group.Scalar          // our secret polynomial
+	shares         []group.Scalar          // its values at 1 to n
 	commitments    []group.Point           // public commitments
 	receivedShares map[string]group.Scalar // shares from others
 }
 
+// errDestroyed is returned when a destroyed Participant is used.
+var errDestroyed = errors.New("participant has been destroyed")
+
+// DuplicateIDError reports that two DKG messages claim the same
+// participant ID: two broadcasts with one ID, a second share from the same
+// sender, or another participant using the receiver's own ID. Completing
+// the DKG with such messages would mix the polynomials of different
+// dealers, so the ceremony must be aborted.
+type DuplicateIDError struct {
+	// ID is the conflicting participant ID.
+	ID group.Scalar
+}
+
+// Error implements the error interface.
+func (e *DuplicateIDError) Error() string {
+	return "frost: duplicate participant ID " + idString(e.ID)
+}
+
+// InvalidShareError reports a private share that does not match its
+// sender's commitments. The sender is either faulty or malicious.
+type InvalidShareError struct {
+	// ID is the sender of the share.
+	ID group.Scalar
+}
+
+// Error implements the error interface.
+func (e *InvalidShareError) Error() string {
+	return "frost: invalid share from participant " + idString(e.ID)
+}
+
+// Zeroize wipes the secret polynomial and the received shares. p cannot
+// be used for the DKG afterwards. [FROST.Finalize] calls it through
+// [Participant.Destroy] once the key share is derived.
+func (p *Participant) Zeroize() {
+	memwipe.Scalars(p.coefficients...)
+	memwipe.Scalars(p.shares...)
+	for _, s := range p.receivedShares {
+		memwipe.Scalars(s)
+	}
+	clear(p.receivedShares)
+}
+
+// Destroy wipes the secret state of p with [Participant.Zeroize] and
+// releases it. Afterwards [FROST.Round2ReceiveShare] and [FROST.Finalize]
+// return an error for p, and [FROST.Round1PrivateSend] panics. Call it to
+// abandon a DKG that will not be finalized; Finalize destroys p itself.
+func (p *Participant) Destroy() {
+	p.Zeroize()
+	p.coefficients = nil
+	p.shares = nil
+	p.receivedShares = nil
+}
+
+// destroyed reports whether p has been destroyed.
+func (p *Participant) destroyed() bool {
+	return p.coefficients == nil
+}
+
 // NewParticipant creates a new participant for the DKG protocol.
 //
 // The id parameter must be a unique integer from 1 to n (total participants).
-// The random reader r is used to generate the participant's secret polynomial.
+// Uniqueness cannot be checked here; [FROST.Round2ReceiveShare] and
+// [FROST.Finalize] return a *[DuplicateIDError] when two participants
+// claim the same ID.
+// The random reader r is used to generate the participant's secret
+// polynomial; like [FROST.SignRound1], NewParticipant fails with
+// [ErrWeakRandomness] if r is evidently broken. The polynomial is also
+// evaluated at 1 to n in one pass, for [FROST.Round1PrivateSend].
 func (f *FROST) NewParticipant(r io.Reader, id int) (*Participant, error) {
+	// ID 0 would make the share the secret's constant term
+	if id < 1 {
+		return nil, fmt.Errorf("participant ID must be positive, got %d", id)
+	}
+
 	// Generate random polynomial of degree t-1
+	r = checkedReader{r}
 	coeffs := make([]group.Scalar, f.threshold)
 	for i := 0; i < f.threshold; i++ {
 		c, err := f.group.RandomScalar(r)
 		if err != nil {
+			memwipe.Scalars(coeffs[:i]...)
 			return nil, err
 		}
 		coeffs[i] = c
 	}
+	if err := checkDistinct(coeffs...); err != nil {
+		memwipe.Scalars(coeffs...)
+		return nil, err
+	}
 
 	// Compute commitments: C_i = coeffs[i] * G
 	commits := make([]group.Point, f.threshold)
 	for i, c := range coeffs {
-		commits[i] = f.group.NewPoint().ScalarMult(c, f.group.Generator())
+		commits[i] = f.group.ScalarBaseMult(c)
 	}
 
 	return &Participant{
-		id:             f.scalarFromInt(id),
+		id:             f.Identifier(id),
 		coefficients:   coeffs,
+		shares:         f.evalPolynomialRange(coeffs, f.total),
 		commitments:    commits,
 		receivedShares: make(map[string]group.Scalar),
 	}, nil
@@ -76,81 +167,386 @@ func (f *FROST) NewParticipant(r io.Reader, id int) (*Participant, error) {
 // broadcast to all other participants. This includes commitments to
 // the participant's secret polynomial.
 func (p *Participant) Round1Broadcast() *Round1Data {
+	commitments := make([]group.Point, len(p.commitments))
+	for i, c := range p.commitments {
+		commitments[i] = c.Clone()
+	}
 	return &Round1Data{
-		ID:          p.id,
-		Commitments: p.commitments,
+		ID:          p.id.Clone(),
+		Commitments: commitments,
 	}
 }
 
 // Round1PrivateSend computes and returns the private share that participant p
 // must send to the specified recipient. This data must be transmitted over a
-// secure, authenticated channel.
+// secure, authenticated channel. All shares must be sent before
+// [FROST.Finalize] is called for p, which wipes its polynomial.
+//
+// The shares for recipients 1 to n are evaluated together when p is
+// created, see [FROST.NewParticipant], and only copied here.
 func (f *FROST) Round1PrivateSend(p *Participant, recipientID int) *Round1PrivateData {
-	toID := f.scalarFromInt(recipientID)
-	share := f.evalPolynomial(p.coefficients, toID)
+	if p.destroyed() {
+		panic("frost: Round1PrivateSend: " + errDestroyed.Error())
+	}
+	toID := f.Identifier(recipientID)
+	var share group.Scalar
+	if recipientID >= 1 && recipientID <= len(p.shares) {
+		share = p.shares[recipientID-1].Clone()
+	} else {
+		share = f.evalPolynomial(p.coefficients, toID)
+	}
 
 	return &Round1PrivateData{
-		FromID: p.id,
+		FromID: p.id.Clone(),
 		ToID:   toID,
 		Share:  share,
 	}
 }
 
 // Round2ReceiveShare verifies a received share against the sender's public
-// commitments and stores it if valid. Returns an error if the share fails
-// verification, indicating a potentially malicious sender.
+// commitments and stores it if valid. Returns an *[InvalidShareError] if
+// the share fails verification, indicating a potentially malicious
+// sender, and an
+// *[InputError] if the share is addressed to another participant or its
+// sender ID is zero, as for a mis-routed message.
 //
 // The verification uses Feldman's VSS scheme: it checks that
 // share * G == sum(Commitment[i] * recipientID^i).
 func (f *FROST) Round2ReceiveShare(p *Participant, data *Round1PrivateData, senderCommitments []group.Point) error {
-	// Verify: share * G == sum(commitments[i] * recipientID^i)
-	lhs := f.group.NewPoint().ScalarMult(data.Share, f.group.Generator())
+	if p != nil && p.destroyed() {
+		return errDestroyed
+	}
+	if err := f.checkDealerInputs(p, data, senderCommitments); err != nil {
+		return err
+	}
+	key := string(data.FromID.Bytes())
+	if _, ok := p.receivedShares[key]; ok || data.FromID.Equal(p.id) {
+		return &DuplicateIDError{ID: data.FromID.Clone()}
+	}
+
+	if !f.verifyShare(data, senderCommitments) {
+		return &InvalidShareError{ID: data.FromID.Clone()}
+	}
 
-	rhs := f.group.NewPoint()
-	xPower := f.scalarFromInt(1)
+	// Store the share
+	p.receivedShares[key] = data.Share.Clone()
+	return nil
+}
 
-	for _, commit := range senderCommitments {
-		term := f.group.NewPoint().ScalarMult(xPower, commit)
-		rhs = f.group.NewPoint().Add(rhs, term)
-		xPower = f.group.NewScalar().Mul(xPower, data.ToID)
+// Round2ReceiveShares is like [FROST.Round2ReceiveShare] for the shares of
+// several senders at once, with senderCommitments[i] the commitments of
+// the sender of shares[i]. The shares are verified concurrently on up to
+// GOMAXPROCS goroutines, or as many as set with [FROST.WithParallelism],
+// so that the O(n·t) scalar multiplications of a large DKG are spread
+// over all cores.
+//
+// Either all shares are stored or none is. If several shares are
+// invalid, the *[InvalidShareError] names the first of them in shares.
+func (f *FROST) Round2ReceiveShares(p *Participant, shares []*Round1PrivateData, senderCommitments [][]group.Point) error {
+	if err := checkNotNil(field{"participant", p}); err != nil {
+		return err
+	}
+	if p.destroyed() {
+		return errDestroyed
+	}
+	if len(senderCommitments) != len(shares) {
+		return &InputError{Field: "senderCommitments", Reason: "length differs from shares"}
+	}
+	seen := make(map[string]bool, len(shares))
+	for i, data := range shares {
+		if err := f.checkDealerInputs(p, data, senderCommitments[i]); err != nil {
+			return err
+		}
+		key := string(data.FromID.Bytes())
+		if _, ok := p.receivedShares[key]; ok || seen[key] || data.FromID.Equal(p.id) {
+			return &DuplicateIDError{ID: data.FromID.Clone()}
+		}
+		seen[key] = true
 	}
 
-	if !lhs.Equal(rhs) {
-		return errors.New("invalid share from participant")
+	valid := make([]bool, len(shares))
+	parallel(len(shares), f.workers(len(shares), 1), func(i int) {
+		valid[i] = f.verifyShare(shares[i], senderCommitments[i])
+	})
+	if i := slices.Index(valid, false); i >= 0 {
+		return &InvalidShareError{ID: shares[i].FromID.Clone()}
 	}
 
-	// Store the share
-	key := string(data.FromID.Bytes())
-	p.receivedShares[key] = data.Share
+	for _, data := range shares {
+		p.receivedShares[string(data.FromID.Bytes())] = data.Share.Clone()
+	}
 	return nil
 }
 
+// verifyShare reports whether data.Share * G equals the sender's
+// commitment polynomial evaluated at the recipient:
+// sum(commitments[i] * recipientID^i).
+func (f *FROST) verifyShare(data *Round1PrivateData, senderCommitments []group.Point) bool {
+	lhs := f.group.ScalarBaseMult(data.Share)
+	rhs := f.evalCommitments(senderCommitments, data.ToID)
+	return lhs.Equal(rhs)
+}
+
 // Finalize completes the DKG protocol for participant p, computing their
 // final key share. This should be called after all shares have been received
-// and verified via [FROST.Round2ReceiveShare].
+// and verified via [FROST.Round2ReceiveShare]: allBroadcasts must hold
+// p's own broadcast and exactly the broadcasts of the participants whose
+// shares p received, or Finalize returns an *[InputError] and leaves p
+// intact.
 //
-// The returned [KeyShare] contains the participant's secret key share and
-// the group's combined public key, which is the same for all participants.
+// The returned [KeyShare] contains the participant's secret key share,
+// the group's combined public key, which is the same for all participants,
+// and the digest of the DKG transcript, see [FROST.TranscriptDigest].
+// Finalize then destroys p with [Participant.Destroy], so that the secret
+// polynomial and the received shares do not outlive the key share; p must
+// therefore have sent all its private shares before.
 func (f *FROST) Finalize(p *Participant, allBroadcasts []*Round1Data) (*KeyShare, error) {
+	if p == nil {
+		return nil, &InputError{Field: "participant", Reason: "is nil"}
+	}
+	if p.destroyed() {
+		return nil, errDestroyed
+	}
+	if err := f.checkBroadcasts(allBroadcasts); err != nil {
+		return nil, err
+	}
+	if err := p.checkBroadcastIDs(allBroadcasts); err != nil {
+		return nil, err
+	}
+	ids := make(map[string]group.Scalar, len(allBroadcasts))
+	for _, b := range allBroadcasts {
+		ids[string(b.ID.Bytes())] = b.ID
+	}
+	if err := p.checkCoverage("allBroadcasts", ids); err != nil {
+		return nil, err
+	}
+	acc := f.NewBroadcastAccumulator(p)
+	for _, b := range sortBroadcasts(allBroadcasts) {
+		acc.add(b)
+	}
+	return f.finalize(p, acc), nil
+}
+
+// finalize derives p's key share once the broadcasts are checked and
+// accumulated in acc, and destroys p.
+func (f *FROST) finalize(p *Participant, acc *BroadcastAccumulator) *KeyShare {
 	// Sum all received shares (including our own)
 	secretKey := f.evalPolynomial(p.coefficients, p.id)
 	for _, share := range p.receivedShares {
-		secretKey = f.group.NewScalar().Add(secretKey, share)
+		secretKey.Add(secretKey, share)
 	}
 
-	// Compute public key share
-	publicKey := f.group.NewPoint().ScalarMult(secretKey, f.group.Generator())
+	p.Destroy()
 
-	// Compute group public key: sum of all constant term commitments
-	groupKey := f.group.NewPoint()
-	for _, broadcast := range allBroadcasts {
-		groupKey = f.group.NewPoint().Add(groupKey, broadcast.Commitments[0])
-	}
+	// Compute public key share
+	publicKey := f.group.ScalarBaseMult(secretKey)
 
 	return &KeyShare{
-		ID:        p.id,
+		ID:        p.id.Clone(),
 		SecretKey: secretKey,
 		PublicKey: publicKey,
-		GroupKey:  groupKey,
-	}, nil
+		// The group key is the sum of all constant term commitments
+		GroupKey:         acc.GroupKey(),
+		TranscriptDigest: acc.TranscriptDigest(),
+	}
+}
+
+// transcriptDomain separates DKG transcript digests from other uses of
+// SHA-256.
+const transcriptDomain = "FROST-DKG-TRANSCRIPT-v1"
+
+// TranscriptDigest returns a digest of a DKG transcript: the group, the
+// threshold parameters and every round 1 broadcast, sorted by participant
+// ID so that the order of allBroadcasts does not matter. It is the value
+// [FROST.Finalize] stores in [KeyShare].TranscriptDigest.
+//
+// Participants that saw the same broadcasts obtain the same digest.
+// Comparing digests out of band after the DKG, before anyone signs,
+// detects a dealer that sent different commitments to different
+// participants, which would leave them with inconsistent key shares or
+// group keys.
+func (f *FROST) TranscriptDigest(allBroadcasts []*Round1Data) ([]byte, error) {
+	if err := f.checkBroadcastSet(allBroadcasts); err != nil {
+		return nil, err
+	}
+	return f.transcriptDigest(allBroadcasts), nil
+}
+
+// checkBroadcastSet checks the broadcasts of a whole DKG as
+// checkBroadcasts does, and that no two carry the same ID.
+func (f *FROST) checkBroadcastSet(allBroadcasts []*Round1Data) error {
+	if err := f.checkBroadcasts(allBroadcasts); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(allBroadcasts))
+	for _, b := range allBroadcasts {
+		key := string(b.ID.Bytes())
+		if seen[key] {
+			return &DuplicateIDError{ID: b.ID.Clone()}
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// transcriptDigest computes TranscriptDigest for validated broadcasts.
+func (f *FROST) transcriptDigest(allBroadcasts []*Round1Data) []byte {
+	h := f.newTranscript()
+	for _, b := range sortBroadcasts(allBroadcasts) {
+		writeBroadcast(h, b)
+	}
+	return h.Sum(nil)
+}
+
+// sortBroadcasts returns allBroadcasts sorted by participant ID, the
+// order in which the transcript hashes them.
+func sortBroadcasts(allBroadcasts []*Round1Data) []*Round1Data {
+	return slices.SortedFunc(slices.Values(allBroadcasts), func(a, b *Round1Data) int {
+		return compareIDs(a.ID, b.ID)
+	})
+}
+
+// newTranscript returns a hash of the transcript header: the domain, the
+// group and the threshold parameters.
+func (f *FROST) newTranscript() hash.Hash {
+	h := sha256.New()
+	writeLenPrefixed(h, []byte(transcriptDomain))
+	writeLenPrefixed(h, []byte(f.group.Name()))
+	writeUint64(h, uint64(f.threshold))
+	writeUint64(h, uint64(f.total))
+	return h
+}
+
+// writeBroadcast adds a broadcast to a transcript hash.
+func writeBroadcast(h hash.Hash, b *Round1Data) {
+	writeLenPrefixed(h, b.ID.Bytes())
+	writeUint64(h, uint64(len(b.Commitments)))
+	for _, c := range b.Commitments {
+		writeLenPrefixed(h, c.Bytes())
+	}
+}
+
+// writeUint64 writes v to h as 8 big-endian bytes.
+func writeUint64(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
+
+// writeLenPrefixed writes b to h, preceded by its length.
+func writeLenPrefixed(h hash.Hash, b []byte) {
+	writeUint64(h, uint64(len(b)))
+	h.Write(b)
+}
+
+// compareIDs orders participant IDs numerically if the scalars convert to
+// big.Int, and by their encoding otherwise.
+func compareIDs(a, b group.Scalar) int {
+	if ai, ok := a.(group.BigIntScalar); ok {
+		if bi, ok := b.(group.BigIntScalar); ok {
+			return ai.BigInt().Cmp(bi.BigInt())
+		}
+	}
+	return bytes.Compare(a.Bytes(), b.Bytes())
+}
+
+// checkBroadcastIDs returns a *DuplicateIDError if two broadcasts carry
+// the same ID, or a broadcast carries p's ID with commitments other than
+// p's own.
+func (p *Participant) checkBroadcastIDs(allBroadcasts []*Round1Data) error {
+	seen := make(map[string]bool, len(allBroadcasts))
+	for _, b := range allBroadcasts {
+		key := string(b.ID.Bytes())
+		if seen[key] {
+			return &DuplicateIDError{ID: b.ID.Clone()}
+		}
+		seen[key] = true
+		if b.ID.Equal(p.id) && !slices.EqualFunc(b.Commitments, p.commitments, group.Point.Equal) {
+			return &DuplicateIDError{ID: b.ID.Clone()}
+		}
+	}
+	return nil
+}
+
+// checkCoverage returns an *InputError unless ids, the IDs of the
+// broadcasts in the argument called name, are exactly p's own and those
+// of the senders of its received shares. A missing broadcast or share
+// would leave p with a secret key share that does not match the group key
+// the other participants derive.
+func (p *Participant) checkCoverage(name string, ids map[string]group.Scalar) error {
+	if _, ok := ids[string(p.id.Bytes())]; !ok {
+		return &InputError{Field: name, Reason: "lacks the participant's own broadcast"}
+	}
+	for _, key := range slices.Sorted(maps.Keys(p.receivedShares)) {
+		if _, ok := ids[key]; !ok {
+			return &InputError{Field: name, Reason: "lacks the broadcast of a participant whose share was received"}
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(ids)) {
+		if _, ok := p.receivedShares[key]; !ok && key != string(p.id.Bytes()) {
+			return &InputError{Field: name, Reason: "has a broadcast from participant " + idString(ids[key]) + ", whose share was not received"}
+		}
+	}
+	return nil
+}
+
+// VerificationShare computes the public verification share of participant
+// id from the round 1 broadcasts of all participants. The result equals
+// the PublicKey that participant derives in [FROST.Finalize], but can be
+// computed by anyone holding the public broadcasts.
+//
+// Each broadcast's commitment polynomial is evaluated at id and the
+// results are summed: Y_id = sum_i sum_k Commitments_i[k] * id^k.
+func (f *FROST) VerificationShare(allBroadcasts []*Round1Data, id int) group.Point {
+	x := f.scalarFromInt(id)
+	var scalars []group.Scalar
+	var points []group.Point
+	for _, b := range allBroadcasts {
+		scalars = append(scalars, f.powers(x, len(b.Commitments))...)
+		points = append(points, b.Commitments...)
+	}
+	return f.group.MultiScalarMult(scalars, points)
+}
+
+// VerificationShares computes the public verification share of every
+// participant with a broadcast in allBroadcasts, in the same order. It
+// sums the commitment polynomials coefficient by coefficient first, so
+// that each share is one evaluation of a single polynomial of threshold
+// terms: all n shares cost O(n·t) point operations, where calling
+// [FROST.VerificationShare] for each costs O(n²·t). Coordinators can
+// compute the shares once after the DKG and keep them for verifying
+// signature shares and attributing blame.
+func (f *FROST) VerificationShares(allBroadcasts []*Round1Data) ([]group.Point, error) {
+	if err := f.checkBroadcastSet(allBroadcasts); err != nil {
+		return nil, err
+	}
+	sum := make([]group.Point, f.threshold)
+	for k := range sum {
+		sum[k] = f.group.NewPoint()
+		for _, b := range allBroadcasts {
+			sum[k].Add(sum[k], b.Commitments[k])
+		}
+	}
+	shares := make([]group.Point, len(allBroadcasts))
+	for i, b := range allBroadcasts {
+		shares[i] = f.evalCommitments(sum, b.ID)
+	}
+	return shares, nil
+}
+
+// evalCommitments evaluates a commitment polynomial at x:
+// sum(commitments[k] * x^k).
+func (f *FROST) evalCommitments(commitments []group.Point, x group.Scalar) group.Point {
+	return f.group.MultiScalarMult(f.powers(x, len(commitments)), commitments)
+}
+
+// powers returns [1, x, x^2, ..., x^(n-1)].
+func (f *FROST) powers(x group.Scalar, n int) []group.Scalar {
+	out := make([]group.Scalar, n)
+	xPower := f.one
+	for i := range out {
+		out[i] = xPower
+		xPower = f.group.NewScalar().Mul(xPower, x)
+	}
+	return out
 }
@@ -28,6 +28,9 @@
 //  3. Signature shares are aggregated into a final signature using [FROST.Aggregate].
 //  4. Anyone can verify the signature using [FROST.Verify].
 //
+// Many signatures can be checked together with [FROST.VerifyBatch], which
+// uses the group's multi-scalar multiplication.
+//
 // # Example
 //
 // Basic usage with 2-of-3 threshold:
@@ -2,8 +2,12 @@ package frost
 
 import (
 	"errors"
+	"fmt"
+	"runtime"
+	"sync"
 
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
 )
 
 // FROST holds the cryptographic group and threshold parameters for the
@@ -13,6 +17,15 @@ type FROST struct {
 	hasher    Hasher
 	threshold int // t - minimum signers needed
 	total     int // n - total participants
+
+	// parallelism caps the goroutines of batch operations; zero means
+	// GOMAXPROCS
+	parallelism int
+
+	// one and ids hold the scalar 1 and the identifiers of participants
+	// 1 to n, derived once; they are shared and must never be modified
+	one group.Scalar
+	ids []group.Scalar
 }
 
 // KeyShare represents a participant's share of the distributed secret key.
@@ -32,6 +45,17 @@ type KeyShare struct {
 	// GroupKey is the combined group public key. This is the same for all
 	// participants and is used to verify signatures.
 	GroupKey group.Point
+
+	// TranscriptDigest is the digest of the DKG transcript computed by
+	// [FROST.Finalize]. Participants should compare it out of band
+	// before signing; see [FROST.TranscriptDigest].
+	TranscriptDigest []byte
+}
+
+// Zeroize wipes the secret key share with [group.Scalar.Zeroize]. The key
+// share must not be used afterwards.
+func (k *KeyShare) Zeroize() {
+	memwipe.Scalars(k.SecretKey)
 }
 
 // Signature represents a Schnorr signature produced by the FROST protocol.
@@ -71,32 +95,214 @@ func NewWithHasher(g group.Group, threshold, total int, hasher Hasher) (*FROST,
 	if total < threshold {
 		return nil, errors.New("total must be >= threshold")
 	}
+	if err := ValidateHasher(hasher); err != nil {
+		return nil, fmt.Errorf("invalid hasher: %w", err)
+	}
 
+	ids := make([]group.Scalar, min(total, maxCachedIDs))
+	for i := range ids {
+		ids[i] = g.NewScalar().SetUint64(uint64(i + 1))
+	}
 	return &FROST{
 		group:     g,
 		hasher:    hasher,
 		threshold: threshold,
 		total:     total,
+		one:       g.NewScalar().SetUint64(1),
+		ids:       ids,
 	}, nil
 }
 
-// scalarFromInt creates a scalar from an integer value.
+// maxCachedIDs bounds the identifiers derived in advance, so that a huge
+// total does not cost memory before any participant exists.
+const maxCachedIDs = 1 << 12
+
+// Threshold returns t, the minimum number of signers.
+func (f *FROST) Threshold() int {
+	return f.threshold
+}
+
+// Total returns n, the number of participants.
+func (f *FROST) Total() int {
+	return f.total
+}
+
+// WithParallelism returns a copy of f that spreads batch operations over
+// at most n goroutines: the group commitment and share sum of
+// [FROST.Aggregate] and [PreparedSession.Aggregate], and the share checks
+// of [FROST.Round2ReceiveShares]. n = 1 runs them sequentially, and n <= 0
+// restores the default of GOMAXPROCS. Aggregation only splits signer sets
+// large enough to gain from it, such as the hundreds of virtual shares of
+// weighted schemes.
+func (f *FROST) WithParallelism(n int) *FROST {
+	c := *f
+	c.parallelism = max(n, 0)
+	return &c
+}
+
+// minAggregateChunk is the smallest number of signers aggregated on one
+// goroutine; smaller sets are not worth the synchronization.
+const minAggregateChunk = 64
+
+// workers returns the number of goroutines to spread n items over, giving
+// each at least minChunk of them.
+func (f *FROST) workers(n, minChunk int) int {
+	limit := f.parallelism
+	if limit == 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	return max(min(limit, n/minChunk), 1)
+}
+
+// parallel calls fn(i) for every i in [0, n), spread over up to workers
+// goroutines, and returns when all calls have returned.
+func parallel(n, workers int, fn func(i int)) {
+	workers = min(n, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// chunks splits [0, n) into k contiguous ranges of nearly equal length and
+// calls fn(j, lo, hi) for the j-th range concurrently, returning when all
+// calls have returned. With k = 1, fn runs on the calling goroutine.
+func chunks(n, k int, fn func(j, lo, hi int)) {
+	if k == 1 {
+		fn(0, 0, n)
+		return
+	}
+	parallel(k, k, func(j int) {
+		fn(j, j*n/k, (j+1)*n/k)
+	})
+}
+
+// One returns a new scalar equal to 1.
+func (f *FROST) One() group.Scalar {
+	return f.one.Clone()
+}
+
+// Identifier returns a new scalar holding the identifier of participant
+// id, the x-coordinate of its share. Identifiers of participants 1 to n
+// are derived once, when f is created, and copied from there.
+func (f *FROST) Identifier(id int) group.Scalar {
+	return f.scalarFromInt(id).Clone()
+}
+
+// scalarFromInt returns the scalar for an integer value, such as a
+// participant ID. For 1 to n it returns the cached identifier itself,
+// which the caller must not modify; use [FROST.Identifier] or Clone for a
+// scalar that is modified or handed out.
 func (f *FROST) scalarFromInt(n int) group.Scalar {
-	s := f.group.NewScalar()
-	buf := make([]byte, 32)
-	buf[31] = byte(n) // big-endian: value goes at the end
-	s.SetBytes(buf)
-	return s
+	if n >= 1 && n <= len(f.ids) {
+		return f.ids[n-1]
+	}
+	return f.group.NewScalar().SetUint64(uint64(n))
+}
+
+// checkScalars returns an error if any of scalars belongs to a different
+// group than f, so that a misconfigured caller gets an error instead of a
+// panic from the arithmetic.
+func (f *FROST) checkScalars(scalars ...group.Scalar) error {
+	for _, s := range scalars {
+		if err := f.group.CheckScalar(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPoints is like checkScalars for points. Points that implement
+// [group.Validator] must also lie in the prime-order subgroup, since a
+// small-order component could leak information about secret scalars
+// multiplied with them.
+func (f *FROST) checkPoints(points ...group.Point) error {
+	for _, p := range points {
+		if err := f.group.CheckPoint(p); err != nil {
+			return err
+		}
+		if v, ok := p.(group.Validator); ok && !v.IsInPrimeSubgroup() {
+			return errors.New("point is not in the prime-order subgroup")
+		}
+	}
+	return nil
+}
+
+// checkCommitments checks the shape of commitments with checkShape and
+// every point with checkPoints.
+func (f *FROST) checkCommitments(commitments []*SigningCommitment) error {
+	if err := f.checkShape(commitments); err != nil {
+		return err
+	}
+	for _, c := range commitments {
+		if err := f.checkPoints(c.HidingPoint, c.BindingPoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publicMult returns s*P for a public scalar s, as in verification. It
+// goes through MultiScalarMult, which groups may implement in variable
+// time and which is faster than the constant-time ScalarMult of bjj.
+func (f *FROST) publicMult(s group.Scalar, P group.Point) group.Point {
+	return f.group.MultiScalarMult([]group.Scalar{s}, []group.Point{P})
 }
 
 // evalPolynomial evaluates a polynomial at point x using Horner's method.
 // The polynomial is represented by its coefficients [a0, a1, ..., an]
 // where p(x) = a0 + a1*x + a2*x^2 + ... + an*x^n.
 func (f *FROST) evalPolynomial(coeffs []group.Scalar, x group.Scalar) group.Scalar {
-	result := f.group.NewScalar().Set(coeffs[len(coeffs)-1])
+	result := coeffs[len(coeffs)-1].Clone()
 	for i := len(coeffs) - 2; i >= 0; i-- {
-		result = f.group.NewScalar().Mul(result, x)
-		result = f.group.NewScalar().Add(result, coeffs[i])
+		result.Mul(result, x)
+		result.Add(result, coeffs[i])
 	}
 	return result
 }
+
+// evalPolynomialRange returns the values of the polynomial with the given
+// coefficients at x = 1, 2, ..., n. Rather than running Horner's rule n
+// times, it computes the forward differences of the polynomial at x = 1
+// from its first values and steps through the remaining points with one
+// addition per coefficient, so that a dealer in a DKG with n in the
+// thousands needs O(t²) multiplications instead of O(n·t).
+func (f *FROST) evalPolynomialRange(coeffs []group.Scalar, n int) []group.Scalar {
+	values := make([]group.Scalar, n)
+	d := len(coeffs)
+	for i := range min(d, n) {
+		values[i] = f.evalPolynomial(coeffs, f.scalarFromInt(i+1))
+	}
+	if n <= d {
+		return values
+	}
+
+	// diffs[k] is the k-th forward difference at the current point; a
+	// polynomial of degree d-1 has no higher ones
+	diffs := make([]group.Scalar, d)
+	for k := range diffs {
+		diffs[k] = values[k].Clone()
+	}
+	for k := 1; k < d; k++ {
+		for i := d - 1; i >= k; i-- {
+			diffs[i].Sub(diffs[i], diffs[i-1])
+		}
+	}
+	for x := 2; x <= n; x++ {
+		for k := 0; k < d-1; k++ {
+			diffs[k].Add(diffs[k], diffs[k+1])
+		}
+		if x > d {
+			values[x-1] = diffs[0].Clone()
+		}
+	}
+	memwipe.Scalars(diffs...)
+	return values
+}
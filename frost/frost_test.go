@@ -1,11 +1,30 @@
 package frost
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"unsafe"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
 	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/group"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/sha3"
 )
 
 func TestDKGAndSign(t *testing.T) {
@@ -549,3 +568,2569 @@ func TestBlake2bHasher(t *testing.T) {
 		t.Error("blake2b signature should not verify with sha256 hasher")
 	}
 }
+
+func TestVerificationShare(t *testing.T) {
+	g := &bjj.BJJ{}
+	total := 4
+	f, _ := New(g, 3, total)
+
+	participants := make([]*Participant, total)
+	broadcasts := make([]*Round1Data, total)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i == j {
+				continue
+			}
+			privateData := f.Round1PrivateSend(sender, j+1)
+			if err := f.Round2ReceiveShare(participants[j], privateData, broadcasts[i].Commitments); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// VerificationShares returns the shares in broadcast order
+	reversed := slices.Clone(broadcasts)
+	slices.Reverse(reversed)
+	shares, err := f.VerificationShares(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range participants {
+		ks, err := f.Finalize(p, broadcasts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.VerificationShare(broadcasts, i+1).Equal(ks.PublicKey) {
+			t.Errorf("verification share for participant %d does not match its public key", i+1)
+		}
+		if !shares[total-1-i].Equal(ks.PublicKey) {
+			t.Errorf("VerificationShares for participant %d does not match its public key", i+1)
+		}
+	}
+
+	var dup *DuplicateIDError
+	if _, err := f.VerificationShares(append(slices.Clone(broadcasts), broadcasts[1])); !errors.As(err, &dup) {
+		t.Errorf("repeated broadcast: err = %v, want *DuplicateIDError", err)
+	}
+	var input *InputError
+	if _, err := f.VerificationShares(nil); !errors.As(err, &input) {
+		t.Errorf("no broadcasts: err = %v, want *InputError", err)
+	}
+}
+
+func TestVerifySignatureShare(t *testing.T) {
+	g := &bjj.BJJ{}
+	total := 3
+	f, _ := New(g, 2, total)
+
+	participants := make([]*Participant, total)
+	broadcasts := make([]*Round1Data, total)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i != j {
+				f.Round2ReceiveShare(participants[j], f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments)
+			}
+		}
+	}
+	keyShares := make([]*KeyShare, total)
+	for i, p := range participants {
+		keyShares[i], _ = f.Finalize(p, broadcasts)
+	}
+
+	message := []byte("share verification")
+	signers := []*KeyShare{keyShares[0], keyShares[2]}
+	nonces := make([]*SigningNonce, 2)
+	commitments := make([]*SigningCommitment, 2)
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+
+	groupKey := keyShares[0].GroupKey
+	for i, ks := range signers {
+		share, err := f.SignRound2(ks, nonces[i], message, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.VerifySignatureShare(share, ks.PublicKey, groupKey, message, commitments) {
+			t.Errorf("valid share from signer %d rejected", i+1)
+		}
+
+		// Wrong public key
+		if f.VerifySignatureShare(share, keyShares[1].PublicKey, groupKey, message, commitments) {
+			t.Error("share should not verify against another participant's public key")
+		}
+
+		// Tampered share
+		tampered := &SignatureShare{ID: share.ID, Z: g.NewScalar().Add(share.Z, share.Z)}
+		if f.VerifySignatureShare(tampered, ks.PublicKey, groupKey, message, commitments) {
+			t.Error("tampered share should not verify")
+		}
+	}
+}
+
+func TestLargeParticipantIDs(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+
+	// 257 and 1 collided when IDs were truncated to a single byte
+	ids := []int{1, 257, 70000}
+	participants := make([]*Participant, len(ids))
+	broadcasts := make([]*Round1Data, len(ids))
+	for i, id := range ids {
+		participants[i], _ = f.NewParticipant(rand.Reader, id)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	if broadcasts[0].ID.Equal(broadcasts[1].ID) {
+		t.Fatal("participants 1 and 257 have the same ID scalar")
+	}
+
+	for i, sender := range participants {
+		for j, id := range ids {
+			if i == j {
+				continue
+			}
+			data := f.Round1PrivateSend(sender, id)
+			if err := f.Round2ReceiveShare(participants[j], data, broadcasts[i].Commitments); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	keyShares := make([]*KeyShare, len(ids))
+	for i, p := range participants {
+		keyShares[i], _ = f.Finalize(p, broadcasts)
+	}
+
+	message := []byte("large ids")
+	signers := keyShares[1:]
+	nonces := make([]*SigningNonce, len(signers))
+	commitments := make([]*SigningCommitment, len(signers))
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+	shares := make([]*SignatureShare, len(signers))
+	for i, ks := range signers {
+		shares[i], _ = f.SignRound2(ks, nonces[i], message, commitments)
+	}
+	sig, _ := f.Aggregate(message, commitments, shares)
+	if !f.Verify(message, sig, keyShares[0].GroupKey) {
+		t.Error("signature with large participant IDs failed to verify")
+	}
+}
+
+func TestOutputsDoNotAlias(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	p, _ := f.NewParticipant(rand.Reader, 1)
+
+	// Using values handed out by the participant as receivers must not
+	// change its internal state
+	first := f.Round1PrivateSend(p, 2).Share
+	b := p.Round1Broadcast()
+	b.ID.Add(b.ID, b.ID)
+	b.Commitments[0].Add(b.Commitments[0], b.Commitments[0])
+
+	again := p.Round1Broadcast()
+	if !again.ID.Equal(f.Identifier(1)) {
+		t.Error("modifying the broadcast ID changed the participant ID")
+	}
+	if again.Commitments[0].Equal(b.Commitments[0]) {
+		t.Error("modifying a broadcast commitment changed the participant's commitment")
+	}
+	if !f.Round1PrivateSend(p, 2).Share.Equal(first) {
+		t.Error("participant shares changed after modifying its broadcast")
+	}
+}
+
+// foreignScalar stands in for a scalar of another group.
+type foreignScalar struct{ group.Scalar }
+
+func TestMismatchedGroup(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+
+	secret, _ := g.RandomScalar(rand.Reader)
+	share := &KeyShare{ID: f.Identifier(1), SecretKey: secret, GroupKey: g.Generator()}
+	nonce, commitment, _ := f.SignRound1(rand.Reader, share)
+
+	// A key share decoded for another curve
+	foreign := *share
+	foreign.SecretKey = foreignScalar{}
+	message := []byte("mismatch")
+	var mismatch *group.MismatchError
+	_, err := f.SignRound2(&foreign, nonce, message, []*SigningCommitment{commitment})
+	if !errors.As(err, &mismatch) {
+		t.Errorf("SignRound2 with a foreign key share: err = %v, want *group.MismatchError", err)
+	}
+	if f.Verify(message, &Signature{R: g.Generator(), Z: foreignScalar{}}, share.GroupKey) {
+		t.Error("Verify accepted a signature with a foreign scalar")
+	}
+}
+
+// torsionPoint returns (0, -1), which has order 2. Every bjj decoder
+// rejects it, so it is written into the point's coordinates directly.
+func torsionPoint(t *testing.T, g *bjj.BJJ) *bjj.Point {
+	t.Helper()
+	p := g.NewPoint().(*bjj.Point)
+	// The extended coordinates are the first field of bjj.Point
+	inner := (*twistededwards.PointExtended)(unsafe.Pointer(p))
+	inner.Y.Neg(&inner.Y)
+	if !p.IsOnCurve() || p.IsIdentity() || p.IsInPrimeSubgroup() {
+		t.Fatal("failed to build a point of order 2")
+	}
+	return p
+}
+
+func TestSmallOrderPoints(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+
+	torsion := torsionPoint(t, g)
+
+	secret, _ := g.RandomScalar(rand.Reader)
+	share := &KeyShare{ID: f.Identifier(1), SecretKey: secret, GroupKey: g.ScalarBaseMult(secret)}
+	nonce, commitment, _ := f.SignRound1(rand.Reader, share)
+
+	bad := *commitment
+	bad.HidingPoint = g.NewPoint().Add(commitment.HidingPoint, torsion)
+	if _, err := f.SignRound2(share, nonce, []byte("msg"), []*SigningCommitment{&bad}); err == nil {
+		t.Error("SignRound2 accepted a commitment outside the prime-order subgroup")
+	}
+	sig := &Signature{R: torsion, Z: g.NewScalar()}
+	if f.Verify([]byte("msg"), sig, share.GroupKey) {
+		t.Error("Verify accepted a signature with a small-order R")
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+
+	// Single-key Schnorr signatures satisfy the same equation as FROST
+	// signatures, so they stand in for the output of a full ceremony
+	sign := func(x group.Scalar, message []byte) *Signature {
+		k, _ := g.RandomScalar(rand.Reader)
+		R := g.ScalarBaseMult(k)
+		c := f.hasher.H2(g, R.Bytes(), g.ScalarBaseMult(x).Bytes(), message)
+		z := g.NewScalar().Add(k, g.NewScalar().Mul(c, x))
+		return &Signature{R: R, Z: z}
+	}
+
+	entries := make([]BatchEntry, 8)
+	for i := range entries {
+		x, _ := g.RandomScalar(rand.Reader)
+		msg := []byte(fmt.Sprintf("message %d", i))
+		entries[i] = BatchEntry{Message: msg, Signature: sign(x, msg), GroupKey: g.ScalarBaseMult(x)}
+		if !f.Verify(msg, entries[i].Signature, entries[i].GroupKey) {
+			t.Fatalf("entry %d does not verify on its own", i)
+		}
+	}
+
+	if ok, err := f.VerifyBatch(rand.Reader, entries); err != nil || !ok {
+		t.Fatalf("VerifyBatch = %v, %v for valid signatures", ok, err)
+	}
+	if ok, _ := f.VerifyBatch(rand.Reader, nil); !ok {
+		t.Error("empty batch rejected")
+	}
+
+	// One bad entry fails the batch
+	bad := slices.Clone(entries)
+	bad[3].Message = []byte("other message")
+	if ok, _ := f.VerifyBatch(rand.Reader, bad); ok {
+		t.Error("batch with a wrong message verified")
+	}
+	bad = slices.Clone(entries)
+	bad[5].Signature = &Signature{R: entries[5].Signature.R, Z: g.NewScalar().Add(entries[5].Signature.Z, g.NewScalar().SetUint64(1))}
+	if ok, _ := f.VerifyBatch(rand.Reader, bad); ok {
+		t.Error("batch with a tampered signature verified")
+	}
+	bad = slices.Clone(entries)
+	bad[0].Signature = nil
+	if ok, _ := f.VerifyBatch(rand.Reader, bad); ok {
+		t.Error("batch with a nil signature verified")
+	}
+
+	// Swapping responses between entries keeps the sums of a plain
+	// aggregate but not of the random combination
+	bad = slices.Clone(entries)
+	bad[1].Signature = &Signature{R: entries[1].Signature.R, Z: entries[2].Signature.Z}
+	bad[2].Signature = &Signature{R: entries[2].Signature.R, Z: entries[1].Signature.Z}
+	if ok, _ := f.VerifyBatch(rand.Reader, bad); ok {
+		t.Error("batch with swapped responses verified")
+	}
+
+	if _, err := f.VerifyBatch(bytes.NewReader(nil), entries); err == nil {
+		t.Error("expected an error from an empty random source")
+	}
+}
+
+// BenchmarkSign measures a complete 3-of-5 signing session: both rounds
+// for every signer, aggregation and verification.
+func BenchmarkSign(b *testing.B) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 3, 5)
+
+	participants := make([]*Participant, 5)
+	broadcasts := make([]*Round1Data, 5)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i != j {
+				f.Round2ReceiveShare(participants[j], f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments)
+			}
+		}
+	}
+	signers := make([]*KeyShare, 3)
+	for i := range signers {
+		signers[i], _ = f.Finalize(participants[i], broadcasts)
+	}
+
+	message := []byte("benchmark message")
+	nonces := make([]*SigningNonce, 3)
+	commitments := make([]*SigningCommitment, 3)
+	sigShares := make([]*SignatureShare, 3)
+	b.ReportAllocs()
+	for b.Loop() {
+		for i, ks := range signers {
+			nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+		}
+		for i, ks := range signers {
+			sigShares[i], _ = f.SignRound2(ks, nonces[i], message, commitments)
+		}
+		sig, _ := f.Aggregate(message, commitments, sigShares)
+		if !f.Verify(message, sig, signers[0].GroupKey) {
+			b.Fatal("signature did not verify")
+		}
+	}
+}
+
+// benchSizes are the threshold parameters of the scaling benchmarks,
+// from the smallest group up to a few hundred participants.
+var benchSizes = []struct{ t, n int }{
+	{2, 3},
+	{3, 5},
+	{7, 10},
+	{34, 50},
+	{67, 100},
+	{171, 256},
+}
+
+// benchAll runs fn as a sub-benchmark for every registered group and every
+// size in benchSizes, named like "baby-jubjub/t=2,n=3".
+func benchAll(b *testing.B, fn func(b *testing.B, f *FROST, t, n int)) {
+	for _, name := range group.Names() {
+		g, err := group.New(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, size := range benchSizes {
+			f, err := New(g, size.t, size.n)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("%s/t=%d,n=%d", name, size.t, size.n), func(b *testing.B) {
+				fn(b, f, size.t, size.n)
+			})
+		}
+	}
+}
+
+// dealKeyShares returns key shares for participants 1 to n from a random
+// polynomial, without the O(n²) cost of a DKG. They are only meant for
+// benchmarks and tests of large signer sets.
+func dealKeyShares(b testing.TB, f *FROST, n int) []*KeyShare {
+	b.Helper()
+	coeffs := make([]group.Scalar, f.threshold)
+	for i := range coeffs {
+		c, err := f.group.RandomScalar(rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		coeffs[i] = c
+	}
+	groupKey := f.group.ScalarBaseMult(coeffs[0])
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		id := f.Identifier(i + 1)
+		sk := f.evalPolynomial(coeffs, id)
+		shares[i] = &KeyShare{
+			ID:        id,
+			SecretKey: sk,
+			PublicKey: f.group.ScalarBaseMult(sk),
+			GroupKey:  groupKey,
+		}
+	}
+	return shares
+}
+
+// benchCommit runs signing round 1 for the first t key shares.
+func benchCommit(b testing.TB, f *FROST, shares []*KeyShare, t int) ([]*SigningNonce, []*SigningCommitment) {
+	b.Helper()
+	nonces := make([]*SigningNonce, t)
+	commitments := make([]*SigningCommitment, t)
+	for i, ks := range shares[:t] {
+		n, c, err := f.SignRound1(rand.Reader, ks)
+		if err != nil {
+			b.Fatal(err)
+		}
+		nonces[i], commitments[i] = n, c
+	}
+	return nonces, commitments
+}
+
+// benchSign returns the signature shares of the first t key shares and
+// the aggregated signature.
+func benchSign(b *testing.B, f *FROST, shares []*KeyShare, t int, message []byte) ([]*SigningCommitment, []*SignatureShare, *Signature) {
+	b.Helper()
+	nonces, commitments := benchCommit(b, f, shares, t)
+	sigShares := make([]*SignatureShare, t)
+	for i, ks := range shares[:t] {
+		ss, err := f.SignRound2(ks, nonces[i], message, commitments)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sigShares[i] = ss
+	}
+	sig, err := f.Aggregate(message, commitments, sigShares)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return commitments, sigShares, sig
+}
+
+// signAll returns the commitments and signature shares of all key shares
+// signing message together, using one prepared session.
+func signAll(tb testing.TB, f *FROST, shares []*KeyShare, message []byte) ([]*SigningCommitment, []*SignatureShare) {
+	tb.Helper()
+	nonces, commitments := benchCommit(tb, f, shares, len(shares))
+	prepared, err := f.Prepare(shares[0].GroupKey, message, commitments)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sigShares := make([]*SignatureShare, len(shares))
+	for i, ks := range shares {
+		if sigShares[i], err = prepared.Sign(ks, nonces[i]); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return commitments, sigShares
+}
+
+// BenchmarkDKG measures the work of one participant in each DKG step:
+// dealing its polynomial to the n-1 others, verifying the n-1 shares it
+// receives, and deriving its key share.
+func BenchmarkDKG(b *testing.B) {
+	b.Run("Round1", func(b *testing.B) {
+		benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+			b.ReportAllocs()
+			for b.Loop() {
+				p, err := f.NewParticipant(rand.Reader, 1)
+				if err != nil {
+					b.Fatal(err)
+				}
+				p.Round1Broadcast()
+				for id := 2; id <= n; id++ {
+					f.Round1PrivateSend(p, id)
+				}
+			}
+		})
+	})
+
+	b.Run("Round2", func(b *testing.B) {
+		benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+			p, data, commitments := benchDealers(b, f, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				clear(p.receivedShares)
+				for i := range data {
+					if err := f.Round2ReceiveShare(p, data[i], commitments[i]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	})
+
+	b.Run("Round2Batch", func(b *testing.B) {
+		benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+			p, data, commitments := benchDealers(b, f, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				clear(p.receivedShares)
+				if err := f.Round2ReceiveShares(p, data, commitments); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+
+	b.Run("Finalize", func(b *testing.B) {
+		benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+			p, data, commitments := benchDealers(b, f, n)
+			for i := range data {
+				if err := f.Round2ReceiveShare(p, data[i], commitments[i]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			broadcasts := make([]*Round1Data, n)
+			broadcasts[0] = p.Round1Broadcast()
+			for i, c := range commitments {
+				broadcasts[i+1] = &Round1Data{ID: f.Identifier(i + 2), Commitments: c}
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				// Finalize destroys the participant, so each iteration
+				// finalizes a copy
+				b.StopTimer()
+				q := &Participant{
+					id:             p.id,
+					coefficients:   cloneScalars(p.coefficients),
+					commitments:    p.commitments,
+					receivedShares: make(map[string]group.Scalar, len(p.receivedShares)),
+				}
+				for k, s := range p.receivedShares {
+					q.receivedShares[k] = s.Clone()
+				}
+				b.StartTimer()
+				if _, err := f.Finalize(q, broadcasts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkEvalPolynomial compares evaluating a dealer's polynomial at 1
+// to n point by point with Horner's rule and in one pass with
+// evalPolynomialRange, for committees much larger than the threshold.
+func BenchmarkEvalPolynomial(b *testing.B) {
+	f, _ := New(&bjj.BJJ{}, 2, 3)
+	for _, size := range []struct{ t, n int }{{34, 100}, {100, 1000}, {667, 1000}} {
+		coeffs := make([]group.Scalar, size.t)
+		for i := range coeffs {
+			coeffs[i], _ = f.group.RandomScalar(rand.Reader)
+		}
+		b.Run(fmt.Sprintf("Horner/t=%d,n=%d", size.t, size.n), func(b *testing.B) {
+			for b.Loop() {
+				for x := 1; x <= size.n; x++ {
+					f.evalPolynomial(coeffs, f.Identifier(x))
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Range/t=%d,n=%d", size.t, size.n), func(b *testing.B) {
+			for b.Loop() {
+				f.evalPolynomialRange(coeffs, size.n)
+			}
+		})
+	}
+}
+
+// benchDealers returns participant 1 of an n-party DKG together with the
+// shares the other n-1 dealers send it and their commitments.
+func benchDealers(b *testing.B, f *FROST, n int) (*Participant, []*Round1PrivateData, [][]group.Point) {
+	b.Helper()
+	p, err := f.NewParticipant(rand.Reader, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := make([]*Round1PrivateData, n-1)
+	commitments := make([][]group.Point, n-1)
+	for i := range data {
+		dealer, err := f.NewParticipant(rand.Reader, i+2)
+		if err != nil {
+			b.Fatal(err)
+		}
+		data[i] = f.Round1PrivateSend(dealer, 1)
+		commitments[i] = dealer.Round1Broadcast().Commitments
+		dealer.Destroy()
+	}
+	return p, data, commitments
+}
+
+// cloneScalars returns deep copies of s.
+func cloneScalars(s []group.Scalar) []group.Scalar {
+	out := make([]group.Scalar, len(s))
+	for i, x := range s {
+		out[i] = x.Clone()
+	}
+	return out
+}
+
+// BenchmarkSignRound1 measures generating one signer's nonces and
+// commitment.
+func BenchmarkSignRound1(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		ks := dealKeyShares(b, f, 1)[0]
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, _, err := f.SignRound1(rand.Reader, ks); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkSignRound2 measures one signer's signature share in a session
+// with t signers.
+func BenchmarkSignRound2(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		shares := dealKeyShares(b, f, t)
+		nonces, commitments := benchCommit(b, f, shares, t)
+		message := []byte("benchmark message")
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := f.SignRound2(shares[0], nonces[0], message, commitments); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkPreparedSign measures every signer's share in a session with t
+// signers, preparing the session once, for comparison with t times
+// BenchmarkSignRound2.
+func BenchmarkPreparedSign(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		shares := dealKeyShares(b, f, t)
+		nonces, commitments := benchCommit(b, f, shares, t)
+		message := []byte("benchmark message")
+		b.ReportAllocs()
+		for b.Loop() {
+			prepared, err := f.Prepare(shares[0].GroupKey, message, commitments)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for i := range t {
+				if _, err := prepared.Sign(shares[i], nonces[i]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkAggregate measures aggregating t signature shares.
+func BenchmarkAggregate(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		message := []byte("benchmark message")
+		commitments, sigShares, _ := benchSign(b, f, dealKeyShares(b, f, t), t, message)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := f.Aggregate(message, commitments, sigShares); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkParallelAggregate measures aggregating the shares of a large
+// signer set sequentially and across GOMAXPROCS goroutines.
+func BenchmarkParallelAggregate(b *testing.B) {
+	f, _ := New(&bjj.BJJ{}, 2, 512)
+	message := []byte("benchmark message")
+	commitments, sigShares := signAll(b, f, dealKeyShares(b, f, 512), message)
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("parallelism=%d", workers), func(b *testing.B) {
+			f := f.WithParallelism(workers)
+			for b.Loop() {
+				if _, err := f.Aggregate(message, commitments, sigShares); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkVerify measures verifying a signature. Its cost does not
+// depend on t or n; the sizes are kept so that results line up with the
+// other benchmarks.
+func BenchmarkVerify(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		message := []byte("benchmark message")
+		shares := dealKeyShares(b, f, t)
+		_, _, sig := benchSign(b, f, shares, t, message)
+		b.ReportAllocs()
+		for b.Loop() {
+			if !f.Verify(message, sig, shares[0].GroupKey) {
+				b.Fatal("signature did not verify")
+			}
+		}
+	})
+}
+
+// BenchmarkVerifyBatch measures verifying n signatures with one
+// VerifyBatch call.
+func BenchmarkVerifyBatch(b *testing.B) {
+	benchAll(b, func(b *testing.B, f *FROST, t, n int) {
+		message := []byte("benchmark message")
+		shares := dealKeyShares(b, f, t)
+		_, _, sig := benchSign(b, f, shares, t, message)
+		entries := make([]BatchEntry, n)
+		for i := range entries {
+			entries[i] = BatchEntry{Message: message, Signature: sig, GroupKey: shares[0].GroupKey}
+		}
+		b.ReportAllocs()
+		for b.Loop() {
+			if ok, err := f.VerifyBatch(rand.Reader, entries); err != nil || !ok {
+				b.Fatal("batch did not verify")
+			}
+		}
+	})
+}
+
+// thresholdSign runs a 2-of-3 DKG with f and signs message with the
+// first two participants, returning the signature and the group key.
+func thresholdSign(t *testing.T, f *FROST, message []byte) (*Signature, group.Point) {
+	t.Helper()
+	signers := runDKG(t, f, 3)[:2]
+
+	nonces := make([]*SigningNonce, 2)
+	commitments := make([]*SigningCommitment, 2)
+	for i, ks := range signers {
+		n, c, err := f.SignRound1(rand.Reader, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces[i], commitments[i] = n, c
+	}
+	sigShares := make([]*SignatureShare, 2)
+	for i, ks := range signers {
+		ss, err := f.SignRound2(ks, nonces[i], message, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigShares[i] = ss
+	}
+	sig, err := f.Aggregate(message, commitments, sigShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig, signers[0].GroupKey
+}
+
+// runDKG runs a DKG with f for participants 1 to n and returns their key
+// shares in ID order.
+func runDKG(t *testing.T, f *FROST, n int) []*KeyShare {
+	t.Helper()
+	participants := make([]*Participant, n)
+	broadcasts := make([]*Round1Data, n)
+	for i := range participants {
+		p, err := f.NewParticipant(rand.Reader, i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		participants[i] = p
+		broadcasts[i] = p.Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i == j {
+				continue
+			}
+			if err := f.Round2ReceiveShare(participants[j], f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	shares := make([]*KeyShare, n)
+	for i, p := range participants {
+		ks, err := f.Finalize(p, broadcasts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares[i] = ks
+	}
+	return shares
+}
+
+func TestKeccak256Hasher(t *testing.T) {
+	g := &bjj.BJJ{}
+	h := NewKeccak256Hasher()
+	f, err := NewWithHasher(g, 2, 3, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("test message with keccak256")
+	sig, groupKey := thresholdSign(t, f, message)
+	if !f.Verify(message, sig, groupKey) {
+		t.Fatal("signature verification failed with Keccak256 hasher")
+	}
+	if f.Verify([]byte("wrong message"), sig, groupKey) {
+		t.Error("signature should not verify with wrong message")
+	}
+	f2, _ := New(g, 2, 3)
+	if f2.Verify(message, sig, groupKey) {
+		t.Error("keccak256 signature should not verify with sha256 hasher")
+	}
+
+	// Recompute the challenge as the documented Solidity code does
+	R, Y := sig.R.Bytes(), groupKey.Bytes()
+	digest := func(counter byte) *big.Int {
+		k := sha3.NewLegacyKeccak256()
+		k.Write(slices.Concat([]byte("FROST-KECCAK256-v1chal"), []byte{counter}, R, Y, message))
+		return new(big.Int).SetBytes(k.Sum(nil))
+	}
+	order := new(big.Int).SetBytes(g.Order())
+	twoTo256 := new(big.Int).Lsh(big.NewInt(1), 256)
+	c := new(big.Int).Mul(digest(0), twoTo256.Mod(twoTo256, order))
+	c.Add(c, digest(1)).Mod(c, order)
+	if h.H2(g, R, Y, message).(*bjj.Scalar).BigInt().Cmp(c) != 0 {
+		t.Error("H2 does not match the documented Solidity computation")
+	}
+}
+
+func TestSHA512Hasher(t *testing.T) {
+	g := &bjj.BJJ{}
+	order := new(big.Int).SetBytes(g.Order())
+	// scalar reduces a SHA-512 digest read in little-endian order
+	scalar := func(data ...[]byte) *big.Int {
+		sum := sha512.Sum512(slices.Concat(data...))
+		slices.Reverse(sum[:])
+		v := new(big.Int).SetBytes(sum[:])
+		return v.Mod(v, order)
+	}
+
+	for _, plain := range []bool{false, true} {
+		h := NewSHA512Hasher()
+		h.PlainChallenge = plain
+		f, err := NewWithHasher(g, 2, 3, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		message := []byte("test message with sha512")
+		sig, groupKey := thresholdSign(t, f, message)
+		if !f.Verify(message, sig, groupKey) {
+			t.Fatalf("plain=%v: signature verification failed with SHA512 hasher", plain)
+		}
+		if f.Verify([]byte("wrong message"), sig, groupKey) {
+			t.Errorf("plain=%v: signature should not verify with wrong message", plain)
+		}
+
+		R, Y := sig.R.Bytes(), groupKey.Bytes()
+		want := scalar([]byte("FROST-SHA512-v1chal"), R, Y, message)
+		if plain {
+			want = scalar(R, Y, message)
+		}
+		if h.H2(g, R, Y, message).(*bjj.Scalar).BigInt().Cmp(want) != 0 {
+			t.Errorf("plain=%v: H2 is not the little-endian reduced digest", plain)
+		}
+	}
+
+	h := NewSHA512Hasher()
+	want := scalar([]byte("FROST-SHA512-v1rho"), []byte("m"), []byte("c"), []byte("i"))
+	if h.H1(g, []byte("m"), []byte("c"), []byte("i")).(*bjj.Scalar).BigInt().Cmp(want) != 0 {
+		t.Error("H1 does not prefix the context string and tag")
+	}
+	sum := sha512.Sum512([]byte("FROST-SHA512-v1msgm"))
+	if !bytes.Equal(h.H4(g, []byte("m")), sum[:]) {
+		t.Error("H4 does not prefix the context string and tag")
+	}
+}
+
+func TestSHAKE256Hasher(t *testing.T) {
+	g := &bjj.BJJ{}
+	order := new(big.Int).SetBytes(g.Order())
+
+	for _, n := range []int{48, 64, 114} {
+		h := NewSHAKE256Hasher()
+		h.ScalarLength = n
+		h.DigestLength = 32
+		f, err := NewWithHasher(g, 2, 3, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		message := []byte("test message with shake256")
+		sig, groupKey := thresholdSign(t, f, message)
+		if !f.Verify(message, sig, groupKey) {
+			t.Fatalf("%d bytes: signature verification failed with SHAKE256 hasher", n)
+		}
+
+		// The challenge reduces n bytes of output read in little-endian order
+		R, Y := sig.R.Bytes(), groupKey.Bytes()
+		out := make([]byte, n)
+		sha3.ShakeSum256(out, slices.Concat([]byte("FROST-SHAKE256-v1chal"), R, Y, message))
+		slices.Reverse(out)
+		want := new(big.Int).SetBytes(out)
+		want.Mod(want, order)
+		if h.H2(g, R, Y, message).(*bjj.Scalar).BigInt().Cmp(want) != 0 {
+			t.Errorf("%d bytes: H2 is not the reduced output", n)
+		}
+		if len(h.H4(g, message)) != 32 || len(h.H5(g, message)) != 32 {
+			t.Errorf("%d bytes: H4 and H5 ignore DigestLength", n)
+		}
+	}
+
+	// reduceWide agrees with big.Int for every chunk alignment
+	for n := 65; n <= 130; n++ {
+		data := make([]byte, n)
+		rand.Read(data)
+		want := new(big.Int).SetBytes(data)
+		want.Mod(want, order)
+		if reduceWide(g, data).(*bjj.Scalar).BigInt().Cmp(want) != 0 {
+			t.Fatalf("reduceWide is wrong for %d bytes", n)
+		}
+	}
+
+	for _, h := range []Hasher{
+		&SHAKE256Hasher{ScalarLength: 32},
+		&SHAKE256Hasher{ScalarLength: -1},
+		&SHAKE256Hasher{DigestLength: -1},
+		&HKDFNonceHasher{Hasher: &SHAKE256Hasher{ScalarLength: 47}},
+	} {
+		if _, err := NewWithHasher(g, 2, 3, h); err == nil {
+			t.Errorf("%+v: accepted", h)
+		}
+	}
+}
+
+func TestPoseidonHasher(t *testing.T) {
+	g := &bjj.BJJ{}
+	h := NewPoseidonHasher()
+	f, err := NewWithHasher(g, 2, 3, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("test message with poseidon2")
+	sig, groupKey := thresholdSign(t, f, message)
+	if !f.Verify(message, sig, groupKey) {
+		t.Fatal("signature verification failed with Poseidon hasher")
+	}
+	if f.Verify([]byte("wrong message"), sig, groupKey) {
+		t.Error("signature should not verify with wrong message")
+	}
+
+	// The challenge uses the packing of bjj.ScalarHashPoseidon2
+	R, Y := sig.R.Bytes(), groupKey.Bytes()
+	pg := &bjj.BJJ{ScalarHash: bjj.ScalarHashPoseidon2}
+	want, _ := pg.HashToScalar([]byte("FROST-POSEIDON2-BN254-v1chal"), R, Y, message)
+	if !h.H2(g, R, Y, message).Equal(want) {
+		t.Error("H2 does not match HashToScalar with ScalarHashPoseidon2")
+	}
+
+	// Digests are field elements
+	d := h.H4(g, message)
+	if len(d) != 32 || new(big.Int).SetBytes(d).Cmp(fr.Modulus()) >= 0 {
+		t.Error("H4 is not a canonical field element")
+	}
+	if bytes.Equal(d, h.H5(g, message)) {
+		t.Error("H4 and H5 are not domain separated")
+	}
+}
+
+func TestTaggedHasher(t *testing.T) {
+	// BIP-340 test vector 0: the challenge hash for its R, public key and
+	// message, checked against secp256k1 verification
+	R, _ := hex.DecodeString("e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca8215")
+	P, _ := hex.DecodeString("f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9")
+	m := make([]byte, 32)
+	want := "6bb6b93a91f2ecc0cd924f4f9baabb5e6eb21745bb00f2cebdaac908bb5d86ce"
+	if got := hex.EncodeToString(taggedHash("BIP0340/challenge", R, P, m)); got != want {
+		t.Errorf("challenge hash = %s, want %s", got, want)
+	}
+
+	g := &bjj.BJJ{}
+	h := NewTaggedHasher()
+	e, _ := new(big.Int).SetString(want, 16)
+	e.Mod(e, new(big.Int).SetBytes(g.Order()))
+	if h.H2(g, R, P, m).(*bjj.Scalar).BigInt().Cmp(e) != 0 {
+		t.Error("H2 is not the challenge hash reduced modulo the order")
+	}
+
+	f, err := NewWithHasher(g, 2, 3, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("test message with tagged hashes")
+	sig, groupKey := thresholdSign(t, f, message)
+	if !f.Verify(message, sig, groupKey) {
+		t.Fatal("signature verification failed with tagged hasher")
+	}
+	if f.Verify([]byte("wrong message"), sig, groupKey) {
+		t.Error("signature should not verify with wrong message")
+	}
+	if bytes.Equal(h.H4(g, message), h.H5(g, message)) {
+		t.Error("H4 and H5 are not domain separated")
+	}
+}
+
+func TestHasherPrefix(t *testing.T) {
+	g := &bjj.BJJ{}
+	hashers := map[string]PrefixedHasher{
+		"SHA256":    &SHA256Hasher{},
+		"Blake2b":   NewBlake2bHasher(),
+		"Keccak256": NewKeccak256Hasher(),
+		"SHA512":    NewSHA512Hasher(),
+		"SHAKE256":  NewSHAKE256Hasher(),
+		"Poseidon":  NewPoseidonHasher(),
+		"Tagged":    NewTaggedHasher(),
+	}
+	msg := []byte("message")
+	for name, h := range hashers {
+		t.Run(name, func(t *testing.T) {
+			before := h.H4(g, msg)
+			ph := h.WithPrefix("my-app-v1")
+			if !bytes.Equal(h.H4(g, msg), before) {
+				t.Fatal("WithPrefix modified the receiver")
+			}
+			if bytes.Equal(ph.H4(g, msg), before) || bytes.Equal(ph.H5(g, msg), h.H5(g, msg)) {
+				t.Error("prefix does not change the digests")
+			}
+			if ph.H1(g, msg, msg, msg).Equal(h.H1(g, msg, msg, msg)) {
+				t.Error("prefix does not change the binding factor")
+			}
+
+			f, err := NewWithHasher(g, 2, 3, ph)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, groupKey := thresholdSign(t, f, msg)
+			if !f.Verify(msg, sig, groupKey) {
+				t.Fatal("signature verification failed with prefixed hasher")
+			}
+		})
+	}
+
+	// The zero prefix keeps the SHA-256 transcript unchanged
+	var h SHA256Hasher
+	want := sha256.Sum256(append([]byte("msg"), msg...))
+	if !bytes.Equal(h.H4(g, msg), want[:]) {
+		t.Error("empty prefix changed the SHA-256 transcript")
+	}
+}
+
+func TestHKDFNonceHasher(t *testing.T) {
+	g := &bjj.BJJ{}
+	h := NewHKDFNonceHasher(NewBlake2bHasher(), []byte("session-1"))
+	f, err := NewWithHasher(g, 2, 3, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, _ := g.RandomScalar(rand.Reader)
+	share := &KeyShare{
+		ID:        g.NewScalar().SetUint64(1),
+		SecretKey: secret,
+		PublicKey: g.ScalarBaseMult(secret),
+	}
+	seed := make([]byte, 64)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	message := []byte("message")
+	nonce, _, err := f.SignRound1Hedged(bytes.NewReader(seed), share, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Recompute the hiding nonce with an independent HKDF implementation
+	var info []byte
+	for _, field := range [][]byte{secret.Bytes(), h.H4(g, message), []byte("session-1")} {
+		info = binary.BigEndian.AppendUint64(info, uint64(len(field)))
+		info = append(info, field...)
+	}
+	okm := make([]byte, 64)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, seed[:32], []byte("FROST-HKDF-NONCE-v1"), info), okm); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := g.NewScalar().SetUniformBytes(okm)
+	if !nonce.D.Equal(want) {
+		t.Error("hiding nonce does not match the HKDF derivation")
+	}
+	if nonce.D.Equal(nonce.E) {
+		t.Error("hiding and binding nonces are equal")
+	}
+
+	// A repeated seed still gives distinct nonces for other sessions and
+	// messages
+	other := NewHKDFNonceHasher(NewBlake2bHasher(), []byte("session-2"))
+	if other.H3(g, seed[:32], secret.Bytes(), message).Equal(want) {
+		t.Error("session ID is not bound into the nonce")
+	}
+	if h.H3(g, seed[:32], secret.Bytes(), []byte("other")).Equal(want) {
+		t.Error("message is not bound into the nonce")
+	}
+	if !h.H3(g, seed[:32], secret.Bytes(), message).Equal(want) {
+		t.Error("derivation is not deterministic")
+	}
+}
+
+// shortMessageHasher is a SHA256Hasher that only signs messages of up to
+// eight bytes.
+type shortMessageHasher struct {
+	SHA256Hasher
+}
+
+func (h *shortMessageHasher) ValidateMessage(g group.Group, msg []byte) error {
+	if len(msg) > 8 {
+		return errors.New("message is too long")
+	}
+	return nil
+}
+
+func (h *shortMessageHasher) Challenge(g group.Group, R, Y, msg []byte) (group.Scalar, error) {
+	return h.H2(g, R, Y, msg), nil
+}
+
+func TestMessageValidator(t *testing.T) {
+	g := &bjj.BJJ{}
+	// Wrapping the hasher keeps its checks
+	f, err := NewWithHasher(g, 2, 2, NewHKDFNonceHasher(&shortMessageHasher{}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := dealKeyShares(t, f, 2)
+	message, long := []byte("short"), []byte("much too long")
+	commitments, sigShares := signAll(t, f, shares, message)
+	sig, err := f.Aggregate(message, commitments, sigShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Verify(message, sig, shares[0].GroupKey) {
+		t.Fatal("valid signature rejected")
+	}
+
+	var inputErr *InputError
+	nonces, commitments := benchCommit(t, f, shares, 2)
+	if _, err := f.SignRound2(shares[0], nonces[0], long, commitments); !errors.As(err, &inputErr) || inputErr.Field != "message" {
+		t.Errorf("SignRound2: got %v, want an InputError for the message", err)
+	}
+	if _, err := f.Prepare(shares[0].GroupKey, long, commitments); !errors.As(err, &inputErr) {
+		t.Errorf("Prepare: got %v, want an InputError", err)
+	}
+	if _, err := f.Aggregate(long, commitments, sigShares); !errors.As(err, &inputErr) {
+		t.Errorf("Aggregate: got %v, want an InputError", err)
+	}
+	if f.Verify(long, sig, shares[0].GroupKey) {
+		t.Error("Verify accepted a message the hasher rejects")
+	}
+}
+
+func TestHasherRegistry(t *testing.T) {
+	names := HasherNames()
+	if len(names) < 7 {
+		t.Fatalf("HasherNames() = %v", names)
+	}
+	for _, name := range names {
+		h, err := NewHasher(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := HasherName(h)
+		if err != nil {
+			t.Errorf("HasherName(NewHasher(%q)): %v", name, err)
+		} else if got != name {
+			t.Errorf("HasherName(NewHasher(%q)) = %q", name, got)
+		}
+
+		// A custom configuration does not match the registered one
+		if ph, ok := h.(PrefixedHasher); ok {
+			if _, err := HasherName(ph.WithPrefix("my-app-v1")); err == nil {
+				t.Errorf("%s: HasherName accepted a custom prefix", name)
+			}
+		}
+	}
+
+	if _, err := NewHasher("md5"); err == nil {
+		t.Error("NewHasher accepted an unknown name")
+	}
+	if _, err := HasherName(NewHKDFNonceHasher(nil, nil)); err == nil {
+		t.Error("HasherName accepted an unnamed hasher")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterHasher accepted a duplicate name")
+		}
+	}()
+	RegisterHasher("sha256", func() Hasher { return &SHA256Hasher{} })
+}
+
+func TestPrehashMessage(t *testing.T) {
+	g := &bjj.BJJ{}
+	message := bytes.Repeat([]byte("large payload "), 10000)
+	hashers := map[string]Hasher{
+		"SHA256":    &SHA256Hasher{Prefix: "app"},
+		"Blake2b":   NewBlake2bHasher(),
+		"Keccak256": NewKeccak256Hasher(),
+		"SHA512":    NewSHA512Hasher(),
+		"SHAKE256":  &SHAKE256Hasher{Prefix: "app", DigestLength: 100},
+		"Tagged":    NewTaggedHasher(),
+		"HKDF":      NewHKDFNonceHasher(NewBlake2bHasher(), nil),
+	}
+	for name, h := range hashers {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewWithHasher(g, 2, 3, h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			digest, err := f.PrehashMessage(iotest.OneByteReader(bytes.NewReader(message[:1000])))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(digest, h.H4(g, message[:1000])) {
+				t.Error("PrehashMessage differs from H4")
+			}
+
+			mh, _ := f.NewMessageHash()
+			mh.Write(message[:5])
+			mh.Write(message[5:])
+			if !bytes.Equal(mh.Sum(nil), h.H4(g, message)) || mh.Size() != len(h.H4(g, nil)) {
+				t.Error("NewMessageHash differs from H4")
+			}
+
+			sig, groupKey := thresholdSign(t, f, mh.Sum(nil))
+			if !f.Verify(mh.Sum(nil), sig, groupKey) {
+				t.Error("signature on prehashed message failed to verify")
+			}
+		})
+	}
+
+	f, _ := NewWithHasher(g, 2, 3, NewPoseidonHasher())
+	if _, err := f.PrehashMessage(bytes.NewReader(message)); err == nil {
+		t.Error("PrehashMessage accepted a hasher that cannot stream")
+	}
+	f, _ = New(g, 2, 3)
+	if _, err := f.PrehashMessage(iotest.ErrReader(io.ErrUnexpectedEOF)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("PrehashMessage read error = %v", err)
+	}
+}
+
+func TestFinalizeCoverage(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 2, 3)
+	participants := make([]*Participant, 3)
+	broadcasts := make([]*Round1Data, 3)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	p1 := participants[0]
+	receive := func(from int) {
+		t.Helper()
+		if err := f.Round2ReceiveShare(p1, f.Round1PrivateSend(participants[from-1], 1), broadcasts[from-1].Commitments); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accumulate := func(broadcasts ...*Round1Data) *BroadcastAccumulator {
+		acc := f.NewBroadcastAccumulator(p1)
+		for _, b := range broadcasts {
+			if err := acc.Add(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return acc
+	}
+
+	// The share of participant 3 has not arrived
+	receive(2)
+	var inputErr *InputError
+	if _, err := f.Finalize(p1, broadcasts); !errors.As(err, &inputErr) || !strings.Contains(err.Error(), "participant 3") {
+		t.Errorf("Finalize without a share: err = %v, want an InputError naming participant 3", err)
+	}
+	if _, err := f.FinalizeAccumulated(p1, accumulate(broadcasts...)); !errors.As(err, &inputErr) {
+		t.Errorf("FinalizeAccumulated without a share: err = %v", err)
+	}
+
+	// Now its broadcast is missing, or the participant's own
+	receive(3)
+	for _, set := range [][]*Round1Data{broadcasts[:2], broadcasts[1:]} {
+		if _, err := f.Finalize(p1, set); !errors.As(err, &inputErr) {
+			t.Errorf("Finalize with %d of 3 broadcasts: err = %v", len(set), err)
+		}
+		if _, err := f.FinalizeAccumulated(p1, accumulate(set...)); !errors.As(err, &inputErr) {
+			t.Errorf("FinalizeAccumulated with %d of 3 broadcasts: err = %v", len(set), err)
+		}
+	}
+
+	// The rejected calls left the participant intact
+	if _, err := f.Finalize(p1, broadcasts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFinalizeDestroysParticipant(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 2)
+	p1, _ := f.NewParticipant(rand.Reader, 1)
+	p2, _ := f.NewParticipant(rand.Reader, 2)
+	broadcasts := []*Round1Data{p1.Round1Broadcast(), p2.Round1Broadcast()}
+	toP1 := f.Round1PrivateSend(p2, 1)
+	toP2 := f.Round1PrivateSend(p1, 2)
+	if err := f.Round2ReceiveShare(p1, toP1, broadcasts[1].Commitments); err != nil {
+		t.Fatal(err)
+	}
+
+	coeff := p1.coefficients[0]
+	own := p1.shares[1]
+	received := p1.receivedShares[string(toP1.FromID.Bytes())]
+	if _, err := f.Finalize(p1, broadcasts); err != nil {
+		t.Fatal(err)
+	}
+	if !coeff.IsZero() || !own.IsZero() || !received.IsZero() {
+		t.Error("Finalize did not wipe the secret polynomial and shares")
+	}
+	if _, err := f.Finalize(p1, broadcasts); err == nil {
+		t.Error("Finalize succeeded twice")
+	}
+	if err := f.Round2ReceiveShare(p1, toP1, broadcasts[1].Commitments); err == nil {
+		t.Error("Round2ReceiveShare accepted a destroyed participant")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Round1PrivateSend did not panic for a destroyed participant")
+			}
+		}()
+		f.Round1PrivateSend(p1, 2)
+	}()
+
+	toP1.Zeroize()
+	if !toP1.Share.IsZero() {
+		t.Error("Round1PrivateData.Zeroize did not wipe the share")
+	}
+	(&KeyShare{}).Zeroize()
+	(&SigningNonce{}).Zeroize()
+
+	// Destroy abandons a DKG that is not finalized
+	coeff = p2.coefficients[1]
+	p2.Destroy()
+	if !coeff.IsZero() {
+		t.Error("Destroy did not wipe the secret polynomial")
+	}
+	if err := f.Round2ReceiveShare(p2, toP2, broadcasts[0].Commitments); err == nil {
+		t.Error("Round2ReceiveShare accepted a destroyed participant")
+	}
+}
+
+func TestCheckConstantTime(t *testing.T) {
+	g := &bjj.BJJ{}
+	for _, name := range HasherNames() {
+		h, _ := NewHasher(name)
+		f, _ := NewWithHasher(g, 2, 3, h)
+		if err := f.CheckConstantTime(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	// A group that does not report its capabilities promises nothing
+	f, _ := New(struct{ group.Group }{g}, 2, 3)
+	if err := f.CheckConstantTime(); err == nil {
+		t.Error("accepted a group without capabilities")
+	}
+	f, _ = NewWithHasher(g, 2, 3, struct{ Hasher }{&SHA256Hasher{}})
+	if err := f.CheckConstantTime(); err == nil {
+		t.Error("accepted a hasher without capabilities")
+	}
+}
+
+func TestInputValidation(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	secret, _ := g.RandomScalar(rand.Reader)
+	share := &KeyShare{ID: f.Identifier(1), SecretKey: secret, GroupKey: g.Generator()}
+	other := &KeyShare{ID: f.Identifier(2), SecretKey: secret, GroupKey: g.Generator()}
+	nonce, own, _ := f.SignRound1(rand.Reader, share)
+	_, theirs, _ := f.SignRound1(rand.Reader, other)
+	_, third, _ := f.SignRound1(rand.Reader, &KeyShare{ID: f.Identifier(3)})
+	message := []byte("malformed")
+	var nilPoint *bjj.Point
+
+	signTests := []struct {
+		name        string
+		share       *KeyShare
+		nonce       *SigningNonce
+		commitments []*SigningCommitment
+		field       string
+	}{
+		{"nil share", nil, nonce, []*SigningCommitment{own}, "share"},
+		{"nil secret", &KeyShare{ID: share.ID, GroupKey: share.GroupKey}, nonce, []*SigningCommitment{own}, "share.SecretKey"},
+		{"nil nonce", share, nil, []*SigningCommitment{own}, "nonce"},
+		{"empty nonce", share, &SigningNonce{}, []*SigningCommitment{own}, "nonce.D"},
+		{"no commitments", share, nonce, nil, "commitments"},
+		{"nil commitment", share, nonce, []*SigningCommitment{own, nil}, "commitments[1]"},
+		{"nil point", share, nonce, []*SigningCommitment{own, {ID: theirs.ID, HidingPoint: theirs.HidingPoint}}, "commitments[1].BindingPoint"},
+		{"typed nil point", share, nonce, []*SigningCommitment{own, {ID: theirs.ID, HidingPoint: nilPoint, BindingPoint: theirs.BindingPoint}}, "commitments[1].HidingPoint"},
+		{"duplicate", share, nonce, []*SigningCommitment{own, own}, "commitments[1]"},
+		{"zero ID", share, nonce, []*SigningCommitment{own, {ID: g.NewScalar(), HidingPoint: theirs.HidingPoint, BindingPoint: theirs.BindingPoint}}, "commitments[1].ID"},
+		{"too many", share, nonce, []*SigningCommitment{own, theirs, theirs, theirs}, "commitments"},
+		{"not a signer", share, nonce, []*SigningCommitment{theirs, third}, "commitments"},
+	}
+	var few *InsufficientSignersError
+	if _, err := f.SignRound2(share, nonce, message, []*SigningCommitment{own}); !errors.As(err, &few) || few.Signers != 1 || few.Threshold != 2 {
+		t.Errorf("SignRound2 with one signer: err = %v, want *InsufficientSignersError", err)
+	}
+	if _, err := f.Aggregate(message, []*SigningCommitment{own}, nil); !errors.As(err, &few) {
+		t.Errorf("Aggregate with one signer: err = %v, want *InsufficientSignersError", err)
+	}
+	for _, tt := range signTests {
+		_, err := f.SignRound2(tt.share, tt.nonce, message, tt.commitments)
+		var inputErr *InputError
+		if !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+			t.Errorf("SignRound2 with %s: err = %v, want *InputError for %s", tt.name, err, tt.field)
+		}
+	}
+
+	commitments := []*SigningCommitment{own, theirs}
+	sigShare, err := f.SignRound2(share, nonce, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregateTests := []struct {
+		name   string
+		shares []*SignatureShare
+		field  string
+	}{
+		{"no shares", nil, "shares"},
+		{"nil share", []*SignatureShare{sigShare, nil}, "shares[1]"},
+		{"nil Z", []*SignatureShare{sigShare, {ID: theirs.ID}}, "shares[1].Z"},
+		{"duplicate", []*SignatureShare{sigShare, sigShare}, "shares[1]"},
+		{"unknown signer", []*SignatureShare{sigShare, {ID: f.Identifier(3), Z: sigShare.Z}}, "shares[1]"},
+	}
+	for _, tt := range aggregateTests {
+		_, err := f.Aggregate(message, commitments, tt.shares)
+		var inputErr *InputError
+		if !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+			t.Errorf("Aggregate with %s: err = %v, want *InputError for %s", tt.name, err, tt.field)
+		}
+	}
+	if f.VerifySignatureShare(nil, g.Generator(), g.Generator(), message, commitments) ||
+		f.VerifySignatureShare(sigShare, nil, g.Generator(), message, []*SigningCommitment{own, nil}) {
+		t.Error("VerifySignatureShare accepted malformed input")
+	}
+	if f.Verify(message, nil, g.Generator()) || f.Verify(message, &Signature{}, g.Generator()) {
+		t.Error("Verify accepted a malformed signature")
+	}
+
+	p1, _ := f.NewParticipant(rand.Reader, 1)
+	p2, _ := f.NewParticipant(rand.Reader, 2)
+	b2 := p2.Round1Broadcast()
+	toP1 := f.Round1PrivateSend(p2, 1)
+	receiveTests := []struct {
+		name        string
+		p           *Participant
+		data        *Round1PrivateData
+		commitments []group.Point
+		field       string
+	}{
+		{"nil participant", nil, toP1, b2.Commitments, "participant"},
+		{"nil data", p1, nil, b2.Commitments, "data"},
+		{"nil share", p1, &Round1PrivateData{FromID: toP1.FromID, ToID: toP1.ToID}, b2.Commitments, "data.Share"},
+		{"wrong recipient", p1, f.Round1PrivateSend(p2, 3), b2.Commitments, "data.ToID"},
+		{"zero sender", p1, &Round1PrivateData{FromID: g.NewScalar(), ToID: toP1.ToID, Share: toP1.Share}, b2.Commitments, "data.FromID"},
+		{"no commitments", p1, toP1, nil, "senderCommitments"},
+		{"nil commitment", p1, toP1, []group.Point{b2.Commitments[0], nil}, "senderCommitments[1]"},
+	}
+	for _, tt := range receiveTests {
+		err := f.Round2ReceiveShare(tt.p, tt.data, tt.commitments)
+		var inputErr *InputError
+		if !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+			t.Errorf("Round2ReceiveShare with %s: err = %v, want *InputError for %s", tt.name, err, tt.field)
+		}
+	}
+
+	finalizeTests := []struct {
+		name       string
+		p          *Participant
+		broadcasts []*Round1Data
+		field      string
+	}{
+		{"nil participant", nil, []*Round1Data{b2}, "participant"},
+		{"no broadcasts", p1, nil, "allBroadcasts"},
+		{"nil broadcast", p1, []*Round1Data{b2, nil}, "allBroadcasts[1]"},
+		{"short polynomial", p1, []*Round1Data{{ID: b2.ID, Commitments: b2.Commitments[:1]}}, "allBroadcasts[0].Commitments"},
+	}
+	for _, tt := range finalizeTests {
+		_, err := f.Finalize(tt.p, tt.broadcasts)
+		var inputErr *InputError
+		if !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+			t.Errorf("Finalize with %s: err = %v, want *InputError for %s", tt.name, err, tt.field)
+		}
+	}
+}
+
+func TestDuplicateParticipantIDs(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	if _, err := f.NewParticipant(rand.Reader, 0); err == nil {
+		t.Error("NewParticipant accepted ID 0")
+	}
+	p1, _ := f.NewParticipant(rand.Reader, 1)
+	p2, _ := f.NewParticipant(rand.Reader, 2)
+	impostor, _ := f.NewParticipant(rand.Reader, 2)
+	b1, b2, bi := p1.Round1Broadcast(), p2.Round1Broadcast(), impostor.Round1Broadcast()
+
+	var dup *DuplicateIDError
+	if err := f.Round2ReceiveShare(p1, f.Round1PrivateSend(p2, 1), b2.Commitments); err != nil {
+		t.Fatal(err)
+	}
+	err := f.Round2ReceiveShare(p1, f.Round1PrivateSend(impostor, 1), bi.Commitments)
+	if !errors.As(err, &dup) || !dup.ID.Equal(b2.ID) {
+		t.Errorf("second share from ID 2: err = %v, want *DuplicateIDError", err)
+	}
+	if err == nil || err.Error() != "frost: duplicate participant ID 2" {
+		t.Errorf("error message = %v", err)
+	}
+
+	// Another participant claiming the receiver's own ID
+	selfClaim, _ := f.NewParticipant(rand.Reader, 1)
+	bs := selfClaim.Round1Broadcast()
+	if err := f.Round2ReceiveShare(p1, f.Round1PrivateSend(selfClaim, 1), bs.Commitments); !errors.As(err, &dup) {
+		t.Errorf("share claiming the receiver's ID: err = %v, want *DuplicateIDError", err)
+	}
+
+	for name, broadcasts := range map[string][]*Round1Data{
+		"two broadcasts for ID 2":   {b1, b2, bi},
+		"own broadcast twice":       {b1, b2, b1},
+		"foreign broadcast for own": {bs, b2},
+	} {
+		if _, err := f.Finalize(p1, broadcasts); !errors.As(err, &dup) {
+			t.Errorf("Finalize with %s: err = %v, want *DuplicateIDError", name, err)
+		}
+	}
+	if _, err := f.Finalize(p1, []*Round1Data{b1, b2}); err != nil {
+		t.Errorf("Finalize with distinct IDs: %v", err)
+	}
+}
+
+func TestRound2ReceiveShares(t *testing.T) {
+	g := &bjj.BJJ{}
+	const n = 20
+	f, _ := New(g, 7, n)
+	deal := func() (*Participant, []*Round1PrivateData, [][]group.Point) {
+		p, _ := f.NewParticipant(rand.Reader, 1)
+		data := make([]*Round1PrivateData, n-1)
+		commitments := make([][]group.Point, n-1)
+		for i := range data {
+			dealer, _ := f.NewParticipant(rand.Reader, i+2)
+			data[i] = f.Round1PrivateSend(dealer, 1)
+			commitments[i] = dealer.Round1Broadcast().Commitments
+		}
+		return p, data, commitments
+	}
+
+	// The batch stores exactly what one call per share stores
+	p, data, commitments := deal()
+	q := &Participant{id: p.id, coefficients: cloneScalars(p.coefficients), receivedShares: make(map[string]group.Scalar)}
+	if err := f.Round2ReceiveShares(p, data, commitments); err != nil {
+		t.Fatal(err)
+	}
+	for i := range data {
+		if err := f.Round2ReceiveShare(q, data[i], commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !maps.EqualFunc(p.receivedShares, q.receivedShares, group.Scalar.Equal) {
+		t.Error("Round2ReceiveShares stored different shares")
+	}
+
+	// Invalid shares: the first is reported and none is stored
+	p, data, commitments = deal()
+	one := g.NewScalar().SetUint64(1)
+	for _, i := range []int{15, 4} {
+		data[i].Share = g.NewScalar().Add(data[i].Share, one)
+	}
+	var invalid *InvalidShareError
+	err := f.Round2ReceiveShares(p, data, commitments)
+	if !errors.As(err, &invalid) || !invalid.ID.Equal(data[4].FromID) {
+		t.Errorf("err = %v, want *InvalidShareError from participant 6", err)
+	}
+	if len(p.receivedShares) != 0 {
+		t.Errorf("%d shares stored despite invalid shares", len(p.receivedShares))
+	}
+	if err := f.Round2ReceiveShare(p, data[15], commitments[15]); !errors.As(err, &invalid) {
+		t.Errorf("Round2ReceiveShare: err = %v, want *InvalidShareError", err)
+	}
+
+	// Duplicate senders within the batch and with stored shares
+	p, data, commitments = deal()
+	var dup *DuplicateIDError
+	twice := append(slices.Clone(data), data[3])
+	if err := f.Round2ReceiveShares(p, twice, append(slices.Clone(commitments), commitments[3])); !errors.As(err, &dup) {
+		t.Errorf("batch with a repeated sender: err = %v, want *DuplicateIDError", err)
+	}
+	if err := f.Round2ReceiveShare(p, data[3], commitments[3]); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Round2ReceiveShares(p, data, commitments); !errors.As(err, &dup) {
+		t.Errorf("batch with a stored sender: err = %v, want *DuplicateIDError", err)
+	}
+
+	var inputErr *InputError
+	if err := f.Round2ReceiveShares(p, data, commitments[1:]); !errors.As(err, &inputErr) {
+		t.Errorf("mismatched lengths: err = %v, want *InputError", err)
+	}
+	if err := f.Round2ReceiveShares(nil, nil, nil); !errors.As(err, &inputErr) {
+		t.Errorf("nil participant: err = %v, want *InputError", err)
+	}
+}
+
+func TestSignerSetBinding(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	shares := runDKG(t, f, 3)
+	message := []byte("signer set")
+
+	nonces := make([]*SigningNonce, 3)
+	commitments := make([]*SigningCommitment, 3)
+	for i, ks := range shares {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+
+	// Signer 1 signs for the set {1, 2}
+	set12 := []*SigningCommitment{commitments[0], commitments[1]}
+	share1, err := f.SignRound2(shares[0], nonces[0], message, set12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.VerifySignatureShare(share1, shares[0].PublicKey, shares[0].GroupKey, message, set12) {
+		t.Fatal("share does not verify for its own signer set")
+	}
+
+	// The share cannot be reused for {1, 3}, even with signer 1's
+	// commitment unchanged
+	set13 := []*SigningCommitment{commitments[0], commitments[2]}
+	if f.VerifySignatureShare(share1, shares[0].PublicKey, shares[0].GroupKey, message, set13) {
+		t.Error("share verifies for another signer set")
+	}
+	share3, _ := f.SignRound2(shares[2], nonces[2], message, set13)
+	sig, err := f.Aggregate(message, set13, []*SignatureShare{share1, share3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Verify(message, sig, shares[0].GroupKey) {
+		t.Error("shares for different signer sets combined into a valid signature")
+	}
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 3, 5)
+	commitments := make([]*SigningCommitment, 4)
+	for i, id := range []int{1, 2, 4, 5} {
+		commitments[i] = &SigningCommitment{ID: f.Identifier(id), HidingPoint: g.Generator(), BindingPoint: g.Generator()}
+	}
+
+	lambdas, err := f.LagrangeCoefficients(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The coefficients interpolate the constant polynomial 1 at 0
+	sum := g.NewScalar()
+	for i, c := range commitments {
+		single, err := f.lagrangeCoefficient(c.ID, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !single.Equal(lambdas[i]) {
+			t.Errorf("coefficient %d differs from lagrangeCoefficient", i)
+		}
+		sum.Add(sum, lambdas[i])
+	}
+	if !sum.Equal(f.Identifier(1)) {
+		t.Error("Lagrange coefficients do not sum to 1")
+	}
+
+	var dup *DuplicateIDError
+	repeated := append(commitments[:2:2], commitments[0])
+	if _, err := f.lagrangeCoefficient(commitments[0].ID, repeated); !errors.As(err, &dup) {
+		t.Errorf("lagrangeCoefficient with a repeated ID: err = %v, want *DuplicateIDError", err)
+	}
+	var inputErr *InputError
+	if _, err := f.LagrangeCoefficients(repeated); !errors.As(err, &inputErr) {
+		t.Errorf("LagrangeCoefficients with a repeated ID: err = %v, want *InputError", err)
+	}
+}
+
+func TestTranscriptDigest(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	participants := make([]*Participant, 3)
+	broadcasts := make([]*Round1Data, 3)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j := range participants {
+			if i != j {
+				f.Round2ReceiveShare(participants[j], f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments)
+			}
+		}
+	}
+
+	digest, err := f.TranscriptDigest(broadcasts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered, _ := f.TranscriptDigest([]*Round1Data{broadcasts[2], broadcasts[0], broadcasts[1]})
+	if !bytes.Equal(digest, reordered) {
+		t.Error("digest depends on the order of the broadcasts")
+	}
+	ks, err := f.Finalize(participants[0], broadcasts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ks.TranscriptDigest, digest) {
+		t.Error("Finalize returned a different transcript digest")
+	}
+
+	// An equivocating dealer changes the digest
+	forged := &Round1Data{ID: broadcasts[2].ID, Commitments: slices.Clone(broadcasts[2].Commitments)}
+	forged.Commitments[1] = g.Generator()
+	other, _ := f.TranscriptDigest([]*Round1Data{broadcasts[0], broadcasts[1], forged})
+	if bytes.Equal(other, digest) {
+		t.Error("digest does not cover every commitment")
+	}
+	f4, _ := New(g, 2, 4)
+	if params, _ := f4.TranscriptDigest(broadcasts); bytes.Equal(params, digest) {
+		t.Error("digest does not cover the threshold parameters")
+	}
+
+	var dup *DuplicateIDError
+	if _, err := f.TranscriptDigest([]*Round1Data{broadcasts[0], broadcasts[0]}); !errors.As(err, &dup) {
+		t.Errorf("TranscriptDigest with a repeated ID: err = %v, want *DuplicateIDError", err)
+	}
+}
+
+// zeroReader returns zeros, like a broken random source.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// repeatingReader returns the same random-looking block on every read.
+type repeatingReader struct{ block []byte }
+
+func (r repeatingReader) Read(p []byte) (int, error) {
+	return copy(p, r.block), nil
+}
+
+func TestWeakRandomness(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	share := runDKG(t, f, 3)[0]
+	block := make([]byte, 64)
+	rand.Read(block)
+
+	for name, r := range map[string]io.Reader{
+		"zero":      zeroReader{},
+		"repeating": repeatingReader{block},
+	} {
+		if _, _, err := f.SignRound1(r, share); !errors.Is(err, ErrWeakRandomness) {
+			t.Errorf("SignRound1 with a %s reader: err = %v, want ErrWeakRandomness", name, err)
+		}
+		if _, err := f.NewParticipant(r, 1); !errors.Is(err, ErrWeakRandomness) {
+			t.Errorf("NewParticipant with a %s reader: err = %v, want ErrWeakRandomness", name, err)
+		}
+		// Hedged nonces stay distinct for distinct messages
+		n1, _, err := f.SignRound1Hedged(r, share, []byte("one"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		n2, _, _ := f.SignRound1Hedged(r, share, []byte("two"))
+		if n1.D.Equal(n2.D) {
+			t.Errorf("hedged nonces repeat with a %s reader", name)
+		}
+	}
+	if _, _, err := f.SignRound1(rand.Reader, share); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	p, _ := f.NewParticipant(rand.Reader, 1)
+	private := f.Round1PrivateSend(p, 2)
+	share := runDKG(t, f, 3)[0]
+	nonce, _, _ := f.SignRound1(rand.Reader, share)
+
+	secrets := map[string]group.Scalar{
+		"key share":   share.SecretKey,
+		"nonce D":     nonce.D,
+		"nonce E":     nonce.E,
+		"DKG share":   private.Share,
+		"coefficient": p.coefficients[0],
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	var out []string
+	for _, v := range []any{share, *share, nonce, *nonce, private, *private, p, *p} {
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			out = append(out, fmt.Sprintf(verb, v))
+		}
+		logger.Info("value", "v", v)
+	}
+	out = append(out, logs.String())
+
+	for _, s := range out {
+		if !strings.Contains(s, "[REDACTED]") {
+			t.Errorf("output does not redact secrets: %s", s)
+		}
+		for name, secret := range secrets {
+			for _, enc := range []string{hex.EncodeToString(secret.Bytes()), secret.(*bjj.Scalar).BigInt().String()} {
+				if strings.Contains(s, enc) {
+					t.Errorf("output leaks the %s: %s", name, s)
+				}
+			}
+		}
+	}
+	if got := share.String(); !strings.Contains(got, "ID: 1,") || !strings.Contains(got, hex.EncodeToString(share.GroupKey.Bytes())) {
+		t.Errorf("KeyShare.String() = %s, want the public fields", got)
+	}
+	p.Destroy()
+	if got := p.String(); !strings.Contains(got, "<destroyed>") {
+		t.Errorf("destroyed Participant.String() = %s", got)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	message := []byte("compact")
+	sig, groupKey := thresholdSign(t, f, message)
+
+	data := sig.Bytes()
+	if len(data) != f.SignatureSize() {
+		t.Fatalf("len(Bytes()) = %d, want %d", len(data), f.SignatureSize())
+	}
+	parsed, err := f.ParseSignature(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Verify(message, parsed, groupKey) || !bytes.Equal(parsed.Bytes(), data) {
+		t.Error("parsed signature differs from the original")
+	}
+
+	n := g.ElementSize()
+	highZ := slices.Clone(data)
+	for i := n; i < len(highZ); i++ {
+		highZ[i] = 0xff
+	}
+	identity := append(g.NewPoint().Bytes(), data[n:]...)
+	for name, bad := range map[string][]byte{
+		"truncated":  data[:len(data)-1],
+		"Z >= order": highZ,
+		"identity R": identity,
+	} {
+		if _, err := f.ParseSignature(bad); err == nil {
+			t.Errorf("ParseSignature accepted a signature with %s", name)
+		}
+	}
+
+	if f.Verify(message, &Signature{R: g.NewPoint(), Z: sig.Z}, groupKey) {
+		t.Error("Verify accepted an identity R")
+	}
+	var inputErr *InputError
+	if err := f.checkSignature(&Signature{R: g.NewPoint(), Z: sig.Z}); !errors.As(err, &inputErr) || inputErr.Field != "signature.R" {
+		t.Errorf("checkSignature with identity R: err = %v", err)
+	}
+}
+
+func TestSignatureShareProof(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	keyShares := runDKG(t, f, 3)
+	message := []byte("share proof")
+
+	signers := []*KeyShare{keyShares[0], keyShares[2]}
+	nonces := make([]*SigningNonce, 2)
+	commitments := make([]*SigningCommitment, 2)
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+	groupKey := keyShares[0].GroupKey
+
+	ks := signers[0]
+	share, err := f.SignRound2(ks, nonces[0], message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := f.ProveSignatureShare(rand.Reader, ks, share, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.PublicKey.Equal(ks.PublicKey) {
+		t.Error("proof.PublicKey is not the signer's verification share")
+	}
+	if !f.VerifySignatureShareProof(share, proof, groupKey, message, commitments) {
+		t.Fatal("valid proof rejected")
+	}
+
+	tampered := &SignatureShare{ID: share.ID, Z: g.NewScalar().Add(share.Z, share.Z)}
+	if _, err := f.ProveSignatureShare(rand.Reader, ks, tampered, message, commitments); err == nil {
+		t.Error("ProveSignatureShare accepted an invalid share")
+	}
+	if f.VerifySignatureShareProof(tampered, proof, groupKey, message, commitments) {
+		t.Error("proof verified for a tampered share")
+	}
+	if f.VerifySignatureShareProof(share, proof, groupKey, []byte("other message"), commitments) {
+		t.Error("proof verified for another message")
+	}
+
+	// A proof claiming another signer's public key must fail
+	forged := *proof
+	forged.PublicKey = keyShares[1].PublicKey
+	if f.VerifySignatureShareProof(share, &forged, groupKey, message, commitments) {
+		t.Error("proof verified with another participant's public key")
+	}
+	forged = *proof
+	forged.Response = g.NewScalar().Add(proof.Response, proof.Challenge)
+	if f.VerifySignatureShareProof(share, &forged, groupKey, message, commitments) {
+		t.Error("proof verified with a modified response")
+	}
+
+	if f.VerifySignatureShareProof(share, nil, groupKey, message, commitments) ||
+		f.VerifySignatureShareProof(share, &SignatureShareProof{}, groupKey, message, commitments) {
+		t.Error("incomplete proof verified")
+	}
+	if _, err := f.ProveSignatureShare(zeroReader{}, ks, share, message, commitments); !errors.Is(err, ErrWeakRandomness) {
+		t.Errorf("ProveSignatureShare with a zero reader: err = %v, want ErrWeakRandomness", err)
+	}
+}
+
+func TestIntermediates(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	keyShares := runDKG(t, f, 3)
+	message := []byte("intermediates")
+
+	signers := []*KeyShare{keyShares[2], keyShares[0]}
+	nonces := make([]*SigningNonce, 2)
+	commitments := make([]*SigningCommitment, 2)
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+	groupKey := keyShares[0].GroupKey
+	inter, err := f.Intermediates(groupKey, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares := make([]*SignatureShare, 2)
+	for i, ks := range signers {
+		if shares[i], err = f.SignRound2(ks, nonces[i], message, commitments); err != nil {
+			t.Fatal(err)
+		}
+		// z_i = d_i + e_i*rho_i + lambda_i*s_i*c, with values in commitment order
+		z := g.NewScalar().Mul(nonces[i].E, inter.BindingFactors[i])
+		z.Add(z, nonces[i].D)
+		lc := g.NewScalar().Mul(inter.Lagrange[i], ks.SecretKey)
+		z.Add(z, lc.Mul(lc, inter.Challenge))
+		if !z.Equal(shares[i].Z) {
+			t.Errorf("share %d does not match the intermediates", i)
+		}
+	}
+	sig, err := f.Aggregate(message, commitments, shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.R.Equal(inter.GroupCommitment) {
+		t.Error("GroupCommitment is not the signature's R")
+	}
+
+	if _, err := f.Intermediates(nil, message, commitments); err == nil {
+		t.Error("nil group key accepted")
+	}
+	if _, err := f.Intermediates(groupKey, message, commitments[:1]); err == nil {
+		t.Error("too few commitments accepted")
+	}
+}
+
+func TestCheckKeyShares(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 3, 5)
+	keyShares := runDKG(t, f, 5)
+	if err := f.CheckKeyShares(keyShares); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.CheckKeyShares([]*KeyShare{keyShares[4], keyShares[1], keyShares[2]}); err != nil {
+		t.Fatal(err)
+	}
+
+	// tampered returns a copy of keyShares with share i replaced by a
+	// consistent share holding a different secret key
+	tampered := func(i int) []*KeyShare {
+		shares := slices.Clone(keyShares)
+		ks := *shares[i]
+		one := g.NewScalar().SetUint64(1)
+		ks.SecretKey = g.NewScalar().Add(ks.SecretKey, one)
+		ks.PublicKey = g.ScalarBaseMult(ks.SecretKey)
+		shares[i] = &ks
+		return shares
+	}
+	var inconsistent *InconsistentKeyShareError
+	err := f.CheckKeyShares(tampered(4))
+	if !errors.As(err, &inconsistent) || inconsistent.ID == nil || !inconsistent.ID.Equal(keyShares[4].ID) {
+		t.Errorf("extra share: err = %v, want share 5 blamed", err)
+	}
+	err = f.CheckKeyShares(tampered(0)[:3])
+	if !errors.As(err, &inconsistent) || inconsistent.ID != nil {
+		t.Errorf("basis share: err = %v, want the group key to mismatch", err)
+	}
+
+	shares := slices.Clone(keyShares)
+	ks := *shares[1]
+	ks.PublicKey = shares[2].PublicKey
+	shares[1] = &ks
+	if err := f.CheckKeyShares(shares); !errors.As(err, &inconsistent) || !inconsistent.ID.Equal(ks.ID) {
+		t.Errorf("public key: err = %v", err)
+	}
+
+	other := runDKG(t, f, 5)
+	if err := f.CheckKeyShares(append(slices.Clone(keyShares[:3]), other[3])); !errors.As(err, &inconsistent) {
+		t.Errorf("share of another key: err = %v", err)
+	}
+
+	var dup *DuplicateIDError
+	if err := f.CheckKeyShares(append(slices.Clone(keyShares[:3]), keyShares[0])); !errors.As(err, &dup) {
+		t.Errorf("duplicate: err = %v, want DuplicateIDError", err)
+	}
+	var input *InputError
+	if err := f.CheckKeyShares(keyShares[:2]); !errors.As(err, &input) {
+		t.Errorf("too few shares: err = %v, want InputError", err)
+	}
+	if err := f.CheckKeyShares([]*KeyShare{keyShares[0], nil, keyShares[2]}); !errors.As(err, &input) {
+		t.Errorf("nil share: err = %v, want InputError", err)
+	}
+}
+
+// recordingOperator is a SecretKeyOperator that keeps the secret key in
+// memory and records its last input.
+type recordingOperator struct {
+	s    group.Scalar
+	err  error
+	last *ShareTermInput
+}
+
+func (o *recordingOperator) ShareTerm(in *ShareTermInput) (group.Scalar, error) {
+	o.last = in
+	if o.err != nil {
+		return nil, o.err
+	}
+	return localKey{o.s}.ShareTerm(in)
+}
+
+func TestSignRound2WithOperator(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 3)
+	keyShares := runDKG(t, f, 3)
+	message := []byte("external key")
+
+	signers := keyShares[1:]
+	nonces := make([]*SigningNonce, 2)
+	commitments := make([]*SigningCommitment, 2)
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+
+	// The operator holds the secret; the share passed in has none
+	op := &recordingOperator{s: signers[0].SecretKey}
+	public := &KeyShare{ID: signers[0].ID, PublicKey: signers[0].PublicKey, GroupKey: signers[0].GroupKey}
+	got, err := f.SignRound2WithOperator(public, op, nonces[0], message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := f.SignRound2(signers[0], nonces[0], message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Z.Equal(want.Z) {
+		t.Fatal("share differs from SignRound2")
+	}
+
+	inter, err := f.Intermediates(public.GroupKey, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !op.last.Lambda.Equal(inter.Lagrange[0]) || !op.last.Challenge.Equal(inter.Challenge) ||
+		!op.last.GroupCommitment.Equal(inter.GroupCommitment) || !bytes.Equal(op.last.Message, message) {
+		t.Error("operator input does not match the intermediates")
+	}
+
+	errHSM := errors.New("token removed")
+	if _, err := f.SignRound2WithOperator(public, &recordingOperator{err: errHSM}, nonces[0], message, commitments); !errors.Is(err, errHSM) {
+		t.Errorf("err = %v, want the operator's error", err)
+	}
+	var input *InputError
+	if _, err := f.SignRound2WithOperator(public, nil, nonces[0], message, commitments); !errors.As(err, &input) {
+		t.Errorf("nil operator: err = %v, want InputError", err)
+	}
+	if _, err := f.SignRound2(public, nonces[0], message, commitments); !errors.As(err, &input) {
+		t.Errorf("SignRound2 without secret: err = %v, want InputError", err)
+	}
+}
+
+func TestPreparedSession(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 3, 5)
+	keyShares := runDKG(t, f, 5)
+	message := []byte("prepared once")
+
+	signers := []*KeyShare{keyShares[4], keyShares[1], keyShares[2]}
+	nonces := make([]*SigningNonce, len(signers))
+	commitments := make([]*SigningCommitment, len(signers))
+	for i, ks := range signers {
+		nonces[i], commitments[i], _ = f.SignRound1(rand.Reader, ks)
+	}
+	groupKey := keyShares[0].GroupKey
+	prepared, err := f.Prepare(groupKey, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inter, err := f.Intermediates(groupKey, message, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prepared.GroupCommitment().Equal(inter.GroupCommitment) || !prepared.Challenge().Equal(inter.Challenge) {
+		t.Error("prepared session differs from the intermediates")
+	}
+
+	shares := make([]*SignatureShare, len(signers))
+	for i, ks := range signers {
+		if shares[i], err = prepared.Sign(ks, nonces[i]); err != nil {
+			t.Fatal(err)
+		}
+		want, err := f.SignRound2(ks, nonces[i], message, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !shares[i].Z.Equal(want.Z) {
+			t.Errorf("share %d differs from SignRound2", i)
+		}
+		if !prepared.VerifyShare(shares[i], ks.PublicKey) {
+			t.Errorf("share %d does not verify", i)
+		}
+	}
+	public := &KeyShare{ID: signers[0].ID, PublicKey: signers[0].PublicKey, GroupKey: groupKey}
+	opShare, err := prepared.SignWithOperator(public, &recordingOperator{s: signers[0].SecretKey}, nonces[0])
+	if err != nil || !opShare.Z.Equal(shares[0].Z) {
+		t.Errorf("SignWithOperator: err = %v, or share differs", err)
+	}
+	if prepared.VerifyShare(shares[1], signers[0].PublicKey) {
+		t.Error("share verified under another signer's public key")
+	}
+
+	sig, err := prepared.Aggregate(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := f.Aggregate(message, commitments, shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig.Bytes(), want.Bytes()) || !f.Verify(message, sig, groupKey) {
+		t.Error("prepared aggregate differs from Aggregate or does not verify")
+	}
+
+	var input *InputError
+	other := *signers[0]
+	other.GroupKey = g.Generator()
+	if _, err := prepared.Sign(&other, nonces[0]); !errors.As(err, &input) {
+		t.Errorf("key share of another group: err = %v, want InputError", err)
+	}
+	if _, err := prepared.Sign(keyShares[0], nonces[0]); !errors.As(err, &input) {
+		t.Errorf("signer without commitment: err = %v, want InputError", err)
+	}
+	if _, err := prepared.Aggregate(shares[:2]); !errors.As(err, &input) {
+		t.Errorf("missing share: err = %v, want InputError", err)
+	}
+	if _, err := f.Prepare(groupKey, message, commitments[:2]); err == nil {
+		t.Error("Prepare accepted fewer than threshold signers")
+	}
+}
+
+func TestParallelAggregate(t *testing.T) {
+	// All 200 participants sign, so that aggregation is split into chunks
+	f, _ := New(&bjj.BJJ{}, 2, 200)
+	message := []byte("many signers")
+	keyShares := dealKeyShares(t, f, 200)
+	groupKey := keyShares[0].GroupKey
+	commitments, sigShares := signAll(t, f, keyShares, message)
+	sig, err := f.WithParallelism(1).Aggregate(message, commitments, sigShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Verify(message, sig, groupKey) {
+		t.Fatal("signature invalid")
+	}
+
+	for _, workers := range []int{1, 2, 3, 16} {
+		fp := f.WithParallelism(workers)
+		if k := fp.workers(len(sigShares), minAggregateChunk); k != min(workers, 3) {
+			t.Errorf("parallelism %d: %d chunks", workers, k)
+		}
+		got, err := fp.Aggregate(message, commitments, sigShares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.R.Equal(sig.R) || !got.Z.Equal(sig.Z) {
+			t.Errorf("parallelism %d: signature differs", workers)
+		}
+		prepared, err := fp.Prepare(groupKey, message, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := prepared.Aggregate(sigShares); err != nil || !got.R.Equal(sig.R) || !got.Z.Equal(sig.Z) {
+			t.Errorf("parallelism %d: prepared signature differs (%v)", workers, err)
+		}
+	}
+	if f.parallelism != 0 || f.WithParallelism(-1).parallelism != 0 {
+		t.Error("WithParallelism modified f or kept a negative limit")
+	}
+}
+
+func FuzzParseSignature(f *testing.F) {
+	g := &bjj.BJJ{}
+	fr, _ := New(g, 2, 3)
+	one := g.NewScalar().SetUint64(1)
+	f.Add((&Signature{R: g.Generator(), Z: one}).Bytes())
+	f.Add((&Signature{R: g.NewPoint(), Z: one}).Bytes())
+	f.Add([]byte{})
+	f.Add(make([]byte, fr.SignatureSize()-1))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sig, err := fr.ParseSignature(data)
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(sig.Bytes(), data) {
+			t.Fatalf("%x parses to a signature encoded as %x", data, sig.Bytes())
+		}
+		fr.Verify([]byte("message"), sig, g.Generator())
+	})
+}
+
+func TestEvalPolynomialRange(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 2, 3)
+	for _, tt := range []struct{ degree, n int }{{1, 1}, {1, 50}, {2, 2}, {3, 3}, {4, 60}, {10, 40}} {
+		coeffs := make([]group.Scalar, tt.degree+1)
+		for i := range coeffs {
+			coeffs[i], _ = f.group.RandomScalar(rand.Reader)
+		}
+		values := f.evalPolynomialRange(coeffs, tt.n)
+		if len(values) != tt.n {
+			t.Fatalf("degree %d: %d values, want %d", tt.degree, len(values), tt.n)
+		}
+		for i, v := range values {
+			if !v.Equal(f.evalPolynomial(coeffs, f.Identifier(i+1))) {
+				t.Fatalf("degree %d: value at %d differs from Horner's rule", tt.degree, i+1)
+			}
+		}
+	}
+
+	// Shares for recipients past n are still evaluated directly
+	p, _ := f.NewParticipant(rand.Reader, 1)
+	if share := f.Round1PrivateSend(p, 7).Share; !share.Equal(f.evalPolynomial(p.coefficients, f.Identifier(7))) {
+		t.Error("share for recipient 7 is wrong")
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	g := &bjj.BJJ{}
+	f, _ := New(g, 2, 5)
+	for _, id := range []int{1, 3, 5, 6, maxCachedIDs + 1} {
+		want := g.NewScalar().SetUint64(uint64(id))
+		got := f.Identifier(id)
+		if !got.Equal(want) {
+			t.Fatalf("Identifier(%d) is wrong", id)
+		}
+		// Callers own the returned scalars
+		got.Add(got, got)
+		if !f.Identifier(id).Equal(want) {
+			t.Fatalf("modifying Identifier(%d) changed the cache", id)
+		}
+	}
+	one := f.One()
+	if !one.Equal(g.NewScalar().SetUint64(1)) {
+		t.Fatal("One is not 1")
+	}
+	one.Add(one, one)
+	if !f.One().Equal(f.Identifier(1)) {
+		t.Fatal("modifying One changed the cache")
+	}
+}
+
+func TestBroadcastAccumulator(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 3, 5)
+	participants := make([]*Participant, 5)
+	broadcasts := make([]*Round1Data, 5)
+	for i := range participants {
+		participants[i], _ = f.NewParticipant(rand.Reader, i+1)
+		broadcasts[i] = participants[i].Round1Broadcast()
+	}
+	for i, sender := range participants {
+		for j, p := range participants {
+			if i != j {
+				if err := f.Round2ReceiveShare(p, f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	coordinator := f.NewBroadcastAccumulator(nil)
+	for _, b := range broadcasts {
+		if err := coordinator.Add(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	digest, _ := f.TranscriptDigest(broadcasts)
+	if coordinator.Len() != 5 || !bytes.Equal(coordinator.TranscriptDigest(), digest) {
+		t.Error("accumulated transcript digest differs")
+	}
+	for id := 1; id <= 5; id++ {
+		if !coordinator.VerificationShare(id).Equal(f.VerificationShare(broadcasts, id)) {
+			t.Errorf("verification share %d differs", id)
+		}
+	}
+
+	var dup *DuplicateIDError
+	var inputErr *InputError
+	if err := coordinator.Add(broadcasts[4]); !errors.As(err, &dup) {
+		t.Errorf("repeated broadcast: err = %v, want DuplicateIDError", err)
+	}
+	if err := coordinator.Add(broadcasts[1]); !errors.As(err, &inputErr) || inputErr.Field != "broadcast.ID" {
+		t.Errorf("broadcast out of order: err = %v", err)
+	}
+	if err := coordinator.Add(&Round1Data{ID: f.Identifier(6)}); !errors.As(err, &inputErr) {
+		t.Errorf("broadcast without commitments: err = %v", err)
+	}
+	if coordinator.Len() != 5 {
+		t.Error("rejected broadcasts were added")
+	}
+	own := f.NewBroadcastAccumulator(participants[0])
+	forged := &Round1Data{ID: broadcasts[0].ID, Commitments: broadcasts[1].Commitments}
+	if err := own.Add(forged); !errors.As(err, &dup) {
+		t.Errorf("forged own broadcast: err = %v, want DuplicateIDError", err)
+	}
+
+	// Finalize and FinalizeAccumulated agree
+	want, err := f.Finalize(participants[0], broadcasts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := f.NewBroadcastAccumulator(participants[1])
+	for _, b := range broadcasts {
+		if err := acc.Add(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.FinalizeAccumulated(participants[2], acc); !errors.As(err, &inputErr) {
+		t.Errorf("accumulator of another participant: err = %v", err)
+	}
+	if _, err := f.FinalizeAccumulated(participants[2], f.NewBroadcastAccumulator(participants[2])); !errors.As(err, &inputErr) {
+		t.Errorf("empty accumulator: err = %v", err)
+	}
+	got, err := f.FinalizeAccumulated(participants[1], acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GroupKey.Equal(want.GroupKey) || !bytes.Equal(got.TranscriptDigest, want.TranscriptDigest) {
+		t.Error("FinalizeAccumulated and Finalize disagree")
+	}
+	if !got.PublicKey.Equal(coordinator.VerificationShare(2)) {
+		t.Error("public key differs from the verification share")
+	}
+}
+
+func TestSigningAllocations(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 2, 10)
+	keyShares := dealKeyShares(t, f, 10)
+	commitments, shares := signAll(t, f, keyShares, []byte("allocations"))
+
+	// Besides the set of seen IDs, validation may only allocate the two
+	// copies of each ID needed to fill it
+	limit := float64(2*len(commitments) + 4)
+	if n := testing.AllocsPerRun(10, func() { f.checkCommitments(commitments) }); n > limit {
+		t.Errorf("checkCommitments: %v allocations, want at most %v", n, limit)
+	}
+	// Matching shares additionally encodes each share's ID once to look
+	// it up in the index of commitments
+	limit += float64(len(shares))
+	if n := testing.AllocsPerRun(10, func() { f.checkShares(shares, commitments) }); n > limit {
+		t.Errorf("checkShares: %v allocations, want at most %v", n, limit)
+	}
+}
+
+func TestDKGMessageEncoding(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 3, 5)
+	p, _ := f.NewParticipant(rand.Reader, 2)
+	b := p.Round1Broadcast()
+
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.ParseRound1Data(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.ID.Equal(b.ID) || !slices.EqualFunc(got.Commitments, b.Commitments, group.Point.Equal) {
+		t.Error("broadcast changed in round trip")
+	}
+
+	priv := f.Round1PrivateSend(p, 4)
+	privData, err := priv.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotPriv, err := f.ParseRound1PrivateData(privData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotPriv.FromID.Equal(priv.FromID) || !gotPriv.ToID.Equal(priv.ToID) || !gotPriv.Share.Equal(priv.Share) {
+		t.Error("private share changed in round trip")
+	}
+
+	// Malformed broadcasts
+	other, _ := New(&bjj.BJJ{}, 2, 5)
+	for name, bad := range map[string][]byte{
+		"empty":          nil,
+		"version":        append([]byte{2}, data[1:]...),
+		"type":           privData,
+		"truncated":      data[:len(data)-1],
+		"trailing bytes": append(slices.Clone(data), 0),
+		"bad point":      append(slices.Clone(data[:len(data)-32]), bytes.Repeat([]byte{0xff}, 32)...),
+	} {
+		if _, err := f.ParseRound1Data(bad); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	if _, err := other.ParseRound1Data(data); err == nil {
+		t.Error("broadcast for another threshold accepted")
+	}
+	zero := f.group.NewScalar()
+	zeroBroadcast := &Round1Data{ID: zero, Commitments: b.Commitments}
+	zeroData, err := zeroBroadcast.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.ParseRound1Data(zeroData); err == nil {
+		t.Error("broadcast with zero ID accepted")
+	}
+	var inputErr *InputError
+	if err := f.checkBroadcast("broadcast", zeroBroadcast); !errors.As(err, &inputErr) || inputErr.Field != "broadcast.ID" {
+		t.Errorf("checkBroadcast accepted a zero ID: %v", err)
+	}
+
+	// Malformed private shares
+	nonCanonical := slices.Clone(privData)
+	copy(nonCanonical[len(nonCanonical)-32:], bytes.Repeat([]byte{0xff}, 32))
+	zeroFrom, _ := (&Round1PrivateData{FromID: zero, ToID: priv.ToID, Share: priv.Share}).MarshalBinary()
+	zeroTo, _ := (&Round1PrivateData{FromID: priv.FromID, ToID: zero, Share: priv.Share}).MarshalBinary()
+	for name, bad := range map[string][]byte{
+		"type":          data,
+		"truncated":     privData[:len(privData)-1],
+		"non-canonical": nonCanonical,
+		"zero FromID":   zeroFrom,
+		"zero ToID":     zeroTo,
+	} {
+		if _, err := f.ParseRound1PrivateData(bad); err == nil {
+			t.Errorf("private share %s: accepted", name)
+		}
+	}
+
+	if _, err := (&Round1Data{}).MarshalBinary(); err == nil {
+		t.Error("incomplete broadcast encoded")
+	}
+}
+
+func TestSigningMessageEncoding(t *testing.T) {
+	f, _ := New(&bjj.BJJ{}, 2, 3)
+	shares := dealKeyShares(t, f, 3)[:2]
+	message := []byte("over the wire")
+	commitments, sigShares := signAll(t, f, shares, message)
+
+	// Coordinator side: everything arrives as bytes
+	received := make([]*SigningCommitment, len(commitments))
+	for i, c := range commitments {
+		data, err := c.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if received[i], err = f.ParseSigningCommitment(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receivedShares := make([]*SignatureShare, len(sigShares))
+	for i, s := range sigShares {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receivedShares[i], err = f.ParseSignatureShare(data); err != nil {
+			t.Fatal(err)
+		}
+		if !receivedShares[i].Z.Equal(s.Z) || !receivedShares[i].ID.Equal(s.ID) {
+			t.Error("signature share changed in round trip")
+		}
+	}
+	sig, err := f.Aggregate(message, received, receivedShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := f.ParseSignature(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Verify(message, parsed, shares[0].GroupKey) {
+		t.Error("signature sent over the wire does not verify")
+	}
+
+	// Malformed commitments
+	commitData, _ := commitments[0].MarshalBinary()
+	shareData, _ := sigShares[0].MarshalBinary()
+	identity := f.group.NewPoint().Bytes()
+	zeroID := slices.Clone(commitData)
+	clear(zeroID[4 : 4+len(commitments[0].ID.Bytes())])
+	badPoint := slices.Clone(commitData)
+	copy(badPoint[len(badPoint)-32:], bytes.Repeat([]byte{0xff}, 32))
+	withIdentity := append(slices.Clone(commitData[:len(commitData)-len(identity)]), identity...)
+	for name, bad := range map[string][]byte{
+		"empty":          nil,
+		"type":           shareData,
+		"truncated":      commitData[:len(commitData)-1],
+		"trailing bytes": append(slices.Clone(commitData), 0),
+		"zero ID":        zeroID,
+		"bad point":      badPoint,
+		"identity":       withIdentity,
+	} {
+		if _, err := f.ParseSigningCommitment(bad); err == nil {
+			t.Errorf("commitment %s: accepted", name)
+		}
+	}
+
+	// Malformed signature shares
+	nonCanonical := slices.Clone(shareData)
+	copy(nonCanonical[len(nonCanonical)-32:], bytes.Repeat([]byte{0xff}, 32))
+	for name, bad := range map[string][]byte{
+		"type":          commitData,
+		"truncated":     shareData[:len(shareData)-1],
+		"non-canonical": nonCanonical,
+	} {
+		if _, err := f.ParseSignatureShare(bad); err == nil {
+			t.Errorf("signature share %s: accepted", name)
+		}
+	}
+
+	if _, err := (&SigningCommitment{}).MarshalBinary(); err == nil {
+		t.Error("incomplete commitment encoded")
+	}
+	if _, err := (&Signature{}).MarshalBinary(); err == nil {
+		t.Error("incomplete signature encoded")
+	}
+}
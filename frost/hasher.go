@@ -1,10 +1,21 @@
 package frost
 
 import (
+	"cmp"
+	"crypto/hkdf"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"slices"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark-crypto/field/hash"
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
 	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
 // Hasher defines the hash operations required by FROST.
@@ -30,12 +41,88 @@ type Hasher interface {
 	H5(g group.Group, encCommitList []byte) []byte
 }
 
+// PrefixedHasher is a Hasher whose domain separation prefix can be
+// changed, so that applications sharing a key can keep their signing
+// transcripts apart. The hashers of this package implement it, except
+// for the [HKDFNonceHasher] wrapper, and also expose the prefix as their
+// Prefix field.
+type PrefixedHasher interface {
+	Hasher
+
+	// WithPrefix returns a copy of the hasher that uses prefix for
+	// domain separation. The receiver is not modified.
+	WithPrefix(prefix string) Hasher
+}
+
+// HasherValidator is implemented by hashers with settings that can be
+// invalid. [NewWithHasher] rejects a hasher whose Validate method fails,
+// so that a misconfiguration is reported when the instance is created
+// rather than in the middle of signing.
+type HasherValidator interface {
+	Validate() error
+}
+
+// ValidateHasher returns the error of h's Validate method, or nil if h
+// does not implement [HasherValidator].
+func ValidateHasher(h Hasher) error {
+	if v, ok := h.(HasherValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// MessageValidator is implemented by hashers that cannot sign every
+// message, such as those that sign a single field element rather than a
+// byte string. FROST checks the message with ValidateMessage before it
+// signs, verifies or aggregates, and computes challenges with Challenge
+// instead of H2, so that such a hasher reports an error where H2 could
+// only panic.
+type MessageValidator interface {
+	// ValidateMessage returns an error if msg cannot be signed in g.
+	ValidateMessage(g group.Group, msg []byte) error
+
+	// Challenge is H2, returning an error if the challenge cannot be
+	// computed.
+	Challenge(g group.Group, R, Y, msg []byte) (group.Scalar, error)
+}
+
+// ValidateMessage returns the error of h's ValidateMessage method, or nil
+// if h does not implement [MessageValidator].
+func ValidateMessage(h Hasher, g group.Group, msg []byte) error {
+	if v, ok := h.(MessageValidator); ok {
+		return v.ValidateMessage(g, msg)
+	}
+	return nil
+}
+
+// Challenge returns h's challenge H2(R, Y, msg), computed with its
+// Challenge method if h implements [MessageValidator].
+func Challenge(h Hasher, g group.Group, R, Y, msg []byte) (group.Scalar, error) {
+	if v, ok := h.(MessageValidator); ok {
+		return v.Challenge(g, R, Y, msg)
+	}
+	return h.H2(g, R, Y, msg), nil
+}
+
 // SHA256Hasher implements Hasher using SHA-256.
 // This is the default hasher for general use.
-type SHA256Hasher struct{}
+//
+// Scalars are derived from 48 bytes of output produced with
+// expand_message_xmd (RFC 9380) so that reducing them modulo the group
+// order does not bias the result.
+type SHA256Hasher struct {
+	// Prefix is prepended to every hash input. It is empty by default,
+	// which keeps the transcripts of earlier versions.
+	Prefix string
+}
+
+// sha256ScalarDST is the domain separation tag for SHA256Hasher's
+// expand_message_xmd calls.
+const sha256ScalarDST = "FROST-SHA256-SCALAR-v1"
 
 func (h *SHA256Hasher) hash(data ...[]byte) []byte {
 	hasher := sha256.New()
+	hasher.Write([]byte(h.Prefix))
 	for _, d := range data {
 		hasher.Write(d)
 	}
@@ -43,9 +130,18 @@ func (h *SHA256Hasher) hash(data ...[]byte) []byte {
 }
 
 func (h *SHA256Hasher) hashToScalar(g group.Group, data ...[]byte) group.Scalar {
-	hash := h.hash(data...)
-	s := g.NewScalar()
-	s.SetBytes(hash)
+	size := len(h.Prefix)
+	for _, d := range data {
+		size += len(d)
+	}
+	msg := make([]byte, 0, size)
+	msg = append(msg, h.Prefix...)
+	for _, d := range data {
+		msg = append(msg, d...)
+	}
+	// Cannot fail: both the tag and the output length are within bounds
+	wide, _ := hash.ExpandMsgXmd(msg, []byte(sha256ScalarDST), 48)
+	s, _ := g.NewScalar().SetUniformBytes(wide)
 	return s
 }
 
@@ -74,6 +170,13 @@ func (h *SHA256Hasher) H5(g group.Group, encCommitList []byte) []byte {
 	return h.hash([]byte("com"), encCommitList)
 }
 
+// WithPrefix implements PrefixedHasher.
+func (h *SHA256Hasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
 // Blake2bHasher implements Hasher using Blake2b-512 with domain separation.
 // This is compatible with Ledger/iden3 FROST implementations.
 //
@@ -113,8 +216,7 @@ func (h *Blake2bHasher) hashToScalar(g group.Group, tag string, data ...[]byte)
 		reversed[i] = hash[len(hash)-1-i]
 	}
 
-	s := g.NewScalar()
-	s.SetBytes(reversed)
+	s, _ := g.NewScalar().SetUniformBytes(reversed)
 	return s
 }
 
@@ -142,3 +244,532 @@ func (h *Blake2bHasher) H4(g group.Group, msg []byte) []byte {
 func (h *Blake2bHasher) H5(g group.Group, encCommitList []byte) []byte {
 	return h.hash("com", encCommitList)
 }
+
+// WithPrefix implements PrefixedHasher.
+func (h *Blake2bHasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// Keccak256Hasher implements Hasher using Keccak-256, the hash function
+// available to EVM contracts as keccak256, so that challenges can be
+// recomputed cheaply by Solidity verifiers.
+//
+// Every input is the packed concatenation prefix || tag || data, as
+// abi.encodePacked produces it. Scalars are derived from two digests that
+// differ in a counter byte appended to the tag, read as one 64-byte
+// big-endian number and reduced modulo the group order, so that they are
+// not biased even when the order is well below 2^256. A Solidity verifier
+// computes the challenge as
+//
+//	uint256 hi = uint256(keccak256(abi.encodePacked(PREFIX, "chal", uint8(0), R, Y, m)));
+//	uint256 lo = uint256(keccak256(abi.encodePacked(PREFIX, "chal", uint8(1), R, Y, m)));
+//	c = addmod(mulmod(hi, TWO_256_MOD_ORDER, ORDER), lo, ORDER);
+type Keccak256Hasher struct {
+	// Prefix is the domain separation prefix.
+	// Default: "FROST-KECCAK256-v1"
+	Prefix string
+}
+
+// NewKeccak256Hasher creates a Keccak256Hasher with the default prefix.
+func NewKeccak256Hasher() *Keccak256Hasher {
+	return &Keccak256Hasher{
+		Prefix: "FROST-KECCAK256-v1",
+	}
+}
+
+func (h *Keccak256Hasher) hash(tag string, counter []byte, data ...[]byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte(tag))
+	hasher.Write(counter)
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	return hasher.Sum(nil)
+}
+
+// hashToScalar hashes data to 64 bytes, keccak256(... 0x00 ...) followed
+// by keccak256(... 0x01 ...), and reduces them modulo the group order.
+func (h *Keccak256Hasher) hashToScalar(g group.Group, tag string, data ...[]byte) group.Scalar {
+	wide := h.hash(tag, []byte{0}, data...)
+	wide = append(wide, h.hash(tag, []byte{1}, data...)...)
+	s, _ := g.NewScalar().SetUniformBytes(wide)
+	return s
+}
+
+// H1 implements Hasher.H1 (binding factor computation).
+func (h *Keccak256Hasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.hashToScalar(g, "rho", msg, encCommitList, signerID)
+}
+
+// H2 implements Hasher.H2 (Schnorr challenge).
+func (h *Keccak256Hasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "chal", R, Y, msg)
+}
+
+// H3 implements Hasher.H3 (nonce generation).
+func (h *Keccak256Hasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "nonce", seed, rho, msg)
+}
+
+// H4 implements Hasher.H4 (message hashing).
+func (h *Keccak256Hasher) H4(g group.Group, msg []byte) []byte {
+	return h.hash("msg", nil, msg)
+}
+
+// H5 implements Hasher.H5 (commitment list hashing).
+func (h *Keccak256Hasher) H5(g group.Group, encCommitList []byte) []byte {
+	return h.hash("com", nil, encCommitList)
+}
+
+// WithPrefix implements PrefixedHasher.
+func (h *Keccak256Hasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// SHA512Hasher implements Hasher using SHA-512 in the style of the
+// SHA-512 ciphersuites of RFC 9591, FROST(Ed25519, SHA-512) and
+// FROST(ristretto255, SHA-512): each input is prefixed with a context
+// string and a tag ("rho", "chal", "nonce", "msg" or "com"), and scalars
+// are the 64-byte digests read in little-endian order and reduced modulo
+// the group order.
+type SHA512Hasher struct {
+	// Prefix is the context string of the ciphersuite.
+	// Default: "FROST-SHA512-v1"
+	Prefix string
+
+	// PlainChallenge makes H2 hash R || Y || msg with no context string
+	// or tag, as FROST(Ed25519, SHA-512) does so that its signatures
+	// verify as Ed25519 signatures.
+	PlainChallenge bool
+}
+
+// NewSHA512Hasher creates a SHA512Hasher with the default context string.
+func NewSHA512Hasher() *SHA512Hasher {
+	return &SHA512Hasher{
+		Prefix: "FROST-SHA512-v1",
+	}
+}
+
+func (h *SHA512Hasher) hash(tag string, data ...[]byte) []byte {
+	hasher := sha512.New()
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte(tag))
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	return hasher.Sum(nil)
+}
+
+// hashToScalar hashes data and converts to a scalar.
+// The 64-byte output is interpreted as little-endian before reducing mod order.
+func (h *SHA512Hasher) hashToScalar(g group.Group, tag string, data ...[]byte) group.Scalar {
+	wide := h.hash(tag, data...)
+	slices.Reverse(wide)
+	s, _ := g.NewScalar().SetUniformBytes(wide)
+	return s
+}
+
+// H1 implements Hasher.H1 (binding factor computation).
+func (h *SHA512Hasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.hashToScalar(g, "rho", msg, encCommitList, signerID)
+}
+
+// H2 implements Hasher.H2 (Schnorr challenge).
+func (h *SHA512Hasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	if h.PlainChallenge {
+		plain := SHA512Hasher{}
+		return plain.hashToScalar(g, "", R, Y, msg)
+	}
+	return h.hashToScalar(g, "chal", R, Y, msg)
+}
+
+// H3 implements Hasher.H3 (nonce generation).
+func (h *SHA512Hasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "nonce", seed, rho, msg)
+}
+
+// H4 implements Hasher.H4 (message hashing).
+func (h *SHA512Hasher) H4(g group.Group, msg []byte) []byte {
+	return h.hash("msg", msg)
+}
+
+// H5 implements Hasher.H5 (commitment list hashing).
+func (h *SHA512Hasher) H5(g group.Group, encCommitList []byte) []byte {
+	return h.hash("com", encCommitList)
+}
+
+// WithPrefix implements PrefixedHasher.
+func (h *SHA512Hasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// SHAKE256Hasher implements Hasher with the SHAKE256 extendable-output
+// function, in the style of the FROST(Ed448, SHAKE256) ciphersuite of
+// RFC 9591: each input is prefixed with a context string and a tag, and
+// scalars are read from the output in little-endian order and reduced
+// modulo the group order.
+//
+// The output lengths are configurable. Longer scalar outputs reduce the
+// bias of the reduction further, and the Ed448 ciphersuite uses 114
+// bytes.
+type SHAKE256Hasher struct {
+	// Prefix is the context string of the ciphersuite.
+	// Default: "FROST-SHAKE256-v1"
+	Prefix string
+
+	// ScalarLength is the number of output bytes reduced to a scalar.
+	// It must be at least 48; [NewWithHasher] rejects shorter lengths.
+	// Default: 64
+	ScalarLength int
+
+	// DigestLength is the length of the H4 and H5 outputs.
+	// Default: 64
+	DigestLength int
+}
+
+// NewSHAKE256Hasher creates a SHAKE256Hasher with the default context
+// string and 64-byte outputs.
+func NewSHAKE256Hasher() *SHAKE256Hasher {
+	return &SHAKE256Hasher{
+		Prefix:       "FROST-SHAKE256-v1",
+		ScalarLength: 64,
+		DigestLength: 64,
+	}
+}
+
+func (h *SHAKE256Hasher) hash(length int, tag string, data ...[]byte) []byte {
+	xof := sha3.NewShake256()
+	xof.Write([]byte(h.Prefix))
+	xof.Write([]byte(tag))
+	for _, d := range data {
+		xof.Write(d)
+	}
+	out := make([]byte, length)
+	xof.Read(out)
+	return out
+}
+
+// hashToScalar hashes data to ScalarLength bytes and converts them to a
+// scalar, interpreting them as little-endian.
+func (h *SHAKE256Hasher) hashToScalar(g group.Group, tag string, data ...[]byte) group.Scalar {
+	wide := h.hash(cmp.Or(h.ScalarLength, 64), tag, data...)
+	slices.Reverse(wide)
+	return reduceWide(g, wide)
+}
+
+// Validate implements HasherValidator. It rejects a ScalarLength below
+// 48, which would bias the reduced scalars, and negative lengths.
+func (h *SHAKE256Hasher) Validate() error {
+	if h.ScalarLength < 0 || h.ScalarLength > 0 && h.ScalarLength < 48 {
+		return fmt.Errorf("SHAKE256Hasher.ScalarLength is %d, must be at least 48", h.ScalarLength)
+	}
+	if h.DigestLength < 0 {
+		return fmt.Errorf("SHAKE256Hasher.DigestLength is %d, must not be negative", h.DigestLength)
+	}
+	return nil
+}
+
+// digestLength returns the length of the H4 and H5 outputs.
+func (h *SHAKE256Hasher) digestLength() int {
+	return cmp.Or(h.DigestLength, 64)
+}
+
+// H1 implements Hasher.H1 (binding factor computation).
+func (h *SHAKE256Hasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.hashToScalar(g, "rho", msg, encCommitList, signerID)
+}
+
+// H2 implements Hasher.H2 (Schnorr challenge).
+func (h *SHAKE256Hasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "chal", R, Y, msg)
+}
+
+// H3 implements Hasher.H3 (nonce generation).
+func (h *SHAKE256Hasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "nonce", seed, rho, msg)
+}
+
+// H4 implements Hasher.H4 (message hashing).
+func (h *SHAKE256Hasher) H4(g group.Group, msg []byte) []byte {
+	return h.hash(h.digestLength(), "msg", msg)
+}
+
+// H5 implements Hasher.H5 (commitment list hashing).
+func (h *SHAKE256Hasher) H5(g group.Group, encCommitList []byte) []byte {
+	return h.hash(h.digestLength(), "com", encCommitList)
+}
+
+// WithPrefix implements PrefixedHasher.
+func (h *SHAKE256Hasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// reduceWide returns the big-endian value data modulo the order of g.
+// Group.SetUniformBytes only accepts 48 to 64 bytes, so longer inputs are
+// reduced 16 bytes at a time with Horner's rule, each chunk zero-extended
+// to 48 bytes for SetUniformBytes.
+func reduceWide(g group.Group, data []byte) group.Scalar {
+	if len(data) <= 64 {
+		s, _ := g.NewScalar().SetUniformBytes(data)
+		return s
+	}
+	const chunk = 16
+	var buf [48]byte
+	buf[48-chunk-1] = 1
+	shift, _ := g.NewScalar().SetUniformBytes(buf[:]) // 2^128
+
+	s := g.NewScalar()
+	c := g.NewScalar()
+	// The first chunk takes the leftover bytes, so that the others are full
+	n := len(data) % chunk
+	if n == 0 {
+		n = chunk
+	}
+	for len(data) > 0 {
+		clear(buf[:])
+		copy(buf[48-n:], data[:n])
+		c.SetUniformBytes(buf[:])
+		s.Mul(s, shift).Add(s, c)
+		data = data[n:]
+		n = chunk
+	}
+	return s
+}
+
+// PoseidonHasher implements Hasher with the Poseidon2 hash over the BN254
+// scalar field, as provided by gnark-crypto, so that binding factors and
+// challenges are cheap to recompute in a SNARK circuit. It is meant for
+// the Baby Jubjub group, which is defined over that field.
+//
+// Each hash absorbs Prefix followed by the tag as its first input. Every
+// input is absorbed as its length followed by its bytes in 31-byte
+// big-endian chunks, the last one zero-padded on the right, each making
+// one field element; this is the packing of bjj.ScalarHashPoseidon2, so
+// that with Baby Jubjub H2 equals HashToScalar of a bjj.BJJ using it.
+// Scalars are the field element digest reduced modulo the group order.
+type PoseidonHasher struct {
+	// Prefix is the domain separation prefix.
+	// Default: "FROST-POSEIDON2-BN254-v1"
+	Prefix string
+}
+
+// NewPoseidonHasher creates a PoseidonHasher with the default prefix.
+func NewPoseidonHasher() *PoseidonHasher {
+	return &PoseidonHasher{
+		Prefix: "FROST-POSEIDON2-BN254-v1",
+	}
+}
+
+// hash returns the 32-byte big-endian field element digest.
+func (h *PoseidonHasher) hash(tag string, data ...[]byte) []byte {
+	hasher := poseidon2.NewMerkleDamgardHasher()
+	for _, d := range append([][]byte{[]byte(h.Prefix + tag)}, data...) {
+		var length [fr.Bytes]byte
+		binary.BigEndian.PutUint64(length[fr.Bytes-8:], uint64(len(d)))
+		hasher.Write(length[:])
+		for len(d) > 0 {
+			var e [fr.Bytes]byte
+			n := copy(e[1:], d)
+			d = d[n:]
+			hasher.Write(e[:])
+		}
+	}
+	return hasher.Sum(nil)
+}
+
+func (h *PoseidonHasher) hashToScalar(g group.Group, tag string, data ...[]byte) group.Scalar {
+	// Zero-extend the digest to the length SetUniformBytes expects
+	var wide [48]byte
+	copy(wide[48-fr.Bytes:], h.hash(tag, data...))
+	s, _ := g.NewScalar().SetUniformBytes(wide[:])
+	return s
+}
+
+// H1 implements Hasher.H1 (binding factor computation).
+func (h *PoseidonHasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.hashToScalar(g, "rho", msg, encCommitList, signerID)
+}
+
+// H2 implements Hasher.H2 (Schnorr challenge).
+func (h *PoseidonHasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "chal", R, Y, msg)
+}
+
+// H3 implements Hasher.H3 (nonce generation).
+func (h *PoseidonHasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "nonce", seed, rho, msg)
+}
+
+// H4 implements Hasher.H4 (message hashing).
+func (h *PoseidonHasher) H4(g group.Group, msg []byte) []byte {
+	return h.hash("msg", msg)
+}
+
+// H5 implements Hasher.H5 (commitment list hashing).
+func (h *PoseidonHasher) H5(g group.Group, encCommitList []byte) []byte {
+	return h.hash("com", encCommitList)
+}
+
+// WithPrefix implements PrefixedHasher.
+func (h *PoseidonHasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// TaggedHasher implements Hasher with the tagged hashes of BIP-340,
+// SHA256(SHA256(tag) || SHA256(tag) || data).
+//
+// H2 is the BIP-340 challenge: the "BIP0340/challenge" hash of
+// R || Y || msg reduced modulo the group order. It matches Bitcoin's
+// consensus rules when the group encodes points as 32-byte x-only
+// coordinates, as BIP-340 does for secp256k1, and signers keep the even-y
+// representatives BIP-340 requires. The other hashes use the tags
+// Prefix + "/rho", "/nonce", "/msg" and "/com". Scalars other than the
+// challenge are derived from 64 bytes, two tagged hashes that differ in
+// a leading counter byte, so that they are unbiased for any group order.
+type TaggedHasher struct {
+	// Prefix is the prefix of the tags other than the challenge's.
+	// Default: "FROST-BIP340"
+	Prefix string
+}
+
+// NewTaggedHasher creates a TaggedHasher with the default prefix.
+func NewTaggedHasher() *TaggedHasher {
+	return &TaggedHasher{
+		Prefix: "FROST-BIP340",
+	}
+}
+
+// taggedHash returns the BIP-340 tagged hash of data under tag.
+func taggedHash(tag string, data ...[]byte) []byte {
+	hasher := newTaggedHash(tag)
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	return hasher.Sum(nil)
+}
+
+// hashToScalar reduces the two tagged hashes of 0x00 || data and
+// 0x01 || data, read as one 64-byte big-endian number.
+func (h *TaggedHasher) hashToScalar(g group.Group, tag string, data ...[]byte) group.Scalar {
+	wide := taggedHash(h.Prefix+tag, append([][]byte{{0}}, data...)...)
+	wide = append(wide, taggedHash(h.Prefix+tag, append([][]byte{{1}}, data...)...)...)
+	s, _ := g.NewScalar().SetUniformBytes(wide)
+	return s
+}
+
+// H1 implements Hasher.H1 (binding factor computation).
+func (h *TaggedHasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.hashToScalar(g, "/rho", msg, encCommitList, signerID)
+}
+
+// H2 implements Hasher.H2 as the BIP-340 challenge.
+func (h *TaggedHasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	// Zero-extending the digest makes SetUniformBytes an exact reduction
+	var wide [48]byte
+	copy(wide[48-sha256.Size:], taggedHash("BIP0340/challenge", R, Y, msg))
+	s, _ := g.NewScalar().SetUniformBytes(wide[:])
+	return s
+}
+
+// H3 implements Hasher.H3 (nonce generation).
+func (h *TaggedHasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.hashToScalar(g, "/nonce", seed, rho, msg)
+}
+
+// H4 implements Hasher.H4 (message hashing).
+func (h *TaggedHasher) H4(g group.Group, msg []byte) []byte {
+	return taggedHash(h.Prefix+"/msg", msg)
+}
+
+// H5 implements Hasher.H5 (commitment list hashing).
+func (h *TaggedHasher) H5(g group.Group, encCommitList []byte) []byte {
+	return taggedHash(h.Prefix+"/com", encCommitList)
+}
+
+// WithPrefix implements PrefixedHasher.
+func (h *TaggedHasher) WithPrefix(prefix string) Hasher {
+	c := *h
+	c.Prefix = prefix
+	return &c
+}
+
+// hkdfNonceSalt is the HKDF salt of HKDFNonceHasher.
+const hkdfNonceSalt = "FROST-HKDF-NONCE-v1"
+
+// HKDFNonceHasher wraps a Hasher and replaces its H3 with a nonce
+// derivation built from HKDF-SHA-256 (RFC 5869), for use with
+// [FROST.SignRound1Hedged]. Every input enters the derivation through an
+// explicit, length-prefixed info string, which makes it straightforward
+// to audit and to reproduce with any HKDF implementation:
+//
+//	PRK   = HKDF-Extract(salt = "FROST-HKDF-NONCE-v1", IKM = seed)
+//	info  = len(share) || share || len(H4(msg)) || H4(msg) || len(SessionID) || SessionID
+//	nonce = HKDF-Expand(PRK, info, 64) mod order
+//
+// where share is the signer's secret key share, H4 is the wrapped
+// hasher's, and each length is 8 bytes big-endian. The other hashes are
+// those of the wrapped hasher.
+type HKDFNonceHasher struct {
+	Hasher
+
+	// SessionID is bound into every nonce. Callers that know a unique
+	// identifier for the signing session should set it, so that nonces
+	// differ between sessions even if the random source repeats. It may
+	// be empty.
+	SessionID []byte
+}
+
+// Validate implements HasherValidator for the wrapped hasher.
+func (h *HKDFNonceHasher) Validate() error {
+	return ValidateHasher(h.Hasher)
+}
+
+// ValidateMessage implements MessageValidator for the wrapped hasher.
+func (h *HKDFNonceHasher) ValidateMessage(g group.Group, msg []byte) error {
+	return ValidateMessage(h.Hasher, g, msg)
+}
+
+// Challenge implements MessageValidator for the wrapped hasher.
+func (h *HKDFNonceHasher) Challenge(g group.Group, R, Y, msg []byte) (group.Scalar, error) {
+	return Challenge(h.Hasher, g, R, Y, msg)
+}
+
+// NewHKDFNonceHasher creates an HKDFNonceHasher that wraps inner and
+// binds sessionID into its nonces. If inner is nil, [SHA256Hasher] is
+// used.
+func NewHKDFNonceHasher(inner Hasher, sessionID []byte) *HKDFNonceHasher {
+	if inner == nil {
+		inner = &SHA256Hasher{}
+	}
+	return &HKDFNonceHasher{
+		Hasher:    inner,
+		SessionID: slices.Clone(sessionID),
+	}
+}
+
+// H3 implements Hasher.H3 with HKDF. The rho argument is the secret key
+// share, as passed by [FROST.SignRound1Hedged].
+func (h *HKDFNonceHasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	msgHash := h.H4(g, msg)
+	info := make([]byte, 0, 24+len(rho)+len(msgHash)+len(h.SessionID))
+	for _, field := range [][]byte{rho, msgHash, h.SessionID} {
+		info = binary.BigEndian.AppendUint64(info, uint64(len(field)))
+		info = append(info, field...)
+	}
+	// Cannot fail: SHA-256 is approved and 64 bytes is within bounds
+	okm, _ := hkdf.Key(sha256.New, seed, []byte(hkdfNonceSalt), string(info), 64)
+	s, _ := g.NewScalar().SetUniformBytes(okm)
+	memwipe.Bytes(info, okm)
+	return s
+}
@@ -0,0 +1,57 @@
+package frost
+
+import "github.com/f3rmion/fy/group"
+
+// SigningIntermediates holds the values every signer derives from the
+// public inputs of a signing session. They are not needed to sign, but
+// let test vectors and other implementations check each step of
+// [FROST.SignRound2] and [FROST.Aggregate] separately.
+type SigningIntermediates struct {
+	// BindingFactors holds the binding factor rho_i of every signer, in
+	// commitment order.
+	BindingFactors []group.Scalar
+
+	// GroupCommitment is R = sum(D_i + rho_i*E_i).
+	GroupCommitment group.Point
+
+	// Challenge is c = H2(R, Y, message).
+	Challenge group.Scalar
+
+	// Lagrange holds the Lagrange coefficient lambda_i of every signer,
+	// in commitment order.
+	Lagrange []group.Scalar
+}
+
+// Intermediates computes the intermediate values of a signing session
+// for message and commitments under groupKey. The commitment list is
+// checked as in [FROST.SignRound2].
+func (f *FROST) Intermediates(groupKey group.Point, message []byte, commitments []*SigningCommitment) (*SigningIntermediates, error) {
+	if err := checkNotNil(field{"groupKey", groupKey}); err != nil {
+		return nil, err
+	}
+	if err := f.checkPoints(groupKey); err != nil {
+		return nil, err
+	}
+	if err := f.checkCommitments(commitments); err != nil {
+		return nil, err
+	}
+	lagrange, err := f.LagrangeCoefficients(commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := f.prepare(groupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	rho := make([]group.Scalar, len(commitments))
+	for i, c := range commitments {
+		rho[i] = s.rho[string(c.ID.Bytes())]
+	}
+	return &SigningIntermediates{
+		BindingFactors:  rho,
+		GroupCommitment: s.r,
+		Challenge:       s.c,
+		Lagrange:        lagrange,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package frost
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// InconsistentKeyShareError reports key shares that do not belong to one
+// threshold key, as found by [FROST.CheckKeyShares].
+type InconsistentKeyShareError struct {
+	// ID is the participant whose share is inconsistent with the others.
+	// It is nil if no single share can be blamed, because the shares
+	// agree with each other but not with their group key.
+	ID group.Scalar
+
+	// Reason describes the inconsistency.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *InconsistentKeyShareError) Error() string {
+	if e.ID == nil {
+		return "frost: inconsistent key shares: " + e.Reason
+	}
+	return fmt.Sprintf("frost: inconsistent key share %s: %s", idString(e.ID), e.Reason)
+}
+
+// CheckKeyShares checks that shares, at least threshold of them with
+// distinct IDs, are the key shares of a single threshold key: every
+// public key matches its secret key, all shares carry the same group key
+// and transcript digest, and every subset of threshold shares
+// interpolates to the secret of that group key.
+//
+// It is meant as a dry run right after a DKG, while the backups of the
+// key shares still exist: a failure then points to a ceremony that must
+// be repeated, rather than to signatures that can never be produced.
+// Inconsistencies yield an *[InconsistentKeyShareError], malformed
+// shares an *[InputError] and repeated IDs a *[DuplicateIDError].
+//
+// CheckKeyShares handles every secret key of the group at once, so run
+// it only where all of them may be brought together.
+func (f *FROST) CheckKeyShares(shares []*KeyShare) error {
+	if len(shares) < f.threshold {
+		return &InputError{
+			Field:  "shares",
+			Reason: fmt.Sprintf("has %d entries, need at least %d", len(shares), f.threshold),
+		}
+	}
+	seen := make(map[string]bool, len(shares))
+	for i, ks := range shares {
+		name := fmt.Sprintf("shares[%d]", i)
+		if err := checkNotNil(field{name, ks}); err != nil {
+			return err
+		}
+		if err := checkNotNil(
+			field{name + ".ID", ks.ID},
+			field{name + ".SecretKey", ks.SecretKey},
+			field{name + ".PublicKey", ks.PublicKey},
+			field{name + ".GroupKey", ks.GroupKey},
+		); err != nil {
+			return err
+		}
+		if err := f.checkScalars(ks.ID, ks.SecretKey); err != nil {
+			return err
+		}
+		if err := f.checkPoints(ks.PublicKey, ks.GroupKey); err != nil {
+			return err
+		}
+		if ks.ID.IsZero() {
+			return &InputError{Field: name + ".ID", Reason: "is zero"}
+		}
+		id := string(ks.ID.Bytes())
+		if seen[id] {
+			return &DuplicateIDError{ID: ks.ID.Clone()}
+		}
+		seen[id] = true
+	}
+
+	first := shares[0]
+	for _, ks := range shares {
+		if !f.group.ScalarBaseMult(ks.SecretKey).Equal(ks.PublicKey) {
+			return &InconsistentKeyShareError{ID: ks.ID.Clone(), Reason: "public key does not match the secret key"}
+		}
+		if !ks.GroupKey.Equal(first.GroupKey) {
+			return &InconsistentKeyShareError{ID: ks.ID.Clone(), Reason: "group key differs from that of the first share"}
+		}
+		if !bytes.Equal(ks.TranscriptDigest, first.TranscriptDigest) {
+			return &InconsistentKeyShareError{ID: ks.ID.Clone(), Reason: "transcript digest differs from that of the first share"}
+		}
+	}
+
+	// The first threshold shares define a polynomial of degree
+	// threshold-1. Its constant term must be the group secret, and every
+	// further share must lie on it; then any threshold of the shares
+	// interpolate to the same polynomial.
+	basis := shares[:f.threshold]
+	secret, err := f.interpolate(basis, f.group.NewScalar())
+	if err != nil {
+		return err
+	}
+	ok := f.group.ScalarBaseMult(secret).Equal(first.GroupKey)
+	memwipe.Scalars(secret)
+	if !ok {
+		return &InconsistentKeyShareError{Reason: "shares do not interpolate to the secret of the group key"}
+	}
+	for _, ks := range shares[f.threshold:] {
+		y, err := f.interpolate(basis, ks.ID)
+		if err != nil {
+			return err
+		}
+		ok := y.Equal(ks.SecretKey)
+		memwipe.Scalars(y)
+		if !ok {
+			return &InconsistentKeyShareError{
+				ID:     ks.ID.Clone(),
+				Reason: fmt.Sprintf("secret key does not lie on the polynomial of the first %d shares", f.threshold),
+			}
+		}
+	}
+	return nil
+}
+
+// interpolate evaluates at x the polynomial through the secret keys of
+// shares, which must have distinct IDs.
+func (f *FROST) interpolate(shares []*KeyShare, x group.Scalar) (group.Scalar, error) {
+	nums := make([]group.Scalar, len(shares))
+	dens := make([]group.Scalar, len(shares))
+	diff := f.group.NewScalar()
+	for i, ks := range shares {
+		nums[i] = f.One()
+		dens[i] = f.One()
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			nums[i].Mul(nums[i], diff.Sub(x, other.ID))
+			dens[i].Mul(dens[i], diff.Sub(ks.ID, other.ID))
+		}
+	}
+	if err := group.BatchInvert(f.group, dens); err != nil {
+		return nil, err
+	}
+	y := f.group.NewScalar()
+	term := f.group.NewScalar()
+	for i, ks := range shares {
+		term.Mul(nums[i], dens[i])
+		y.Add(y, term.Mul(term, ks.SecretKey))
+	}
+	memwipe.Scalars(term)
+	return y, nil
+}
@@ -0,0 +1,318 @@
+package frost
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// Binary protocol messages start with a version byte and a type byte,
+// followed by length-prefixed fields: every scalar and point encoding is
+// preceded by its length as a 16-bit big-endian integer, and the
+// commitments of a broadcast by their count, also 16 bits.
+//
+//	Round1Data:        1 | 1 | ID | count | commitment...
+//	Round1PrivateData: 1 | 2 | FromID | ToID | Share
+//	SigningCommitment: 1 | 3 | ID | HidingPoint | BindingPoint
+//	SignatureShare:    1 | 4 | ID | Z
+//
+// Signatures keep their fixed-size compact encoding, [Signature.Bytes],
+// which is what verifiers and other FROST implementations expect.
+const (
+	messageVersion     = 1
+	messageBroadcast   = 1
+	messagePrivateData = 2
+	messageCommitment  = 3
+	messageShare       = 4
+)
+
+// errTruncated is returned for messages that end before their last field.
+var errTruncated = errors.New("message is truncated")
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the
+// broadcast for the network. Decode it with [FROST.ParseRound1Data].
+func (d *Round1Data) MarshalBinary() ([]byte, error) {
+	if d.ID == nil || len(d.Commitments) > 0xffff {
+		return nil, errors.New("frost: cannot encode an incomplete broadcast")
+	}
+	out := []byte{messageVersion, messageBroadcast}
+	out = appendField(out, d.ID.Bytes())
+	out = binary.BigEndian.AppendUint16(out, uint16(len(d.Commitments)))
+	for _, c := range d.Commitments {
+		if c == nil {
+			return nil, errors.New("frost: cannot encode an incomplete broadcast")
+		}
+		out = appendField(out, c.Bytes())
+	}
+	return out, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the
+// private share for a secure channel. Decode it with
+// [FROST.ParseRound1PrivateData]. The result holds the share in the
+// clear; wipe it once it has been sent.
+func (d *Round1PrivateData) MarshalBinary() ([]byte, error) {
+	if d.FromID == nil || d.ToID == nil || d.Share == nil {
+		return nil, errors.New("frost: cannot encode an incomplete private share")
+	}
+	out := []byte{messageVersion, messagePrivateData}
+	out = appendField(out, d.FromID.Bytes())
+	out = appendField(out, d.ToID.Bytes())
+	return appendField(out, d.Share.Bytes()), nil
+}
+
+// ParseRound1Data decodes a broadcast encoded with
+// [Round1Data.MarshalBinary] and checks it as [FROST.Finalize] does: it
+// must carry a nonzero ID and hold threshold commitments, all valid
+// points of f's group.
+func (f *FROST) ParseRound1Data(data []byte) (*Round1Data, error) {
+	r, err := newMessageReader(data, messageBroadcast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broadcast: %w", err)
+	}
+	id, err := r.id(f.group)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broadcast ID: %w", err)
+	}
+	count, err := r.uint16()
+	if err != nil {
+		return nil, fmt.Errorf("invalid broadcast: %w", err)
+	}
+	if int(count) != f.threshold {
+		return nil, fmt.Errorf("invalid broadcast: %d commitments, want %d", count, f.threshold)
+	}
+	commitments, err := r.points(f.group, int(count))
+	if err != nil {
+		return nil, fmt.Errorf("invalid broadcast commitments: %w", err)
+	}
+	if err := r.done(); err != nil {
+		return nil, fmt.Errorf("invalid broadcast: %w", err)
+	}
+	d := &Round1Data{ID: id, Commitments: commitments}
+	if err := f.checkBroadcast("broadcast", d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ParseRound1PrivateData decodes a private share encoded with
+// [Round1PrivateData.MarshalBinary]. Scalars must be canonical and the
+// IDs nonzero. [FROST.Round2ReceiveShare] checks the share itself.
+func (f *FROST) ParseRound1PrivateData(data []byte) (*Round1PrivateData, error) {
+	r, err := newMessageReader(data, messagePrivateData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private share: %w", err)
+	}
+	var d Round1PrivateData
+	for _, s := range []struct {
+		name string
+		dst  *group.Scalar
+		read func(*messageReader, group.Group) (group.Scalar, error)
+	}{
+		{"FromID", &d.FromID, (*messageReader).id},
+		{"ToID", &d.ToID, (*messageReader).id},
+		{"Share", &d.Share, (*messageReader).scalar},
+	} {
+		if *s.dst, err = s.read(r, f.group); err != nil {
+			d.Zeroize()
+			return nil, fmt.Errorf("invalid private share %s: %w", s.name, err)
+		}
+	}
+	if err := r.done(); err != nil {
+		d.Zeroize()
+		return nil, fmt.Errorf("invalid private share: %w", err)
+	}
+	return &d, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the
+// commitment for the coordinator. Decode it with
+// [FROST.ParseSigningCommitment].
+func (c *SigningCommitment) MarshalBinary() ([]byte, error) {
+	if c.ID == nil || c.HidingPoint == nil || c.BindingPoint == nil {
+		return nil, errors.New("frost: cannot encode an incomplete commitment")
+	}
+	out := []byte{messageVersion, messageCommitment}
+	out = appendField(out, c.ID.Bytes())
+	out = appendField(out, c.HidingPoint.Bytes())
+	return appendField(out, c.BindingPoint.Bytes()), nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the
+// signature share for the coordinator. Decode it with
+// [FROST.ParseSignatureShare].
+func (s *SignatureShare) MarshalBinary() ([]byte, error) {
+	if s.ID == nil || s.Z == nil {
+		return nil, errors.New("frost: cannot encode an incomplete signature share")
+	}
+	out := []byte{messageVersion, messageShare}
+	out = appendField(out, s.ID.Bytes())
+	return appendField(out, s.Z.Bytes()), nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It returns
+// [Signature.Bytes]; decode it with [FROST.ParseSignature].
+func (sig *Signature) MarshalBinary() ([]byte, error) {
+	if sig.R == nil || sig.Z == nil {
+		return nil, errors.New("frost: cannot encode an incomplete signature")
+	}
+	return sig.Bytes(), nil
+}
+
+// ParseSigningCommitment decodes a commitment encoded with
+// [SigningCommitment.MarshalBinary]. The ID must be a nonzero canonical
+// scalar, and both points canonically encoded points of the prime-order
+// subgroup other than the identity.
+func (f *FROST) ParseSigningCommitment(data []byte) (*SigningCommitment, error) {
+	r, err := newMessageReader(data, messageCommitment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commitment: %w", err)
+	}
+	id, err := r.id(f.group)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commitment ID: %w", err)
+	}
+	points, err := r.points(f.group, 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commitment points: %w", err)
+	}
+	if err := r.done(); err != nil {
+		return nil, fmt.Errorf("invalid commitment: %w", err)
+	}
+	if err := f.checkPoints(points...); err != nil {
+		return nil, fmt.Errorf("invalid commitment points: %w", err)
+	}
+	for i, name := range []string{"HidingPoint", "BindingPoint"} {
+		if points[i].IsIdentity() {
+			return nil, &InputError{Field: "commitment." + name, Reason: "is the identity"}
+		}
+	}
+	return &SigningCommitment{ID: id, HidingPoint: points[0], BindingPoint: points[1]}, nil
+}
+
+// ParseSignatureShare decodes a signature share encoded with
+// [SignatureShare.MarshalBinary]. The ID must be a nonzero canonical
+// scalar and Z canonical; [FROST.VerifySignatureShare] checks the share
+// itself.
+func (f *FROST) ParseSignatureShare(data []byte) (*SignatureShare, error) {
+	r, err := newMessageReader(data, messageShare)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature share: %w", err)
+	}
+	id, err := r.id(f.group)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature share ID: %w", err)
+	}
+	z, err := r.scalar(f.group)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature share Z: %w", err)
+	}
+	if err := r.done(); err != nil {
+		return nil, fmt.Errorf("invalid signature share: %w", err)
+	}
+	return &SignatureShare{ID: id, Z: z}, nil
+}
+
+// appendField appends b to out, preceded by its 16-bit length.
+func appendField(out, b []byte) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(len(b)))
+	return append(out, b...)
+}
+
+// messageReader reads the fields of a binary protocol message.
+type messageReader struct {
+	data []byte
+}
+
+// newMessageReader checks the version and type of a message and returns
+// a reader for its fields.
+func newMessageReader(data []byte, typ byte) (*messageReader, error) {
+	if len(data) < 2 {
+		return nil, errTruncated
+	}
+	if data[0] != messageVersion {
+		return nil, fmt.Errorf("unsupported message version %d", data[0])
+	}
+	if data[1] != typ {
+		return nil, fmt.Errorf("message has type %d, want %d", data[1], typ)
+	}
+	return &messageReader{data[2:]}, nil
+}
+
+// uint16 reads a 16-bit big-endian integer.
+func (r *messageReader) uint16() (uint16, error) {
+	if len(r.data) < 2 {
+		return 0, errTruncated
+	}
+	v := binary.BigEndian.Uint16(r.data)
+	r.data = r.data[2:]
+	return v, nil
+}
+
+// field reads a length-prefixed field.
+func (r *messageReader) field() ([]byte, error) {
+	n, err := r.uint16()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.data) < int(n) {
+		return nil, errTruncated
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// scalar reads a length-prefixed canonical scalar of g.
+func (r *messageReader) scalar(g group.Group) (group.Scalar, error) {
+	b, err := r.field()
+	if err != nil {
+		return nil, err
+	}
+	return g.NewScalar().SetCanonicalBytes(b)
+}
+
+// id reads a participant identifier: a canonical, nonzero scalar of g.
+func (r *messageReader) id(g group.Group) (group.Scalar, error) {
+	id, err := r.scalar(g)
+	if err != nil {
+		return nil, err
+	}
+	if id.IsZero() {
+		return nil, errors.New("identifier is zero")
+	}
+	return id, nil
+}
+
+// points reads n length-prefixed points of g, which must be encoded
+// canonically.
+func (r *messageReader) points(g group.Group, n int) ([]group.Point, error) {
+	encoded := make([][]byte, n)
+	for i := range encoded {
+		var err error
+		if encoded[i], err = r.field(); err != nil {
+			return nil, err
+		}
+	}
+	points, err := group.DecodePoints(g, encoded)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range points {
+		if !bytes.Equal(p.Bytes(), encoded[i]) {
+			return nil, fmt.Errorf("point %d: non-canonical encoding", i)
+		}
+	}
+	return points, nil
+}
+
+// done returns an error if the message has bytes after its last field.
+func (r *messageReader) done() error {
+	if len(r.data) != 0 {
+		return fmt.Errorf("%d trailing bytes", len(r.data))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package frost
+
+import "github.com/f3rmion/fy/group"
+
+// SecretKeyOperator computes the part of a signature share that depends
+// on the secret key s of a key share, lambda*s*c, so that s can stay in
+// an external module such as a hardware security module or a PKCS#11
+// token and never enter process memory. [FROST.SignRound2WithOperator]
+// calls it instead of reading [KeyShare].SecretKey.
+//
+// This protects s against disclosure of the signer's memory, not against
+// a compromised signer: an operator that answers for any input reveals s
+// to whoever can call it, since s = term/(lambda*c). A module guarding a
+// high-value key should recompute lambda and c from the commitments,
+// group key and message in [ShareTermInput], and apply its own policy to
+// the message, before answering.
+type SecretKeyOperator interface {
+	// ShareTerm returns lambda*s*c for in.Lambda and in.Challenge.
+	ShareTerm(in *ShareTermInput) (group.Scalar, error)
+}
+
+// ShareTermInput is the signing session a [SecretKeyOperator] is asked to
+// contribute to. Lambda and Challenge are derived from the other fields
+// as in [FROST.Intermediates], so an operator can check them.
+type ShareTermInput struct {
+	// ID is the signer's identifier.
+	ID group.Scalar
+
+	// Lambda is the signer's Lagrange coefficient in the signer set.
+	Lambda group.Scalar
+
+	// Challenge is c = H2(R, Y, message).
+	Challenge group.Scalar
+
+	// GroupCommitment is R.
+	GroupCommitment group.Point
+
+	// GroupKey is Y.
+	GroupKey group.Point
+
+	// Message is the message being signed.
+	Message []byte
+
+	// Commitments are the commitments of all signers.
+	Commitments []*SigningCommitment
+}
+
+// SignRound2WithOperator is like [FROST.SignRound2] for a key share whose
+// secret key is held by op. The SecretKey of share is not used and may be
+// nil; its ID, PublicKey and GroupKey must be set.
+func (f *FROST) SignRound2WithOperator(
+	share *KeyShare,
+	op SecretKeyOperator,
+	nonce *SigningNonce,
+	message []byte,
+	commitments []*SigningCommitment,
+) (*SignatureShare, error) {
+	if err := checkNotNil(field{"op", op}); err != nil {
+		return nil, err
+	}
+	if err := f.checkPublicSigningInputs(share, nonce, commitments); err != nil {
+		return nil, err
+	}
+	s, err := f.prepare(share.GroupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	return f.signRound2(share, op, nonce, s)
+}
+
+// localKey is the SecretKeyOperator of a secret key in memory.
+type localKey struct {
+	s group.Scalar
+}
+
+// ShareTerm implements SecretKeyOperator.
+func (k localKey) ShareTerm(in *ShareTermInput) (group.Scalar, error) {
+	term := in.Lambda.Clone()
+	term.Mul(term, k.s)
+	return term.Mul(term, in.Challenge), nil
+}
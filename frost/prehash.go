@@ -0,0 +1,135 @@
+package frost
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/f3rmion/fy/group"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// StreamHasher is a Hasher whose message hash H4 can be computed
+// incrementally, so that large messages can be prehashed without holding
+// them in memory. See [FROST.PrehashMessage].
+type StreamHasher interface {
+	Hasher
+
+	// NewH4 returns a hash.Hash whose Sum is H4 of the data written to
+	// it. It returns an error if the hasher cannot stream H4.
+	NewH4(g group.Group) (hash.Hash, error)
+}
+
+// NewMessageHash returns a hash.Hash that computes H4 of the data written
+// to it, for callers that produce a message incrementally. Its Sum is the
+// prehashed message to sign and verify in place of the data. It returns
+// an error if the hasher does not implement [StreamHasher].
+func (f *FROST) NewMessageHash() (hash.Hash, error) {
+	s, ok := f.hasher.(StreamHasher)
+	if !ok {
+		return nil, fmt.Errorf("frost: hasher %T cannot stream messages", f.hasher)
+	}
+	return s.NewH4(f.group)
+}
+
+// PrehashMessage reads r until EOF and returns H4 of its contents, so that
+// a payload too large to hold in memory can be threshold-signed: the
+// signers sign the returned digest, and verifiers prehash the payload the
+// same way before calling [FROST.Verify]. Signing the digest binds the
+// payload as long as H4 is collision resistant.
+func (f *FROST) PrehashMessage(r io.Reader) ([]byte, error) {
+	h, err := f.NewMessageHash()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, fmt.Errorf("frost: reading message: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *SHA256Hasher) NewH4(g group.Group) (hash.Hash, error) {
+	hasher := sha256.New()
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte("msg"))
+	return hasher, nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *Blake2bHasher) NewH4(g group.Group) (hash.Hash, error) {
+	hasher, _ := blake2b.New512(nil)
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte("msg"))
+	return hasher, nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *Keccak256Hasher) NewH4(g group.Group) (hash.Hash, error) {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte("msg"))
+	return hasher, nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *SHA512Hasher) NewH4(g group.Group) (hash.Hash, error) {
+	hasher := sha512.New()
+	hasher.Write([]byte(h.Prefix))
+	hasher.Write([]byte("msg"))
+	return hasher, nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *SHAKE256Hasher) NewH4(g group.Group) (hash.Hash, error) {
+	xof := sha3.NewShake256()
+	xof.Write([]byte(h.Prefix))
+	xof.Write([]byte("msg"))
+	return &shakeDigest{ShakeHash: xof, size: h.digestLength()}, nil
+}
+
+// NewH4 implements StreamHasher.
+func (h *TaggedHasher) NewH4(g group.Group) (hash.Hash, error) {
+	return newTaggedHash(h.Prefix + "/msg"), nil
+}
+
+// NewH4 implements StreamHasher if the wrapped hasher does.
+func (h *HKDFNonceHasher) NewH4(g group.Group) (hash.Hash, error) {
+	s, ok := h.Hasher.(StreamHasher)
+	if !ok {
+		return nil, errors.New("frost: wrapped hasher cannot stream messages")
+	}
+	return s.NewH4(g)
+}
+
+// newTaggedHash returns a SHA-256 state that has absorbed the BIP-340 tag
+// prefix SHA256(tag) || SHA256(tag).
+func newTaggedHash(tag string) hash.Hash {
+	tagHash := sha256.Sum256([]byte(tag))
+	hasher := sha256.New()
+	hasher.Write(tagHash[:])
+	hasher.Write(tagHash[:])
+	return hasher
+}
+
+// shakeDigest adapts a SHAKE state to hash.Hash with a fixed output size.
+type shakeDigest struct {
+	sha3.ShakeHash
+	size int
+}
+
+// Sum appends size bytes of output without changing the state.
+func (d *shakeDigest) Sum(b []byte) []byte {
+	out := make([]byte, d.size)
+	d.ShakeHash.Clone().Read(out)
+	return append(b, out...)
+}
+
+// Size returns the output size.
+func (d *shakeDigest) Size() int {
+	return d.size
+}
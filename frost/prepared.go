@@ -0,0 +1,182 @@
+package frost
+
+import "github.com/f3rmion/fy/group"
+
+// PreparedSession holds the values of a signing session that depend only
+// on its public inputs: the binding factors, the group commitment R, the
+// challenge and the Lagrange coefficients. [FROST.SignRound2],
+// [FROST.VerifySignatureShare] and [FROST.Aggregate] each derive them from
+// scratch, hashing the whole commitment list once per signer; a process
+// that signs for several key shares, or a coordinator that checks every
+// share before aggregating, can prepare the session once with
+// [FROST.Prepare] and use its methods instead.
+//
+// A PreparedSession is immutable and safe for concurrent use. It keeps
+// references to the commitments and message it was prepared with, which
+// must not be modified afterwards.
+type PreparedSession struct {
+	f           *FROST
+	groupKey    group.Point
+	message     []byte
+	commitments []*SigningCommitment
+
+	// rho and lagrange map signer IDs to their binding factors and
+	// Lagrange coefficients; lagrange is nil if only some coefficients
+	// are needed, and computed per signer then
+	rho      map[string]group.Scalar
+	lagrange map[string]group.Scalar
+
+	r group.Point
+	c group.Scalar
+}
+
+// Prepare computes the shared values of the signing session for message
+// and commitments under groupKey. The commitment list is checked as in
+// [FROST.SignRound2].
+func (f *FROST) Prepare(groupKey group.Point, message []byte, commitments []*SigningCommitment) (*PreparedSession, error) {
+	if err := checkNotNil(field{"groupKey", groupKey}); err != nil {
+		return nil, err
+	}
+	if err := f.checkPoints(groupKey); err != nil {
+		return nil, err
+	}
+	if err := f.checkCommitments(commitments); err != nil {
+		return nil, err
+	}
+	lagrange, err := f.LagrangeCoefficients(commitments)
+	if err != nil {
+		return nil, err
+	}
+	s, err := f.prepare(groupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	s.lagrange = make(map[string]group.Scalar, len(commitments))
+	for i, c := range commitments {
+		s.lagrange[string(c.ID.Bytes())] = lagrange[i]
+	}
+	return s, nil
+}
+
+// prepare computes the binding factors, R and the challenge for checked
+// inputs, leaving the Lagrange coefficients to be computed on demand. It
+// returns an error if the hasher cannot sign message.
+func (f *FROST) prepare(groupKey group.Point, message []byte, commitments []*SigningCommitment) (*PreparedSession, error) {
+	if err := f.checkMessage(message); err != nil {
+		return nil, err
+	}
+	encCommitList := f.encodeCommitments(commitments)
+	rho := f.computeBindingFactors(message, encCommitList, commitments)
+	R := f.groupCommitment(rho, commitments)
+	c, err := Challenge(f.hasher, f.group, R.Bytes(), groupKey.Bytes(), message)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedSession{
+		f:           f,
+		groupKey:    groupKey,
+		message:     message,
+		commitments: commitments,
+		rho:         rho,
+		r:           R,
+		c:           c,
+	}, nil
+}
+
+// GroupCommitment returns R = sum(D_i + rho_i*E_i).
+func (s *PreparedSession) GroupCommitment() group.Point {
+	return s.r.Clone()
+}
+
+// Challenge returns c = H2(R, Y, message).
+func (s *PreparedSession) Challenge() group.Scalar {
+	return s.c.Clone()
+}
+
+// Sign is [FROST.SignRound2] for the prepared session. share must belong
+// to the group key the session was prepared for.
+func (s *PreparedSession) Sign(share *KeyShare, nonce *SigningNonce) (*SignatureShare, error) {
+	if err := s.f.checkSecretKey(share); err != nil {
+		return nil, err
+	}
+	if err := s.checkSigner(share, nonce); err != nil {
+		return nil, err
+	}
+	return s.f.signRound2(share, localKey{share.SecretKey}, nonce, s)
+}
+
+// SignWithOperator is [FROST.SignRound2WithOperator] for the prepared
+// session.
+func (s *PreparedSession) SignWithOperator(share *KeyShare, op SecretKeyOperator, nonce *SigningNonce) (*SignatureShare, error) {
+	if err := checkNotNil(field{"op", op}); err != nil {
+		return nil, err
+	}
+	if err := s.checkSigner(share, nonce); err != nil {
+		return nil, err
+	}
+	return s.f.signRound2(share, op, nonce, s)
+}
+
+// VerifyShare is [FROST.VerifySignatureShare] for the prepared session.
+func (s *PreparedSession) VerifyShare(share *SignatureShare, publicKey group.Point) bool {
+	if !s.f.validShare(share, publicKey) {
+		return false
+	}
+	w, P, err := s.shareTerms(share)
+	if err != nil {
+		return false
+	}
+	return P.Equal(s.f.publicMult(w, publicKey))
+}
+
+// Aggregate is [FROST.Aggregate] for the prepared session.
+func (s *PreparedSession) Aggregate(shares []*SignatureShare) (*Signature, error) {
+	if err := s.f.checkShares(shares, s.commitments); err != nil {
+		return nil, err
+	}
+	return s.f.aggregate(s.r, shares), nil
+}
+
+// checkSigner checks the public inputs of a signer in the session.
+func (s *PreparedSession) checkSigner(share *KeyShare, nonce *SigningNonce) error {
+	if err := s.f.checkSignerInputs(share, nonce); err != nil {
+		return err
+	}
+	if !share.GroupKey.Equal(s.groupKey) {
+		return &InputError{Field: "share.GroupKey", Reason: "differs from the prepared session"}
+	}
+	if _, ok := s.rho[string(share.ID.Bytes())]; !ok {
+		return &InputError{Field: "commitments", Reason: "no commitment from this signer"}
+	}
+	return nil
+}
+
+// lambda returns the Lagrange coefficient of signer id.
+func (s *PreparedSession) lambda(id group.Scalar) (group.Scalar, error) {
+	if s.lagrange != nil {
+		return s.lagrange[string(id.Bytes())].Clone(), nil
+	}
+	return s.f.lagrangeCoefficient(id, s.commitments)
+}
+
+// shareTerms splits the signature share check into w = lambda_i*c and
+// P = z_i*G - D_i - rho_i*E_i, so that the share is valid for the public
+// key Y_i exactly when P == w*Y_i. share must be validated by the caller.
+func (s *PreparedSession) shareTerms(share *SignatureShare) (w group.Scalar, P group.Point, err error) {
+	own := findCommitment(s.commitments, share.ID)
+	if own == nil {
+		return nil, nil, &InputError{Field: "commitments", Reason: "no commitment from this signer"}
+	}
+	lambda, err := s.lambda(share.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// P = z_i*G - (D_i + rho_i*E_i)
+	rho := s.rho[string(share.ID.Bytes())]
+	nonceTerm := s.f.publicMult(rho, own.BindingPoint)
+	nonceTerm.Add(own.HidingPoint, nonceTerm)
+	P = s.f.group.ScalarBaseMult(share.Z)
+	P.Sub(P, nonceTerm)
+	return lambda.Mul(lambda, s.c), P, nil
+}
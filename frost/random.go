@@ -0,0 +1,62 @@
+package frost
+
+import (
+	"errors"
+	"io"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// ErrWeakRandomness is returned by [FROST.SignRound1] and
+// [FROST.NewParticipant] when the random source is evidently broken: it
+// returned a block of identical bytes, or two secret values drawn from it
+// came out equal. Such a source would produce predictable nonces or
+// polynomials, which leak the secret key.
+//
+// The check only catches gross failures such as a reader that returns
+// zeros or repeats itself. To stay safe with a random source that may be
+// weak in subtler ways, sign with [FROST.SignRound1Hedged], which mixes
+// the secret key share and the message into every nonce.
+var ErrWeakRandomness = errors.New("frost: random source returned non-random output")
+
+// minCheckedRead is the shortest read checked for constant output; shorter
+// reads are constant by chance too often.
+const minCheckedRead = 16
+
+// checkedReader fails reads of at least minCheckedRead bytes that consist
+// of a single repeated byte value.
+type checkedReader struct {
+	r io.Reader
+}
+
+// Read implements io.Reader.
+func (c checkedReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n >= minCheckedRead && isConstant(p[:n]) {
+		return 0, ErrWeakRandomness
+	}
+	return n, err
+}
+
+// isConstant reports whether all bytes of b are equal. It reads every
+// byte without branching on their values, since b holds secret material.
+func isConstant(b []byte) bool {
+	var diff byte
+	for _, v := range b {
+		diff |= v ^ b[0]
+	}
+	return diff == 0
+}
+
+// checkDistinct returns ErrWeakRandomness if any two of scalars, which
+// were drawn independently at random, are equal.
+func checkDistinct(scalars ...group.Scalar) error {
+	for i := range scalars {
+		for j := i + 1; j < len(scalars); j++ {
+			if scalars[i].Equal(scalars[j]) {
+				return ErrWeakRandomness
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package frost
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// redacted replaces secret values in String, GoString and LogValue output,
+// so that logging a key share or nonce with fmt or log/slog does not leak
+// it. The methods use value receivers so that they also apply when a
+// struct is printed by value.
+const redacted = "[REDACTED]"
+
+// String returns the public fields of k, with the secret key redacted.
+func (k KeyShare) String() string {
+	return fmt.Sprintf("KeyShare{ID: %s, SecretKey: %s, PublicKey: %s, GroupKey: %s}",
+		fmtID(k.ID), fmtSecret(k.SecretKey), fmtPoint(k.PublicKey), fmtPoint(k.GroupKey))
+}
+
+// GoString is like String, for the %#v verb.
+func (k KeyShare) GoString() string {
+	return "frost." + k.String()
+}
+
+// LogValue implements slog.LogValuer, redacting the secret key.
+func (k KeyShare) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", fmtID(k.ID)),
+		slog.String("secret_key", fmtSecret(k.SecretKey)),
+		slog.String("public_key", fmtPoint(k.PublicKey)),
+		slog.String("group_key", fmtPoint(k.GroupKey)),
+	)
+}
+
+// String returns the ID of n, with the nonces redacted.
+func (n SigningNonce) String() string {
+	return fmt.Sprintf("SigningNonce{ID: %s, D: %s, E: %s}", fmtID(n.ID), fmtSecret(n.D), fmtSecret(n.E))
+}
+
+// GoString is like String, for the %#v verb.
+func (n SigningNonce) GoString() string {
+	return "frost." + n.String()
+}
+
+// LogValue implements slog.LogValuer, redacting the nonces.
+func (n SigningNonce) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", fmtID(n.ID)),
+		slog.String("d", fmtSecret(n.D)),
+		slog.String("e", fmtSecret(n.E)),
+	)
+}
+
+// String returns the sender and recipient of d, with the share redacted.
+func (d Round1PrivateData) String() string {
+	return fmt.Sprintf("Round1PrivateData{FromID: %s, ToID: %s, Share: %s}",
+		fmtID(d.FromID), fmtID(d.ToID), fmtSecret(d.Share))
+}
+
+// GoString is like String, for the %#v verb.
+func (d Round1PrivateData) GoString() string {
+	return "frost." + d.String()
+}
+
+// LogValue implements slog.LogValuer, redacting the share.
+func (d Round1PrivateData) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("from_id", fmtID(d.FromID)),
+		slog.String("to_id", fmtID(d.ToID)),
+		slog.String("share", fmtSecret(d.Share)),
+	)
+}
+
+// String returns the ID of p and the number of shares it has received,
+// with its secret polynomial redacted.
+func (p Participant) String() string {
+	return fmt.Sprintf("Participant{ID: %s, Polynomial: %s, ReceivedShares: %d}",
+		fmtID(p.id), p.polynomialString(), len(p.receivedShares))
+}
+
+// GoString is like String, for the %#v verb.
+func (p Participant) GoString() string {
+	return "frost." + p.String()
+}
+
+// LogValue implements slog.LogValuer, redacting the secret polynomial.
+func (p Participant) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", fmtID(p.id)),
+		slog.String("polynomial", p.polynomialString()),
+		slog.Int("received_shares", len(p.receivedShares)),
+	)
+}
+
+// polynomialString describes the secret polynomial without revealing it.
+func (p Participant) polynomialString() string {
+	if p.coefficients == nil {
+		return "<destroyed>"
+	}
+	return redacted
+}
+
+// fmtID formats a public participant ID.
+func fmtID(id group.Scalar) string {
+	if isNil(id) {
+		return "<nil>"
+	}
+	return idString(id)
+}
+
+// fmtPoint formats a public point as the hex encoding of its bytes.
+func fmtPoint(p group.Point) string {
+	if isNil(p) {
+		return "<nil>"
+	}
+	return hex.EncodeToString(p.Bytes())
+}
+
+// fmtSecret stands in for a secret scalar, showing only whether it is set.
+func fmtSecret(s group.Scalar) string {
+	if isNil(s) {
+		return "<nil>"
+	}
+	return redacted
+}
@@ -0,0 +1,121 @@
+package frost
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// NamedHasher is a Hasher with a stable identifier, suitable for recording
+// in serialized signatures, key shares and session envelopes so that
+// verifiers can reconstruct the hasher with [NewHasher]. The hashers of
+// this package implement it, except for the [HKDFNonceHasher] wrapper.
+type NamedHasher interface {
+	Hasher
+
+	// Name returns the identifier the hasher is registered under with
+	// [RegisterHasher], such as "sha256". It names the construction, not
+	// its configuration; see [HasherName].
+	Name() string
+}
+
+var (
+	hasherRegistryMu sync.RWMutex
+	hasherRegistry   = make(map[string]func() Hasher)
+)
+
+func init() {
+	RegisterHasher("sha256", func() Hasher { return &SHA256Hasher{} })
+	RegisterHasher("blake2b-512", func() Hasher { return NewBlake2bHasher() })
+	RegisterHasher("keccak256", func() Hasher { return NewKeccak256Hasher() })
+	RegisterHasher("sha512", func() Hasher { return NewSHA512Hasher() })
+	RegisterHasher("shake256", func() Hasher { return NewSHAKE256Hasher() })
+	RegisterHasher("poseidon2-bn254", func() Hasher { return NewPoseidonHasher() })
+	RegisterHasher("bip340", func() Hasher { return NewTaggedHasher() })
+}
+
+// RegisterHasher makes a Hasher available to [NewHasher] under name, which
+// should match the value returned by the hasher's Name method. The factory
+// returns the hasher in its default configuration. Identifiers are
+// recorded in serialized data and must never change once published.
+//
+// RegisterHasher panics if factory is nil or if name is already
+// registered.
+func RegisterHasher(name string, factory func() Hasher) {
+	hasherRegistryMu.Lock()
+	defer hasherRegistryMu.Unlock()
+
+	if factory == nil {
+		panic("frost: RegisterHasher factory is nil")
+	}
+	if _, dup := hasherRegistry[name]; dup {
+		panic("frost: RegisterHasher called twice for " + name)
+	}
+	hasherRegistry[name] = factory
+}
+
+// NewHasher returns a new instance of the Hasher registered under name,
+// in its default configuration.
+func NewHasher(name string) (Hasher, error) {
+	hasherRegistryMu.RLock()
+	factory, ok := hasherRegistry[name]
+	hasherRegistryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("frost: unknown hasher %q", name)
+	}
+	return factory(), nil
+}
+
+// HasherNames returns the names of the registered hashers in sorted order.
+func HasherNames() []string {
+	hasherRegistryMu.RLock()
+	defer hasherRegistryMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(hasherRegistry))
+}
+
+// HasherName returns the identifier to record for h. It returns an error
+// unless h is a [NamedHasher] whose name is registered and [NewHasher]
+// reconstructs a hasher with the same configuration, so that a recorded
+// name always identifies the exact hashes used. Hashers with a custom
+// configuration, such as a non-default Prefix, should be registered under
+// a name of their own.
+func HasherName(h Hasher) (string, error) {
+	named, ok := h.(NamedHasher)
+	if !ok {
+		return "", fmt.Errorf("frost: hasher %T has no name", h)
+	}
+	name := named.Name()
+	registered, err := NewHasher(name)
+	if err != nil {
+		return "", err
+	}
+	if !reflect.DeepEqual(registered, h) {
+		return "", fmt.Errorf("frost: hasher %q does not have its registered configuration", name)
+	}
+	return name, nil
+}
+
+// Name implements NamedHasher.
+func (h *SHA256Hasher) Name() string { return "sha256" }
+
+// Name implements NamedHasher.
+func (h *Blake2bHasher) Name() string { return "blake2b-512" }
+
+// Name implements NamedHasher.
+func (h *Keccak256Hasher) Name() string { return "keccak256" }
+
+// Name implements NamedHasher.
+func (h *SHA512Hasher) Name() string { return "sha512" }
+
+// Name implements NamedHasher.
+func (h *SHAKE256Hasher) Name() string { return "shake256" }
+
+// Name implements NamedHasher.
+func (h *PoseidonHasher) Name() string { return "poseidon2-bn254" }
+
+// Name implements NamedHasher.
+func (h *TaggedHasher) Name() string { return "bip340" }
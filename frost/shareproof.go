@@ -0,0 +1,173 @@
+package frost
+
+import (
+	"errors"
+	"io"
+
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// shareProofDomain separates share proof challenges from every other hash
+// in the protocol.
+const shareProofDomain = "FROST-SHARE-PROOF-v1"
+
+// SignatureShareProof shows that a signature share was produced with the
+// secret key share behind PublicKey. A signer attaches it to its
+// [SignatureShare] with [FROST.ProveSignatureShare], and anyone holding
+// the session's public inputs can check it with
+// [FROST.VerifySignatureShareProof].
+//
+// The share is valid for Y_i exactly when P == w*Y_i, where
+// w = lambda_i*c and P = z_i*G - D_i - rho_i*E_i, which is a discrete log
+// equality between (G, Y_i) and (w*G, P). Because w*G is a public multiple
+// of G, the proof is that equation together with a Schnorr proof of
+// knowledge of s_i for Y_i, bound to the share and the session.
+type SignatureShareProof struct {
+	// PublicKey is the signer's public verification share Y_i.
+	PublicKey group.Point
+
+	// Challenge is the Fiat-Shamir challenge e.
+	Challenge group.Scalar
+
+	// Response is k + e*s_i for the prover's random k.
+	Response group.Scalar
+}
+
+// ProveSignatureShare produces a correctness proof for sigShare, the
+// signature share that keyShare produced over message and commitments
+// with [FROST.SignRound2]. It returns an error if sigShare is not valid
+// for the key share, so a signer never publishes a proof for a bad share.
+//
+// The proof draws one random scalar from r; a broken r is detected as in
+// [FROST.SignRound1].
+func (f *FROST) ProveSignatureShare(
+	r io.Reader,
+	keyShare *KeyShare,
+	sigShare *SignatureShare,
+	message []byte,
+	commitments []*SigningCommitment,
+) (*SignatureShareProof, error) {
+	if err := checkNotNil(field{"keyShare", keyShare}, field{"sigShare", sigShare}); err != nil {
+		return nil, err
+	}
+	if err := checkNotNil(
+		field{"keyShare.ID", keyShare.ID},
+		field{"keyShare.SecretKey", keyShare.SecretKey},
+		field{"keyShare.PublicKey", keyShare.PublicKey},
+		field{"keyShare.GroupKey", keyShare.GroupKey},
+		field{"sigShare.ID", sigShare.ID},
+		field{"sigShare.Z", sigShare.Z},
+	); err != nil {
+		return nil, err
+	}
+	if err := f.checkScalars(keyShare.ID, keyShare.SecretKey, sigShare.ID, sigShare.Z); err != nil {
+		return nil, err
+	}
+	if err := f.checkPoints(keyShare.PublicKey, keyShare.GroupKey); err != nil {
+		return nil, err
+	}
+	if !sigShare.ID.Equal(keyShare.ID) {
+		return nil, &InputError{Field: "sigShare.ID", Reason: "does not match keyShare.ID"}
+	}
+
+	w, P, err := f.shareTerms(sigShare, keyShare.GroupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	Y := keyShare.PublicKey
+	if !P.Equal(f.publicMult(w, Y)) {
+		return nil, errors.New("frost: signature share is not valid for this key share")
+	}
+
+	k, err := f.group.RandomScalar(checkedReader{r})
+	if err != nil {
+		return nil, err
+	}
+	defer memwipe.Scalars(k)
+
+	A := f.group.ScalarBaseMult(k)
+	e, err := f.shareProofChallenge(sigShare, Y, A, keyShare.GroupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	s := f.group.NewScalar().Mul(e, keyShare.SecretKey)
+	s.Add(k, s)
+	return &SignatureShareProof{PublicKey: Y, Challenge: e, Response: s}, nil
+}
+
+// VerifySignatureShareProof checks proof for share, the signature share
+// of signer share.ID over message and commitments for groupKey. It
+// returns true if the share is valid for proof.PublicKey and the proof
+// was made by the holder of the matching secret key share.
+//
+// A valid proof attributes the share to proof.PublicKey, not to the
+// signer's ID: callers must still check that proof.PublicKey is the
+// signer's verification share, from the DKG broadcasts with
+// [FROST.VerificationShare] or from a published roster. With that check,
+// a third party can tell a bad share from a good one without trusting the
+// coordinator that relayed it.
+func (f *FROST) VerifySignatureShareProof(
+	share *SignatureShare,
+	proof *SignatureShareProof,
+	groupKey group.Point,
+	message []byte,
+	commitments []*SigningCommitment,
+) bool {
+	if share == nil || proof == nil || checkNotNil(
+		field{"share.ID", share.ID},
+		field{"share.Z", share.Z},
+		field{"proof.PublicKey", proof.PublicKey},
+		field{"proof.Challenge", proof.Challenge},
+		field{"proof.Response", proof.Response},
+		field{"groupKey", groupKey},
+	) != nil {
+		return false
+	}
+	if f.checkScalars(share.ID, share.Z, proof.Challenge, proof.Response) != nil ||
+		f.checkPoints(proof.PublicKey, groupKey) != nil {
+		return false
+	}
+	Y := proof.PublicKey
+	if Y.IsIdentity() {
+		return false
+	}
+
+	w, P, err := f.shareTerms(share, groupKey, message, commitments)
+	if err != nil {
+		return false
+	}
+	if !P.Equal(f.publicMult(w, Y)) {
+		return false
+	}
+
+	// A = Response*G - Challenge*Y
+	A := f.group.ScalarBaseMult(proof.Response)
+	A.Sub(A, f.publicMult(proof.Challenge, Y))
+	e, err := f.shareProofChallenge(share, Y, A, groupKey, message, commitments)
+	if err != nil {
+		return false
+	}
+	return e.Equal(proof.Challenge)
+}
+
+// shareProofChallenge computes the challenge of a share proof. It binds
+// the proof to the share, the signer's public key and the whole session,
+// so a proof cannot be moved to another share or session.
+func (f *FROST) shareProofChallenge(
+	share *SignatureShare,
+	Y, A, groupKey group.Point,
+	message []byte,
+	commitments []*SigningCommitment,
+) (group.Scalar, error) {
+	return f.group.HashToScalar(
+		[]byte(shareProofDomain),
+		share.ID.Bytes(),
+		share.Z.Bytes(),
+		Y.Bytes(),
+		A.Bytes(),
+		groupKey.Bytes(),
+		f.hasher.H4(f.group, message),
+		f.hasher.H5(f.group, f.encodeCommitments(commitments)),
+	)
+}
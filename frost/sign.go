@@ -1,9 +1,11 @@
 package frost
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
 )
 
 // SigningNonce holds the secret nonce values generated by a participant
@@ -19,6 +21,12 @@ type SigningNonce struct {
 	E group.Scalar
 }
 
+// Zeroize wipes the nonces with [group.Scalar.Zeroize]. Call it once the
+// signature share has been computed; the nonce must not be used again.
+func (n *SigningNonce) Zeroize() {
+	memwipe.Scalars(n.D, n.E)
+}
+
 // SigningCommitment is the public commitment broadcast by a participant
 // during round 1 of signing.
 type SigningCommitment struct {
@@ -48,30 +56,65 @@ type SignatureShare struct {
 // to all other signers.
 //
 // Each call to SignRound1 generates new random nonces. Nonces must never
-// be reused across signing sessions.
+// be reused across signing sessions. If r returns constant output or the
+// same nonce twice, SignRound1 fails with [ErrWeakRandomness].
 func (f *FROST) SignRound1(r io.Reader, share *KeyShare) (*SigningNonce, *SigningCommitment, error) {
+	r = checkedReader{r}
 	d, err := f.group.RandomScalar(r)
 	if err != nil {
 		return nil, nil, err
 	}
 	e, err := f.group.RandomScalar(r)
 	if err != nil {
+		memwipe.Scalars(d)
+		return nil, nil, err
+	}
+	if err := checkDistinct(d, e); err != nil {
+		memwipe.Scalars(d, e)
+		return nil, nil, err
+	}
+
+	return f.newSigningNonce(share, d, e), f.newSigningCommitment(share, d, e), nil
+}
+
+// SignRound1Hedged is like [FROST.SignRound1] but derives each nonce with
+// H3 from fresh randomness, the signer's secret key share and the message.
+// If the random source is weak or repeats, the nonces still differ for
+// distinct messages and key shares, which protects against the key
+// recovery attacks caused by nonce reuse.
+func (f *FROST) SignRound1Hedged(r io.Reader, share *KeyShare, message []byte) (*SigningNonce, *SigningCommitment, error) {
+	var seedD, seedE [32]byte
+	if _, err := io.ReadFull(r, seedD[:]); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(r, seedE[:]); err != nil {
 		return nil, nil, err
 	}
 
-	nonce := &SigningNonce{
-		ID: share.ID,
+	secret := share.SecretKey.Bytes()
+	d := f.hasher.H3(f.group, seedD[:], secret, message)
+	e := f.hasher.H3(f.group, seedE[:], secret, message)
+	memwipe.Bytes(secret)
+
+	return f.newSigningNonce(share, d, e), f.newSigningCommitment(share, d, e), nil
+}
+
+// newSigningNonce wraps the nonce scalars d and e for share.
+func (f *FROST) newSigningNonce(share *KeyShare, d, e group.Scalar) *SigningNonce {
+	return &SigningNonce{
+		ID: share.ID.Clone(),
 		D:  d,
 		E:  e,
 	}
+}
 
-	commitment := &SigningCommitment{
-		ID:           share.ID,
-		HidingPoint:  f.group.NewPoint().ScalarMult(d, f.group.Generator()),
-		BindingPoint: f.group.NewPoint().ScalarMult(e, f.group.Generator()),
+// newSigningCommitment computes the public commitment to nonces d and e.
+func (f *FROST) newSigningCommitment(share *KeyShare, d, e group.Scalar) *SigningCommitment {
+	return &SigningCommitment{
+		ID:           share.ID.Clone(),
+		HidingPoint:  f.group.ScalarBaseMult(d),
+		BindingPoint: f.group.ScalarBaseMult(e),
 	}
-
-	return nonce, commitment, nil
 }
 
 // SignRound2 computes this participant's signature share for the given message.
@@ -80,44 +123,72 @@ func (f *FROST) SignRound1(r io.Reader, share *KeyShare) (*SigningNonce, *Signin
 //
 // The commitments slice must include commitments from all signers participating
 // in this signing session (at least threshold signers).
+//
+// The share is bound to the signer set: every binding factor hashes the
+// encoded list of all signer IDs and commitments with H1, and the
+// challenge hashes the group commitment R derived from them. A share
+// produced for one signer set therefore does not verify or aggregate
+// under any other. The challenge itself remains H2(R, Y, message), so
+// that signatures verify as plain Schnorr signatures.
 func (f *FROST) SignRound2(
 	share *KeyShare,
 	nonce *SigningNonce,
 	message []byte,
 	commitments []*SigningCommitment,
 ) (*SignatureShare, error) {
-	// Encode commitment list for binding factor computation
-	encCommitList := f.encodeCommitments(commitments)
-
-	// Compute binding factors for each signer using H1
-	bindingFactors := f.computeBindingFactors(message, encCommitList, commitments)
-
-	// Compute group commitment R = sum(D_i + rho_i * E_i)
-	R := f.group.NewPoint()
-	for _, comm := range commitments {
-		rho := bindingFactors[string(comm.ID.Bytes())]
-		rhoE := f.group.NewPoint().ScalarMult(rho, comm.BindingPoint)
-		term := f.group.NewPoint().Add(comm.HidingPoint, rhoE)
-		R = f.group.NewPoint().Add(R, term)
+	if err := f.checkSigningInputs(share, nonce, commitments); err != nil {
+		return nil, err
 	}
+	s, err := f.prepare(share.GroupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	return f.signRound2(share, localKey{share.SecretKey}, nonce, s)
+}
 
-	// Compute challenge c = H2(R, GroupKey, message)
-	c := f.hasher.H2(f.group, R.Bytes(), share.GroupKey.Bytes(), message)
-
+// signRound2 computes the signature share for checked inputs in session
+// s, with the secret key term from op.
+func (f *FROST) signRound2(
+	share *KeyShare,
+	op SecretKeyOperator,
+	nonce *SigningNonce,
+	s *PreparedSession,
+) (*SignatureShare, error) {
 	// Compute Lagrange coefficient for this signer
-	lambda := f.lagrangeCoefficient(share.ID, commitments)
+	lambda, err := s.lambda(share.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Compute signature share: z_i = d + rho * e + lambda * s * c
-	myRho := bindingFactors[string(share.ID.Bytes())]
+	myRho := s.rho[string(share.ID.Bytes())]
+
+	term, err := op.ShareTerm(&ShareTermInput{
+		ID:              share.ID,
+		Lambda:          lambda,
+		Challenge:       s.c.Clone(),
+		GroupCommitment: s.r.Clone(),
+		GroupKey:        share.GroupKey,
+		Message:         s.message,
+		Commitments:     s.commitments,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if isNil(term) {
+		return nil, &InputError{Field: "share term", Reason: "is nil"}
+	}
+	if err := f.checkScalars(term); err != nil {
+		return nil, err
+	}
 
-	z := f.group.NewScalar().Mul(myRho, nonce.E)                // rho * e
-	z = f.group.NewScalar().Add(nonce.D, z)                     // d + rho * e
-	lambdaS := f.group.NewScalar().Mul(lambda, share.SecretKey) // lambda * s
-	lambdaSC := f.group.NewScalar().Mul(lambdaS, c)             // lambda * s * c
-	z = f.group.NewScalar().Add(z, lambdaSC)                    // d + rho*e + lambda*s*c
+	z := f.group.NewScalar().Mul(myRho, nonce.E) // rho * e
+	z.Add(nonce.D, z)                            // d + rho * e
+	z.Add(z, term)                               // d + rho*e + lambda*s*c
+	memwipe.Scalars(term)
 
 	return &SignatureShare{
-		ID: share.ID,
+		ID: share.ID.Clone(),
 		Z:  z,
 	}, nil
 }
@@ -132,52 +203,205 @@ func (f *FROST) Aggregate(
 	commitments []*SigningCommitment,
 	shares []*SignatureShare,
 ) (*Signature, error) {
+	if err := f.checkCommitments(commitments); err != nil {
+		return nil, err
+	}
+	if err := f.checkShares(shares, commitments); err != nil {
+		return nil, err
+	}
+	if err := f.checkMessage(message); err != nil {
+		return nil, err
+	}
+
 	// Encode commitment list and recompute R
 	encCommitList := f.encodeCommitments(commitments)
 	bindingFactors := f.computeBindingFactors(message, encCommitList, commitments)
-	R := f.group.NewPoint()
-	for _, comm := range commitments {
-		rho := bindingFactors[string(comm.ID.Bytes())]
-		rhoE := f.group.NewPoint().ScalarMult(rho, comm.BindingPoint)
-		term := f.group.NewPoint().Add(comm.HidingPoint, rhoE)
-		R = f.group.NewPoint().Add(R, term)
+	R := f.groupCommitment(bindingFactors, commitments)
+	return f.aggregate(R, shares), nil
+}
+
+// aggregate sums the checked signature shares into a signature with group
+// commitment R. Large sets are summed in chunks, see
+// [FROST.WithParallelism].
+func (f *FROST) aggregate(R group.Point, shares []*SignatureShare) *Signature {
+	k := f.workers(len(shares), minAggregateChunk)
+	if k == 1 {
+		return &Signature{R: R.Clone(), Z: f.sumShares(shares)}
 	}
+	sums := make([]group.Scalar, k)
+	chunks(len(shares), k, func(j, lo, hi int) {
+		sums[j] = f.sumShares(shares[lo:hi])
+	})
+	z := sums[0]
+	for _, sum := range sums[1:] {
+		z.Add(z, sum)
+	}
+	return &Signature{R: R.Clone(), Z: z}
+}
 
-	// Sum all z shares
+// sumShares returns the sum of the Z values of shares.
+func (f *FROST) sumShares(shares []*SignatureShare) group.Scalar {
 	z := f.group.NewScalar()
 	for _, s := range shares {
-		z = f.group.NewScalar().Add(z, s.Z)
+		z.Add(z, s.Z)
 	}
-
-	return &Signature{R: R, Z: z}, nil
+	return z
 }
 
 // Verify checks whether a FROST signature is valid for the given message
 // and group public key. Returns true if the signature is valid.
 //
 // This performs standard Schnorr signature verification:
-// z*G == R + c*Y, where c = H2(R, Y, message).
+// z*G == R + c*Y, where c = H2(R, Y, message). Signatures whose R is the
+// identity or outside the prime-order subgroup are rejected, so that a
+// valid signature cannot be altered into another valid one.
 func (f *FROST) Verify(message []byte, sig *Signature, groupKey group.Point) bool {
+	if f.checkSignature(sig) != nil || isNil(groupKey) || f.checkPoints(groupKey) != nil {
+		return false
+	}
+	if f.checkMessage(message) != nil {
+		return false
+	}
+
 	// c = H2(R, GroupKey, message)
-	c := f.hasher.H2(f.group, sig.R.Bytes(), groupKey.Bytes(), message)
+	c, err := Challenge(f.hasher, f.group, sig.R.Bytes(), groupKey.Bytes(), message)
+	if err != nil {
+		return false
+	}
 
 	// Check: z*G == R + c*Y
-	lhs := f.group.NewPoint().ScalarMult(sig.Z, f.group.Generator())
+	lhs := f.group.ScalarBaseMult(sig.Z)
 
-	cY := f.group.NewPoint().ScalarMult(c, groupKey)
-	rhs := f.group.NewPoint().Add(sig.R, cY)
+	rhs := f.publicMult(c, groupKey)
+	rhs.Add(sig.R, rhs)
 
 	return lhs.Equal(rhs)
 }
 
+// BatchEntry is a signature to check with [FROST.VerifyBatch], together
+// with the message and group public key it must verify against.
+type BatchEntry struct {
+	Message   []byte
+	Signature *Signature
+	GroupKey  group.Point
+}
+
+// VerifyBatch checks many signatures at once and returns true only if all
+// of them are valid. It verifies a random linear combination of the
+// equations z_i*G == R_i + c_i*Y_i with a single multi-scalar
+// multiplication, which is much faster than calling [FROST.Verify] for
+// each entry. The coefficients are drawn from r, and an error is returned
+// only if reading from r fails.
+//
+// A false result does not identify the invalid signature; use Verify on
+// the entries to find it.
+func (f *FROST) VerifyBatch(r io.Reader, entries []BatchEntry) (bool, error) {
+	// sum(a_i*z_i)*G - sum(a_i*R_i) - sum(a_i*c_i*Y_i) must be the identity
+	zSum := f.group.NewScalar()
+	tmp := f.group.NewScalar()
+	scalars := make([]group.Scalar, 0, 2*len(entries)+1)
+	points := make([]group.Point, 0, 2*len(entries)+1)
+	for _, e := range entries {
+		if f.checkSignature(e.Signature) != nil || isNil(e.GroupKey) || f.checkPoints(e.GroupKey) != nil {
+			return false, nil
+		}
+		if f.checkMessage(e.Message) != nil {
+			return false, nil
+		}
+		c, err := Challenge(f.hasher, f.group, e.Signature.R.Bytes(), e.GroupKey.Bytes(), e.Message)
+		if err != nil {
+			return false, nil
+		}
+		a, err := f.group.RandomScalar(r)
+		if err != nil {
+			return false, fmt.Errorf("batch coefficient: %w", err)
+		}
+
+		// a and c are not used again, so they hold -a and -a*c
+		zSum.Add(zSum, tmp.Mul(a, e.Signature.Z))
+		c.Mul(c, a).Negate(c)
+		scalars = append(scalars, a.Negate(a), c)
+		points = append(points, e.Signature.R, e.GroupKey)
+	}
+	scalars = append(scalars, zSum)
+	points = append(points, f.group.Generator())
+
+	return f.group.MultiScalarMult(scalars, points).IsIdentity(), nil
+}
+
+// VerifySignatureShare checks a single signer's signature share against
+// its public verification share, so that a coordinator can identify a
+// misbehaving signer instead of only learning that the aggregate is
+// invalid. Returns true if the share is valid.
+//
+// The check is z_i*G == D_i + rho_i*E_i + lambda_i*c*Y_i, where Y_i is the
+// signer's public key and c is the challenge for the group commitment.
+func (f *FROST) VerifySignatureShare(
+	share *SignatureShare,
+	publicKey group.Point,
+	groupKey group.Point,
+	message []byte,
+	commitments []*SigningCommitment,
+) bool {
+	if !f.validShare(share, publicKey) || isNil(groupKey) || f.checkPoints(groupKey) != nil {
+		return false
+	}
+	w, P, err := f.shareTerms(share, groupKey, message, commitments)
+	if err != nil {
+		return false
+	}
+	return P.Equal(f.publicMult(w, publicKey))
+}
+
+// validShare reports whether share and publicKey are complete and valid
+// elements of the group.
+func (f *FROST) validShare(share *SignatureShare, publicKey group.Point) bool {
+	if share == nil || checkNotNil(
+		field{"share.ID", share.ID},
+		field{"share.Z", share.Z},
+		field{"publicKey", publicKey},
+	) != nil {
+		return false
+	}
+	return f.checkScalars(share.ID, share.Z) == nil && f.checkPoints(publicKey) == nil
+}
+
+// shareTerms is [PreparedSession.shareTerms] for a session that has not
+// been prepared. share must be validated by the caller.
+func (f *FROST) shareTerms(
+	share *SignatureShare,
+	groupKey group.Point,
+	message []byte,
+	commitments []*SigningCommitment,
+) (w group.Scalar, P group.Point, err error) {
+	if err := f.checkCommitments(commitments); err != nil {
+		return nil, nil, err
+	}
+	s, err := f.prepare(groupKey, message, commitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.shareTerms(share)
+}
+
 // encodeCommitments serializes the commitment list for hashing.
 // The encoding is: ID || HidingPoint || BindingPoint for each commitment.
 func (f *FROST) encodeCommitments(commitments []*SigningCommitment) []byte {
-	var commBytes []byte
+	points := make([]group.Point, 0, 2*len(commitments))
 	for _, c := range commitments {
+		points = append(points, c.HidingPoint, c.BindingPoint)
+	}
+	encoded := group.EncodePoints(f.group, points)
+
+	var size int
+	for _, enc := range encoded {
+		size += len(enc)
+	}
+	commBytes := make([]byte, 0, size+len(commitments)*f.group.ScalarSize())
+	for i, c := range commitments {
 		commBytes = append(commBytes, c.ID.Bytes()...)
-		commBytes = append(commBytes, c.HidingPoint.Bytes()...)
-		commBytes = append(commBytes, c.BindingPoint.Bytes()...)
+		commBytes = append(commBytes, encoded[2*i]...)
+		commBytes = append(commBytes, encoded[2*i+1]...)
 	}
 	return commBytes
 }
@@ -185,35 +409,107 @@ func (f *FROST) encodeCommitments(commitments []*SigningCommitment) []byte {
 // computeBindingFactors derives the binding factor for each signer from
 // the message and all signing commitments using H1. This ensures that each
 // signer's contribution is bound to the specific signing session.
+// Callers encode the commitment list once per session and every H1 call
+// hashes those same bytes; H1 must receive the whole list, not a digest
+// of it, to keep the transcript of Ledger's and iden3's implementations.
 func (f *FROST) computeBindingFactors(message, encCommitList []byte, commitments []*SigningCommitment) map[string]group.Scalar {
-	factors := make(map[string]group.Scalar)
+	factors := make(map[string]group.Scalar, len(commitments))
 
 	for _, c := range commitments {
-		rho := f.hasher.H1(f.group, message, encCommitList, c.ID.Bytes())
-		factors[string(c.ID.Bytes())] = rho
+		id := c.ID.Bytes()
+		factors[string(id)] = f.hasher.H1(f.group, message, encCommitList, id)
 	}
 
 	return factors
 }
 
+// groupCommitment computes R = sum(D_i + rho_i * E_i) over all signers
+// with a single multi-scalar multiplication, or one per chunk of signers
+// for large sets, see [FROST.WithParallelism].
+func (f *FROST) groupCommitment(bindingFactors map[string]group.Scalar, commitments []*SigningCommitment) group.Point {
+	scalars := make([]group.Scalar, 0, 2*len(commitments))
+	points := make([]group.Point, 0, 2*len(commitments))
+	for _, comm := range commitments {
+		scalars = append(scalars, f.one, bindingFactors[string(comm.ID.Bytes())])
+		points = append(points, comm.HidingPoint, comm.BindingPoint)
+	}
+
+	k := f.workers(len(commitments), minAggregateChunk)
+	if k == 1 {
+		return f.group.MultiScalarMult(scalars, points)
+	}
+	partial := make([]group.Point, k)
+	chunks(len(commitments), k, func(j, lo, hi int) {
+		partial[j] = f.group.MultiScalarMult(scalars[2*lo:2*hi], points[2*lo:2*hi])
+	})
+	R := partial[0]
+	for _, P := range partial[1:] {
+		R.Add(R, P)
+	}
+	return R
+}
+
 // lagrangeCoefficient computes the Lagrange interpolation coefficient for
 // the given participant ID within the set of signing participants.
 // This is used to combine signature shares into a valid threshold signature.
-func (f *FROST) lagrangeCoefficient(id group.Scalar, commitments []*SigningCommitment) group.Scalar {
-	num := f.scalarFromInt(1)
-	den := f.scalarFromInt(1)
+//
+// The inversion uses the group's Invert, which is constant time for
+// groups declaring [group.Capabilities].ConstantTimeScalars. A zero
+// denominator means id appears twice in commitments and yields a
+// *DuplicateIDError.
+func (f *FROST) lagrangeCoefficient(id group.Scalar, commitments []*SigningCommitment) (group.Scalar, error) {
+	num, den := f.lagrangeFraction(id, commitments)
+	if den.IsZero() {
+		return nil, &DuplicateIDError{ID: id.Clone()}
+	}
+	denInv, err := f.group.NewScalar().Invert(den)
+	if err != nil {
+		return nil, err
+	}
+	return num.Mul(num, denInv), nil
+}
 
+// LagrangeCoefficients returns the Lagrange coefficient of every signer in
+// commitments, in the same order, inverting all denominators at once with
+// [group.BatchInvert]. A coordinator checking every signature share can
+// use it instead of recomputing one coefficient per share.
+func (f *FROST) LagrangeCoefficients(commitments []*SigningCommitment) ([]group.Scalar, error) {
+	if err := f.checkShape(commitments); err != nil {
+		return nil, err
+	}
+	nums := make([]group.Scalar, len(commitments))
+	dens := make([]group.Scalar, len(commitments))
+	for i, c := range commitments {
+		nums[i], dens[i] = f.lagrangeFraction(c.ID, commitments)
+	}
+	if err := group.BatchInvert(f.group, dens); err != nil {
+		return nil, err
+	}
+	for i := range nums {
+		nums[i].Mul(nums[i], dens[i])
+	}
+	return nums, nil
+}
+
+// lagrangeFraction returns the numerator prod(x_j) and denominator
+// prod(x_j - id) of the Lagrange coefficient of id, over the other
+// signers x_j in commitments. Only the first commitment from id is
+// skipped, so a repeated id makes the denominator zero.
+func (f *FROST) lagrangeFraction(id group.Scalar, commitments []*SigningCommitment) (num, den group.Scalar) {
+	num = f.One()
+	den = f.One()
+
+	diff := f.group.NewScalar()
+	skipped := false
 	for _, c := range commitments {
-		if c.ID.Equal(id) {
+		if !skipped && c.ID.Equal(id) {
+			skipped = true
 			continue
 		}
 		// num *= c.ID
-		num = f.group.NewScalar().Mul(num, c.ID)
+		num.Mul(num, c.ID)
 		// den *= (c.ID - id)
-		diff := f.group.NewScalar().Sub(c.ID, id)
-		den = f.group.NewScalar().Mul(den, diff)
+		den.Mul(den, diff.Sub(c.ID, id))
 	}
-
-	denInv, _ := f.group.NewScalar().Invert(den)
-	return f.group.NewScalar().Mul(num, denInv)
+	return num, den
 }
@@ -0,0 +1,337 @@
+package frost
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// InputError reports a malformed argument to a FROST method, such as a nil
+// share, an empty commitment list or a commitment without its points.
+// Values received from the network may be malformed, so the methods that
+// take them return an *InputError instead of panicking.
+type InputError struct {
+	// Field names the offending argument, for example
+	// "commitments[2].HidingPoint".
+	Field string
+
+	// Reason describes what is wrong with it.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *InputError) Error() string {
+	return fmt.Sprintf("frost: invalid %s: %s", e.Field, e.Reason)
+}
+
+// InsufficientSignersError reports a signing session with fewer distinct
+// signers than the threshold. Shares produced for such a session cannot
+// aggregate to a valid signature.
+type InsufficientSignersError struct {
+	// Signers is the number of signers in the commitment list.
+	Signers int
+
+	// Threshold is the minimum number of signers.
+	Threshold int
+}
+
+// Error implements the error interface.
+func (e *InsufficientSignersError) Error() string {
+	return fmt.Sprintf("frost: %d signers, need at least %d", e.Signers, e.Threshold)
+}
+
+// isNil reports whether v is nil or a nil pointer wrapped in an interface,
+// as left by a decoder for a missing field.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// field is a named argument checked by checkNotNil.
+type field struct {
+	name  string
+	value any
+}
+
+// checkNotNil returns an *InputError for the first of fields that is nil.
+func checkNotNil(fields ...field) error {
+	for _, f := range fields {
+		if isNil(f.value) {
+			return &InputError{Field: f.name, Reason: "is nil"}
+		}
+	}
+	return nil
+}
+
+// checkEntry is checkNotNil for entry i of the list argument named list,
+// with field names relative to the entry, such as ".ID". The full name is
+// only formatted for the error, so that checking long lists allocates
+// nothing.
+func checkEntry(list string, i int, fields ...field) error {
+	for _, f := range fields {
+		if isNil(f.value) {
+			return &InputError{Field: entryName(list, i) + f.name, Reason: "is nil"}
+		}
+	}
+	return nil
+}
+
+// entryName returns the name of entry i of list, such as "shares[2]".
+func entryName(list string, i int) string {
+	return fmt.Sprintf("%s[%d]", list, i)
+}
+
+// CheckCommitments returns an error if commitments is not a well-formed
+// commitment list for a signing session: it must hold at least threshold
+// and at most as many signers as participants, with distinct nonzero IDs,
+// and every commitment must be complete and belong to f's group. Too few
+// signers yield an *[InsufficientSignersError] and malformed lists an
+// *[InputError]. [FROST.SignRound2] and [FROST.Aggregate]
+// run this check themselves; call it to reject a list received from the
+// network before inspecting it.
+func (f *FROST) CheckCommitments(commitments []*SigningCommitment) error {
+	return f.checkCommitments(commitments)
+}
+
+// checkSigningInputs checks that share and nonce are complete and belong
+// to a signer in commitments.
+func (f *FROST) checkSigningInputs(share *KeyShare, nonce *SigningNonce, commitments []*SigningCommitment) error {
+	if err := f.checkSecretKey(share); err != nil {
+		return err
+	}
+	return f.checkPublicSigningInputs(share, nonce, commitments)
+}
+
+// checkSecretKey checks that share holds a valid secret key.
+func (f *FROST) checkSecretKey(share *KeyShare) error {
+	if err := checkNotNil(field{"share", share}); err != nil {
+		return err
+	}
+	if err := checkNotNil(field{"share.SecretKey", share.SecretKey}); err != nil {
+		return err
+	}
+	return f.checkScalars(share.SecretKey)
+}
+
+// checkPublicSigningInputs is checkSigningInputs for a share whose secret
+// key is held by a [SecretKeyOperator].
+func (f *FROST) checkPublicSigningInputs(share *KeyShare, nonce *SigningNonce, commitments []*SigningCommitment) error {
+	if err := f.checkSignerInputs(share, nonce); err != nil {
+		return err
+	}
+	if err := f.checkCommitments(commitments); err != nil {
+		return err
+	}
+	if findCommitment(commitments, share.ID) == nil {
+		return &InputError{Field: "commitments", Reason: "no commitment from this signer"}
+	}
+	return nil
+}
+
+// checkSignerInputs checks the public parts of a signer's key share and
+// its nonce.
+func (f *FROST) checkSignerInputs(share *KeyShare, nonce *SigningNonce) error {
+	if err := checkNotNil(field{"share", share}, field{"nonce", nonce}); err != nil {
+		return err
+	}
+	if err := checkNotNil(
+		field{"share.ID", share.ID},
+		field{"share.GroupKey", share.GroupKey},
+		field{"nonce.D", nonce.D},
+		field{"nonce.E", nonce.E},
+	); err != nil {
+		return err
+	}
+	if err := f.checkScalars(share.ID, nonce.D, nonce.E); err != nil {
+		return err
+	}
+	return f.checkPoints(share.GroupKey)
+}
+
+// checkMessage checks that the hasher can sign message, see
+// [MessageValidator].
+func (f *FROST) checkMessage(message []byte) error {
+	if err := ValidateMessage(f.hasher, f.group, message); err != nil {
+		return &InputError{Field: "message", Reason: err.Error()}
+	}
+	return nil
+}
+
+// checkShares checks that every signature share is complete and matches
+// exactly one of commitments, which must already have been checked.
+func (f *FROST) checkShares(shares []*SignatureShare, commitments []*SigningCommitment) error {
+	if len(shares) != len(commitments) {
+		return &InputError{
+			Field:  "shares",
+			Reason: fmt.Sprintf("has %d entries for %d commitments", len(shares), len(commitments)),
+		}
+	}
+	// Index the commitments once, so that matching is linear in the
+	// number of signers
+	index := make(map[string]int, len(commitments))
+	for j, c := range commitments {
+		index[string(c.ID.Bytes())] = j
+	}
+	matched := make([]bool, len(commitments))
+	for i, s := range shares {
+		if err := checkEntry("shares", i, field{"", s}); err != nil {
+			return err
+		}
+		if err := checkEntry("shares", i, field{".ID", s.ID}, field{".Z", s.Z}); err != nil {
+			return err
+		}
+		if err := f.checkScalars(s.ID, s.Z); err != nil {
+			return err
+		}
+		j, ok := index[string(s.ID.Bytes())]
+		if !ok {
+			return &InputError{Field: entryName("shares", i), Reason: "no commitment from this signer"}
+		}
+		if matched[j] {
+			return &InputError{Field: entryName("shares", i), Reason: "duplicate participant ID"}
+		}
+		matched[j] = true
+	}
+	return nil
+}
+
+// checkShape checks that a commitment list holds between threshold and
+// total signers with distinct nonzero IDs, and has no nil fields.
+func (f *FROST) checkShape(commitments []*SigningCommitment) error {
+	if len(commitments) == 0 {
+		return &InputError{Field: "commitments", Reason: "is empty"}
+	}
+	if len(commitments) < f.threshold {
+		return &InsufficientSignersError{Signers: len(commitments), Threshold: f.threshold}
+	}
+	if len(commitments) > f.total {
+		return &InputError{
+			Field:  "commitments",
+			Reason: fmt.Sprintf("has %d entries, more than the %d participants", len(commitments), f.total),
+		}
+	}
+	seen := make(map[string]bool, len(commitments))
+	for i, c := range commitments {
+		if err := checkEntry("commitments", i, field{"", c}); err != nil {
+			return err
+		}
+		if err := checkEntry("commitments", i,
+			field{".ID", c.ID},
+			field{".HidingPoint", c.HidingPoint},
+			field{".BindingPoint", c.BindingPoint},
+		); err != nil {
+			return err
+		}
+		if err := f.checkScalars(c.ID); err != nil {
+			return err
+		}
+		if c.ID.IsZero() {
+			return &InputError{Field: entryName("commitments", i) + ".ID", Reason: "is zero"}
+		}
+		id := string(c.ID.Bytes())
+		if seen[id] {
+			return &InputError{Field: entryName("commitments", i), Reason: "duplicate participant ID"}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// checkDealerInputs checks the arguments of [FROST.Round2ReceiveShare].
+func (f *FROST) checkDealerInputs(p *Participant, data *Round1PrivateData, senderCommitments []group.Point) error {
+	if err := checkNotNil(field{"participant", p}, field{"data", data}); err != nil {
+		return err
+	}
+	if err := checkNotNil(
+		field{"data.FromID", data.FromID},
+		field{"data.ToID", data.ToID},
+		field{"data.Share", data.Share},
+	); err != nil {
+		return err
+	}
+	if err := f.checkScalars(data.FromID, data.ToID, data.Share); err != nil {
+		return err
+	}
+	if data.FromID.IsZero() {
+		return &InputError{Field: "data.FromID", Reason: "is zero"}
+	}
+	if !data.ToID.Equal(p.id) {
+		return &InputError{Field: "data.ToID", Reason: "share is addressed to another participant"}
+	}
+	return f.checkPolynomial("senderCommitments", senderCommitments)
+}
+
+// checkBroadcasts checks the round 1 broadcasts passed to [FROST.Finalize].
+func (f *FROST) checkBroadcasts(allBroadcasts []*Round1Data) error {
+	if len(allBroadcasts) == 0 {
+		return &InputError{Field: "allBroadcasts", Reason: "is empty"}
+	}
+	for i, b := range allBroadcasts {
+		if err := f.checkBroadcast(entryName("allBroadcasts", i), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBroadcast checks a single round 1 broadcast, the argument called
+// name.
+func (f *FROST) checkBroadcast(name string, b *Round1Data) error {
+	if err := checkNotNil(field{name, b}); err != nil {
+		return err
+	}
+	if err := checkNotNil(field{name + ".ID", b.ID}); err != nil {
+		return err
+	}
+	if err := f.checkScalars(b.ID); err != nil {
+		return err
+	}
+	if b.ID.IsZero() {
+		return &InputError{Field: name + ".ID", Reason: "is zero"}
+	}
+	return f.checkPolynomial(name+".Commitments", b.Commitments)
+}
+
+// checkPolynomial checks that commitments commit to a polynomial of
+// degree threshold-1 with valid points.
+func (f *FROST) checkPolynomial(name string, commitments []group.Point) error {
+	if len(commitments) != f.threshold {
+		return &InputError{
+			Field:  name,
+			Reason: fmt.Sprintf("has %d entries, want %d", len(commitments), f.threshold),
+		}
+	}
+	for i, c := range commitments {
+		if err := checkNotNil(field{fmt.Sprintf("%s[%d]", name, i), c}); err != nil {
+			return err
+		}
+	}
+	return f.checkPoints(commitments...)
+}
+
+// idString formats a participant ID for error messages, as a decimal
+// integer if the scalar converts to one.
+func idString(id group.Scalar) string {
+	if b, ok := id.(group.BigIntScalar); ok {
+		return b.BigInt().String()
+	}
+	return fmt.Sprintf("%x", id.Bytes())
+}
+
+// findCommitment returns the commitment of signer id, or nil.
+func findCommitment(commitments []*SigningCommitment, id group.Scalar) *SigningCommitment {
+	for _, c := range commitments {
+		if c.ID.Equal(id) {
+			return c
+		}
+	}
+	return nil
+}
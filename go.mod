@@ -5,9 +5,7 @@ go 1.25.4
 require (
 	github.com/consensys/gnark-crypto v0.19.2
 	golang.org/x/crypto v0.46.0
+	golang.org/x/sys v0.39.0
 )
 
-require (
-	github.com/bits-and-blooms/bitset v1.20.0 // indirect
-	golang.org/x/sys v0.39.0 // indirect
-)
+require github.com/bits-and-blooms/bitset v1.20.0 // indirect
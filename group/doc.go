@@ -28,6 +28,20 @@
 //  1. Create a Scalar type that wraps your field element and implements [Scalar]
 //  2. Create a Point type that wraps your curve point and implements [Point]
 //  3. Create a Group type that implements [Group] as a factory
+//  4. Call [Register] from an init function so that [New] can construct
+//     the group from its name
+//
+// Group.ScalarBaseMult and Group.MultiScalarMult may simply delegate to
+// the generic [ScalarBaseMult] and [MultiScalarMult] helpers. Curves with
+// faster algorithms, such as precomputed generator tables or Pippenger's
+// method, should use them: FROST spends most of its time in base-point
+// multiplications and in multi-scalar multiplications for group
+// commitments and share verification.
+//
+// Methods receiving a Scalar or Point of another implementation must not
+// fail with a bare type assertion. Group.CheckScalar and Group.CheckPoint
+// return a *[MismatchError] for such values, and arithmetic methods, which
+// have no error result, panic with one.
 //
 // See the bjj package for a complete implementation using Baby Jubjub.
 //
@@ -37,6 +51,10 @@
 //
 //   - Scalar arithmetic is performed modulo the group order
 //   - Point operations are constant-time where possible
+//   - Scalar.Equal and Scalar.IsZero are constant-time, so that secret
+//     values can be compared without leaking them through timing
 //   - Random scalars are generated from cryptographically secure sources
 //   - Invalid curve points are rejected in SetBytes
+//   - HashToPoint never computes a hash times the generator, which would
+//     reveal the discrete logarithm of the result
 package group
@@ -0,0 +1,22 @@
+package group
+
+import "fmt"
+
+// MismatchError reports that a scalar or point created by one [Group]
+// implementation was used with another, for example a key share decoded
+// for one curve passed to a FROST instance configured for a different one.
+//
+// [Group.CheckScalar] and [Group.CheckPoint] return a *MismatchError for
+// foreign values. Arithmetic methods, which cannot return errors, panic
+// with a *MismatchError instead.
+type MismatchError struct {
+	// Group is the name of the group that rejected the value.
+	Group string
+
+	// Value is the foreign scalar or point.
+	Value any
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("group: %T is not an element of %s", e.Value, e.Group)
+}
@@ -1,7 +1,10 @@
 package group
 
 import (
+	"encoding"
+	"fmt"
 	"io"
+	"math/big"
 )
 
 // Scalar represents an element of the scalar field associated with a
@@ -14,7 +17,18 @@ import (
 //
 // Implementations must ensure all operations produce results in the
 // valid range [0, order).
+//
+// Scalars implement the standard encoding interfaces so that they can be
+// embedded in structs handled by encoding/gob, encoding/json and similar
+// packages. The binary form is the canonical encoding returned by Bytes
+// and the text form is its hex encoding. Decoding is strict and rejects
+// values that are not fully reduced.
 type Scalar interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+
 	// Add sets the receiver to a+b and returns it.
 	Add(a, b Scalar) Scalar
 	// Sub sets the receiver to a-b and returns it.
@@ -28,15 +42,59 @@ type Scalar interface {
 	Invert(a Scalar) (Scalar, error)
 	// Set sets the receiver to a and returns it.
 	Set(a Scalar) Scalar
+	// Clone returns a new scalar with the same value as the receiver.
+	// Use it rather than sharing a scalar that may later be used as a
+	// receiver, since that would modify every holder of the value.
+	Clone() Scalar
+	// Select sets the receiver to a if cond is 1 and to b if cond is 0,
+	// and returns it. cond must be 0 or 1. Implementations should run in
+	// time independent of cond so that callers can choose between secret
+	// values without branching.
+	Select(a, b Scalar, cond int) Scalar
+	// SetUint64 sets the receiver to v (reduced modulo the group order)
+	// and returns it.
+	SetUint64(v uint64) Scalar
 	// Bytes returns the canonical byte representation of the scalar.
 	Bytes() []byte
 	// SetBytes sets the receiver from a byte slice and returns it.
 	// Returns an error if the data is invalid or out of range.
+	// Implementations may reduce out-of-range values instead, so that
+	// distinct inputs map to the same scalar; use SetCanonicalBytes to
+	// parse untrusted encodings.
 	SetBytes(data []byte) (Scalar, error)
-	// Equal reports whether the receiver equals b.
+	// SetCanonicalBytes sets the receiver from the canonical encoding
+	// returned by Bytes and returns it. It is strict: data must be
+	// exactly ScalarSize bytes and encode a value below the group order,
+	// so that every scalar has exactly one accepted encoding.
+	SetCanonicalBytes(data []byte) (Scalar, error)
+	// SetUniformBytes sets the receiver to a uniformly distributed scalar
+	// derived from data, which must be 48 to 64 uniformly random bytes
+	// such as a hash output, and returns it. Reducing that many bytes
+	// modulo the group order leaves a negligible bias, unlike SetBytes on
+	// a 32-byte input. Returns an error if data has the wrong length.
+	SetUniformBytes(data []byte) (Scalar, error)
+	// Equal reports whether the receiver equals b. It must run in
+	// constant time, since scalars are often secret.
 	Equal(b Scalar) bool
-	// IsZero reports whether the receiver is zero.
+	// IsZero reports whether the receiver is zero, in constant time.
 	IsZero() bool
+	// Zeroize sets the receiver to zero and overwrites the memory that
+	// held its value. Wiping is best effort: copies made earlier by the
+	// runtime or by the caller are not reached.
+	Zeroize()
+}
+
+// BigIntScalar is implemented by scalars that can be converted to and from
+// [big.Int] values. Generic code can use it through a type assertion when
+// it needs the integer value of a scalar, such as a participant ID.
+type BigIntScalar interface {
+	Scalar
+	// BigInt returns the value of the scalar as a new big.Int in
+	// [0, order).
+	BigInt() *big.Int
+	// SetBigInt sets the receiver to v reduced modulo the group order and
+	// returns it.
+	SetBigInt(v *big.Int) Scalar
 }
 
 // Point represents an element of a cryptographic group, typically a point
@@ -48,7 +106,15 @@ type Scalar interface {
 //
 // The identity element (zero point, point at infinity) is the additive
 // identity: P + Identity = P for all points P.
+//
+// Like [Scalar], points implement the standard binary and text encoding
+// interfaces using the encoding returned by Bytes and its hex encoding.
 type Point interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+
 	// Add sets the receiver to a+b and returns it.
 	Add(a, b Point) Point
 	// Sub sets the receiver to a-b and returns it.
@@ -59,6 +125,12 @@ type Point interface {
 	ScalarMult(s Scalar, p Point) Point
 	// Set sets the receiver to a and returns it.
 	Set(a Point) Point
+	// Clone returns a new point equal to the receiver.
+	Clone() Point
+	// Select sets the receiver to a if cond is 1 and to b if cond is 0,
+	// and returns it. cond must be 0 or 1. Implementations should run in
+	// time independent of cond.
+	Select(a, b Point, cond int) Point
 	// Bytes returns the canonical byte representation of the point.
 	Bytes() []byte
 	// SetBytes sets the receiver from a byte slice and returns it.
@@ -68,6 +140,26 @@ type Point interface {
 	Equal(b Point) bool
 	// IsIdentity reports whether the receiver is the identity element.
 	IsIdentity() bool
+	// Zeroize sets the receiver to the identity element, overwriting its
+	// previous coordinates.
+	Zeroize()
+}
+
+// Validator is implemented by points of curves whose group of points is
+// larger than the prime-order subgroup used for signatures, such as
+// twisted Edwards curves with a cofactor. Generic code receiving points
+// from outside should reject those for which IsInPrimeSubgroup is false,
+// or map them into the subgroup with ClearCofactor where the protocol
+// allows it.
+type Validator interface {
+	// IsOnCurve reports whether the receiver satisfies the curve equation.
+	IsOnCurve() bool
+	// IsInPrimeSubgroup reports whether the receiver is on the curve and
+	// in the prime-order subgroup.
+	IsInPrimeSubgroup() bool
+	// ClearCofactor sets the receiver to a multiplied by the cofactor and
+	// returns it. The result is always in the prime-order subgroup.
+	ClearCofactor(a Point) Point
 }
 
 // Group defines a cryptographic group suitable for use with FROST threshold
@@ -77,13 +169,30 @@ type Point interface {
 //
 // A Group implementation encapsulates all curve-specific details, allowing
 // the FROST implementation to be generic over different elliptic curves.
+// Its methods must be safe for concurrent use; the values they return need
+// not be.
 //
 // Example usage:
 //
 //	g := &bjj.BJJ{}  // or any other Group implementation
 //	scalar, _ := g.RandomScalar(rand.Reader)
-//	point := g.NewPoint().ScalarMult(scalar, g.Generator())
+//	point := g.ScalarBaseMult(scalar)
 type Group interface {
+	// Name returns a short identifier for the group, such as
+	// "baby-jubjub", suitable for tagging serialized data. Implementations
+	// register themselves under this name with [Register].
+	Name() string
+	// ScalarSize returns the length in bytes of a serialized [Scalar].
+	ScalarSize() int
+	// ElementSize returns the length in bytes of a serialized [Point].
+	ElementSize() int
+	// CheckScalar returns a *[MismatchError] if s was not created by this
+	// group. Call it on values from outside the program, such as
+	// configuration or keystores, before using them in arithmetic, which
+	// panics on foreign values.
+	CheckScalar(s Scalar) error
+	// CheckPoint is like CheckScalar for points.
+	CheckPoint(p Point) error
 	// NewScalar returns a new zero scalar.
 	NewScalar() Scalar
 	// NewPoint returns a new identity point.
@@ -94,6 +203,165 @@ type Group interface {
 	RandomScalar(r io.Reader) (Scalar, error)
 	// HashToScalar hashes the input data to a scalar.
 	HashToScalar(data ...[]byte) (Scalar, error)
+	// HashToPoint hashes the input data to a group element whose
+	// discrete logarithm is unknown, following RFC 9380 (hash_to_curve)
+	// with domain separation tag dst. Use it to derive independent
+	// generators rather than multiplying the generator by a hash.
+	HashToPoint(dst string, data ...[]byte) (Point, error)
 	// Order returns the group order as a byte slice.
 	Order() []byte
+	// ScalarBaseMult returns s times the generator. Implementations can
+	// use precomputed tables of generator multiples to make it much faster
+	// than ScalarMult. Those without can use [ScalarBaseMult].
+	ScalarBaseMult(s Scalar) Point
+	// MultiScalarMult returns the sum of scalars[i]*points[i]. It panics
+	// if the slices differ in length. Implementations may run in variable
+	// time, so it must only be used with public scalars. Implementations
+	// without a specialized algorithm can use [MultiScalarMult].
+	MultiScalarMult(scalars []Scalar, points []Point) Point
+}
+
+// PointDecoder is implemented by groups that can decode and validate many
+// points faster than with one Point.SetBytes call per point.
+type PointDecoder interface {
+	// DecodePoints decodes and validates every encoding in data with the
+	// same checks as Point.SetBytes. The error identifies the first
+	// invalid encoding.
+	DecodePoints(data [][]byte) ([]Point, error)
+}
+
+// PointEncoder is implemented by groups that can encode many points
+// faster than with one Point.Bytes call per point.
+type PointEncoder interface {
+	// EncodePoints returns Point.Bytes of every point.
+	EncodePoints(points []Point) [][]byte
+}
+
+// EncodePoints returns the encoding of every point, using g's batch
+// encoder if it implements [PointEncoder] and Point.Bytes otherwise.
+func EncodePoints(g Group, points []Point) [][]byte {
+	if e, ok := g.(PointEncoder); ok {
+		return e.EncodePoints(points)
+	}
+	out := make([][]byte, len(points))
+	for i, p := range points {
+		out[i] = p.Bytes()
+	}
+	return out
+}
+
+// DecodePoints decodes and validates every point encoding in data, using
+// g's batch decoder if it implements [PointDecoder] and Point.SetBytes
+// otherwise. The error identifies the first invalid encoding.
+func DecodePoints(g Group, data [][]byte) ([]Point, error) {
+	if d, ok := g.(PointDecoder); ok {
+		return d.DecodePoints(data)
+	}
+	points := make([]Point, len(data))
+	for i, b := range data {
+		p, err := g.NewPoint().SetBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
+// Capabilities describes the side-channel guarantees of a group
+// implementation, so that code handling secrets can check them. The zero
+// value promises nothing.
+type Capabilities struct {
+	// ConstantTimeScalars reports that the arithmetic, encoding,
+	// comparison and zeroization methods of Scalar, as well as
+	// RandomScalar and HashToScalar, run in time independent of the
+	// values involved. Conversions to and from big.Int are not covered.
+	ConstantTimeScalars bool
+
+	// ConstantTimeScalarMult reports that ScalarBaseMult and
+	// Point.ScalarMult run in time independent of the scalar.
+	// MultiScalarMult is meant for public inputs and is not covered.
+	ConstantTimeScalarMult bool
+}
+
+// CapabilityReporter is implemented by groups that declare their
+// side-channel guarantees.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the capabilities g declares, or the zero value
+// if it does not implement [CapabilityReporter].
+func CapabilitiesOf(g Group) Capabilities {
+	if r, ok := g.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// ScalarBaseMult computes s times the generator of g with ScalarMult. It
+// is a fallback for [Group] implementations without a faster base-point
+// multiplication.
+func ScalarBaseMult(g Group, s Scalar) Point {
+	return g.NewPoint().ScalarMult(s, g.Generator())
+}
+
+// MultiScalarMult computes the sum of scalars[i]*points[i] in g with one
+// ScalarMult and Add per term. It is a fallback for [Group]
+// implementations that have no faster multi-scalar multiplication.
+// It panics if the slices differ in length.
+func MultiScalarMult(g Group, scalars []Scalar, points []Point) Point {
+	if len(scalars) != len(points) {
+		panic("group: MultiScalarMult called with mismatched lengths")
+	}
+	return Accumulate(g.NewPoint(), g.NewPoint(), scalars, points)
+}
+
+// Accumulate adds the sum of scalars[i]*points[i] to acc in place and
+// returns acc. Each product is computed in scratch, which is overwritten,
+// so that summing over a large signer set allocates no intermediate
+// points; scratch must not alias acc or any of the points. It panics if
+// the slices differ in length.
+func Accumulate(acc, scratch Point, scalars []Scalar, points []Point) Point {
+	if len(scalars) != len(points) {
+		panic("group: Accumulate called with mismatched lengths")
+	}
+	for i := range scalars {
+		acc.Add(acc, scratch.ScalarMult(scalars[i], points[i]))
+	}
+	return acc
+}
+
+// BatchInvert replaces every scalar in scalars with its inverse using
+// Montgomery's trick: a single Invert call and three multiplications per
+// element. It runs in constant time if the group's scalar arithmetic
+// does. The scalars are modified in place and must not alias each other.
+// If any scalar is zero it returns an error naming the first zero scalar
+// and leaves scalars unchanged.
+func BatchInvert(g Group, scalars []Scalar) error {
+	if len(scalars) == 0 {
+		return nil
+	}
+	// prefix[i] holds the product of scalars[0..i-1]
+	prefix := make([]Scalar, len(scalars))
+	acc := g.NewScalar().SetUint64(1)
+	for i, s := range scalars {
+		if s.IsZero() {
+			return fmt.Errorf("group: cannot invert zero scalar %d", i)
+		}
+		prefix[i] = acc.Clone()
+		acc.Mul(acc, s)
+	}
+	inv, err := g.NewScalar().Invert(acc)
+	if err != nil {
+		return err
+	}
+	// inv holds the inverse of scalars[0..i]; peel off one factor at a time
+	tmp := g.NewScalar()
+	for i := len(scalars) - 1; i >= 0; i-- {
+		tmp.Mul(inv, prefix[i])
+		inv.Mul(inv, scalars[i])
+		scalars[i].Set(tmp)
+	}
+	return nil
 }
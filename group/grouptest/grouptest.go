@@ -0,0 +1,506 @@
+// Package grouptest provides a conformance test suite for implementations
+// of the [group.Group] interface.
+//
+// Authors of a new curve backend can check it against the full algebraic
+// and encoding contract with a single test:
+//
+//	func TestConformance(t *testing.T) {
+//		grouptest.RunGroupTests(t, &mycurve.Group{})
+//	}
+package grouptest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// RunGroupTests runs the conformance suite against g as subtests of t.
+func RunGroupTests(t *testing.T, g group.Group) {
+	t.Run("ScalarField", func(t *testing.T) { testScalarField(t, g) })
+	t.Run("ScalarEncoding", func(t *testing.T) { testScalarEncoding(t, g) })
+	t.Run("ScalarHelpers", func(t *testing.T) { testScalarHelpers(t, g) })
+	t.Run("PointGroup", func(t *testing.T) { testPointGroup(t, g) })
+	t.Run("ScalarMult", func(t *testing.T) { testScalarMult(t, g) })
+	t.Run("PointEncoding", func(t *testing.T) { testPointEncoding(t, g) })
+	t.Run("PointHelpers", func(t *testing.T) { testPointHelpers(t, g) })
+	t.Run("Order", func(t *testing.T) { testOrder(t, g) })
+	t.Run("Hashing", func(t *testing.T) { testHashing(t, g) })
+	t.Run("Metadata", func(t *testing.T) { testMetadata(t, g) })
+}
+
+// randomScalar returns a random non-zero scalar of g.
+func randomScalar(t *testing.T, g group.Group) group.Scalar {
+	t.Helper()
+	for {
+		s, err := g.RandomScalar(rand.Reader)
+		if err != nil {
+			t.Fatalf("RandomScalar: %v", err)
+		}
+		if !s.IsZero() {
+			return s
+		}
+	}
+}
+
+// randomPoint returns a random point of g.
+func randomPoint(t *testing.T, g group.Group) group.Point {
+	t.Helper()
+	return g.ScalarBaseMult(randomScalar(t, g))
+}
+
+func testScalarField(t *testing.T, g group.Group) {
+	a, b, c := randomScalar(t, g), randomScalar(t, g), randomScalar(t, g)
+	zero, one := g.NewScalar(), g.NewScalar().SetUint64(1)
+
+	if !g.NewScalar().Add(a, b).Equal(g.NewScalar().Add(b, a)) {
+		t.Error("addition is not commutative")
+	}
+	ab := g.NewScalar().Add(a, b)
+	bc := g.NewScalar().Add(b, c)
+	if !g.NewScalar().Add(ab, c).Equal(g.NewScalar().Add(a, bc)) {
+		t.Error("addition is not associative")
+	}
+	if !g.NewScalar().Mul(a, b).Equal(g.NewScalar().Mul(b, a)) {
+		t.Error("multiplication is not commutative")
+	}
+	ab = g.NewScalar().Mul(a, b)
+	bc = g.NewScalar().Mul(b, c)
+	if !g.NewScalar().Mul(ab, c).Equal(g.NewScalar().Mul(a, bc)) {
+		t.Error("multiplication is not associative")
+	}
+	lhs := g.NewScalar().Mul(a, g.NewScalar().Add(b, c))
+	rhs := g.NewScalar().Add(g.NewScalar().Mul(a, b), g.NewScalar().Mul(a, c))
+	if !lhs.Equal(rhs) {
+		t.Error("multiplication does not distribute over addition")
+	}
+
+	if !g.NewScalar().Add(a, zero).Equal(a) {
+		t.Error("a + 0 != a")
+	}
+	if !g.NewScalar().Mul(a, one).Equal(a) {
+		t.Error("a * 1 != a")
+	}
+	if !g.NewScalar().Add(a, g.NewScalar().Negate(a)).IsZero() {
+		t.Error("a + (-a) != 0")
+	}
+	if !g.NewScalar().Sub(a, b).Equal(g.NewScalar().Add(a, g.NewScalar().Negate(b))) {
+		t.Error("a - b != a + (-b)")
+	}
+	inv, err := g.NewScalar().Invert(a)
+	if err != nil {
+		t.Fatalf("Invert: %v", err)
+	}
+	if !g.NewScalar().Mul(a, inv).Equal(one) {
+		t.Error("a * a^-1 != 1")
+	}
+	if _, err := g.NewScalar().Invert(zero); err == nil {
+		t.Error("Invert(0) should return an error")
+	}
+
+	// The receiver may alias the operands
+	x := a.Clone()
+	x.Add(x, x)
+	if !x.Equal(g.NewScalar().Add(a, a)) {
+		t.Error("Add with an aliased receiver gave a different result")
+	}
+	x = a.Clone()
+	x.Mul(x, b)
+	if !x.Equal(g.NewScalar().Mul(a, b)) {
+		t.Error("Mul with an aliased receiver gave a different result")
+	}
+}
+
+func testScalarEncoding(t *testing.T, g group.Group) {
+	a := randomScalar(t, g)
+
+	enc := a.Bytes()
+	if len(enc) != g.ScalarSize() {
+		t.Errorf("len(Bytes()) = %d, ScalarSize() = %d", len(enc), g.ScalarSize())
+	}
+	if len(g.NewScalar().Bytes()) != g.ScalarSize() {
+		t.Error("the zero scalar does not encode to ScalarSize bytes")
+	}
+	dec, err := g.NewScalar().SetBytes(enc)
+	if err != nil {
+		t.Fatalf("SetBytes: %v", err)
+	}
+	if !dec.Equal(a) {
+		t.Error("Bytes/SetBytes round trip changed the scalar")
+	}
+
+	bin, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	dec = g.NewScalar()
+	if err := dec.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if !dec.Equal(a) {
+		t.Error("binary marshaling round trip changed the scalar")
+	}
+	if err := g.NewScalar().UnmarshalBinary(bin[:len(bin)-1]); err == nil {
+		t.Error("UnmarshalBinary accepted a truncated encoding")
+	}
+
+	dec, err = g.NewScalar().SetCanonicalBytes(enc)
+	if err != nil || !dec.Equal(a) {
+		t.Errorf("SetCanonicalBytes rejected or changed a canonical encoding: %v", err)
+	}
+	for name, data := range map[string][]byte{
+		"truncated": enc[:len(enc)-1],
+		"extended":  append([]byte{0}, enc...),
+		"all ones":  bytes.Repeat([]byte{0xff}, g.ScalarSize()),
+	} {
+		if _, err := g.NewScalar().SetCanonicalBytes(data); err == nil {
+			t.Errorf("SetCanonicalBytes accepted a non-canonical encoding (%s)", name)
+		}
+	}
+
+	text, err := a.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	dec = g.NewScalar()
+	if err := dec.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if !dec.Equal(a) {
+		t.Error("text marshaling round trip changed the scalar")
+	}
+
+	uniform := make([]byte, 64)
+	rand.Read(uniform)
+	if _, err := g.NewScalar().SetUniformBytes(uniform); err != nil {
+		t.Errorf("SetUniformBytes(64 bytes): %v", err)
+	}
+	if _, err := g.NewScalar().SetUniformBytes(uniform[:16]); err == nil {
+		t.Error("SetUniformBytes accepted 16 bytes")
+	}
+}
+
+func testScalarHelpers(t *testing.T, g group.Group) {
+	a, b := randomScalar(t, g), randomScalar(t, g)
+
+	c := a.Clone()
+	if !c.Equal(a) {
+		t.Fatal("Clone() != original")
+	}
+	c.Add(c, b)
+	if c.Equal(a) {
+		t.Error("modifying a clone changed the original")
+	}
+	if !g.NewScalar().Set(a).Equal(a) {
+		t.Error("Set(a) != a")
+	}
+
+	if !g.NewScalar().Select(a, b, 1).Equal(a) || !g.NewScalar().Select(a, b, 0).Equal(b) {
+		t.Error("Select picked the wrong operand")
+	}
+
+	three := g.NewScalar().SetUint64(3)
+	one := g.NewScalar().SetUint64(1)
+	sum := g.NewScalar().Add(one, g.NewScalar().Add(one, one))
+	if !three.Equal(sum) {
+		t.Error("SetUint64(3) != 1 + 1 + 1")
+	}
+
+	z := a.Clone()
+	z.Zeroize()
+	if !z.IsZero() {
+		t.Error("Zeroize did not set the scalar to zero")
+	}
+
+	batch := []group.Scalar{a.Clone(), b.Clone(), three.Clone()}
+	if err := group.BatchInvert(g, batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range []group.Scalar{a, b, three} {
+		if !g.NewScalar().Mul(s, batch[i]).Equal(one) {
+			t.Errorf("BatchInvert: scalar %d times its inverse != 1", i)
+		}
+	}
+	withZero := []group.Scalar{a.Clone(), g.NewScalar()}
+	if err := group.BatchInvert(g, withZero); err == nil {
+		t.Error("BatchInvert inverted zero")
+	}
+	if !withZero[0].Equal(a) {
+		t.Error("failed BatchInvert modified its input")
+	}
+}
+
+func testPointGroup(t *testing.T, g group.Group) {
+	p, q, r := randomPoint(t, g), randomPoint(t, g), randomPoint(t, g)
+	identity := g.NewPoint()
+
+	if !identity.IsIdentity() {
+		t.Error("NewPoint() is not the identity")
+	}
+	if g.Generator().IsIdentity() {
+		t.Error("the generator is the identity")
+	}
+	if !g.NewPoint().Add(p, identity).Equal(p) {
+		t.Error("P + O != P")
+	}
+	if !g.NewPoint().Add(p, q).Equal(g.NewPoint().Add(q, p)) {
+		t.Error("point addition is not commutative")
+	}
+	pq := g.NewPoint().Add(p, q)
+	qr := g.NewPoint().Add(q, r)
+	if !g.NewPoint().Add(pq, r).Equal(g.NewPoint().Add(p, qr)) {
+		t.Error("point addition is not associative")
+	}
+	if !g.NewPoint().Add(p, g.NewPoint().Negate(p)).IsIdentity() {
+		t.Error("P + (-P) != O")
+	}
+	if !g.NewPoint().Sub(p, q).Equal(g.NewPoint().Add(p, g.NewPoint().Negate(q))) {
+		t.Error("P - Q != P + (-Q)")
+	}
+	if !g.NewPoint().Add(p, p).Equal(g.NewPoint().ScalarMult(g.NewScalar().SetUint64(2), p)) {
+		t.Error("P + P != 2P")
+	}
+
+	x := p.Clone()
+	x.Add(x, x)
+	if !x.Equal(g.NewPoint().Add(p, p)) {
+		t.Error("Add with an aliased receiver gave a different result")
+	}
+}
+
+func testScalarMult(t *testing.T, g group.Group) {
+	a, b := randomScalar(t, g), randomScalar(t, g)
+	p := randomPoint(t, g)
+
+	if !g.NewPoint().ScalarMult(g.NewScalar(), p).IsIdentity() {
+		t.Error("0 * P != O")
+	}
+	if !g.NewPoint().ScalarMult(g.NewScalar().SetUint64(1), p).Equal(p) {
+		t.Error("1 * P != P")
+	}
+	lhs := g.NewPoint().ScalarMult(g.NewScalar().Add(a, b), p)
+	rhs := g.NewPoint().Add(g.NewPoint().ScalarMult(a, p), g.NewPoint().ScalarMult(b, p))
+	if !lhs.Equal(rhs) {
+		t.Error("(a + b)P != aP + bP")
+	}
+	lhs = g.NewPoint().ScalarMult(a, g.NewPoint().ScalarMult(b, p))
+	rhs = g.NewPoint().ScalarMult(g.NewScalar().Mul(a, b), p)
+	if !lhs.Equal(rhs) {
+		t.Error("a(bP) != (ab)P")
+	}
+
+	if !g.ScalarBaseMult(a).Equal(g.NewPoint().ScalarMult(a, g.Generator())) {
+		t.Error("ScalarBaseMult(a) != ScalarMult(a, G)")
+	}
+	if !g.ScalarBaseMult(g.NewScalar()).IsIdentity() {
+		t.Error("ScalarBaseMult(0) != O")
+	}
+
+	for _, n := range []int{0, 1, 2, 7, 33} {
+		scalars := make([]group.Scalar, n)
+		points := make([]group.Point, n)
+		for i := range n {
+			scalars[i] = randomScalar(t, g)
+			points[i] = randomPoint(t, g)
+		}
+		want := group.MultiScalarMult(g, scalars, points)
+		if !g.MultiScalarMult(scalars, points).Equal(want) {
+			t.Errorf("MultiScalarMult with %d terms differs from the generic result", n)
+		}
+	}
+
+	// Accumulate adds to a nonzero accumulator and overwrites only scratch
+	a, b = randomScalar(t, g), randomScalar(t, g)
+	p, q := randomPoint(t, g), randomPoint(t, g)
+	start := randomPoint(t, g)
+	want := g.NewPoint().Add(start, g.NewPoint().ScalarMult(a, p))
+	want.Add(want, g.NewPoint().ScalarMult(b, q))
+	acc := start.Clone()
+	got := group.Accumulate(acc, g.NewPoint(), []group.Scalar{a, b}, []group.Point{p, q})
+	if got != acc || !acc.Equal(want) {
+		t.Error("Accumulate(P0, [a, b], [P, Q]) != P0 + a*P + b*Q")
+	}
+}
+
+func testPointEncoding(t *testing.T, g group.Group) {
+	p := randomPoint(t, g)
+
+	for _, q := range []group.Point{p, g.NewPoint(), g.Generator()} {
+		enc := q.Bytes()
+		if len(enc) != g.ElementSize() {
+			t.Errorf("len(Bytes()) = %d, ElementSize() = %d", len(enc), g.ElementSize())
+		}
+		dec, err := g.NewPoint().SetBytes(enc)
+		if err != nil {
+			t.Fatalf("SetBytes: %v", err)
+		}
+		if !dec.Equal(q) {
+			t.Error("Bytes/SetBytes round trip changed the point")
+		}
+	}
+
+	enc := p.Bytes()
+	invalid := map[string][]byte{
+		"Empty":     {},
+		"Truncated": enc[:len(enc)-1],
+		"Extended":  append(bytes.Clone(enc), 0),
+		"AllOnes":   bytes.Repeat([]byte{0xff}, g.ElementSize()),
+	}
+	for name, data := range invalid {
+		if _, err := g.NewPoint().SetBytes(data); err == nil {
+			t.Errorf("SetBytes accepted an invalid encoding (%s)", name)
+		}
+	}
+
+	bin, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	dec := g.NewPoint()
+	if err := dec.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if !dec.Equal(p) {
+		t.Error("binary marshaling round trip changed the point")
+	}
+	text, err := p.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+	dec = g.NewPoint()
+	if err := dec.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if !dec.Equal(p) {
+		t.Error("text marshaling round trip changed the point")
+	}
+
+	points, err := group.DecodePoints(g, [][]byte{enc, g.Generator().Bytes()})
+	if err != nil {
+		t.Fatalf("DecodePoints: %v", err)
+	}
+	if !points[0].Equal(p) || !points[1].Equal(g.Generator()) {
+		t.Error("DecodePoints returned different points")
+	}
+	if _, err := group.DecodePoints(g, [][]byte{enc, enc[:1]}); err == nil {
+		t.Error("DecodePoints accepted an invalid encoding")
+	}
+
+	q := randomPoint(t, g)
+	batch := []group.Point{p, g.NewPoint(), g.NewPoint().Add(p, q)}
+	for i, e := range group.EncodePoints(g, batch) {
+		if !bytes.Equal(e, batch[i].Bytes()) {
+			t.Errorf("EncodePoints differs from Bytes for point %d", i)
+		}
+	}
+}
+
+func testPointHelpers(t *testing.T, g group.Group) {
+	p, q := randomPoint(t, g), randomPoint(t, g)
+
+	c := p.Clone()
+	if !c.Equal(p) {
+		t.Fatal("Clone() != original")
+	}
+	c.Add(c, q)
+	if c.Equal(p) {
+		t.Error("modifying a clone changed the original")
+	}
+	if !g.NewPoint().Set(p).Equal(p) {
+		t.Error("Set(P) != P")
+	}
+	if !g.NewPoint().Select(p, q, 1).Equal(p) || !g.NewPoint().Select(p, q, 0).Equal(q) {
+		t.Error("Select picked the wrong operand")
+	}
+
+	z := p.Clone()
+	z.Zeroize()
+	if !z.IsIdentity() {
+		t.Error("Zeroize did not set the point to the identity")
+	}
+
+	if v, ok := p.(group.Validator); ok {
+		if !v.IsOnCurve() || !v.IsInPrimeSubgroup() {
+			t.Error("a multiple of the generator failed validation")
+		}
+	}
+}
+
+func testOrder(t *testing.T, g group.Group) {
+	// The order reduces to zero, so order - 1 acts as -1
+	order, err := g.NewScalar().SetBytes(g.Order())
+	if err != nil {
+		t.Fatalf("SetBytes(Order()): %v", err)
+	}
+	if !order.IsZero() {
+		t.Error("the group order does not reduce to zero")
+	}
+	minusOne := g.NewScalar().Sub(order, g.NewScalar().SetUint64(1))
+	if !g.ScalarBaseMult(minusOne).Equal(g.NewPoint().Negate(g.Generator())) {
+		t.Error("(order - 1) * G != -G")
+	}
+}
+
+func testHashing(t *testing.T, g group.Group) {
+	s1, err := g.HashToScalar([]byte("grouptest"))
+	if err != nil {
+		t.Fatalf("HashToScalar: %v", err)
+	}
+	s2, _ := g.HashToScalar([]byte("grouptest"))
+	s3, _ := g.HashToScalar([]byte("grouptest2"))
+	if !s1.Equal(s2) || s1.Equal(s3) {
+		t.Error("HashToScalar is not a deterministic function of its input")
+	}
+
+	p1, err := g.HashToPoint("grouptest-v1", []byte("input"))
+	if err != nil {
+		t.Fatalf("HashToPoint: %v", err)
+	}
+	p2, _ := g.HashToPoint("grouptest-v1", []byte("input"))
+	p3, _ := g.HashToPoint("grouptest-v2", []byte("input"))
+	if !p1.Equal(p2) || p1.Equal(p3) {
+		t.Error("HashToPoint is not a deterministic function of its tag and input")
+	}
+	if p1.IsIdentity() {
+		t.Error("HashToPoint returned the identity")
+	}
+	if v, ok := p1.(group.Validator); ok && !v.IsInPrimeSubgroup() {
+		t.Error("HashToPoint returned a point outside the prime-order subgroup")
+	}
+	if _, err := g.HashToPoint("", []byte("input")); err == nil {
+		t.Error("HashToPoint accepted an empty domain separation tag")
+	}
+}
+
+func testMetadata(t *testing.T, g group.Group) {
+	if g.Name() == "" {
+		t.Error("Name() is empty")
+	}
+	registered, err := group.New(g.Name())
+	if err != nil {
+		t.Errorf("group %q is not registered: %v", g.Name(), err)
+	} else if registered.Name() != g.Name() {
+		t.Errorf("group.New(%q).Name() = %q", g.Name(), registered.Name())
+	}
+
+	if err := g.CheckScalar(g.NewScalar()); err != nil {
+		t.Errorf("CheckScalar rejected the group's own scalar: %v", err)
+	}
+	if err := g.CheckPoint(g.NewPoint()); err != nil {
+		t.Errorf("CheckPoint rejected the group's own point: %v", err)
+	}
+	var mismatch *group.MismatchError
+	if err := g.CheckScalar(foreignScalar{}); !errors.As(err, &mismatch) {
+		t.Errorf("CheckScalar(foreign) = %v, want *group.MismatchError", err)
+	}
+	if err := g.CheckPoint(foreignPoint{}); !errors.As(err, &mismatch) {
+		t.Errorf("CheckPoint(foreign) = %v, want *group.MismatchError", err)
+	}
+}
+
+// foreignScalar and foreignPoint stand in for elements of another group.
+type foreignScalar struct{ group.Scalar }
+type foreignPoint struct{ group.Point }
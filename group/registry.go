@@ -0,0 +1,54 @@
+package group
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() Group)
+)
+
+// Register makes a Group implementation available to [New] under name,
+// which should match the value returned by the group's Name method.
+// Implementations normally call Register from an init function, so that
+// importing their package is enough to make them available.
+//
+// Register panics if factory is nil or if name is already registered.
+func Register(name string, factory func() Group) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("group: Register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("group: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// New returns a new instance of the Group registered under name, such as
+// a ciphersuite identifier read from serialized data. The package
+// implementing the group must have been imported.
+func New(name string) (Group, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("group: unknown group %q (forgotten import?)", name)
+	}
+	return factory(), nil
+}
+
+// Names returns the names of the registered groups in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(registry))
+}
@@ -0,0 +1,564 @@
+// Package jsbind is the JavaScript-facing API of fy, which cmd/fy-wasm
+// compiles to WebAssembly. It exposes DKG and signing through a single
+// JSON call interface, [API.Call], so that the WebAssembly binding is a
+// thin shim, and browser participants exchange the same message files as
+// the fy command (see package keyfile) and can join its ceremonies.
+//
+// Participants and signing sessions stay inside the API and are referred
+// to by integer handles; their secrets never cross into JavaScript. Key
+// shares do, as the result of dkg.finalize and the input of sign.round1,
+// and must be stored by the caller as carefully as a key share file.
+//
+// The methods and their parameters are:
+//
+//	dkg.new               {ceremony, id, echo}         -> {handle}
+//	dkg.round1            {handle}                     -> {broadcast, shares}
+//	dkg.receiveBroadcast  {handle, broadcast}          -> {pending}
+//	dkg.receiveShare      {handle, share}              -> {pending}
+//	dkg.echo              {handle}                     -> {digest}
+//	dkg.receiveEcho       {handle, from, digest}       -> {}
+//	dkg.finalize          {handle}                     -> {keyShare}
+//	sign.round1           {keyShare, message}          -> {handle, commitment}
+//	sign.round2           {handle, commitments}        -> {share}
+//	aggregate             {ceremony, groupKey, message, commitments, shares} -> {signature}
+//	verify                {ceremony, groupKey, message, signature} -> {}
+//	release               {handle}                     -> {}
+//
+// Messages, digests, keys and shares are hex encoded. dkg.finalize and
+// sign.round2 release their handle; release abandons one early.
+package jsbind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/keyfile"
+	"github.com/f3rmion/fy/session"
+)
+
+// API holds the participants and signing sessions created through it.
+// It is not safe for concurrent use, which JavaScript never attempts.
+type API struct {
+	rand    io.Reader
+	next    int
+	handles map[int]any
+}
+
+// New returns an API drawing its randomness from rand, normally
+// crypto/rand.Reader.
+func New(rand io.Reader) *API {
+	return &API{rand: rand, next: 1, handles: make(map[int]any)}
+}
+
+// methods maps method names to their implementations.
+var methods = map[string]func(a *API, params []byte) (any, error){
+	"dkg.new":              (*API).dkgNew,
+	"dkg.round1":           (*API).dkgRound1,
+	"dkg.receiveBroadcast": (*API).dkgReceiveBroadcast,
+	"dkg.receiveShare":     (*API).dkgReceiveShare,
+	"dkg.echo":             (*API).dkgEcho,
+	"dkg.receiveEcho":      (*API).dkgReceiveEcho,
+	"dkg.finalize":         (*API).dkgFinalize,
+	"sign.round1":          (*API).signRound1,
+	"sign.round2":          (*API).signRound2,
+	"aggregate":            (*API).aggregate,
+	"verify":               (*API).verify,
+	"release":              (*API).release,
+}
+
+// Call runs method with its JSON-encoded params and returns its
+// JSON-encoded result.
+func (a *API) Call(method string, params []byte) ([]byte, error) {
+	m, ok := methods[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+	result, err := m(a, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return json.Marshal(result)
+}
+
+// dkgState is a participant in a DKG.
+type dkgState struct {
+	c *keyfile.Ceremony
+	p *session.Participant
+}
+
+// signState is a signer between the two signing rounds.
+type signState struct {
+	c  *keyfile.Ceremony
+	id int
+	s  *session.SigningSession
+}
+
+// handleParams selects an object by handle.
+type handleParams struct {
+	Handle int `json:"handle"`
+}
+
+// handleResult returns a new handle.
+type handleResult struct {
+	Handle int `json:"handle"`
+}
+
+// pendingResult lists the participants whose DKG messages are missing.
+type pendingResult struct {
+	Pending []int `json:"pending"`
+}
+
+// pending returns the participants p is waiting for, as a list that is
+// never null in JSON.
+func pending(p *session.Participant) pendingResult {
+	return pendingResult{append([]int{}, p.Status().Pending...)}
+}
+
+// add stores v under a new handle.
+func (a *API) add(v any) int {
+	h := a.next
+	a.next++
+	a.handles[h] = v
+	return h
+}
+
+// dkg returns the DKG participant with handle h.
+func (a *API) dkg(h int) (*dkgState, error) {
+	if st, ok := a.handles[h].(*dkgState); ok {
+		return st, nil
+	}
+	return nil, fmt.Errorf("no DKG participant with handle %d", h)
+}
+
+func (a *API) dkgNew(params []byte) (any, error) {
+	var in struct {
+		Ceremony keyfile.Ceremony `json:"ceremony"`
+		ID       int              `json:"id"`
+		Echo     bool             `json:"echo"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	if err := in.Ceremony.Check(); err != nil {
+		return nil, err
+	}
+	if err := in.Ceremony.CheckID(in.ID); err != nil {
+		return nil, err
+	}
+	p, err := in.Ceremony.NewParticipant(in.ID, &session.Config{EchoBroadcast: in.Echo})
+	if err != nil {
+		return nil, err
+	}
+	return handleResult{a.add(&dkgState{c: &in.Ceremony, p: p})}, nil
+}
+
+func (a *API) dkgRound1(params []byte) (any, error) {
+	st, err := a.dkgParams(params)
+	if err != nil {
+		return nil, err
+	}
+	out, err := st.p.GenerateRound1(a.rand, st.c.IDs())
+	if err != nil {
+		return nil, err
+	}
+	result := struct {
+		Broadcast *keyfile.Broadcast `json:"broadcast"`
+		Shares    []*keyfile.Share   `json:"shares"`
+	}{Broadcast: keyfile.NewBroadcast(st.c, st.p.ID(), out.Broadcast)}
+	for _, to := range st.c.IDs() {
+		share, ok := out.PrivateShares[to]
+		if !ok {
+			continue
+		}
+		result.Shares = append(result.Shares, &keyfile.Share{
+			Ceremony: st.c.ID,
+			From:     st.p.ID(),
+			To:       to,
+			Share:    keyfile.EncodeHex(share.Share),
+		})
+		share.Zeroize()
+	}
+	return result, nil
+}
+
+func (a *API) dkgReceiveBroadcast(params []byte) (any, error) {
+	var in struct {
+		Handle    int               `json:"handle"`
+		Broadcast keyfile.Broadcast `json:"broadcast"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	st, err := a.dkg(in.Handle)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCeremony(st.c, in.Broadcast.Ceremony); err != nil {
+		return nil, err
+	}
+	b, err := in.Broadcast.Decode(st.p)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.p.ReceiveBroadcast(b); err != nil {
+		return nil, err
+	}
+	return pending(st.p), nil
+}
+
+func (a *API) dkgReceiveShare(params []byte) (any, error) {
+	var in struct {
+		Handle int           `json:"handle"`
+		Share  keyfile.Share `json:"share"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	st, err := a.dkg(in.Handle)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCeremony(st.c, in.Share.Ceremony); err != nil {
+		return nil, err
+	}
+	if in.Share.To != st.p.ID() {
+		return nil, fmt.Errorf("share for participant %d, not %d", in.Share.To, st.p.ID())
+	}
+	g, err := group.New(st.c.Group)
+	if err != nil {
+		return nil, err
+	}
+	share, err := in.Share.Decode(g)
+	if err != nil {
+		return nil, err
+	}
+	if err := st.p.ReceivePrivateShare(share); err != nil {
+		return nil, err
+	}
+	return pending(st.p), nil
+}
+
+func (a *API) dkgEcho(params []byte) (any, error) {
+	st, err := a.dkgParams(params)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := st.p.EchoDigest()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Digest string `json:"digest"`
+	}{hex.EncodeToString(digest)}, nil
+}
+
+func (a *API) dkgReceiveEcho(params []byte) (any, error) {
+	var in struct {
+		Handle int    `json:"handle"`
+		From   int    `json:"from"`
+		Digest string `json:"digest"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	st, err := a.dkg(in.Handle)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := hex.DecodeString(in.Digest)
+	if err != nil {
+		return nil, errors.New("invalid digest")
+	}
+	return struct{}{}, st.p.ReceiveEcho(in.From, digest)
+}
+
+func (a *API) dkgFinalize(params []byte) (any, error) {
+	var in handleParams
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	st, err := a.dkg(in.Handle)
+	if err != nil {
+		return nil, err
+	}
+	result, err := st.p.ProcessRound1(nil)
+	if err != nil {
+		return nil, err
+	}
+	delete(a.handles, in.Handle)
+	defer result.KeyShare.Zeroize()
+	return struct {
+		KeyShare *keyfile.KeyShare `json:"keyShare"`
+	}{keyfile.New(st.c, st.p.ID(), result.KeyShare, result.AllPublicKeys)}, nil
+}
+
+func (a *API) signRound1(params []byte) (any, error) {
+	var in struct {
+		KeyShare keyfile.KeyShare `json:"keyShare"`
+		Message  string           `json:"message"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	if err := in.KeyShare.Ceremony.Check(); err != nil {
+		return nil, err
+	}
+	message, err := decodeMessage(in.Message)
+	if err != nil {
+		return nil, err
+	}
+	p, err := in.KeyShare.Participant(nil)
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.NewSigningSession(a.rand, message)
+	if err != nil {
+		return nil, err
+	}
+	c := in.KeyShare.Ceremony
+	commitment := s.Commitment()
+	return struct {
+		Handle     int                 `json:"handle"`
+		Commitment *keyfile.Commitment `json:"commitment"`
+	}{
+		Handle: a.add(&signState{c: &c, id: p.ID(), s: s}),
+		Commitment: &keyfile.Commitment{
+			Ceremony: c.ID,
+			Message:  keyfile.MessageDigest(message),
+			From:     p.ID(),
+			Hiding:   keyfile.EncodeHex(commitment.HidingPoint),
+			Binding:  keyfile.EncodeHex(commitment.BindingPoint),
+		},
+	}, nil
+}
+
+func (a *API) signRound2(params []byte) (any, error) {
+	var in struct {
+		Handle      int                  `json:"handle"`
+		Commitments []keyfile.Commitment `json:"commitments"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	st, ok := a.handles[in.Handle].(*signState)
+	if !ok {
+		return nil, fmt.Errorf("no signing session with handle %d", in.Handle)
+	}
+	digest := keyfile.MessageDigest(st.s.Message())
+	commitments, _, err := decodeCommitments(st.c, digest, in.Commitments)
+	if err != nil {
+		return nil, err
+	}
+	// The session is consumed by signing, successful or not
+	delete(a.handles, in.Handle)
+	share, err := st.s.Sign(commitments)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Share *keyfile.SignatureShare `json:"share"`
+	}{&keyfile.SignatureShare{
+		Ceremony: st.c.ID,
+		Message:  digest,
+		From:     st.id,
+		Share:    keyfile.EncodeHex(share.Z),
+	}}, nil
+}
+
+func (a *API) aggregate(params []byte) (any, error) {
+	var in struct {
+		Ceremony    keyfile.Ceremony         `json:"ceremony"`
+		GroupKey    string                   `json:"groupKey"`
+		Message     string                   `json:"message"`
+		Commitments []keyfile.Commitment     `json:"commitments"`
+		Shares      []keyfile.SignatureShare `json:"shares"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	f, g, groupKey, message, err := signingParams(&in.Ceremony, in.GroupKey, in.Message)
+	if err != nil {
+		return nil, err
+	}
+	digest := keyfile.MessageDigest(message)
+	commitments, signers, err := decodeCommitments(&in.Ceremony, digest, in.Commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(in.Shares) != len(signers) {
+		return nil, fmt.Errorf("%d signature shares for %d commitments", len(in.Shares), len(signers))
+	}
+	shares := make([]*frost.SignatureShare, len(signers))
+	for _, sf := range in.Shares {
+		if err := checkCeremony(&in.Ceremony, sf.Ceremony); err != nil {
+			return nil, err
+		}
+		if sf.Message != digest {
+			return nil, fmt.Errorf("share from %d for message %s, not %s", sf.From, sf.Message, digest)
+		}
+		i := slices.Index(signers, sf.From)
+		if i < 0 || shares[i] != nil {
+			return nil, fmt.Errorf("unexpected share from %d", sf.From)
+		}
+		z, err := keyfile.DecodeScalar(g, sf.Share)
+		if err != nil {
+			return nil, fmt.Errorf("invalid share from %d: %w", sf.From, err)
+		}
+		shares[i] = &frost.SignatureShare{ID: commitments[i].ID, Z: z}
+	}
+
+	sig, err := session.Aggregate(f, message, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Verify(f, message, sig, groupKey); err != nil {
+		return nil, fmt.Errorf("aggregated signature is invalid for the group key: %w", err)
+	}
+	return struct {
+		Signature *keyfile.Signature `json:"signature"`
+	}{&keyfile.Signature{
+		Ceremony:  in.Ceremony.ID,
+		Message:   digest,
+		Signature: hex.EncodeToString(sig.Bytes()),
+	}}, nil
+}
+
+func (a *API) verify(params []byte) (any, error) {
+	var in struct {
+		Ceremony  keyfile.Ceremony  `json:"ceremony"`
+		GroupKey  string            `json:"groupKey"`
+		Message   string            `json:"message"`
+		Signature keyfile.Signature `json:"signature"`
+	}
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	f, _, groupKey, message, err := signingParams(&in.Ceremony, in.GroupKey, in.Message)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(in.Signature.Signature)
+	if err != nil {
+		return nil, errors.New("invalid signature encoding")
+	}
+	sig, err := f.ParseSignature(data)
+	if err != nil {
+		return nil, err
+	}
+	return struct{}{}, session.Verify(f, message, sig, groupKey)
+}
+
+func (a *API) release(params []byte) (any, error) {
+	var in handleParams
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	v, ok := a.handles[in.Handle]
+	if !ok {
+		return nil, fmt.Errorf("no object with handle %d", in.Handle)
+	}
+	if st, ok := v.(*signState); ok {
+		st.s.Zeroize()
+	}
+	delete(a.handles, in.Handle)
+	return struct{}{}, nil
+}
+
+// dkgParams decodes handleParams and returns the DKG participant.
+func (a *API) dkgParams(params []byte) (*dkgState, error) {
+	var in handleParams
+	if err := decode(params, &in); err != nil {
+		return nil, err
+	}
+	return a.dkg(in.Handle)
+}
+
+// signingParams decodes the parameters shared by aggregate and verify.
+func signingParams(c *keyfile.Ceremony, groupKeyHex, messageHex string) (*frost.FROST, group.Group, group.Point, []byte, error) {
+	if err := c.Check(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	f, err := c.NewFROST()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	g, _ := group.New(c.Group)
+	groupKey, err := keyfile.DecodePoint(g, groupKeyHex)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("invalid group key: %w", err)
+	}
+	message, err := decodeMessage(messageHex)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return f, g, groupKey, message, nil
+}
+
+// decodeCommitments decodes the commitments of the signers of the message
+// with the given digest, in ID order, and returns them with the signers'
+// IDs.
+func decodeCommitments(c *keyfile.Ceremony, digest string, list []keyfile.Commitment) ([]*frost.SigningCommitment, []int, error) {
+	f, err := c.NewFROST()
+	if err != nil {
+		return nil, nil, err
+	}
+	g, _ := group.New(c.Group)
+	list = slices.Clone(list)
+	slices.SortFunc(list, func(a, b keyfile.Commitment) int { return a.From - b.From })
+	commitments := make([]*frost.SigningCommitment, len(list))
+	signers := make([]int, len(list))
+	for i, cf := range list {
+		if err := checkCeremony(c, cf.Ceremony); err != nil {
+			return nil, nil, err
+		}
+		if cf.Message != digest {
+			return nil, nil, fmt.Errorf("commitment from %d for message %s, not %s", cf.From, cf.Message, digest)
+		}
+		if err := c.CheckID(cf.From); err != nil {
+			return nil, nil, err
+		}
+		if commitments[i], err = cf.Decode(g); err != nil {
+			return nil, nil, fmt.Errorf("commitment from %d: %w", cf.From, err)
+		}
+		signers[i] = cf.From
+	}
+	if err := f.CheckCommitments(commitments); err != nil {
+		return nil, nil, err
+	}
+	return commitments, signers, nil
+}
+
+// checkCeremony returns an error if a message belongs to another ceremony
+// than c.
+func checkCeremony(c *keyfile.Ceremony, id string) error {
+	if id != c.ID {
+		return fmt.Errorf("message belongs to ceremony %s, not %s", id, c.ID)
+	}
+	return nil
+}
+
+// decodeMessage decodes a hex-encoded message to sign.
+func decodeMessage(s string) ([]byte, error) {
+	message, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid message encoding: want hex")
+	}
+	return message, nil
+}
+
+// decode decodes JSON params into v, rejecting unknown fields.
+func decode(params []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package jsbind
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/f3rmion/fy/internal/keyfile"
+)
+
+// call runs method with params and decodes its result into result.
+func call(t *testing.T, a *API, method string, params, result any) {
+	t.Helper()
+	if err := tryCall(a, method, params, result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tryCall is like call but returns the error.
+func tryCall(a *API, method string, params, result any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	out, err := a.Call(method, data)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(out, result)
+}
+
+type round1Result struct {
+	Broadcast keyfile.Broadcast `json:"broadcast"`
+	Shares    []keyfile.Share   `json:"shares"`
+}
+
+// runDKG runs a DKG with echo broadcast for every participant of c, each
+// through its own API, and returns the key shares.
+func runDKG(t *testing.T, c *keyfile.Ceremony) []*keyfile.KeyShare {
+	t.Helper()
+	apis := make([]*API, c.Total)
+	handles := make([]int, c.Total)
+	round1 := make([]round1Result, c.Total)
+	for i := range apis {
+		apis[i] = New(rand.Reader)
+		var h handleResult
+		call(t, apis[i], "dkg.new", map[string]any{"ceremony": c, "id": i + 1, "echo": true}, &h)
+		handles[i] = h.Handle
+		call(t, apis[i], "dkg.round1", handleParams{h.Handle}, &round1[i])
+	}
+
+	for i, a := range apis {
+		var pending pendingResult
+		for j, r := range round1 {
+			if i == j {
+				continue
+			}
+			call(t, a, "dkg.receiveBroadcast", map[string]any{"handle": handles[i], "broadcast": r.Broadcast}, &pending)
+			for _, s := range r.Shares {
+				if s.To == i+1 {
+					call(t, a, "dkg.receiveShare", map[string]any{"handle": handles[i], "share": s}, &pending)
+				}
+			}
+		}
+		if len(pending.Pending) != 0 {
+			t.Fatalf("participant %d still waiting for %v", i+1, pending.Pending)
+		}
+	}
+
+	digests := make([]string, c.Total)
+	for i, a := range apis {
+		var echo struct {
+			Digest string `json:"digest"`
+		}
+		call(t, a, "dkg.echo", handleParams{handles[i]}, &echo)
+		digests[i] = echo.Digest
+	}
+	keyShares := make([]*keyfile.KeyShare, c.Total)
+	for i, a := range apis {
+		for j, d := range digests {
+			if i != j {
+				call(t, a, "dkg.receiveEcho", map[string]any{"handle": handles[i], "from": j + 1, "digest": d}, nil)
+			}
+		}
+		var result struct {
+			KeyShare *keyfile.KeyShare `json:"keyShare"`
+		}
+		call(t, a, "dkg.finalize", handleParams{handles[i]}, &result)
+		keyShares[i] = result.KeyShare
+		if len(a.handles) != 0 {
+			t.Errorf("participant %d: finalize left %d handles", i+1, len(a.handles))
+		}
+	}
+	return keyShares
+}
+
+// testCeremony returns a 2-of-3 ceremony.
+func testCeremony() *keyfile.Ceremony {
+	return &keyfile.Ceremony{Version: keyfile.Version, ID: "js", Group: "baby-jubjub", Hasher: "sha256", Threshold: 2, Total: 3}
+}
+
+func TestDKGAndSigning(t *testing.T) {
+	c := testCeremony()
+	keyShares := runDKG(t, c)
+	if err := keyfile.Check(keyShares); err != nil {
+		t.Fatal(err)
+	}
+	groupKey := keyShares[0].GroupKey
+	message := hex.EncodeToString([]byte("signed in the browser"))
+
+	a := New(rand.Reader)
+	signers := []*keyfile.KeyShare{keyShares[2], keyShares[0]}
+	handles := make([]int, len(signers))
+	var commitments []keyfile.Commitment
+	for i, ks := range signers {
+		var r struct {
+			Handle     int                `json:"handle"`
+			Commitment keyfile.Commitment `json:"commitment"`
+		}
+		call(t, a, "sign.round1", map[string]any{"keyShare": ks, "message": message}, &r)
+		handles[i] = r.Handle
+		commitments = append(commitments, r.Commitment)
+	}
+	var shares []keyfile.SignatureShare
+	for _, h := range handles {
+		var r struct {
+			Share keyfile.SignatureShare `json:"share"`
+		}
+		call(t, a, "sign.round2", map[string]any{"handle": h, "commitments": commitments}, &r)
+		shares = append(shares, r.Share)
+	}
+	if err := tryCall(a, "sign.round2", map[string]any{"handle": handles[0], "commitments": commitments}, nil); err == nil {
+		t.Error("signing session used twice")
+	}
+
+	var agg struct {
+		Signature keyfile.Signature `json:"signature"`
+	}
+	call(t, a, "aggregate", map[string]any{
+		"ceremony": c, "groupKey": groupKey, "message": message,
+		"commitments": commitments, "shares": shares,
+	}, &agg)
+	call(t, a, "verify", map[string]any{
+		"ceremony": c, "groupKey": groupKey, "message": message, "signature": agg.Signature,
+	}, nil)
+	if err := tryCall(a, "verify", map[string]any{
+		"ceremony": c, "groupKey": groupKey, "message": "00", "signature": agg.Signature,
+	}, nil); err == nil {
+		t.Error("signature verified for another message")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := testCeremony()
+	a := New(rand.Reader)
+	var h handleResult
+	call(t, a, "dkg.new", map[string]any{"ceremony": c, "id": 1}, &h)
+	var r round1Result
+	call(t, a, "dkg.round1", handleParams{h.Handle}, &r)
+
+	other := *c
+	other.ID = "other"
+	b := New(rand.Reader)
+	var h2 handleResult
+	call(t, b, "dkg.new", map[string]any{"ceremony": &other, "id": 2}, &h2)
+
+	tests := []struct {
+		name   string
+		api    *API
+		method string
+		params any
+		want   string
+	}{
+		{"unknown method", a, "dkg.round3", struct{}{}, "unknown method"},
+		{"unknown field", a, "dkg.round1", map[string]any{"handle": h.Handle, "extra": 1}, "unknown field"},
+		{"unknown handle", a, "dkg.round1", handleParams{99}, "no DKG participant"},
+		{"bad ID", a, "dkg.new", map[string]any{"ceremony": c, "id": 4}, "participant ID"},
+		{"bad ceremony", a, "dkg.new", map[string]any{"ceremony": keyfile.Ceremony{}, "id": 1}, "unsupported version"},
+		{"other ceremony", b, "dkg.receiveBroadcast", map[string]any{"handle": h2.Handle, "broadcast": r.Broadcast}, "belongs to ceremony"},
+		{"bad message", a, "sign.round1", map[string]any{"keyShare": keyfile.KeyShare{Ceremony: *c}, "message": "xyz"}, "message encoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tryCall(tt.api, tt.method, tt.params, nil)
+			if err == nil {
+				t.Fatal("call succeeded")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	call(t, a, "release", handleParams{h.Handle}, nil)
+	if err := tryCall(a, "dkg.round1", handleParams{h.Handle}, nil); err == nil {
+		t.Error("released handle still usable")
+	}
+}
@@ -0,0 +1,186 @@
+package keyfile
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// Default scrypt parameters for new encrypted files, the interactive
+// parameters recommended for scrypt.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Limits on the scrypt parameters accepted from a file, so that a crafted
+// file cannot make decryption use unbounded memory or time.
+const (
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+)
+
+// ErrDecrypt is returned when an encrypted key share cannot be
+// decrypted, because the passphrase is wrong or the file was modified.
+var ErrDecrypt = errors.New("keyfile: wrong passphrase or corrupted key share")
+
+// Encrypted is a [KeyShare] encrypted under a passphrase. The key is
+// derived with scrypt and the key share is sealed with
+// ChaCha20-Poly1305. The ceremony ID and participant ID are stored in
+// the clear, so that operators can tell files apart, and are
+// authenticated with the key share.
+type Encrypted struct {
+	Version    int    `json:"version"`
+	Ceremony   string `json:"ceremony"`
+	ID         int    `json:"id"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Encrypt encrypts ks under passphrase, drawing the salt and nonce from
+// rand.
+func Encrypt(ks *KeyShare, passphrase []byte, rand io.Reader) (*Encrypted, error) {
+	plaintext, err := json.Marshal(ks)
+	if err != nil {
+		return nil, err
+	}
+	defer memwipe.Bytes(plaintext)
+
+	salt := make([]byte, 16)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand, nonce); err != nil {
+		return nil, err
+	}
+	e := &Encrypted{
+		Version:  Version,
+		Ceremony: ks.Ceremony.ID,
+		ID:       ks.ID,
+		KDF:      "scrypt",
+		N:        scryptN,
+		R:        scryptR,
+		P:        scryptP,
+		Salt:     hex.EncodeToString(salt),
+		Nonce:    hex.EncodeToString(nonce),
+	}
+	aead, err := e.aead(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	e.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, plaintext, e.additionalData()))
+	return e, nil
+}
+
+// LoadEncrypted reads the encrypted key share file at path.
+func LoadEncrypted(path string) (*Encrypted, error) {
+	var e Encrypted
+	if err := readJSON(path, &e); err != nil {
+		return nil, err
+	}
+	if e.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported version %d", path, e.Version)
+	}
+	return &e, nil
+}
+
+// Decrypt decrypts the key share with passphrase. It returns [ErrDecrypt]
+// if the passphrase is wrong or the file was modified.
+func (e *Encrypted) Decrypt(passphrase []byte) (*KeyShare, error) {
+	if e.KDF != "scrypt" {
+		return nil, fmt.Errorf("keyfile: unsupported key derivation %q", e.KDF)
+	}
+	if e.N < 2 || e.N > maxScryptN || e.R < 1 || e.R > maxScryptR || e.P < 1 || e.P > maxScryptP {
+		return nil, errors.New("keyfile: scrypt parameters out of range")
+	}
+	salt, err := hex.DecodeString(e.Salt)
+	if err != nil {
+		return nil, errors.New("keyfile: invalid salt")
+	}
+	nonce, err := hex.DecodeString(e.Nonce)
+	if err != nil || len(nonce) != chacha20poly1305.NonceSizeX {
+		return nil, errors.New("keyfile: invalid nonce")
+	}
+	ciphertext, err := hex.DecodeString(e.Ciphertext)
+	if err != nil {
+		return nil, errors.New("keyfile: invalid ciphertext")
+	}
+	aead, err := e.aead(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, e.additionalData())
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	defer memwipe.Bytes(plaintext)
+
+	var ks KeyShare
+	if err := decodeJSON(bytes.NewReader(plaintext), &ks); err != nil {
+		return nil, err
+	}
+	if ks.Ceremony.ID != e.Ceremony || ks.ID != e.ID {
+		return nil, ErrDecrypt
+	}
+	if err := ks.Ceremony.Check(); err != nil {
+		return nil, err
+	}
+	return &ks, nil
+}
+
+// aead derives the encryption key from passphrase.
+func (e *Encrypted) aead(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, e.N, e.R, e.P, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	defer memwipe.Bytes(key)
+	return chacha20poly1305.NewX(key)
+}
+
+// additionalData binds the cleartext metadata to the ciphertext.
+func (e *Encrypted) additionalData() []byte {
+	return fmt.Appendf(nil, "fy-keyshare-v%d|%s|%d|%s|%d|%d|%d", e.Version, e.Ceremony, e.ID, e.KDF, e.N, e.R, e.P)
+}
+
+// PassphraseEnv is the environment variable read by [ReadPassphrase].
+const PassphraseEnv = "FY_PASSPHRASE"
+
+// ReadPassphrase returns the passphrase stored in the file at path, or
+// in the environment variable FY_PASSPHRASE if path is empty. A trailing
+// newline is removed.
+func ReadPassphrase(path string) ([]byte, error) {
+	var passphrase []byte
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		passphrase = data
+	} else {
+		passphrase = []byte(os.Getenv(PassphraseEnv))
+	}
+	passphrase = bytes.TrimRight(passphrase, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("keyfile: no passphrase: use a passphrase file or set %s", PassphraseEnv)
+	}
+	return passphrase, nil
+}
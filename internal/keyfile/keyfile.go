@@ -0,0 +1,286 @@
+// Package keyfile defines the JSON files in which the fy commands store
+// ceremony parameters and key shares, their passphrase-encrypted form,
+// and the messages participants exchange during a ceremony.
+package keyfile
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/session"
+
+	_ "github.com/f3rmion/fy/bjj" // register the baby-jubjub group
+)
+
+// Version is the format version of the files.
+const Version = 1
+
+// Ceremony describes a key generation ceremony. Every message file of the
+// ceremony carries its ID, so that messages from different ceremonies
+// cannot be mixed up.
+type Ceremony struct {
+	Version   int    `json:"version"`
+	ID        string `json:"id"`
+	Group     string `json:"group"`
+	Hasher    string `json:"hasher"`
+	Threshold int    `json:"threshold"`
+	Total     int    `json:"total"`
+}
+
+// KeyShare is the result of a ceremony for one participant. It is secret.
+type KeyShare struct {
+	Ceremony         Ceremony       `json:"ceremony"`
+	ID               int            `json:"id"`
+	SecretKey        string         `json:"secretKey"`
+	PublicKey        string         `json:"publicKey"`
+	GroupKey         string         `json:"groupKey"`
+	TranscriptDigest string         `json:"transcriptDigest"`
+	PublicKeys       map[int]string `json:"publicKeys"`
+}
+
+// LoadCeremony reads and checks the ceremony file at path.
+func LoadCeremony(path string) (*Ceremony, error) {
+	var c Ceremony
+	if err := readJSON(path, &c); err != nil {
+		return nil, err
+	}
+	if err := c.Check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Load reads the key share file at path.
+func Load(path string) (*KeyShare, error) {
+	var ks KeyShare
+	if err := readJSON(path, &ks); err != nil {
+		return nil, err
+	}
+	if err := ks.Ceremony.Check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &ks, nil
+}
+
+// Check returns an error if c is not a supported ceremony.
+func (c *Ceremony) Check() error {
+	if c.Version != Version {
+		return fmt.Errorf("unsupported version %d", c.Version)
+	}
+	if c.ID == "" {
+		return errors.New("missing ceremony ID")
+	}
+	return nil
+}
+
+// NewFROST creates the FROST instance for the ceremony.
+func (c *Ceremony) NewFROST() (*frost.FROST, error) {
+	g, err := group.New(c.Group)
+	if err != nil {
+		return nil, err
+	}
+	h, err := frost.NewHasher(c.Hasher)
+	if err != nil {
+		return nil, err
+	}
+	return frost.NewWithHasher(g, c.Threshold, c.Total, h)
+}
+
+// NewParticipant creates the session participant id for the ceremony.
+// cfg may be nil; its Hasher is replaced by the ceremony's.
+func (c *Ceremony) NewParticipant(id int, cfg *session.Config) (*session.Participant, error) {
+	g, err := group.New(c.Group)
+	if err != nil {
+		return nil, err
+	}
+	h, err := frost.NewHasher(c.Hasher)
+	if err != nil {
+		return nil, err
+	}
+	var config session.Config
+	if cfg != nil {
+		config = *cfg
+	}
+	config.Hasher = h
+	return session.NewParticipantWithConfig(g, c.Threshold, c.Total, id, &config)
+}
+
+// IDs returns the participant IDs of the ceremony, 1 to Total.
+func (c *Ceremony) IDs() []int {
+	ids := make([]int, c.Total)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// CheckID returns an error if id is not a participant of the ceremony.
+func (c *Ceremony) CheckID(id int) error {
+	if id < 1 || id > c.Total {
+		return fmt.Errorf("participant ID must be between 1 and %d, got %d", c.Total, id)
+	}
+	return nil
+}
+
+// New encodes the key share ks of participant id, with the verification
+// shares of all participants.
+func New(c *Ceremony, id int, ks *frost.KeyShare, publicKeys map[int]group.Point) *KeyShare {
+	f := &KeyShare{
+		Ceremony:         *c,
+		ID:               id,
+		SecretKey:        EncodeHex(ks.SecretKey),
+		PublicKey:        EncodeHex(ks.PublicKey),
+		GroupKey:         EncodeHex(ks.GroupKey),
+		TranscriptDigest: hex.EncodeToString(ks.TranscriptDigest),
+		PublicKeys:       make(map[int]string, len(publicKeys)),
+	}
+	for i, pk := range publicKeys {
+		f.PublicKeys[i] = EncodeHex(pk)
+	}
+	return f
+}
+
+// Decode decodes the key share, checking that its public key matches the
+// secret.
+func (f *KeyShare) Decode() (*frost.KeyShare, error) {
+	g, err := group.New(f.Ceremony.Group)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Ceremony.CheckID(f.ID); err != nil {
+		return nil, err
+	}
+	secret, err := DecodeScalar(g, f.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+	publicKey, err := DecodePoint(g, f.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	groupKey, err := DecodePoint(g, f.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group key: %w", err)
+	}
+	digest, err := hex.DecodeString(f.TranscriptDigest)
+	if err != nil {
+		return nil, errors.New("invalid transcript digest")
+	}
+	if !g.ScalarBaseMult(secret).Equal(publicKey) {
+		return nil, errors.New("public key does not match the secret key")
+	}
+	return &frost.KeyShare{
+		ID:               g.NewScalar().SetUint64(uint64(f.ID)),
+		SecretKey:        secret,
+		PublicKey:        publicKey,
+		GroupKey:         groupKey,
+		TranscriptDigest: digest,
+	}, nil
+}
+
+// Participant returns the session participant holding the key share,
+// ready to sign. cfg is passed to [Ceremony.NewParticipant].
+func (f *KeyShare) Participant(cfg *session.Config) (*session.Participant, error) {
+	p, err := f.Ceremony.NewParticipant(f.ID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := f.Decode()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetKeyShare(ks); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Check checks that the key share files belong to one ceremony and one
+// threshold key, as [frost.FROST.CheckKeyShares] does for decoded shares.
+// The verification shares recorded in the files must also agree with
+// each other and with the public keys of the shares. At least the
+// threshold of the ceremony must be given.
+func Check(files []*KeyShare) error {
+	if len(files) == 0 {
+		return errors.New("no key shares given")
+	}
+	first := files[0]
+	f, err := first.Ceremony.NewFROST()
+	if err != nil {
+		return err
+	}
+	shares := make([]*frost.KeyShare, 0, len(files))
+	defer func() {
+		for _, ks := range shares {
+			ks.Zeroize()
+		}
+	}()
+	for _, file := range files {
+		if file.Ceremony != first.Ceremony {
+			return fmt.Errorf("key share %d: ceremony differs from that of key share %d", file.ID, first.ID)
+		}
+		ks, err := file.Decode()
+		if err != nil {
+			return fmt.Errorf("key share %d: %w", file.ID, err)
+		}
+		shares = append(shares, ks)
+		if !maps.Equal(file.PublicKeys, first.PublicKeys) {
+			return fmt.Errorf("key share %d: verification shares differ from those of key share %d", file.ID, first.ID)
+		}
+		if pk, ok := file.PublicKeys[file.ID]; ok && pk != file.PublicKey {
+			return fmt.Errorf("key share %d: public key differs from its recorded verification share", file.ID)
+		}
+	}
+	return f.CheckKeyShares(shares)
+}
+
+// EncodeHex encodes a point or scalar.
+func EncodeHex(v interface{ Bytes() []byte }) string {
+	return hex.EncodeToString(v.Bytes())
+}
+
+// DecodeScalar decodes a canonically encoded scalar of g.
+func DecodeScalar(g group.Group, s string) (group.Scalar, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.NewScalar().SetCanonicalBytes(b)
+}
+
+// DecodePoint decodes a point of g.
+func DecodePoint(g group.Group, s string) (group.Point, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return g.NewPoint().SetBytes(b)
+}
+
+// readJSON decodes the JSON file at path into v.
+func readJSON(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := decodeJSON(f, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// decodeJSON decodes a JSON value from r into v, rejecting unknown
+// fields.
+func decodeJSON(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,128 @@
+package keyfile
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/session"
+)
+
+// testKeyShare returns the key share of participant 1 of a 2-of-3 group.
+func testKeyShare(t testing.TB) *KeyShare {
+	t.Helper()
+	shares, _, err := session.QuickDKG(&bjj.BJJ{}, 2, 3, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Ceremony{Version: Version, ID: "test", Group: "baby-jubjub", Hasher: "sha256", Threshold: 2, Total: 3}
+	return New(c, 1, shares[0], nil)
+}
+
+func TestDecode(t *testing.T) {
+	ks := testKeyShare(t)
+	if _, err := ks.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Participant(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := *ks
+	bad.PublicKey = bad.GroupKey
+	if _, err := bad.Decode(); err == nil {
+		t.Error("Decode accepted a public key that does not match the secret")
+	}
+	bad = *ks
+	bad.ID = 4
+	if _, err := bad.Decode(); err == nil {
+		t.Error("Decode accepted an ID outside the ceremony")
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	ks := testKeyShare(t)
+	passphrase := []byte("correct horse battery staple")
+	e, err := Encrypt(ks, passphrase, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := e.Decrypt(passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SecretKey != ks.SecretKey || got.ID != ks.ID || got.Ceremony != ks.Ceremony {
+		t.Error("decrypted key share differs from the original")
+	}
+
+	if _, err := e.Decrypt([]byte("wrong")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong passphrase: err = %v, want ErrDecrypt", err)
+	}
+	relabeled := *e
+	relabeled.ID = 2
+	if _, err := relabeled.Decrypt(passphrase); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("modified participant ID: err = %v, want ErrDecrypt", err)
+	}
+	expensive := *e
+	expensive.N = 1 << 30
+	if _, err := expensive.Decrypt(passphrase); err == nil {
+		t.Error("Decrypt accepted excessive scrypt parameters")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	shares, roster, err := session.QuickDKG(&bjj.BJJ{}, 2, 3, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Ceremony{Version: Version, ID: "test", Group: "baby-jubjub", Hasher: "sha256", Threshold: 2, Total: 3}
+	files := make([]*KeyShare, len(shares))
+	for i, ks := range shares {
+		files[i] = New(c, i+1, ks, roster.PublicKeys)
+	}
+	if err := Check(files); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(files[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	// with returns files with a modified copy of the second file
+	with := func(modify func(ks *KeyShare)) []*KeyShare {
+		ks := *files[1]
+		modify(&ks)
+		return []*KeyShare{files[0], &ks, files[2]}
+	}
+	for name, bad := range map[string][]*KeyShare{
+		"too few":      files[:1],
+		"ceremony":     with(func(ks *KeyShare) { ks.Ceremony.ID = "other" }),
+		"secret key":   with(func(ks *KeyShare) { ks.SecretKey = files[0].SecretKey; ks.PublicKey = files[0].PublicKey }),
+		"group key":    with(func(ks *KeyShare) { ks.GroupKey = files[0].PublicKey }),
+		"public keys":  with(func(ks *KeyShare) { ks.PublicKeys = map[int]string{1: files[1].PublicKey} }),
+		"duplicate ID": with(func(ks *KeyShare) { ks.ID = 1 }),
+	} {
+		if err := Check(bad); err == nil {
+			t.Errorf("%s: inconsistent key shares accepted", name)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	data, err := json.Marshal(testKeyShare(f))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add([]byte(`{"ceremony":{"version":1,"group":"baby-jubjub","hasher":"sha256","threshold":2,"total":3},"id":1}`))
+	f.Add([]byte(`{"ceremony":{"version":1,"group":"baby-jubjub","hasher":"sha256","threshold":0,"total":0},"id":0}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var ks KeyShare
+		if err := decodeJSON(bytes.NewReader(data), &ks); err != nil {
+			return
+		}
+		ks.Participant(nil)
+	})
+}
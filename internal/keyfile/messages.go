@@ -0,0 +1,120 @@
+package keyfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/session"
+)
+
+// Broadcast is a participant's DKG round 1 broadcast.
+type Broadcast struct {
+	Ceremony    string   `json:"ceremony"`
+	From        int      `json:"from"`
+	Commitments []string `json:"commitments"`
+}
+
+// Share is a DKG round 1 private share from one participant to another.
+// It is secret.
+type Share struct {
+	Ceremony string `json:"ceremony"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Share    string `json:"share"`
+}
+
+// Commitment is a signer's round 1 commitment for a message, identified
+// by its [MessageDigest].
+type Commitment struct {
+	Ceremony string `json:"ceremony"`
+	Message  string `json:"message"`
+	From     int    `json:"from"`
+	Hiding   string `json:"hiding"`
+	Binding  string `json:"binding"`
+}
+
+// SignatureShare is a signer's round 2 signature share.
+type SignatureShare struct {
+	Ceremony string `json:"ceremony"`
+	Message  string `json:"message"`
+	From     int    `json:"from"`
+	Share    string `json:"share"`
+}
+
+// Signature is an aggregated signature in its compact encoding.
+type Signature struct {
+	Ceremony  string `json:"ceremony"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// NewBroadcast encodes the round 1 broadcast b of participant from.
+func NewBroadcast(c *Ceremony, from int, b *frost.Round1Data) *Broadcast {
+	f := &Broadcast{Ceremony: c.ID, From: from}
+	for _, p := range b.Commitments {
+		f.Commitments = append(f.Commitments, EncodeHex(p))
+	}
+	return f
+}
+
+// Decode decodes the broadcast for the receiving participant p.
+func (b *Broadcast) Decode(p *session.Participant) (*frost.Round1Data, error) {
+	commitments, err := DecodeHexList(b.Commitments)
+	if err != nil {
+		return nil, err
+	}
+	return p.DecodeBroadcast(b.From, commitments)
+}
+
+// Decode decodes the share, a scalar of g.
+func (s *Share) Decode(g group.Group) (*frost.Round1PrivateData, error) {
+	value, err := DecodeScalar(g, s.Share)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share: %w", err)
+	}
+	return &frost.Round1PrivateData{
+		FromID: g.NewScalar().SetUint64(uint64(s.From)),
+		ToID:   g.NewScalar().SetUint64(uint64(s.To)),
+		Share:  value,
+	}, nil
+}
+
+// Decode decodes the commitment, validating both points.
+func (c *Commitment) Decode(g group.Group) (*frost.SigningCommitment, error) {
+	points, err := DecodeHexList([]string{c.Hiding, c.Binding})
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := group.DecodePoints(g, points)
+	if err != nil {
+		return nil, err
+	}
+	return &frost.SigningCommitment{
+		ID:           g.NewScalar().SetUint64(uint64(c.From)),
+		HidingPoint:  decoded[0],
+		BindingPoint: decoded[1],
+	}, nil
+}
+
+// MessageDigest identifies a message in the signing files.
+func MessageDigest(message []byte) string {
+	sum := sha256.Sum256(message)
+	return hex.EncodeToString(sum[:])
+}
+
+// DecodeHexList decodes a list of hex strings.
+func DecodeHexList(list []string) ([][]byte, error) {
+	out := make([][]byte, len(list))
+	for i, s := range list {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, errors.New("invalid hex encoding")
+		}
+		out[i] = b
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+// Package memwipe overwrites secrets held in memory, such as key shares,
+// nonces and their encodings, once they are no longer needed.
+//
+// Go gives no guarantee that a secret leaves no other copies behind, for
+// example after the garbage collector moved it or a stack grew, so wiping
+// is a best-effort measure that shortens the lifetime of secrets rather
+// than a guarantee that they are gone.
+package memwipe
+
+import (
+	"runtime"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// Bytes overwrites every byte of bufs with zero. The buffers are kept
+// alive until the writes are done so that the compiler cannot remove them
+// as dead stores.
+func Bytes(bufs ...[]byte) {
+	for _, b := range bufs {
+		clear(b)
+		runtime.KeepAlive(b)
+	}
+}
+
+// Scalars wipes every non-nil scalar with [group.Scalar.Zeroize], which
+// overwrites its internal representation.
+func Scalars(scalars ...group.Scalar) {
+	for _, s := range scalars {
+		if s != nil {
+			s.Zeroize()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package securemem provides guarded buffers for secrets that a
+// long-running process keeps in memory, in the manner of memguard.
+//
+// On Unix systems a [Buffer] is allocated outside the Go heap, so the
+// garbage collector never copies it, and locked into RAM so that it is
+// never written to swap. It is fenced by inaccessible guard pages, which
+// make an access past its end fault, and the rest of its first page is
+// filled with a random canary that reveals writes before its start. On
+// other systems a Buffer is an ordinary heap allocation with a canary.
+package securemem
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"sync"
+
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// Buffer is a fixed-size region of guarded memory. Its methods are safe
+// for concurrent use, but the slice returned by Bytes is not protected by
+// the Buffer's lock.
+type Buffer struct {
+	mu     sync.Mutex
+	mem    []byte // the whole allocation, including any guard pages
+	inner  []byte // the accessible part: canary followed by data
+	data   []byte // the secret, at the end of inner
+	canary []byte // expected content of inner before data
+}
+
+// New allocates a zeroed guarded buffer of size bytes. It returns an
+// error if the memory cannot be allocated or locked, for example because
+// the process's RLIMIT_MEMLOCK is too low.
+func New(size int) (*Buffer, error) {
+	if size < 0 {
+		return nil, errors.New("securemem: negative size")
+	}
+	mem, inner, err := alloc(size)
+	if err != nil {
+		return nil, err
+	}
+	b := &Buffer{
+		mem:    mem,
+		inner:  inner,
+		data:   inner[len(inner)-size:],
+		canary: make([]byte, len(inner)-size),
+	}
+	rand.Read(b.canary)
+	copy(inner, b.canary)
+	return b, nil
+}
+
+// Bytes returns the buffer's memory. It returns nil once the buffer has
+// been destroyed. Writing past the end of the returned slice faults on
+// systems with guard pages.
+func (b *Buffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+// Intact reports whether the canary before the data is unchanged. It
+// returns false once the buffer has been destroyed.
+func (b *Buffer) Intact() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.intact()
+}
+
+func (b *Buffer) intact() bool {
+	if b.inner == nil {
+		return false
+	}
+	prefix := b.inner[:len(b.canary)]
+	return subtle.ConstantTimeCompare(prefix, b.canary) == 1
+}
+
+// Destroy wipes the buffer and releases its memory. It returns an error
+// if the canary was overwritten, which indicates a memory corruption bug,
+// but releases the buffer regardless. Destroying a buffer twice is a
+// no-op.
+func (b *Buffer) Destroy() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.inner == nil {
+		return nil
+	}
+	intact := b.intact()
+	memwipe.Bytes(b.inner)
+	err := free(b.mem, b.inner)
+	b.mem, b.inner, b.data = nil, nil, nil
+	if !intact {
+		return errors.New("securemem: canary overwritten, memory corruption detected")
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package securemem
+
+// alloc returns a heap allocation with one byte of room for the canary.
+// Without guard pages or locking, only the canary protects it.
+func alloc(size int) (mem, inner []byte, err error) {
+	mem = make([]byte, size+1)
+	return mem, mem, nil
+}
+
+// free does nothing; the garbage collector reclaims the memory.
+func free(mem, inner []byte) error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package securemem
+
+import "testing"
+
+func TestBuffer(t *testing.T) {
+	b, err := New(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := b.Bytes()
+	if len(data) != 32 {
+		t.Fatalf("len(Bytes()) = %d", len(data))
+	}
+	for i, v := range data {
+		if v != 0 {
+			t.Fatalf("byte %d of a new buffer is %d", i, v)
+		}
+	}
+	copy(data, "secret")
+	if !b.Intact() {
+		t.Error("writing the data broke the canary")
+	}
+	if err := b.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Bytes() != nil || b.Intact() {
+		t.Error("destroyed buffer is still accessible")
+	}
+	if err := b.Destroy(); err != nil {
+		t.Errorf("second Destroy: %v", err)
+	}
+
+	// An underflow overwrites the canary
+	b, err = New(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.inner[len(b.inner)-33] ^= 1
+	if b.Intact() {
+		t.Error("canary corruption went unnoticed")
+	}
+	if err := b.Destroy(); err == nil {
+		t.Error("Destroy did not report the corruption")
+	}
+}
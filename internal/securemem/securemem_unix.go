@@ -0,0 +1,48 @@
+//go:build unix
+
+package securemem
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// alloc maps size bytes rounded up to whole pages, plus a guard page on
+// either side, and locks the accessible pages into RAM.
+func alloc(size int) (mem, inner []byte, err error) {
+	page := os.Getpagesize()
+	// Leave at least one byte of canary before the data
+	innerLen := (size + 1 + page - 1) / page * page
+	mem, err = unix.Mmap(-1, 0, innerLen+2*page, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("securemem: mmap: %w", err)
+	}
+	inner = mem[page : page+innerLen]
+	if err := unix.Mprotect(mem[:page], unix.PROT_NONE); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, fmt.Errorf("securemem: mprotect: %w", err)
+	}
+	if err := unix.Mprotect(mem[page+innerLen:], unix.PROT_NONE); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, fmt.Errorf("securemem: mprotect: %w", err)
+	}
+	if err := unix.Mlock(inner); err != nil {
+		unix.Munmap(mem)
+		return nil, nil, fmt.Errorf("securemem: mlock: %w", err)
+	}
+	return mem, inner, nil
+}
+
+// free unlocks and unmaps memory returned by alloc.
+func free(mem, inner []byte) error {
+	if err := unix.Munlock(inner); err != nil {
+		unix.Munmap(mem)
+		return fmt.Errorf("securemem: munlock: %w", err)
+	}
+	if err := unix.Munmap(mem); err != nil {
+		return fmt.Errorf("securemem: munmap: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/f3rmion/fy/frost"
+)
+
+// BatchSigningSession signs several messages in a single ceremony. Each
+// signer publishes one commitment per message in round 1, and in round 2
+// produces one signature share per message, so a whole batch costs the
+// same number of network round trips as a single signature.
+//
+// Like [SigningSession], a batch session can be used only once.
+//
+// Create batch sessions using [Participant.NewBatchSigningSession].
+type BatchSigningSession struct {
+	mu          sync.Mutex
+	frost       *frost.FROST
+	keyShare    *frost.KeyShare
+	secret      *sealed // the key share's secret, with GuardedMemory
+	messages    [][]byte
+	nonces      []*frost.SigningNonce
+	sealed      *sealed // the nonces' scalars, with GuardedMemory
+	commitments []*frost.SigningCommitment
+	consumed    bool
+	progress    *tracker
+	nonceStore  NonceStore
+}
+
+// NewBatchSigningSession creates a signing session for the given messages,
+// generating fresh nonces for each of them.
+//
+// The participant must have completed DKG before creating signing sessions.
+func (p *Participant) NewBatchSigningSession(rng io.Reader, messages [][]byte) (*BatchSigningSession, error) {
+	keyShare, secret, err := p.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("no messages to sign")
+	}
+
+	progress := newTracker(p.config)
+	progress.startRound(RoundSign1)
+
+	b := &BatchSigningSession{
+		frost:       p.frost,
+		keyShare:    keyShare,
+		secret:      secret,
+		messages:    make([][]byte, len(messages)),
+		nonces:      make([]*frost.SigningNonce, len(messages)),
+		commitments: make([]*frost.SigningCommitment, len(messages)),
+		progress:    progress,
+		nonceStore:  p.config.NonceStore,
+	}
+	for i, message := range messages {
+		nonce, commitment, err := p.signRound1(rng, keyShare, secret, message)
+		if err != nil {
+			progress.complete(err)
+			return nil, err
+		}
+		// Copy message to prevent external modification
+		b.messages[i] = append([]byte(nil), message...)
+		b.nonces[i] = nonce
+		b.commitments[i] = commitment
+	}
+	if p.config.GuardedMemory {
+		var err error
+		if b.sealed, err = sealNonces(p.group, b.nonces...); err != nil {
+			b.zeroNonces()
+			progress.complete(err)
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Len returns the number of messages in the batch.
+func (b *BatchSigningSession) Len() int {
+	return len(b.messages)
+}
+
+// Messages returns the messages being signed, in batch order.
+func (b *BatchSigningSession) Messages() [][]byte {
+	return b.messages
+}
+
+// Commitments returns this signer's commitments, one per message in batch
+// order, to be broadcast to the other signers.
+func (b *BatchSigningSession) Commitments() []*frost.SigningCommitment {
+	return b.commitments
+}
+
+// Sign produces one signature share per message.
+//
+// allCommitments[i] must hold the commitments of all participating signers
+// for message i, including this participant's own.
+//
+// This method consumes the session. After Sign returns (successfully or
+// not), the internal nonces are zeroed.
+func (b *BatchSigningSession) Sign(allCommitments [][]*frost.SigningCommitment) ([]*frost.SignatureShare, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.consumed {
+		return nil, errors.New("session already consumed: nonce reuse prevented")
+	}
+
+	// Mark as consumed immediately, before any operations that might fail
+	b.consumed = true
+	defer b.zeroNonces()
+
+	b.progress.startRound(RoundSign2)
+	shares, err := b.sign(allCommitments)
+	b.progress.complete(err)
+	return shares, err
+}
+
+// sign checks the commitment lists and computes the signature shares.
+func (b *BatchSigningSession) sign(allCommitments [][]*frost.SigningCommitment) ([]*frost.SignatureShare, error) {
+	if len(allCommitments) != len(b.messages) {
+		return nil, fmt.Errorf("expected commitments for %d messages, got %d", len(b.messages), len(allCommitments))
+	}
+
+	// Check every list before producing any share
+	for i, commitments := range allCommitments {
+		if err := b.frost.CheckCommitments(commitments); err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		if !containsCommitment(commitments, b.commitments[i]) {
+			return nil, fmt.Errorf("message %d: own commitment not found in commitment list", i)
+		}
+	}
+	for _, c := range allCommitments[0] {
+		if !c.ID.Equal(b.commitments[0].ID) {
+			b.progress.received(RoundSign1, scalarToInt(c.ID))
+		}
+	}
+
+	// Record the nonces as used before any share can leave this process
+	if b.nonceStore != nil {
+		for _, c := range b.commitments {
+			if err := b.nonceStore.MarkUsed(sessionID(c)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	keyShare, release, err := openKeyShare(b.keyShare, b.secret)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	closeNonces, err := openNonces(b.sealed, b.nonces...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeNonces()
+
+	shares := make([]*frost.SignatureShare, len(b.messages))
+	for i, commitments := range allCommitments {
+		share, err := b.frost.SignRound2(keyShare, b.nonces[i], b.messages[i], commitments)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		shares[i] = share
+	}
+	return shares, nil
+}
+
+// zeroNonces wipes the secret nonces to prevent accidental reuse.
+func (b *BatchSigningSession) zeroNonces() {
+	b.sealed.destroy()
+	b.sealed = nil
+	for _, n := range b.nonces {
+		n.Zeroize()
+	}
+	clear(b.nonces)
+	b.nonces = nil
+}
+
+// IsConsumed returns true if this session has already been used for signing.
+func (b *BatchSigningSession) IsConsumed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consumed
+}
+
+// containsCommitment reports whether list holds exactly the commitment own.
+func containsCommitment(list []*frost.SigningCommitment, own *frost.SigningCommitment) bool {
+	for _, c := range list {
+		if c.ID.Equal(own.ID) {
+			return c.HidingPoint.Equal(own.HidingPoint) && c.BindingPoint.Equal(own.BindingPoint)
+		}
+	}
+	return false
+}
+
+// AggregateBatch combines the signature shares of a batch into one
+// signature per message.
+//
+// commitments[i] and shares[i] hold the commitments and signature shares
+// of all signers for messages[i].
+func AggregateBatch(
+	f *frost.FROST,
+	messages [][]byte,
+	commitments [][]*frost.SigningCommitment,
+	shares [][]*frost.SignatureShare,
+) ([]*frost.Signature, error) {
+	if len(commitments) != len(messages) || len(shares) != len(messages) {
+		return nil, errors.New("number of commitment and share lists must match number of messages")
+	}
+
+	sigs := make([]*frost.Signature, len(messages))
+	for i, message := range messages {
+		sig, err := Aggregate(f, message, commitments[i], shares[i])
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
@@ -0,0 +1,211 @@
+package session
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"time"
+
+	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+)
+
+// NoncePolicy selects how signing nonces are generated.
+type NoncePolicy int
+
+const (
+	// NonceRandom draws signing nonces directly from the random source.
+	NonceRandom NoncePolicy = iota
+
+	// NonceHedged derives signing nonces from fresh randomness, the secret
+	// key share and the message, so that a faulty random source does not
+	// lead to nonce reuse. See [frost.FROST.SignRound1Hedged].
+	NonceHedged
+)
+
+// Config holds the parameters of a deployment. All participants and
+// coordinators of a group must use the same Hasher and Context, otherwise
+// their signature shares will not combine into a valid signature.
+//
+// Config has no encoding setting: points are encoded by the group passed
+// alongside it, for Baby Jubjub as selected by the Encoding field of
+// [github.com/f3rmion/fy/bjj.BJJ], and protocol messages by the versioned
+// format of package frost. Participants and coordinators must therefore
+// also be created with identically configured groups.
+//
+// The zero value is a valid configuration equivalent to the defaults used
+// by [NewParticipant].
+type Config struct {
+	// Hasher is the hash function used for binding factors and challenges.
+	// If nil, [frost.SHA256Hasher] is used.
+	Hasher frost.Hasher
+
+	// Context is an application-specific string bound into every hash in
+	// the signing transcript, so that signatures made for one application
+	// cannot be replayed in another. It is length-prefixed to the message
+	// inputs of the hasher, including those of the challenge H2 and the
+	// message hash H4, so with a non-empty Context the signature covers
+	// the bound message rather than the message itself: it does not
+	// verify under plain Schnorr, EVM or iden3 verifiers given only the
+	// message. Verify with a FROST instance from [Config.NewFROST] with
+	// the same Context.
+	//
+	// Context cannot be combined with [bjj.Iden3Hasher], which only signs
+	// single field elements.
+	Context string
+
+	// NoncePolicy selects how signing nonces are generated.
+	NoncePolicy NoncePolicy
+
+	// NonceStore, if set, records every signing session before it signs,
+	// so that nonces cannot be reused even across process restarts.
+	NonceStore NonceStore
+
+	// Timeout is the maximum duration of each round. When it elapses with
+	// messages outstanding, CheckTimeout and the round-completing methods
+	// fail the ceremony with a [*TimeoutError]. Zero disables timeouts.
+	Timeout time.Duration
+
+	// EchoBroadcast requires every participant to confirm, via an echo
+	// digest, that it received the same round 1 broadcasts before the DKG
+	// completes. This protects against a dealer that sends different
+	// commitments to different participants. See [Participant.EchoDigest].
+	EchoBroadcast bool
+
+	// Selector chooses which signers a [RetryCoordinator] invites. If nil,
+	// [LowestIDSelector] is used.
+	Selector SignerSelector
+
+	// Observer, if set, is notified of ceremony progress.
+	Observer Observer
+
+	// Metrics, if set, receives instrumentation events.
+	Metrics Metrics
+
+	// GuardedMemory keeps the secret of the key share and the signing
+	// nonces in guarded memory while they are idle: outside the Go heap,
+	// locked into RAM, fenced by guard pages and checked with a canary.
+	// They are copied to the heap only for the operations that use them
+	// and wiped right after. It is meant for signer daemons that hold key
+	// shares for a long time. The key share in [DKGResult] still holds the
+	// secret in ordinary memory so that it can be stored; wipe it once it
+	// has been. Completing the DKG, [Participant.SetKeyShare] and creating
+	// signing sessions fail if the memory cannot be locked, for example
+	// because RLIMIT_MEMLOCK is too low.
+	GuardedMemory bool
+
+	// RequireConstantTime makes [NewParticipantWithConfig] fail unless the
+	// group and hasher declare that every computation on secrets runs in
+	// constant time. See [frost.FROST.CheckConstantTime].
+	RequireConstantTime bool
+}
+
+// NewFROST creates a FROST instance configured with the hasher and
+// context of c. Coordinators and verifiers should use it to obtain an
+// instance that matches the participants'.
+func (c *Config) NewFROST(g group.Group, threshold, total int) (*frost.FROST, error) {
+	if c != nil && c.Context != "" && isIden3(c.Hasher) {
+		return nil, errors.New("session: Context cannot be used with bjj.Iden3Hasher")
+	}
+	return frost.NewWithHasher(g, threshold, total, c.hasher())
+}
+
+// isIden3 reports whether h is a [bjj.Iden3Hasher], possibly wrapped in a
+// [frost.HKDFNonceHasher].
+func isIden3(h frost.Hasher) bool {
+	if w, ok := h.(*frost.HKDFNonceHasher); ok {
+		h = w.Hasher
+	}
+	_, ok := h.(*bjj.Iden3Hasher)
+	return ok
+}
+
+// hasher returns the configured hasher, wrapped to bind the context.
+func (c *Config) hasher() frost.Hasher {
+	var h frost.Hasher = &frost.SHA256Hasher{}
+	if c != nil && c.Hasher != nil {
+		h = c.Hasher
+	}
+	if c == nil || c.Context == "" {
+		return h
+	}
+	return &contextHasher{inner: h, context: []byte(c.Context)}
+}
+
+// contextHasher binds an application context into the message inputs of
+// another hasher. The context is length-prefixed so that it cannot be
+// confused with the message.
+type contextHasher struct {
+	inner   frost.Hasher
+	context []byte
+}
+
+// bind returns len(context) || context || msg.
+func (h *contextHasher) bind(msg []byte) []byte {
+	out := make([]byte, 0, 8+len(h.context)+len(msg))
+	out = binary.BigEndian.AppendUint64(out, uint64(len(h.context)))
+	out = append(out, h.context...)
+	return append(out, msg...)
+}
+
+// H1 implements frost.Hasher.
+func (h *contextHasher) H1(g group.Group, msg, encCommitList, signerID []byte) group.Scalar {
+	return h.inner.H1(g, h.bind(msg), encCommitList, signerID)
+}
+
+// H2 implements frost.Hasher.
+func (h *contextHasher) H2(g group.Group, R, Y, msg []byte) group.Scalar {
+	return h.inner.H2(g, R, Y, h.bind(msg))
+}
+
+// H3 implements frost.Hasher.
+func (h *contextHasher) H3(g group.Group, seed, rho, msg []byte) group.Scalar {
+	return h.inner.H3(g, seed, rho, h.bind(msg))
+}
+
+// H4 implements frost.Hasher.
+func (h *contextHasher) H4(g group.Group, msg []byte) []byte {
+	return h.inner.H4(g, h.bind(msg))
+}
+
+// H5 implements frost.Hasher.
+func (h *contextHasher) H5(g group.Group, encCommitList []byte) []byte {
+	return h.inner.H5(g, encCommitList)
+}
+
+// Capabilities implements frost.HasherCapabilityReporter. Binding the
+// context only processes public data, so the inner hasher's guarantees
+// carry over.
+func (h *contextHasher) Capabilities() frost.HasherCapabilities {
+	return frost.HasherCapabilitiesOf(h.inner)
+}
+
+// Validate implements frost.HasherValidator for the inner hasher.
+func (h *contextHasher) Validate() error {
+	return frost.ValidateHasher(h.inner)
+}
+
+// ValidateMessage implements frost.MessageValidator for the inner hasher.
+func (h *contextHasher) ValidateMessage(g group.Group, msg []byte) error {
+	return frost.ValidateMessage(h.inner, g, h.bind(msg))
+}
+
+// Challenge implements frost.MessageValidator for the inner hasher.
+func (h *contextHasher) Challenge(g group.Group, R, Y, msg []byte) (group.Scalar, error) {
+	return frost.Challenge(h.inner, g, R, Y, h.bind(msg))
+}
+
+// NewH4 implements frost.StreamHasher if the inner hasher does.
+func (h *contextHasher) NewH4(g group.Group) (hash.Hash, error) {
+	s, ok := h.inner.(frost.StreamHasher)
+	if !ok {
+		return nil, errors.New("session: hasher cannot stream messages")
+	}
+	d, err := s.NewH4(g)
+	if err != nil {
+		return nil, err
+	}
+	d.Write(h.bind(nil))
+	return d, nil
+}
@@ -0,0 +1,254 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/f3rmion/fy/frost"
+)
+
+// Coordinator drives a single signing ceremony on behalf of a set of
+// signers. It collects each signer's commitment, hands out the complete
+// commitment list, then collects the signature shares and aggregates them.
+//
+// Like the rest of this package, Coordinator does not perform any network
+// communication; the application delivers messages to it as they arrive.
+// A Coordinator is safe for concurrent use.
+type Coordinator struct {
+	mu          sync.Mutex
+	frost       *frost.FROST
+	message     []byte
+	signers     []int
+	commitments map[int]*frost.SigningCommitment
+	shares      map[int]*frost.SignatureShare
+	progress    *tracker
+}
+
+// NewCoordinator creates a coordinator for signing message with the given
+// signers. The signer IDs must be distinct, and at least the threshold of
+// f must be given.
+func NewCoordinator(f *frost.FROST, message []byte, signerIDs []int) (*Coordinator, error) {
+	return NewCoordinatorWithConfig(f, message, signerIDs, nil)
+}
+
+// NewCoordinatorWithConfig creates a coordinator using the ceremony
+// parameters in cfg. The FROST instance f should be obtained from
+// [Config.NewFROST] so that its hasher matches the participants'.
+func NewCoordinatorWithConfig(f *frost.FROST, message []byte, signerIDs []int, cfg *Config) (*Coordinator, error) {
+	if len(signerIDs) < 2 {
+		return nil, errors.New("at least two signers are required")
+	}
+	if len(signerIDs) < f.Threshold() {
+		return nil, &frost.InsufficientSignersError{Signers: len(signerIDs), Threshold: f.Threshold()}
+	}
+	signers := slices.Clone(signerIDs)
+	slices.Sort(signers)
+	if len(slices.Compact(slices.Clone(signers))) != len(signers) {
+		return nil, errors.New("duplicate signer ID")
+	}
+
+	// Copy message to prevent external modification
+	msgCopy := make([]byte, len(message))
+	copy(msgCopy, message)
+
+	c := &Coordinator{
+		frost:       f,
+		message:     msgCopy,
+		signers:     signers,
+		commitments: make(map[int]*frost.SigningCommitment),
+		shares:      make(map[int]*frost.SignatureShare),
+		progress:    newTracker(cfg),
+	}
+	c.progress.startRound(RoundSign1)
+	return c, nil
+}
+
+// Message returns the message being signed.
+func (c *Coordinator) Message() []byte {
+	return c.message
+}
+
+// Signers returns the IDs of the signers taking part, in ascending order.
+func (c *Coordinator) Signers() []int {
+	return slices.Clone(c.signers)
+}
+
+// AddCommitment records a signer's round 1 commitment. Once commitments
+// from all signers have been received, the ceremony moves to round 2 and
+// [Coordinator.Commitments] returns the complete list.
+func (c *Coordinator) AddCommitment(commitment *frost.SigningCommitment) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if c.progress.round != RoundSign1 {
+		return errors.New("commitment phase is over")
+	}
+	if commitment == nil || commitment.ID == nil || commitment.HidingPoint == nil || commitment.BindingPoint == nil {
+		return errors.New("incomplete commitment")
+	}
+	id := scalarToInt(commitment.ID)
+	if !slices.Contains(c.signers, id) {
+		return fmt.Errorf("participant %d is not a signer in this session", id)
+	}
+	if _, exists := c.commitments[id]; exists {
+		return fmt.Errorf("duplicate commitment from participant %d", id)
+	}
+	c.commitments[id] = commitment
+	c.progress.received(RoundSign1, id)
+
+	if len(c.commitments) == len(c.signers) {
+		c.progress.startRound(RoundSign2)
+	}
+	return nil
+}
+
+// Commitments returns the commitments of all signers, ordered by ID, to be
+// sent to every signer for round 2. It returns an error until all
+// commitments have been received, and once the ceremony has failed.
+func (c *Coordinator) Commitments() ([]*frost.SigningCommitment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.progress.done && c.progress.err != nil {
+		return nil, c.checkOpen()
+	}
+	if err := c.checkTimeout(); err != nil {
+		return nil, err
+	}
+	if len(c.commitments) != len(c.signers) {
+		return nil, fmt.Errorf("waiting for commitments from participants %v", c.missing(RoundSign1))
+	}
+	return c.commitmentList(), nil
+}
+
+// AddShare records a signer's signature share. Shares are accepted only
+// after all commitments have been received.
+func (c *Coordinator) AddShare(share *frost.SignatureShare) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if c.progress.round != RoundSign2 {
+		return errors.New("not accepting signature shares")
+	}
+	if share == nil || share.ID == nil || share.Z == nil {
+		return errors.New("incomplete signature share")
+	}
+	id := scalarToInt(share.ID)
+	if !slices.Contains(c.signers, id) {
+		return fmt.Errorf("participant %d is not a signer in this session", id)
+	}
+	if _, exists := c.shares[id]; exists {
+		return fmt.Errorf("duplicate signature share from participant %d", id)
+	}
+	c.shares[id] = share
+	c.progress.received(RoundSign2, id)
+	return nil
+}
+
+// Aggregate combines the collected signature shares into a signature.
+// All signers must have delivered their shares.
+func (c *Coordinator) Aggregate() (*frost.Signature, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := c.checkTimeout(); err != nil {
+		return nil, err
+	}
+	if c.progress.round != RoundSign2 || len(c.shares) != len(c.signers) {
+		return nil, fmt.Errorf("waiting for signature shares from participants %v", c.missing(RoundSign2))
+	}
+
+	shares := make([]*frost.SignatureShare, 0, len(c.signers))
+	for _, id := range c.signers {
+		shares = append(shares, c.shares[id])
+	}
+	sig, err := Aggregate(c.frost, c.message, c.commitmentList(), shares)
+	c.progress.complete(err)
+	return sig, err
+}
+
+// Status reports the progress of the signing ceremony. Contributed and
+// Pending partition the signers by whether their message for the current
+// round has been received.
+func (c *Coordinator) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var contributed, pending []int
+	if !c.progress.done {
+		for _, id := range c.signers {
+			if c.hasContributed(c.progress.round, id) {
+				contributed = append(contributed, id)
+			} else {
+				pending = append(pending, id)
+			}
+		}
+	}
+	return c.progress.status(contributed, pending)
+}
+
+// Progress returns the fraction of messages received for the current
+// round. See [Status.Progress].
+func (c *Coordinator) Progress() float64 {
+	return c.Status().Progress()
+}
+
+// checkOpen returns an error if the ceremony is over, wrapping the error
+// that failed it, such as a [*TimeoutError].
+func (c *Coordinator) checkOpen() error {
+	if !c.progress.done {
+		return nil
+	}
+	if c.progress.err != nil {
+		return fmt.Errorf("signing ceremony failed: %w", c.progress.err)
+	}
+	return errors.New("signing ceremony already complete")
+}
+
+// commitmentList returns the received commitments ordered by signer ID.
+func (c *Coordinator) commitmentList() []*frost.SigningCommitment {
+	list := make([]*frost.SigningCommitment, 0, len(c.commitments))
+	for _, id := range c.signers {
+		if comm, ok := c.commitments[id]; ok {
+			list = append(list, comm)
+		}
+	}
+	return list
+}
+
+// hasContributed reports whether signer id delivered its message for round r.
+func (c *Coordinator) hasContributed(r Round, id int) bool {
+	switch r {
+	case RoundSign1:
+		_, ok := c.commitments[id]
+		return ok
+	case RoundSign2:
+		_, ok := c.shares[id]
+		return ok
+	default:
+		return false
+	}
+}
+
+// missing returns the signers that have not delivered their message for
+// round r, in ascending order.
+func (c *Coordinator) missing(r Round) []int {
+	var ids []int
+	for _, id := range c.signers {
+		if !c.hasContributed(r, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
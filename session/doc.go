@@ -35,6 +35,15 @@
 //
 //	// Store result.KeyShare securely
 //
+// A malicious participant could send different broadcasts to different
+// peers. To detect this, deliver the broadcasts with
+// [Participant.ReceiveBroadcast], exchange the digests returned by
+// [Participant.EchoDigest], and pass them in Round1Input.Echoes; set
+// [Config].EchoBroadcast to make the check mandatory.
+//
+// For tests and single-machine setups, [QuickDKG] runs the whole ceremony
+// in-process and returns every key share along with the group [Roster].
+//
 // # Signing
 //
 // Signing uses a session-based API that ensures nonces are never reused:
@@ -61,6 +70,79 @@
 // second time returns an error, preventing accidental nonce reuse which
 // would compromise security.
 //
+// A session can be handed to another process holding the same key share
+// with [SigningSession.Export] and [Participant.ImportSigningSession].
+// Exporting consumes the local session, so only the importer can sign.
+// Both need a [Config].NonceStore, which records each import so that the
+// same export cannot be imported twice.
+//
+// The consumed flag lives in memory. To keep nonces single-use across
+// restarts and snapshots, set [Config].NonceStore to a durable store such
+// as one opened with [OpenFileNonceStore]; every session is recorded there
+// before it produces a signature share.
+//
+// To sign many messages at once, [Participant.NewBatchSigningSession]
+// exchanges one commitment per message in a single round and returns one
+// share per message; [AggregateBatch] combines them into signatures.
+//
+// A [Coordinator] can collect commitments and shares on behalf of the
+// signers and aggregate them once every signer has contributed.
+//
+// When more than threshold signers are available, a [RetryCoordinator]
+// tolerates signers that stall or misbehave. It signs through the [Signer]
+// interface, excludes any signer that misses the [Config].Timeout or sends
+// an invalid share, and retries with another subset and fresh nonces:
+//
+//	rc, err := session.NewRetryCoordinator(f, roster, signers, cfg)
+//	sig, err := rc.Sign(ctx, message)
+//
+// Which signers are invited is decided by the [SignerSelector] in
+// [Config].Selector, for example [RoundRobinSelector] to spread load or
+// [LatencySelector] to prefer the fastest signers.
+//
+// # Configuration
+//
+// [Config] gathers the parameters every member of a deployment must agree
+// on, such as the hasher and an application context string bound into all
+// signatures, together with local options like the nonce policy:
+//
+//	cfg := &session.Config{
+//		Hasher:      frost.NewBlake2bHasher(),
+//		Context:     "example.com/payments",
+//		NoncePolicy: session.NonceHedged,
+//	}
+//	p, err := session.NewParticipantWithConfig(group, threshold, total, myID, cfg)
+//
+// Coordinators and verifiers obtain a matching FROST instance from
+// [Config.NewFROST].
+//
+// Signer daemons that hold a key share for a long time can set
+// [Config].GuardedMemory to keep the secret and the signing nonces in
+// locked, guard-paged memory between uses.
+//
+// # Observing Progress
+//
+// [Participant.Status] and [Coordinator.Status] report the current round,
+// which peers have and have not contributed to it, and the elapsed time,
+// so operators can see who is blocking a stuck ceremony. To report
+// progress during DKG, deliver messages one at a time with
+// [Participant.ReceiveBroadcast] and [Participant.ReceivePrivateShare]
+// before calling ProcessRound1.
+//
+// Set [Config].Timeout to bound each round. Once a round's deadline passes,
+// CheckTimeout (and the methods that complete a round) fail the ceremony
+// with a [*TimeoutError] naming the participants that did not deliver.
+//
+// Register an [Observer] with [Participant.SetObserver] to be notified when
+// rounds start, messages arrive and ceremonies complete:
+//
+//	p.SetObserver(myObserver)
+//
+// Embed [NopObserver] in your type to implement only the callbacks you need.
+// For monitoring, [Participant.SetMetrics] accepts a [Metrics] sink that
+// counts ceremony outcomes, records round latency and attributes
+// verification failures to participants.
+//
 // # Transport Agnostic
 //
 // This package does not handle network communication. You are responsible
@@ -0,0 +1,176 @@
+package session
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/f3rmion/fy/frost"
+)
+
+// echoDomain separates echo digests from other uses of SHA-256.
+const echoDomain = "FROST-DKG-ECHO-v1"
+
+// EchoMismatchError is returned by [Participant.ProcessRound1] when another
+// participant reports a different set of round 1 broadcasts than this
+// participant received. This means some dealer equivocated, sending
+// different commitments to different participants; completing the DKG
+// would split the group key, so the ceremony is aborted.
+// [CheckTranscriptDigests] returns it for digests compared after the DKG.
+type EchoMismatchError struct {
+	// Mismatched lists the participants whose echo digest differs from
+	// this participant's.
+	Mismatched []int
+}
+
+// Error implements the error interface.
+func (e *EchoMismatchError) Error() string {
+	return fmt.Sprintf("round 1 broadcasts differ from those seen by participants %v", e.Mismatched)
+}
+
+// EchoDigest returns a digest of all round 1 broadcasts this participant
+// has received, including its own. Broadcast the digest to every other
+// participant, who pass it to [Participant.ReceiveEcho] or include it in
+// [Round1Input].Echoes; ProcessRound1 then checks that everyone saw the
+// same broadcasts.
+//
+// All broadcasts must have been delivered with [Participant.ReceiveBroadcast]
+// before calling EchoDigest.
+func (p *Participant) EchoDigest() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkReceiving(); err != nil {
+		return nil, err
+	}
+	return p.echoDigest()
+}
+
+// ReceiveEcho records the echo digest sent by participant from.
+// Receiving the same digest twice is harmless; a conflicting digest from
+// the same sender is rejected.
+func (p *Participant) ReceiveEcho(from int, digest []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkReceiving(); err != nil {
+		return err
+	}
+	return p.receiveEcho(from, digest)
+}
+
+// receiveEcho stores digest, rejecting unknown and conflicting senders.
+func (p *Participant) receiveEcho(from int, digest []byte) error {
+	if from == p.id || !slices.Contains(p.roster, from) {
+		return fmt.Errorf("echo from participant %d not expected", from)
+	}
+	if prev, exists := p.echoes[from]; exists {
+		if !bytes.Equal(prev, digest) {
+			return fmt.Errorf("duplicate echo from participant %d", from)
+		}
+		return nil
+	}
+	p.echoes[from] = bytes.Clone(digest)
+	return nil
+}
+
+// echoDigest hashes the broadcasts of every roster member in ID order.
+func (p *Participant) echoDigest() ([]byte, error) {
+	var missing []int
+	for _, id := range p.roster {
+		if _, ok := p.broadcasts[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("waiting for broadcasts from participants %v", missing)
+	}
+
+	broadcasts := make([]*frost.Round1Data, 0, len(p.broadcasts))
+	for _, id := range slices.Sorted(maps.Keys(p.broadcasts)) {
+		broadcasts = append(broadcasts, p.broadcasts[id])
+	}
+	return broadcastDigest(broadcasts), nil
+}
+
+// checkEchoes compares the received echo digests with this participant's
+// own view. Without [Config].EchoBroadcast, only the echoes actually
+// received are checked.
+func (p *Participant) checkEchoes() error {
+	if p.config.EchoBroadcast {
+		var missing []int
+		for _, id := range p.roster {
+			if _, ok := p.echoes[id]; !ok && id != p.id {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			slices.Sort(missing)
+			return fmt.Errorf("missing echo digests from participants %v", missing)
+		}
+	}
+	if len(p.echoes) == 0 {
+		return nil
+	}
+
+	own, err := p.echoDigest()
+	if err != nil {
+		return err
+	}
+	var mismatched []int
+	for _, id := range slices.Sorted(maps.Keys(p.echoes)) {
+		if !bytes.Equal(p.echoes[id], own) {
+			mismatched = append(mismatched, id)
+		}
+	}
+	if len(mismatched) > 0 {
+		return &EchoMismatchError{Mismatched: mismatched}
+	}
+	return nil
+}
+
+// CheckTranscriptDigests compares this participant's DKG transcript
+// digest with the digests reported by other participants, keyed by ID,
+// for example over a channel independent of the one used for the DKG.
+// It returns an *[EchoMismatchError] listing the participants that
+// finished the DKG with a different transcript; their group keys or key
+// shares may be inconsistent with this participant's, so the group must
+// not be used for signing.
+func CheckTranscriptDigests(result *DKGResult, digests map[int][]byte) error {
+	var mismatched []int
+	for _, id := range slices.Sorted(maps.Keys(digests)) {
+		if !bytes.Equal(digests[id], result.TranscriptDigest) {
+			mismatched = append(mismatched, id)
+		}
+	}
+	if len(mismatched) > 0 {
+		return &EchoMismatchError{Mismatched: mismatched}
+	}
+	return nil
+}
+
+// broadcastDigest returns SHA-256 over an unambiguous encoding of
+// broadcasts, which must be sorted by ID.
+func broadcastDigest(broadcasts []*frost.Round1Data) []byte {
+	h := sha256.New()
+	h.Write([]byte(echoDomain))
+	var buf [4]byte
+	write := func(b []byte) {
+		binary.BigEndian.PutUint32(buf[:], uint32(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+	for _, b := range broadcasts {
+		write(b.ID.Bytes())
+		binary.BigEndian.PutUint32(buf[:], uint32(len(b.Commitments)))
+		h.Write(buf[:])
+		for _, c := range b.Commitments {
+			write(c.Bytes())
+		}
+	}
+	return h.Sum(nil)
+}
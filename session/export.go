@@ -0,0 +1,201 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
+)
+
+// exportVersion is the format version of exported signing sessions.
+const exportVersion = 1
+
+// importPrefix marks the [NonceStore] records of imported sessions, which
+// are kept apart from the records [SigningSession.Sign] makes.
+const importPrefix = "import:"
+
+// errNoNonceStore is returned when a session would be exported or
+// imported without a [NonceStore] to record it.
+var errNoNonceStore = errors.New("exporting or importing a signing session requires a NonceStore")
+
+// sessionIDDomain separates session IDs from other uses of SHA-256.
+const sessionIDDomain = "FROST-SESSION-ID-v1"
+
+// ID returns an identifier for the session, derived from its commitment.
+// It is stable across [SigningSession.Export] and
+// [Participant.ImportSigningSession], so it can be used to correlate the
+// session between processes.
+func (s *SigningSession) ID() string {
+	return sessionID(s.commitment)
+}
+
+// sessionID hashes the participant ID and nonce commitments.
+func sessionID(c *frost.SigningCommitment) string {
+	h := sha256.New()
+	h.Write([]byte(sessionIDDomain))
+	h.Write(c.ID.Bytes())
+	h.Write(c.HidingPoint.Bytes())
+	h.Write(c.BindingPoint.Bytes())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Export serializes the session, including its secret nonces, so that
+// another process holding the same key share can complete it with
+// [Participant.ImportSigningSession].
+//
+// Export hands the session over: this session is consumed and its nonces
+// are zeroed, so it cannot also be used to sign here. The exported data
+// contains secret nonces and must be protected like a key share while in
+// transit.
+//
+// Export returns an error if the participant has no [Config].NonceStore,
+// since nothing else would stop the export from being imported twice.
+func (s *SigningSession) Export() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nonceStore == nil {
+		return nil, errNoNonceStore
+	}
+	if s.consumed {
+		return nil, errors.New("session already consumed: nonce reuse prevented")
+	}
+	s.consumed = true
+	defer s.zeroNonces()
+
+	closeNonce, err := openNonces(s.sealed, s.nonce)
+	if err != nil {
+		return nil, err
+	}
+	defer closeNonce()
+	d, e := s.nonce.D.Bytes(), s.nonce.E.Bytes()
+	defer memwipe.Bytes(d, e)
+
+	var out []byte
+	out = append(out, exportVersion)
+	for _, field := range [][]byte{
+		s.commitment.ID.Bytes(),
+		s.message,
+		d,
+		e,
+		s.commitment.HidingPoint.Bytes(),
+		s.commitment.BindingPoint.Bytes(),
+	} {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(field)))
+		out = append(out, field...)
+	}
+
+	s.progress.complete(nil)
+	return out, nil
+}
+
+// ImportSigningSession restores a session exported with
+// [SigningSession.Export]. The session must belong to this participant,
+// which must hold the same key share as the exporting process.
+//
+// The participant must have a [Config].NonceStore, shared with or as
+// durable as the exporting process's. The import is recorded there, and
+// importing the same export again returns [ErrNonceReused].
+func (p *Participant) ImportSigningSession(data []byte) (*SigningSession, error) {
+	if p.config.NonceStore == nil {
+		return nil, errNoNonceStore
+	}
+	keyShare, secret, err := p.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || data[0] != exportVersion {
+		return nil, errors.New("unsupported session export format")
+	}
+
+	fields := make([][]byte, 6)
+	rest := data[1:]
+	for i := range fields {
+		if len(rest) < 4 {
+			return nil, errors.New("truncated session export")
+		}
+		n := binary.BigEndian.Uint32(rest)
+		rest = rest[4:]
+		if uint64(len(rest)) < uint64(n) {
+			return nil, errors.New("truncated session export")
+		}
+		fields[i], rest = rest[:n], rest[n:]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data in session export")
+	}
+
+	g := p.group
+	// Every field except the message has the group's fixed encoding size
+	sizes := []int{g.ScalarSize(), len(fields[1]), g.ScalarSize(), g.ScalarSize(), g.ElementSize(), g.ElementSize()}
+	for i, size := range sizes {
+		if len(fields[i]) != size {
+			return nil, fmt.Errorf("session export field %d has length %d, want %d", i, len(fields[i]), size)
+		}
+	}
+
+	id, err := g.NewScalar().SetCanonicalBytes(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid participant ID: %w", err)
+	}
+	if !id.Equal(keyShare.ID) {
+		return nil, fmt.Errorf("session belongs to participant %d, not %d", scalarToInt(id), p.id)
+	}
+	d, err := g.NewScalar().SetCanonicalBytes(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hiding nonce: %w", err)
+	}
+	e, err := g.NewScalar().SetCanonicalBytes(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid binding nonce: %w", err)
+	}
+	hiding, err := g.NewPoint().SetBytes(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hiding commitment: %w", err)
+	}
+	binding, err := g.NewPoint().SetBytes(fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid binding commitment: %w", err)
+	}
+	if !commits(g, d, hiding) || !commits(g, e, binding) {
+		return nil, errors.New("nonces do not match commitment")
+	}
+
+	nonce := &frost.SigningNonce{ID: id, D: d, E: e}
+	commitment := &frost.SigningCommitment{ID: id, HidingPoint: hiding, BindingPoint: binding}
+	if err := p.config.NonceStore.MarkUsed(importPrefix + sessionID(commitment)); err != nil {
+		nonce.Zeroize()
+		return nil, err
+	}
+	var sealedNonce *sealed
+	if p.config.GuardedMemory {
+		if sealedNonce, err = sealNonces(g, nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	progress := newTracker(p.config)
+	progress.startRound(RoundSign1)
+
+	return &SigningSession{
+		frost:      p.frost,
+		keyShare:   keyShare,
+		secret:     secret,
+		message:    append([]byte(nil), fields[1]...),
+		nonce:      nonce,
+		sealed:     sealedNonce,
+		commitment: commitment,
+		progress:   progress,
+		nonceStore: p.config.NonceStore,
+	}, nil
+}
+
+// commits reports whether point equals nonce times the generator.
+func commits(g group.Group, nonce group.Scalar, point group.Point) bool {
+	return g.ScalarBaseMult(nonce).Equal(point)
+}
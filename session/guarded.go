@@ -0,0 +1,134 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/internal/memwipe"
+	"github.com/f3rmion/fy/internal/securemem"
+)
+
+// sealed holds scalars in guarded memory while they are not in use. See
+// [Config].GuardedMemory.
+type sealed struct {
+	g   group.Group
+	n   int
+	buf *securemem.Buffer
+}
+
+// seal copies scalars into a new guarded buffer and wipes the originals.
+func seal(g group.Group, scalars ...group.Scalar) (*sealed, error) {
+	size := g.ScalarSize()
+	buf, err := securemem.New(len(scalars) * size)
+	if err != nil {
+		return nil, fmt.Errorf("guarded memory: %w", err)
+	}
+	mem := buf.Bytes()
+	for i, s := range scalars {
+		enc := s.Bytes()
+		copy(mem[i*size:], enc)
+		memwipe.Bytes(enc)
+	}
+	memwipe.Scalars(scalars...)
+	s := &sealed{g: g, n: len(scalars), buf: buf}
+	// Release the memory of sealed values that are dropped without destroy
+	runtime.AddCleanup(s, func(buf *securemem.Buffer) { buf.Destroy() }, buf)
+	return s, nil
+}
+
+// open returns heap copies of the sealed scalars. The caller must wipe
+// them with memwipe.Scalars as soon as they have been used.
+func (s *sealed) open() ([]group.Scalar, error) {
+	mem := s.buf.Bytes()
+	if mem == nil {
+		return nil, errors.New("guarded memory has been released")
+	}
+	if !s.buf.Intact() {
+		return nil, errors.New("guarded memory is corrupted")
+	}
+	size := s.g.ScalarSize()
+	out := make([]group.Scalar, s.n)
+	for i := range out {
+		v, err := s.g.NewScalar().SetCanonicalBytes(mem[i*size : (i+1)*size])
+		if err != nil {
+			memwipe.Scalars(out...)
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// destroy wipes and releases the guarded memory. It is a no-op on nil.
+func (s *sealed) destroy() {
+	if s != nil {
+		s.buf.Destroy()
+	}
+}
+
+// publicKeyShare returns a copy of ks without its secret, as kept by a
+// participant whose secret is sealed.
+func publicKeyShare(ks *frost.KeyShare) *frost.KeyShare {
+	return &frost.KeyShare{
+		ID:               ks.ID,
+		PublicKey:        ks.PublicKey,
+		GroupKey:         ks.GroupKey,
+		TranscriptDigest: ks.TranscriptDigest,
+	}
+}
+
+// openKeyShare returns the key share to sign with, opening its secret if
+// it is sealed, and a function that wipes the opened copy.
+func openKeyShare(ks *frost.KeyShare, secret *sealed) (*frost.KeyShare, func(), error) {
+	if secret == nil {
+		return ks, func() {}, nil
+	}
+	scalars, err := secret.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	opened := publicKeyShare(ks)
+	opened.SecretKey = scalars[0]
+	return opened, opened.Zeroize, nil
+}
+
+// sealNonces moves the secret nonces into guarded memory, in the order
+// D and E of each nonce, and removes them from the nonces.
+func sealNonces(g group.Group, nonces ...*frost.SigningNonce) (*sealed, error) {
+	scalars := make([]group.Scalar, 0, 2*len(nonces))
+	for _, n := range nonces {
+		scalars = append(scalars, n.D, n.E)
+	}
+	s, err := seal(g, scalars...)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nonces {
+		n.D, n.E = nil, nil
+	}
+	return s, nil
+}
+
+// openNonces restores the nonces sealed by sealNonces into nonces, which
+// must be the same values, and returns a function that wipes them again.
+func openNonces(s *sealed, nonces ...*frost.SigningNonce) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	scalars, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range nonces {
+		n.D, n.E = scalars[2*i], scalars[2*i+1]
+	}
+	return func() {
+		memwipe.Scalars(scalars...)
+		for _, n := range nonces {
+			n.D, n.E = nil, nil
+		}
+	}, nil
+}
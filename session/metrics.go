@@ -0,0 +1,156 @@
+package session
+
+import "time"
+
+// Metrics receives instrumentation events from participants and signing
+// sessions. It is designed to map directly onto counter and histogram
+// primitives of monitoring systems such as Prometheus:
+//
+//	type promMetrics struct {
+//		started   *prometheus.CounterVec   // labels: ceremony
+//		completed *prometheus.CounterVec   // labels: ceremony
+//		failed    *prometheus.CounterVec   // labels: ceremony
+//		latency   *prometheus.HistogramVec // labels: round
+//		badShares *prometheus.CounterVec   // labels: participant
+//	}
+//
+//	func (m *promMetrics) CeremonyStarted(c session.Ceremony) {
+//		m.started.WithLabelValues(c.String()).Inc()
+//	}
+//
+// Implementations must be safe for concurrent use, since several sessions
+// may report at the same time. Embed [NopMetrics] to implement only the
+// methods of interest.
+type Metrics interface {
+	// CeremonyStarted is called when a participant begins a ceremony.
+	CeremonyStarted(ceremony Ceremony)
+
+	// CeremonyCompleted is called when a ceremony finishes successfully.
+	CeremonyCompleted(ceremony Ceremony)
+
+	// CeremonyFailed is called when a ceremony finishes with an error.
+	CeremonyFailed(ceremony Ceremony)
+
+	// ObserveRoundLatency records the wall-clock time spent in a round,
+	// measured from the start of the round to the start of the next one
+	// (or to completion for the final round). This includes time spent
+	// waiting for other participants.
+	ObserveRoundLatency(round Round, d time.Duration)
+
+	// VerificationFailure is called when a message from the given
+	// participant fails cryptographic verification.
+	VerificationFailure(participantID int)
+}
+
+// NopMetrics is a [Metrics] implementation that discards all events.
+type NopMetrics struct{}
+
+// CeremonyStarted implements Metrics.
+func (NopMetrics) CeremonyStarted(Ceremony) {}
+
+// CeremonyCompleted implements Metrics.
+func (NopMetrics) CeremonyCompleted(Ceremony) {}
+
+// CeremonyFailed implements Metrics.
+func (NopMetrics) CeremonyFailed(Ceremony) {}
+
+// ObserveRoundLatency implements Metrics.
+func (NopMetrics) ObserveRoundLatency(Round, time.Duration) {}
+
+// VerificationFailure implements Metrics.
+func (NopMetrics) VerificationFailure(int) {}
+
+// tracker follows a single ceremony through its rounds and reports
+// progress to the registered observer and metrics.
+type tracker struct {
+	observer     Observer
+	metrics      Metrics
+	timeout      time.Duration
+	round        Round
+	started      time.Time
+	roundStarted time.Time
+	finished     time.Time
+	done         bool
+	err          error
+}
+
+// newTracker returns a tracker reporting to the observer and metrics in
+// cfg, which may be nil. Missing hooks are replaced with no-op
+// implementations.
+func newTracker(cfg *Config) *tracker {
+	t := &tracker{observer: NopObserver{}, metrics: NopMetrics{}}
+	if cfg != nil {
+		if cfg.Observer != nil {
+			t.observer = cfg.Observer
+		}
+		if cfg.Metrics != nil {
+			t.metrics = cfg.Metrics
+		}
+		t.timeout = cfg.Timeout
+	}
+	return t
+}
+
+// startRound marks the beginning of round r. The first round also marks
+// the start of the ceremony.
+func (t *tracker) startRound(r Round) {
+	now := time.Now()
+	if t.round == 0 {
+		t.started = now
+		t.metrics.CeremonyStarted(r.Ceremony())
+	} else {
+		t.metrics.ObserveRoundLatency(t.round, now.Sub(t.roundStarted))
+	}
+	t.round = r
+	t.roundStarted = now
+	t.observer.OnRoundStart(r)
+}
+
+// received reports a message from participant from produced in round r.
+func (t *tracker) received(r Round, from int) {
+	t.observer.OnMessageReceived(r, from)
+}
+
+// verificationFailed reports a message from participant from that failed
+// verification.
+func (t *tracker) verificationFailed(from int) {
+	t.metrics.VerificationFailure(from)
+}
+
+// complete marks the end of the ceremony with the given outcome.
+func (t *tracker) complete(err error) {
+	if t.done {
+		return
+	}
+	t.done = true
+	t.err = err
+	t.finished = time.Now()
+	ceremony := t.round.Ceremony()
+	t.metrics.ObserveRoundLatency(t.round, t.finished.Sub(t.roundStarted))
+	if err != nil {
+		t.metrics.CeremonyFailed(ceremony)
+	} else {
+		t.metrics.CeremonyCompleted(ceremony)
+	}
+	t.observer.OnComplete(ceremony, err)
+}
+
+// status returns the ceremony status with the given contribution lists.
+func (t *tracker) status(contributed, pending []int) Status {
+	st := Status{
+		Ceremony:    t.round.Ceremony(),
+		Round:       t.round,
+		Done:        t.done,
+		Err:         t.err,
+		Contributed: contributed,
+		Pending:     pending,
+		Started:     t.started,
+	}
+	st.Deadline, _ = t.deadline()
+	if t.done {
+		st.Elapsed = t.finished.Sub(t.started)
+	} else if !t.started.IsZero() {
+		st.Elapsed = time.Since(t.started)
+	}
+	return st
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNonceReused is returned when a signing session whose nonces were
+// already used is asked to sign again.
+var ErrNonceReused = errors.New("nonce already used: nonce reuse prevented")
+
+// NonceStore records which signing nonces have been used. A session is
+// recorded, by its [SigningSession.ID], before its signature share is
+// computed, so a session restored from a snapshot or imported twice cannot
+// sign a second time.
+//
+// The in-memory consumed flag of a [SigningSession] only protects a
+// single process. Set [Config].NonceStore to a durable store such as
+// [FileNonceStore] to keep the guarantee across restarts.
+type NonceStore interface {
+	// MarkUsed records id as used. It must return [ErrNonceReused] if id
+	// was recorded before, and must only return nil once the record is
+	// durable.
+	MarkUsed(id string) error
+}
+
+// MemoryNonceStore is a [NonceStore] that keeps records in memory. It
+// prevents reuse between sessions of one process, for example of an
+// imported session, but forgets everything on restart.
+type MemoryNonceStore struct {
+	mu   sync.Mutex
+	used map[string]struct{}
+}
+
+// NewMemoryNonceStore returns an empty in-memory nonce store.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{used: make(map[string]struct{})}
+}
+
+// MarkUsed implements NonceStore.
+func (s *MemoryNonceStore) MarkUsed(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.used[id]; ok {
+		return ErrNonceReused
+	}
+	s.used[id] = struct{}{}
+	return nil
+}
+
+// FileNonceStore is a [NonceStore] backed by an append-only file. Each
+// used session ID is written on its own line and synced to disk before
+// MarkUsed returns.
+type FileNonceStore struct {
+	mu   sync.Mutex
+	file *os.File
+	used map[string]struct{}
+}
+
+// OpenFileNonceStore opens the nonce store at path, creating it if it
+// does not exist, and loads the IDs recorded so far.
+func OpenFileNonceStore(path string) (*FileNonceStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nonce store: %w", err)
+	}
+
+	used := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			used[id] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read nonce store: %w", err)
+	}
+
+	return &FileNonceStore{file: f, used: used}, nil
+}
+
+// MarkUsed implements NonceStore.
+func (s *FileNonceStore) MarkUsed(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("nonce store is closed")
+	}
+	if _, ok := s.used[id]; ok {
+		return ErrNonceReused
+	}
+	if _, err := s.file.WriteString(id + "\n"); err != nil {
+		return fmt.Errorf("failed to record nonce: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to record nonce: %w", err)
+	}
+	s.used[id] = struct{}{}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileNonceStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
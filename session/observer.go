@@ -0,0 +1,110 @@
+package session
+
+// Ceremony identifies the kind of ceremony a participant is taking part in.
+type Ceremony int
+
+const (
+	// CeremonyDKG is a distributed key generation ceremony.
+	CeremonyDKG Ceremony = iota + 1
+
+	// CeremonySigning is a threshold signing ceremony.
+	CeremonySigning
+)
+
+// String returns a human-readable name for the ceremony.
+func (c Ceremony) String() string {
+	switch c {
+	case CeremonyDKG:
+		return "dkg"
+	case CeremonySigning:
+		return "signing"
+	default:
+		return "unknown"
+	}
+}
+
+// Round identifies a protocol round within a ceremony.
+type Round int
+
+const (
+	// RoundDKG1 generates the commitment broadcast and private shares.
+	RoundDKG1 Round = iota + 1
+
+	// RoundDKG2 verifies received shares and derives the key share.
+	RoundDKG2
+
+	// RoundSign1 generates nonces and the signing commitment.
+	RoundSign1
+
+	// RoundSign2 produces the signature share.
+	RoundSign2
+)
+
+// Ceremony returns the ceremony this round belongs to.
+func (r Round) Ceremony() Ceremony {
+	switch r {
+	case RoundDKG1, RoundDKG2:
+		return CeremonyDKG
+	case RoundSign1, RoundSign2:
+		return CeremonySigning
+	default:
+		return 0
+	}
+}
+
+// String returns a human-readable name for the round.
+func (r Round) String() string {
+	switch r {
+	case RoundDKG1:
+		return "dkg-round1"
+	case RoundDKG2:
+		return "dkg-round2"
+	case RoundSign1:
+		return "sign-round1"
+	case RoundSign2:
+		return "sign-round2"
+	default:
+		return "unknown"
+	}
+}
+
+// Observer receives notifications as a ceremony progresses. Applications
+// can use it to drive progress displays and alerts without polling.
+//
+// Callbacks are invoked synchronously from the goroutine driving the
+// ceremony, so implementations should return quickly and must not call
+// back into the participant or session that emitted the event.
+//
+// Embed [NopObserver] to implement only the callbacks of interest.
+type Observer interface {
+	// OnRoundStart is called when the participant begins the given round.
+	OnRoundStart(round Round)
+
+	// OnMessageReceived is called for each message accepted from another
+	// participant. The round identifies the round that produced the message
+	// (for example, RoundDKG1 for broadcasts and private shares).
+	OnMessageReceived(round Round, from int)
+
+	// OnParticipantTimeout is called when participant id failed to deliver
+	// its message for the given round before the deadline.
+	OnParticipantTimeout(round Round, id int)
+
+	// OnComplete is called when a ceremony finishes. The error is nil on
+	// success and describes the failure otherwise.
+	OnComplete(ceremony Ceremony, err error)
+}
+
+// NopObserver is an [Observer] that ignores all events.
+type NopObserver struct{}
+
+// OnRoundStart implements Observer.
+func (NopObserver) OnRoundStart(Round) {}
+
+// OnMessageReceived implements Observer.
+func (NopObserver) OnMessageReceived(Round, int) {}
+
+// OnParticipantTimeout implements Observer.
+func (NopObserver) OnParticipantTimeout(Round, int) {}
+
+// OnComplete implements Observer.
+func (NopObserver) OnComplete(Ceremony, error) {}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// String returns the ID and state of p. It never includes the key share's
+// secret or DKG secrets.
+func (p *Participant) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Sprintf("Participant{ID: %d, Finalized: %t, KeyShare: %s}", p.id, p.finalized, p.keyShareString())
+}
+
+// GoString is like String, for the %#v verb.
+func (p *Participant) GoString() string {
+	return "session." + p.String()
+}
+
+// LogValue implements slog.LogValuer without logging secrets.
+func (p *Participant) LogValue() slog.Value {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slog.GroupValue(
+		slog.Int("id", p.id),
+		slog.Bool("finalized", p.finalized),
+		slog.String("key_share", p.keyShareString()),
+	)
+}
+
+// keyShareString describes the key share, which prints with its secret
+// redacted.
+func (p *Participant) keyShareString() string {
+	if p.keyShare == nil {
+		return "<nil>"
+	}
+	return p.keyShare.String()
+}
+
+// String returns the ID and state of s, with its nonces redacted.
+func (s *SigningSession) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("SigningSession{ID: %s, Message: %d bytes, Consumed: %t, Nonce: %s}",
+		s.ID(), len(s.message), s.consumed, nonceString(s.consumed))
+}
+
+// GoString is like String, for the %#v verb.
+func (s *SigningSession) GoString() string {
+	return "session." + s.String()
+}
+
+// LogValue implements slog.LogValuer without logging the nonces.
+func (s *SigningSession) LogValue() slog.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slog.GroupValue(
+		slog.String("id", s.ID()),
+		slog.Int("message_len", len(s.message)),
+		slog.Bool("consumed", s.consumed),
+		slog.String("nonce", nonceString(s.consumed)),
+	)
+}
+
+// String returns the size and state of b, with its nonces redacted.
+func (b *BatchSigningSession) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return fmt.Sprintf("BatchSigningSession{Messages: %d, Consumed: %t, Nonces: %s}",
+		len(b.messages), b.consumed, nonceString(b.consumed))
+}
+
+// GoString is like String, for the %#v verb.
+func (b *BatchSigningSession) GoString() string {
+	return "session." + b.String()
+}
+
+// LogValue implements slog.LogValuer without logging the nonces.
+func (b *BatchSigningSession) LogValue() slog.Value {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slog.GroupValue(
+		slog.Int("messages", len(b.messages)),
+		slog.Bool("consumed", b.consumed),
+		slog.String("nonces", nonceString(b.consumed)),
+	)
+}
+
+// nonceString describes the nonces of a session: wiped once it is
+// consumed, redacted before.
+func nonceString(consumed bool) string {
+	if consumed {
+		return "<wiped>"
+	}
+	return "[REDACTED]"
+}
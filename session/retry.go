@@ -0,0 +1,430 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f3rmion/fy/frost"
+)
+
+// Signer is a participant that a [RetryCoordinator] can ask for
+// commitments and signature shares. Implementations typically wrap a
+// network client for a remote signer; [NewLocalSigner] adapts an
+// in-process [Participant].
+type Signer interface {
+	// ID returns the signer's participant identifier.
+	ID() int
+
+	// Commit starts a new signing session for message and returns its
+	// commitment. Every call must use fresh nonces.
+	Commit(ctx context.Context, message []byte) (*frost.SigningCommitment, error)
+
+	// Sign produces the signature share for the session whose commitment
+	// is included in commitments.
+	Sign(ctx context.Context, message []byte, commitments []*frost.SigningCommitment) (*frost.SignatureShare, error)
+}
+
+// Abandoner is implemented by signers that hold a signing session for
+// every commitment they hand out. When an attempt fails, a
+// [RetryCoordinator] calls Abandon with each commitment it received in
+// that attempt, so that the signer can wipe the nonces of sessions that
+// will never sign instead of keeping them in memory.
+type Abandoner interface {
+	// Abandon discards the session of commitment, if it has not signed.
+	Abandon(commitment *frost.SigningCommitment)
+}
+
+// localSigner adapts a Participant to the Signer interface.
+type localSigner struct {
+	mu       sync.Mutex
+	p        *Participant
+	rng      io.Reader
+	sessions map[string]*SigningSession
+}
+
+// NewLocalSigner returns a [Signer] backed by an in-process participant
+// that has completed DKG. Randomness for nonces is drawn from rng. The
+// signer implements [Abandoner], and wipes the session of a commitment
+// that is only produced after ctx is done.
+func NewLocalSigner(p *Participant, rng io.Reader) Signer {
+	return &localSigner{
+		p:        p,
+		rng:      rng,
+		sessions: make(map[string]*SigningSession),
+	}
+}
+
+// ID implements Signer.
+func (s *localSigner) ID() int {
+	return s.p.ID()
+}
+
+// Commit implements Signer.
+func (s *localSigner) Commit(ctx context.Context, message []byte) (*frost.SigningCommitment, error) {
+	sess, err := s.p.NewSigningSession(s.rng, message)
+	if err != nil {
+		return nil, err
+	}
+	// The caller has stopped waiting, so nobody will use this commitment
+	if err := ctx.Err(); err != nil {
+		sess.Zeroize()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[string(sess.Commitment().HidingPoint.Bytes())] = sess
+	return sess.Commitment(), nil
+}
+
+// Sign implements Signer.
+func (s *localSigner) Sign(ctx context.Context, message []byte, commitments []*frost.SigningCommitment) (*frost.SignatureShare, error) {
+	s.mu.Lock()
+	var sess *SigningSession
+	for _, c := range commitments {
+		key := string(c.HidingPoint.Bytes())
+		if found, ok := s.sessions[key]; ok {
+			sess = found
+			delete(s.sessions, key)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if sess == nil {
+		return nil, errors.New("no signing session for the given commitments")
+	}
+	return sess.Sign(commitments)
+}
+
+// Abandon implements Abandoner.
+func (s *localSigner) Abandon(commitment *frost.SigningCommitment) {
+	if commitment == nil || commitment.HidingPoint == nil {
+		return
+	}
+	key := string(commitment.HidingPoint.Bytes())
+	s.mu.Lock()
+	sess, ok := s.sessions[key]
+	delete(s.sessions, key)
+	s.mu.Unlock()
+
+	if ok {
+		sess.Zeroize()
+	}
+}
+
+// SignersExhaustedError is returned by [RetryCoordinator.Sign] when too
+// few well-behaved signers remain to form a signing quorum.
+type SignersExhaustedError struct {
+	// Faulty maps the ID of every excluded signer to the reason it was
+	// excluded.
+	Faulty map[int]error
+}
+
+// Error implements the error interface.
+func (e *SignersExhaustedError) Error() string {
+	var parts []string
+	for _, id := range slices.Sorted(maps.Keys(e.Faulty)) {
+		parts = append(parts, fmt.Sprintf("%d (%v)", id, e.Faulty[id]))
+	}
+	return "not enough responsive signers; excluded: " + strings.Join(parts, ", ")
+}
+
+// RetryCoordinator produces signatures in the presence of unresponsive or
+// malicious signers, in the spirit of ROAST. It invites a quorum of
+// threshold signers chosen by a [SignerSelector]; if any of them stalls past the configured timeout or
+// submits an invalid signature share, that signer is excluded and a new
+// attempt is made with a different quorum and fresh nonces. This repeats
+// until a valid signature is produced or too few signers remain.
+type RetryCoordinator struct {
+	frost    *frost.FROST
+	roster   *Roster
+	signers  map[int]Signer
+	config   *Config
+	selector SignerSelector
+}
+
+// NewRetryCoordinator creates a retry coordinator for the group described
+// by roster. The signers must be distinct members of the roster, and at
+// least roster.Threshold of them must be given. The Timeout, Observer and
+// Metrics settings of cfg apply to each attempt, and its Selector chooses
+// the signers to invite.
+func NewRetryCoordinator(f *frost.FROST, roster *Roster, signers []Signer, cfg *Config) (*RetryCoordinator, error) {
+	if len(signers) < roster.Threshold {
+		return nil, fmt.Errorf("need at least %d signers, got %d", roster.Threshold, len(signers))
+	}
+	byID := make(map[int]Signer, len(signers))
+	for _, s := range signers {
+		id := s.ID()
+		if !roster.Contains(id) {
+			return nil, fmt.Errorf("signer %d is not in the roster", id)
+		}
+		if _, exists := byID[id]; exists {
+			return nil, fmt.Errorf("duplicate signer %d", id)
+		}
+		byID[id] = s
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	var selector SignerSelector = LowestIDSelector{}
+	if cfg.Selector != nil {
+		selector = cfg.Selector
+	}
+	return &RetryCoordinator{
+		frost:    f,
+		roster:   roster,
+		signers:  byID,
+		config:   cfg,
+		selector: selector,
+	}, nil
+}
+
+// Sign produces a signature on message, retrying with different signer
+// quorums as needed. It returns a [*SignersExhaustedError] if no quorum of
+// well-behaved signers remains, or the context's error if ctx is done.
+func (rc *RetryCoordinator) Sign(ctx context.Context, message []byte) (*frost.Signature, error) {
+	faulty := make(map[int]error)
+	for {
+		quorum, err := rc.selectQuorum(faulty)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, faults, err := rc.attempt(ctx, message, quorum)
+		if err == nil {
+			return sig, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if len(faults) == 0 {
+			// The failure cannot be attributed to a signer, so retrying
+			// with another quorum will not help.
+			return nil, err
+		}
+		for id, ferr := range faults {
+			faulty[id] = ferr
+		}
+	}
+}
+
+// selectQuorum asks the selector for threshold signers that have not
+// been excluded.
+func (rc *RetryCoordinator) selectQuorum(faulty map[int]error) ([]int, error) {
+	var available []int
+	for _, id := range slices.Sorted(maps.Keys(rc.signers)) {
+		if _, bad := faulty[id]; !bad {
+			available = append(available, id)
+		}
+	}
+	if len(available) < rc.roster.Threshold {
+		return nil, &SignersExhaustedError{Faulty: faulty}
+	}
+
+	quorum := rc.selector.Select(available, rc.roster.Threshold)
+	if len(quorum) != rc.roster.Threshold {
+		return nil, fmt.Errorf("selector returned %d signers, need %d", len(quorum), rc.roster.Threshold)
+	}
+	for i, id := range quorum {
+		if !slices.Contains(available, id) || slices.Contains(quorum[:i], id) {
+			return nil, fmt.Errorf("selector returned unavailable or duplicate signer %d", id)
+		}
+	}
+	return quorum, nil
+}
+
+// attempt runs one signing ceremony with the given quorum. On failure it
+// returns the signers responsible, if any, and abandons the commitments
+// it received.
+func (rc *RetryCoordinator) attempt(ctx context.Context, message []byte, quorum []int) (sig *frost.Signature, faults map[int]error, err error) {
+	c, err := NewCoordinatorWithConfig(rc.frost, message, quorum, rc.config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	faults = make(map[int]error)
+	latency := make(map[int]time.Duration)
+	received := make(map[int]*frost.SigningCommitment)
+	defer func() {
+		for _, id := range quorum {
+			rc.selector.Report(id, latency[id], faults[id])
+		}
+		if err != nil {
+			rc.abandon(received)
+		}
+	}()
+
+	// Round 1: collect commitments
+	commitResults := rc.collect(ctx, quorum, func(ctx context.Context, s Signer) (any, error) {
+		return s.Commit(ctx, message)
+	})
+	for _, id := range quorum {
+		res := commitResults[id]
+		latency[id] += res.latency
+		if res.err != nil {
+			faults[id] = res.err
+			continue
+		}
+		comm := res.value.(*frost.SigningCommitment)
+		if comm == nil || comm.ID == nil {
+			faults[id] = errors.New("signer returned no commitment")
+			continue
+		}
+		received[id] = comm
+		if scalarToInt(comm.ID) != id {
+			faults[id] = errors.New("commitment carries wrong participant ID")
+			continue
+		}
+		if err := c.AddCommitment(comm); err != nil {
+			faults[id] = err
+		}
+	}
+	if len(faults) > 0 {
+		return nil, faults, c.abort(RoundSign1, faults)
+	}
+
+	commitments, err := c.Commitments()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prepared, err := rc.frost.Prepare(rc.roster.GroupKey, message, commitments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Round 2: collect and verify signature shares
+	shareResults := rc.collect(ctx, quorum, func(ctx context.Context, s Signer) (any, error) {
+		return s.Sign(ctx, message, commitments)
+	})
+	for _, id := range quorum {
+		res := shareResults[id]
+		latency[id] += res.latency
+		if res.err != nil {
+			faults[id] = res.err
+			continue
+		}
+		share := res.value.(*frost.SignatureShare)
+		if share == nil || share.ID == nil {
+			faults[id] = errors.New("signer returned no signature share")
+			continue
+		}
+		if scalarToInt(share.ID) != id ||
+			!prepared.VerifyShare(share, rc.roster.PublicKeys[id]) {
+			c.progress.verificationFailed(id)
+			faults[id] = errors.New("invalid signature share")
+			continue
+		}
+		if err := c.AddShare(share); err != nil {
+			faults[id] = err
+		}
+	}
+	if len(faults) > 0 {
+		return nil, faults, c.abort(RoundSign2, faults)
+	}
+
+	sig, err = c.Aggregate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := Verify(rc.frost, message, sig, rc.roster.GroupKey); err != nil {
+		return nil, nil, err
+	}
+	return sig, nil, nil
+}
+
+// abandon hands the commitments of a failed attempt back to the signers
+// that implement [Abandoner].
+func (rc *RetryCoordinator) abandon(commitments map[int]*frost.SigningCommitment) {
+	for id, comm := range commitments {
+		if a, ok := rc.signers[id].(Abandoner); ok {
+			a.Abandon(comm)
+		}
+	}
+}
+
+// result is the outcome of a request to a single signer.
+type result struct {
+	value   any
+	err     error
+	latency time.Duration
+}
+
+// collect calls fn for every signer in quorum concurrently, bounding each
+// call by the configured timeout, and returns the results by signer ID.
+func (rc *RetryCoordinator) collect(ctx context.Context, quorum []int, fn func(context.Context, Signer) (any, error)) map[int]result {
+	if rc.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rc.config.Timeout)
+		defer cancel()
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[int]result, len(quorum))
+	for _, id := range quorum {
+		wg.Add(1)
+		go func(s Signer) {
+			defer wg.Done()
+
+			start := time.Now()
+			done := make(chan result, 1)
+			go func() {
+				v, err := fn(ctx, s)
+				done <- result{value: v, err: err}
+			}()
+
+			var res result
+			select {
+			case res = <-done:
+			case <-ctx.Done():
+				res = result{err: ctx.Err()}
+			}
+			res.latency = time.Since(start)
+
+			mu.Lock()
+			results[s.ID()] = res
+			mu.Unlock()
+		}(rc.signers[id])
+	}
+	wg.Wait()
+	return results
+}
+
+// abort fails the ceremony because of the given faulty signers. Signers
+// that did not answer in time are reported as a timeout.
+func (c *Coordinator) abort(round Round, faults map[int]error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stalled []int
+	for id, err := range faults {
+		if errors.Is(err, context.DeadlineExceeded) {
+			stalled = append(stalled, id)
+		}
+	}
+	if len(stalled) > 0 {
+		slices.Sort(stalled)
+		deadline, _ := c.progress.deadline()
+		err := &TimeoutError{Round: round, Deadline: deadline}
+		if round == RoundSign1 {
+			err.MissingCommitments = stalled
+		} else {
+			err.MissingShares = stalled
+		}
+		c.progress.expire(err)
+		return err
+	}
+
+	err := fmt.Errorf("signers %v failed", slices.Sorted(maps.Keys(faults)))
+	c.progress.complete(err)
+	return err
+}
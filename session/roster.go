@@ -0,0 +1,34 @@
+package session
+
+import (
+	"slices"
+
+	"github.com/f3rmion/fy/group"
+)
+
+// Roster describes the membership of a threshold group established by a
+// DKG ceremony. It contains only public information and can be shared
+// with coordinators and verifiers.
+type Roster struct {
+	// Threshold is the minimum number of signers required (t).
+	Threshold int
+
+	// IDs lists the participant identifiers in ascending order.
+	IDs []int
+
+	// GroupKey is the combined public key of the group.
+	GroupKey group.Point
+
+	// PublicKeys maps each participant ID to its public verification share.
+	PublicKeys map[int]group.Point
+}
+
+// Total returns the number of participants in the group (n).
+func (r *Roster) Total() int {
+	return len(r.IDs)
+}
+
+// Contains reports whether id is a member of the group.
+func (r *Roster) Contains(id int) bool {
+	return slices.Contains(r.IDs, id)
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SignerSelector decides which signers a [RetryCoordinator] invites to
+// each signing attempt. Set it with [Config].Selector; the default is
+// [LowestIDSelector].
+//
+// Implementations must be safe for concurrent use.
+type SignerSelector interface {
+	// Select returns threshold distinct IDs chosen from available, which
+	// lists the signers not yet excluded from the current request in
+	// ascending order and holds at least threshold entries.
+	Select(available []int, threshold int) []int
+
+	// Report records the outcome of an attempt for signer id: how long it
+	// took to answer and the error that got it excluded, if any.
+	Report(id int, latency time.Duration, err error)
+}
+
+// LowestIDSelector always invites the signers with the lowest IDs.
+type LowestIDSelector struct{}
+
+// Select implements SignerSelector.
+func (LowestIDSelector) Select(available []int, threshold int) []int {
+	return slices.Clone(available[:threshold])
+}
+
+// Report implements SignerSelector.
+func (LowestIDSelector) Report(int, time.Duration, error) {}
+
+// RoundRobinSelector spreads the signing load by rotating through the
+// available signers from one request to the next.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	next int
+}
+
+// Select implements SignerSelector.
+func (s *RoundRobinSelector) Select(available []int, threshold int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Start at the first available signer at or after the cursor
+	start, _ := slices.BinarySearch(available, s.next)
+	selected := make([]int, threshold)
+	for i := range selected {
+		selected[i] = available[(start+i)%len(available)]
+	}
+	s.next = selected[threshold-1] + 1
+	return selected
+}
+
+// Report implements SignerSelector.
+func (s *RoundRobinSelector) Report(int, time.Duration, error) {}
+
+// LatencySelector invites the signers that have answered fastest
+// recently. Each signer's latency is tracked as an exponentially weighted
+// moving average; signers without history are tried first so that they
+// get measured, and a signer that fails is charged Penalty.
+type LatencySelector struct {
+	// Penalty is the latency charged for a failed attempt. If zero, one
+	// second is used.
+	Penalty time.Duration
+
+	mu      sync.Mutex
+	latency map[int]time.Duration
+}
+
+// Select implements SignerSelector.
+func (s *LatencySelector) Select(available []int, threshold int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return selectBest(available, threshold, func(id int) float64 {
+		return float64(s.latency[id])
+	})
+}
+
+// Report implements SignerSelector.
+func (s *LatencySelector) Report(id int, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		latency = cmp.Or(s.Penalty, time.Second)
+	}
+	if s.latency == nil {
+		s.latency = make(map[int]time.Duration)
+	}
+	if prev, ok := s.latency[id]; ok {
+		// Weight the new sample by 1/4
+		latency = (3*prev + latency) / 4
+	}
+	s.latency[id] = latency
+}
+
+// ReliabilitySelector invites the signers with the best track record.
+// Each signer is scored by its share of successful attempts, with one
+// success and one failure assumed up front so that new signers start at
+// one half.
+type ReliabilitySelector struct {
+	mu        sync.Mutex
+	successes map[int]int
+	failures  map[int]int
+}
+
+// Select implements SignerSelector.
+func (s *ReliabilitySelector) Select(available []int, threshold int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return selectBest(available, threshold, func(id int) float64 {
+		ok, failed := s.successes[id], s.failures[id]
+		return -float64(ok+1) / float64(ok+failed+2)
+	})
+}
+
+// Report implements SignerSelector.
+func (s *ReliabilitySelector) Report(id int, _ time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.successes == nil {
+		s.successes = make(map[int]int)
+		s.failures = make(map[int]int)
+	}
+	if err != nil {
+		s.failures[id]++
+	} else {
+		s.successes[id]++
+	}
+}
+
+// selectBest returns the threshold signers with the lowest cost, breaking
+// ties by ID, in ascending ID order.
+func selectBest(available []int, threshold int, cost func(int) float64) []int {
+	ranked := slices.Clone(available)
+	slices.SortStableFunc(ranked, func(a, b int) int {
+		return cmp.Compare(cost(a), cost(b))
+	})
+	selected := ranked[:threshold]
+	slices.Sort(selected)
+	return selected
+}
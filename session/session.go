@@ -1,9 +1,16 @@
 package session
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/f3rmion/fy/frost"
 	"github.com/f3rmion/fy/group"
@@ -12,27 +19,49 @@ import (
 // Participant manages a single participant's state throughout DKG and signing
 // ceremonies. Create instances using [NewParticipant].
 type Participant struct {
+	mu        sync.Mutex
 	id        int
 	frost     *frost.FROST
 	group     group.Group
 	keyShare  *frost.KeyShare
+	secret    *sealed // the key share's secret, with GuardedMemory
 	dkgState  *frost.Participant
 	finalized bool
+	config    *Config
+	dkg       *tracker
+
+	// DKG messages collected so far, keyed by sender ID.
+	roster        []int
+	broadcasts    map[int]*frost.Round1Data
+	privateShares map[int]*frost.Round1PrivateData
+	echoes        map[int][]byte
 }
 
 // DKGResult contains the output of a successful DKG ceremony.
 type DKGResult struct {
 	// KeyShare is this participant's share of the distributed key.
 	// Store this securely; it is required for signing.
+	//
+	// With [Config].GuardedMemory, the participant signs with a sealed
+	// copy of the secret, and KeyShare holds the only other copy, in
+	// ordinary memory. Wipe it with [frost.KeyShare.Zeroize] once it has
+	// been stored.
 	KeyShare *frost.KeyShare
 
 	// GroupKey is the combined public key for the threshold group.
 	// This is the same for all participants and is used to verify signatures.
 	GroupKey group.Point
 
-	// AllPublicKeys maps participant IDs to their individual public keys.
-	// This can be used to verify each participant's contributions.
+	// AllPublicKeys maps participant IDs to their public verification shares,
+	// computed from the broadcast commitments. Each entry equals the
+	// PublicKey in that participant's KeyShare and can be used to verify
+	// its signature shares and to attribute blame.
 	AllPublicKeys map[int]group.Point
+
+	// TranscriptDigest is the digest of all round 1 broadcasts, equal to
+	// KeyShare.TranscriptDigest. Compare it with the other participants'
+	// using [CheckTranscriptDigests] before signing.
+	TranscriptDigest []byte
 }
 
 // Round1Output contains all messages generated during DKG round 1.
@@ -54,6 +83,10 @@ type Round1Input struct {
 	// PrivateShares contains the private shares sent TO this participant
 	// from all other participants.
 	PrivateShares []*frost.Round1PrivateData
+
+	// Echoes optionally maps participant IDs to the digests they returned
+	// from [Participant.EchoDigest]. See [Config].EchoBroadcast.
+	Echoes map[int][]byte
 }
 
 // NewParticipant creates a new participant for FROST ceremonies.
@@ -67,38 +100,43 @@ type Round1Input struct {
 // The returned Participant can be used for one DKG ceremony and then
 // for multiple signing sessions.
 func NewParticipant(g group.Group, threshold, total, id int) (*Participant, error) {
-	if id < 1 || id > total {
-		return nil, fmt.Errorf("participant ID must be between 1 and %d, got %d", total, id)
-	}
-
-	f, err := frost.New(g, threshold, total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create FROST instance: %w", err)
-	}
-
-	return &Participant{
-		id:    id,
-		frost: f,
-		group: g,
-	}, nil
+	return NewParticipantWithConfig(g, threshold, total, id, nil)
 }
 
 // NewParticipantWithHasher creates a participant with a custom hash function.
 // Use this for Ledger/iden3 compatibility with [frost.Blake2bHasher].
 func NewParticipantWithHasher(g group.Group, threshold, total, id int, hasher frost.Hasher) (*Participant, error) {
+	return NewParticipantWithConfig(g, threshold, total, id, &Config{Hasher: hasher})
+}
+
+// NewParticipantWithConfig creates a participant using the ceremony
+// parameters in cfg. A nil cfg selects the defaults.
+func NewParticipantWithConfig(g group.Group, threshold, total, id int, cfg *Config) (*Participant, error) {
 	if id < 1 || id > total {
 		return nil, fmt.Errorf("participant ID must be between 1 and %d, got %d", total, id)
 	}
 
-	f, err := frost.NewWithHasher(g, threshold, total, hasher)
+	f, err := cfg.NewFROST(g, threshold, total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create FROST instance: %w", err)
 	}
+	if cfg != nil && cfg.RequireConstantTime {
+		if err := f.CheckConstantTime(); err != nil {
+			return nil, fmt.Errorf("constant-time mode: %w", err)
+		}
+	}
+
+	// Copy the configuration so later changes by the caller do not apply
+	var config Config
+	if cfg != nil {
+		config = *cfg
+	}
 
 	return &Participant{
-		id:    id,
-		frost: f,
-		group: g,
+		id:     id,
+		frost:  f,
+		group:  g,
+		config: &config,
 	}, nil
 }
 
@@ -108,16 +146,46 @@ func (p *Participant) ID() int {
 }
 
 // KeyShare returns this participant's key share after DKG completion.
-// Returns nil if DKG has not been finalized.
+// Returns nil if DKG has not been finalized. With [Config].GuardedMemory,
+// the returned share has no SecretKey, which stays in guarded memory.
 func (p *Participant) KeyShare() *frost.KeyShare {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.keyShare
 }
 
+// signingKey returns the key share and, with GuardedMemory, its sealed
+// secret, or an error if the DKG has not been completed.
+func (p *Participant) signingKey() (*frost.KeyShare, *sealed, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keyShare == nil {
+		return nil, nil, errors.New("DKG not complete: no key share available")
+	}
+	return p.keyShare, p.secret, nil
+}
+
 // FROST returns the underlying FROST instance for advanced use cases.
 func (p *Participant) FROST() *frost.FROST {
 	return p.frost
 }
 
+// SetObserver registers an observer that is notified of the progress of
+// ceremonies started afterwards. Passing nil removes the observer.
+func (p *Participant) SetObserver(o Observer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config.Observer = o
+}
+
+// SetMetrics registers a metrics sink for ceremonies started afterwards.
+// Passing nil removes it.
+func (p *Participant) SetMetrics(m Metrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config.Metrics = m
+}
+
 // GenerateRound1 generates all round 1 DKG messages.
 //
 // This creates:
@@ -127,20 +195,34 @@ func (p *Participant) FROST() *frost.FROST {
 // The broadcast should be sent to all participants. Each private share
 // should be sent only to its intended recipient over a secure channel.
 func (p *Participant) GenerateRound1(rng io.Reader, allParticipantIDs []int) (*Round1Output, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.dkgState != nil {
 		return nil, errors.New("round 1 already generated")
 	}
 
+	dkg := newTracker(p.config)
+	dkg.startRound(RoundDKG1)
+
 	// Create internal participant state
 	participant, err := p.frost.NewParticipant(rng, p.id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create participant: %w", err)
+		err = fmt.Errorf("failed to create participant: %w", err)
+		dkg.complete(err)
+		return nil, err
 	}
+	p.dkg = dkg
 	p.dkgState = participant
 
 	// Generate broadcast
 	broadcast := participant.Round1Broadcast()
 
+	p.roster = slices.Clone(allParticipantIDs)
+	p.broadcasts = map[int]*frost.Round1Data{p.id: broadcast}
+	p.privateShares = make(map[int]*frost.Round1PrivateData)
+	p.echoes = make(map[int][]byte)
+
 	// Generate private shares for all other participants
 	privateShares := make(map[int]*frost.Round1PrivateData)
 	for _, recipientID := range allParticipantIDs {
@@ -157,87 +239,399 @@ func (p *Participant) GenerateRound1(rng io.Reader, allParticipantIDs []int) (*R
 	}, nil
 }
 
+// ReceiveBroadcast records a round 1 broadcast from another participant.
+//
+// Messages may be fed to the participant one at a time as they arrive, so
+// that [Participant.Status] can report who has not contributed yet. They
+// are verified when the DKG is completed with [Participant.ProcessRound1].
+// Receiving the same broadcast twice is harmless; a conflicting broadcast
+// from the same sender is rejected.
+func (p *Participant) ReceiveBroadcast(b *frost.Round1Data) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkReceiving(); err != nil {
+		return err
+	}
+	return p.receiveBroadcast(b)
+}
+
+// ReceivePrivateShare records a round 1 private share sent to this
+// participant. See [Participant.ReceiveBroadcast] for details.
+func (p *Participant) ReceivePrivateShare(share *frost.Round1PrivateData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkReceiving(); err != nil {
+		return err
+	}
+	return p.receivePrivateShare(share)
+}
+
+// DecodeBroadcast reconstructs the round 1 broadcast of participant from
+// out of its commitments as encoded by Point.Bytes, decoding and
+// validating all of them in one batch with [group.DecodePoints]. Use it to
+// ingest broadcasts received over the network before passing them to
+// [Participant.ReceiveBroadcast] or [Participant.ProcessRound1].
+func (p *Participant) DecodeBroadcast(from int, commitments [][]byte) (*frost.Round1Data, error) {
+	points, err := group.DecodePoints(p.group, commitments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broadcast from participant %d: %w", from, err)
+	}
+	return &frost.Round1Data{
+		ID:          p.frost.Identifier(from),
+		Commitments: points,
+	}, nil
+}
+
+// checkReceiving reports whether the participant is waiting for round 1
+// messages.
+func (p *Participant) checkReceiving() error {
+	if p.finalized {
+		return errors.New("DKG already finalized")
+	}
+	if p.dkgState == nil {
+		return errors.New("must call GenerateRound1 before receiving messages")
+	}
+	if p.dkg.done {
+		return fmt.Errorf("DKG ceremony failed: %w", p.dkg.err)
+	}
+	return nil
+}
+
+// receiveBroadcast stores b, rejecting unknown and conflicting senders.
+func (p *Participant) receiveBroadcast(b *frost.Round1Data) error {
+	if b == nil || b.ID == nil {
+		return errors.New("incomplete broadcast")
+	}
+	from := scalarToInt(b.ID)
+	if !slices.Contains(p.roster, from) {
+		return fmt.Errorf("broadcast from participant %d not in roster", from)
+	}
+	if prev, exists := p.broadcasts[from]; exists {
+		if !sameBroadcast(prev, b) {
+			return fmt.Errorf("duplicate broadcast from participant %d", from)
+		}
+		return nil
+	}
+	p.broadcasts[from] = b
+	p.dkg.received(RoundDKG1, from)
+	return nil
+}
+
+// receivePrivateShare stores share, rejecting unknown and conflicting
+// senders and shares addressed to another participant.
+func (p *Participant) receivePrivateShare(share *frost.Round1PrivateData) error {
+	if share == nil || share.FromID == nil || share.ToID == nil || share.Share == nil {
+		return errors.New("incomplete private share")
+	}
+	from := scalarToInt(share.FromID)
+	if from == p.id {
+		return fmt.Errorf("private share claims to come from this participant %d", from)
+	}
+	if !slices.Contains(p.roster, from) {
+		return fmt.Errorf("private share from participant %d not in roster", from)
+	}
+	if to := scalarToInt(share.ToID); to != p.id {
+		return fmt.Errorf("private share from participant %d is addressed to participant %d", from, to)
+	}
+	if prev, exists := p.privateShares[from]; exists {
+		if !prev.Share.Equal(share.Share) || !prev.ToID.Equal(share.ToID) {
+			return fmt.Errorf("duplicate private share from participant %d", from)
+		}
+		return nil
+	}
+	// Keep a copy, which is wiped once the key share is derived
+	p.privateShares[from] = &frost.Round1PrivateData{
+		FromID: share.FromID.Clone(),
+		ToID:   share.ToID.Clone(),
+		Share:  share.Share.Clone(),
+	}
+	p.dkg.received(RoundDKG1, from)
+	return nil
+}
+
+// sameBroadcast reports whether a and b carry identical commitments.
+func sameBroadcast(a, b *frost.Round1Data) bool {
+	if !a.ID.Equal(b.ID) || len(a.Commitments) != len(b.Commitments) {
+		return false
+	}
+	for i := range a.Commitments {
+		if !bytes.Equal(a.Commitments[i].Bytes(), b.Commitments[i].Bytes()) {
+			return false
+		}
+	}
+	return true
+}
+
 // ProcessRound1 processes received round 1 messages and completes the DKG.
 //
 // This verifies all received shares against their sender's commitments,
-// then computes the final key share. After this call, the participant
+// concurrently with [frost.FROST.Round2ReceiveShares], then computes the
+// final key share. After this call, the participant
 // is ready for signing operations.
 //
 // The input must contain:
 //   - Broadcasts from ALL participants (including this one)
 //   - Private shares from all OTHER participants
+//
+// Messages already delivered through [Participant.ReceiveBroadcast] and
+// [Participant.ReceivePrivateShare] need not be repeated; input may be nil
+// if everything was delivered that way.
+// ProcessRound1 returns an error naming the missing participants if any
+// broadcast or private share has not arrived; the DKG then stays open for
+// them.
+//
+// If echo digests were received (see [Participant.EchoDigest]), they are
+// checked against this participant's own view of the broadcasts and the
+// DKG fails with an [*EchoMismatchError] if any differ.
 func (p *Participant) ProcessRound1(input *Round1Input) (*DKGResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.dkgState == nil {
 		return nil, errors.New("must call GenerateRound1 before ProcessRound1")
 	}
-	if p.finalized {
-		return nil, errors.New("DKG already finalized")
+	if err := p.checkReceiving(); err != nil {
+		return nil, err
 	}
 
-	// Build a map of broadcasts by sender ID for lookup
-	broadcastByID := make(map[string]*frost.Round1Data)
-	for _, b := range input.Broadcasts {
-		key := string(b.ID.Bytes())
-		if _, exists := broadcastByID[key]; exists {
-			return nil, fmt.Errorf("duplicate broadcast from participant")
+	if input != nil {
+		if err := p.receiveInput(input); err != nil {
+			p.dkg.complete(err)
+			return nil, err
 		}
-		broadcastByID[key] = b
 	}
+	if err := p.checkTimeout(); err != nil {
+		return nil, err
+	}
+	if err := p.checkComplete(); err != nil {
+		return nil, err
+	}
+	if err := p.checkEchoes(); err != nil {
+		p.dkg.complete(err)
+		return nil, err
+	}
+
+	p.dkg.startRound(RoundDKG2)
+	result, err := p.processRound1()
+	p.dkg.complete(err)
+	return result, err
+}
 
-	// Verify and receive each share
+// checkComplete returns an error naming the peers whose broadcast or
+// private share has not been received. Finalizing without them would give
+// a key share and group key that differ from the other participants'.
+// The ceremony stays open, so that the missing messages can still be
+// delivered before ProcessRound1 is called again.
+func (p *Participant) checkComplete() error {
+	missingBroadcasts, missingShares := p.missingMessages()
+	var parts []string
+	if len(missingBroadcasts) > 0 {
+		parts = append(parts, fmt.Sprintf("broadcasts from %v", missingBroadcasts))
+	}
+	if len(missingShares) > 0 {
+		parts = append(parts, fmt.Sprintf("private shares from %v", missingShares))
+	}
+	if len(parts) > 0 {
+		return fmt.Errorf("round 1 is incomplete: missing %s", strings.Join(parts, ", "))
+	}
+	return nil
+}
+
+// receiveInput records all messages in input.
+func (p *Participant) receiveInput(input *Round1Input) error {
+	for _, b := range input.Broadcasts {
+		if err := p.receiveBroadcast(b); err != nil {
+			return err
+		}
+	}
 	for _, share := range input.PrivateShares {
-		senderBroadcast, ok := broadcastByID[string(share.FromID.Bytes())]
+		if err := p.receivePrivateShare(share); err != nil {
+			return err
+		}
+	}
+	for from, digest := range input.Echoes {
+		if err := p.receiveEcho(from, digest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processRound1 verifies the round 1 messages and finalizes the key share.
+func (p *Participant) processRound1() (*DKGResult, error) {
+	// Verify and receive all shares
+	froms := slices.Sorted(maps.Keys(p.privateShares))
+	shares := make([]*frost.Round1PrivateData, len(froms))
+	commitments := make([][]group.Point, len(froms))
+	for i, from := range froms {
+		senderBroadcast, ok := p.broadcasts[from]
 		if !ok {
 			return nil, fmt.Errorf("missing broadcast from sender of private share")
 		}
-
-		err := p.frost.Round2ReceiveShare(p.dkgState, share, senderBroadcast.Commitments)
-		if err != nil {
-			return nil, fmt.Errorf("invalid share from participant: %w", err)
+		shares[i], commitments[i] = p.privateShares[from], senderBroadcast.Commitments
+	}
+	if err := p.frost.Round2ReceiveShares(p.dkgState, shares, commitments); err != nil {
+		var invalid *frost.InvalidShareError
+		if errors.As(err, &invalid) {
+			i := slices.IndexFunc(shares, func(s *frost.Round1PrivateData) bool { return s.FromID.Equal(invalid.ID) })
+			p.dkg.verificationFailed(froms[i])
 		}
+		return nil, fmt.Errorf("failed to verify shares: %w", err)
 	}
 
-	// Finalize to get key share
-	keyShare, err := p.frost.Finalize(p.dkgState, input.Broadcasts)
+	// Finalize to get key share, feeding the broadcasts in ID order
+	ids := slices.Sorted(maps.Keys(p.broadcasts))
+	acc := p.frost.NewBroadcastAccumulator(p.dkgState)
+	for _, id := range ids {
+		if err := acc.Add(p.broadcasts[id]); err != nil {
+			return nil, fmt.Errorf("failed to finalize DKG: %w", err)
+		}
+	}
+	keyShare, err := p.frost.FinalizeAccumulated(p.dkgState, acc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to finalize DKG: %w", err)
 	}
 
-	p.keyShare = keyShare
+	if err := p.setKeyShare(keyShare); err != nil {
+		return nil, err
+	}
 	p.finalized = true
-	p.dkgState = nil // clear DKG state, no longer needed
+	// Finalize has wiped the secret DKG state, which is no longer needed
+	p.dkgState = nil
+	for _, share := range p.privateShares {
+		share.Zeroize()
+	}
 
-	// Build public keys map
-	allPublicKeys := make(map[int]group.Point)
-	for _, b := range input.Broadcasts {
-		// The first commitment (index 0) is the public key for that participant
-		// Actually, individual public keys would need to be computed from the key shares
-		// For now, we just store the constant term commitment
-		id := scalarToInt(b.ID)
-		allPublicKeys[id] = b.Commitments[0]
+	// Build public keys map from the summed commitments
+	allPublicKeys := make(map[int]group.Point, len(ids))
+	for _, id := range ids {
+		allPublicKeys[id] = acc.VerificationShare(id)
 	}
 
 	return &DKGResult{
-		KeyShare:      keyShare,
-		GroupKey:      keyShare.GroupKey,
-		AllPublicKeys: allPublicKeys,
+		KeyShare:         keyShare,
+		GroupKey:         keyShare.GroupKey,
+		AllPublicKeys:    allPublicKeys,
+		TranscriptDigest: keyShare.TranscriptDigest,
 	}, nil
 }
 
 // SetKeyShare allows setting a previously-saved key share.
 // Use this when restoring a participant from persistent storage.
-func (p *Participant) SetKeyShare(ks *frost.KeyShare) {
-	p.keyShare = ks
+//
+// With [Config].GuardedMemory, the participant seals a copy of the
+// secret, and the caller should wipe ks with [frost.KeyShare.Zeroize]
+// once it no longer needs it. SetKeyShare returns an error if ks has no
+// secret or the memory cannot be guarded.
+func (p *Participant) SetKeyShare(ks *frost.KeyShare) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.setKeyShare(ks); err != nil {
+		return err
+	}
 	p.finalized = true
+	return nil
+}
+
+// setKeyShare stores ks, sealing a copy of its secret with GuardedMemory.
+func (p *Participant) setKeyShare(ks *frost.KeyShare) error {
+	if !p.config.GuardedMemory {
+		p.keyShare = ks
+		return nil
+	}
+	if ks.SecretKey == nil {
+		return errors.New("key share has no secret")
+	}
+	secret, err := seal(p.group, ks.SecretKey.Clone())
+	if err != nil {
+		return err
+	}
+	p.secret.destroy()
+	p.secret = secret
+	p.keyShare = publicKeyShare(ks)
+	return nil
 }
 
-// scalarToInt extracts the integer value from a scalar.
-// This assumes the scalar represents a small integer (participant ID).
+// scalarToInt extracts the integer value from a scalar representing a
+// participant ID. It returns 0, which is never a valid ID, if the scalar
+// is too large to be one.
 func scalarToInt(s group.Scalar) int {
-	bytes := s.Bytes()
-	// Participant IDs are small, so we just need the last byte
-	if len(bytes) > 0 {
-		return int(bytes[len(bytes)-1])
+	var v *big.Int
+	if b, ok := s.(group.BigIntScalar); ok {
+		v = b.BigInt()
+	} else {
+		v = new(big.Int).SetBytes(s.Bytes())
+	}
+	if !v.IsInt64() || v.Int64() > math.MaxInt32 {
+		return 0
+	}
+	return int(v.Int64())
+}
+
+// QuickDKG runs a complete DKG ceremony for n participants in-process and
+// returns every participant's key share, ordered by ID, along with the
+// resulting roster.
+//
+// This is useful for testing or single-machine threshold setups where all
+// participants are in the same process. For distributed key generation,
+// use [Participant] instead.
+func QuickDKG(g group.Group, threshold, total int, rng io.Reader) ([]*frost.KeyShare, *Roster, error) {
+	allIDs := make([]int, total)
+	for i := range allIDs {
+		allIDs[i] = i + 1
+	}
+
+	// Round 1: Generate broadcasts and private shares
+	participants := make([]*Participant, total)
+	outputs := make([]*Round1Output, total)
+	for i, id := range allIDs {
+		p, err := NewParticipant(g, threshold, total, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		r1, err := p.GenerateRound1(rng, allIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("participant %d: %w", id, err)
+		}
+		participants[i] = p
+		outputs[i] = r1
+	}
+
+	broadcasts := make([]*frost.Round1Data, total)
+	for i, r1 := range outputs {
+		broadcasts[i] = r1.Broadcast
+	}
+
+	// Round 2: Verify shares and derive key shares
+	keyShares := make([]*frost.KeyShare, total)
+	roster := &Roster{
+		Threshold: threshold,
+		IDs:       allIDs,
+	}
+	for i, p := range participants {
+		privateShares := make([]*frost.Round1PrivateData, 0, total-1)
+		for j, r1 := range outputs {
+			if i != j {
+				privateShares = append(privateShares, r1.PrivateShares[p.ID()])
+			}
+		}
+
+		result, err := p.ProcessRound1(&Round1Input{
+			Broadcasts:    broadcasts,
+			PrivateShares: privateShares,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("participant %d: %w", p.ID(), err)
+		}
+		keyShares[i] = result.KeyShare
+		if i == 0 {
+			roster.GroupKey = result.GroupKey
+			roster.PublicKeys = result.AllPublicKeys
+		}
 	}
-	return 0
+
+	return keyShares, roster, nil
 }
@@ -1,8 +1,22 @@
 package session
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	mrand "math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/f3rmion/fy/bjj"
 	"github.com/f3rmion/fy/frost"
@@ -168,14 +182,18 @@ func TestNonceReusePrevention(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	commitment := sess.Commitment()
-	commitments := []*frost.SigningCommitment{commitment}
+	other, _ := participants[1].NewSigningSession(rand.Reader, message)
+	commitments := []*frost.SigningCommitment{sess.Commitment(), other.Commitment()}
+	nonce := sess.nonce
 
 	// First sign should succeed
 	_, err = sess.Sign(commitments)
 	if err != nil {
 		t.Fatalf("first sign failed: %v", err)
 	}
+	if !nonce.D.IsZero() || !nonce.E.IsZero() {
+		t.Error("nonces should be wiped after signing")
+	}
 
 	// Second sign should fail (nonce reuse prevention)
 	_, err = sess.Sign(commitments)
@@ -303,9 +321,9 @@ func TestSetKeyShare(t *testing.T) {
 	g := &bjj.BJJ{}
 	threshold := 2
 	total := 3
-	allIDs := []int{1, 2, 3}
+	allIDs := []int{1, 2}
 
-	// Run DKG for first participant only
+	// Run DKG for the first two participants only
 	p1, _ := NewParticipant(g, threshold, total, 1)
 	p2, _ := NewParticipant(g, threshold, total, 2)
 
@@ -325,7 +343,9 @@ func TestSetKeyShare(t *testing.T) {
 
 	// Create a new participant and set key share (simulating restore from storage)
 	p1Restored, _ := NewParticipant(g, threshold, total, 1)
-	p1Restored.SetKeyShare(result1.KeyShare)
+	if err := p1Restored.SetKeyShare(result1.KeyShare); err != nil {
+		t.Fatal(err)
+	}
 
 	// Should be able to sign with restored participant
 	message := []byte("restored participant test")
@@ -355,6 +375,41 @@ func TestSetKeyShare(t *testing.T) {
 	}
 }
 
+func TestSetKeyShareConcurrent(t *testing.T) {
+	participants, _ := runTestDKG(t, &bjj.BJJ{}, 2, 3)
+	ks := participants[0].KeyShare()
+	p, _ := NewParticipant(&bjj.BJJ{}, 2, 3, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			if err := p.SetKeyShare(ks); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			p.Status()
+			p.KeyShare()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			// Sessions may start before the first SetKeyShare
+			p.NewSigningSession(rand.Reader, []byte("concurrent"))
+		}
+	}()
+	wg.Wait()
+	if !p.Status().Done || p.KeyShare() != ks {
+		t.Error("key share was not set")
+	}
+}
 func TestAggregateValidation(t *testing.T) {
 	g := &bjj.BJJ{}
 	f, _ := frost.New(g, 2, 3)
@@ -521,3 +576,1696 @@ func TestMissingOwnCommitment(t *testing.T) {
 		t.Error("should fail when own commitment is missing")
 	}
 }
+
+// recordingObserver records observer events for assertions.
+type recordingObserver struct {
+	NopObserver
+	rounds    []Round
+	received  map[Round][]int
+	timeouts  map[Round][]int
+	completed map[Ceremony]error
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{
+		received:  make(map[Round][]int),
+		timeouts:  make(map[Round][]int),
+		completed: make(map[Ceremony]error),
+	}
+}
+
+func (o *recordingObserver) OnRoundStart(round Round) {
+	o.rounds = append(o.rounds, round)
+}
+
+func (o *recordingObserver) OnMessageReceived(round Round, from int) {
+	o.received[round] = append(o.received[round], from)
+}
+
+func (o *recordingObserver) OnParticipantTimeout(round Round, id int) {
+	o.timeouts[round] = append(o.timeouts[round], id)
+}
+
+func (o *recordingObserver) OnComplete(ceremony Ceremony, err error) {
+	o.completed[ceremony] = err
+}
+
+func TestObserver(t *testing.T) {
+	g := &bjj.BJJ{}
+	threshold := 2
+	total := 3
+	allIDs := []int{1, 2, 3}
+
+	obs := newRecordingObserver()
+
+	participants := make([]*Participant, total)
+	for i := 0; i < total; i++ {
+		p, _ := NewParticipant(g, threshold, total, i+1)
+		participants[i] = p
+	}
+	participants[0].SetObserver(obs)
+
+	r1Outputs := make([]*Round1Output, total)
+	for i, p := range participants {
+		r1, _ := p.GenerateRound1(rand.Reader, allIDs)
+		r1Outputs[i] = r1
+	}
+
+	broadcasts := make([]*frost.Round1Data, total)
+	for i, r1 := range r1Outputs {
+		broadcasts[i] = r1.Broadcast
+	}
+
+	for i, p := range participants {
+		var privateShares []*frost.Round1PrivateData
+		for j, r1 := range r1Outputs {
+			if i == j {
+				continue
+			}
+			if share, ok := r1.PrivateShares[p.ID()]; ok {
+				privateShares = append(privateShares, share)
+			}
+		}
+		if _, err := p.ProcessRound1(&Round1Input{
+			Broadcasts:    broadcasts,
+			PrivateShares: privateShares,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err, ok := obs.completed[CeremonyDKG]; !ok || err != nil {
+		t.Errorf("expected successful DKG completion, got ok=%v err=%v", ok, err)
+	}
+	// One broadcast and one private share from each other participant
+	if got := obs.received[RoundDKG1]; len(got) != 2*(total-1) {
+		t.Errorf("expected %d round 1 messages, got %v", 2*(total-1), got)
+	}
+
+	message := []byte("observer test")
+	sess1, _ := participants[0].NewSigningSession(rand.Reader, message)
+	sess2, _ := participants[1].NewSigningSession(rand.Reader, message)
+	commitments := []*frost.SigningCommitment{sess1.Commitment(), sess2.Commitment()}
+
+	if _, err := sess1.Sign(commitments); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Round{RoundDKG1, RoundDKG2, RoundSign1, RoundSign2}
+	if len(obs.rounds) != len(want) {
+		t.Fatalf("expected rounds %v, got %v", want, obs.rounds)
+	}
+	for i := range want {
+		if obs.rounds[i] != want[i] {
+			t.Errorf("round %d: expected %v, got %v", i, want[i], obs.rounds[i])
+		}
+	}
+	if got := obs.received[RoundSign1]; len(got) != 1 || got[0] != 2 {
+		t.Errorf("expected one commitment from participant 2, got %v", got)
+	}
+	if err, ok := obs.completed[CeremonySigning]; !ok || err != nil {
+		t.Errorf("expected successful signing completion, got ok=%v err=%v", ok, err)
+	}
+}
+
+// countingMetrics counts metrics events for assertions.
+type countingMetrics struct {
+	NopMetrics
+	started   map[Ceremony]int
+	completed map[Ceremony]int
+	failed    map[Ceremony]int
+	latencies map[Round]int
+	badShares map[int]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{
+		started:   make(map[Ceremony]int),
+		completed: make(map[Ceremony]int),
+		failed:    make(map[Ceremony]int),
+		latencies: make(map[Round]int),
+		badShares: make(map[int]int),
+	}
+}
+
+func (m *countingMetrics) CeremonyStarted(c Ceremony)   { m.started[c]++ }
+func (m *countingMetrics) CeremonyCompleted(c Ceremony) { m.completed[c]++ }
+func (m *countingMetrics) CeremonyFailed(c Ceremony)    { m.failed[c]++ }
+func (m *countingMetrics) VerificationFailure(id int)   { m.badShares[id]++ }
+
+func (m *countingMetrics) ObserveRoundLatency(r Round, d time.Duration) {
+	m.latencies[r]++
+}
+
+func TestMetrics(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2}
+
+	p1, _ := NewParticipant(g, 2, 2, 1)
+	p2, _ := NewParticipant(g, 2, 2, 2)
+	m := newCountingMetrics()
+	p1.SetMetrics(m)
+
+	r1_1, _ := p1.GenerateRound1(rand.Reader, allIDs)
+	r1_2, _ := p2.GenerateRound1(rand.Reader, allIDs)
+
+	// Tamper with the share sent to participant 1
+	bad := *r1_2.PrivateShares[1]
+	bad.Share = g.NewScalar().Add(bad.Share, bad.Share)
+
+	_, err := p1.ProcessRound1(&Round1Input{
+		Broadcasts:    []*frost.Round1Data{r1_1.Broadcast, r1_2.Broadcast},
+		PrivateShares: []*frost.Round1PrivateData{&bad},
+	})
+	if err == nil {
+		t.Fatal("expected tampered share to be rejected")
+	}
+
+	if m.started[CeremonyDKG] != 1 {
+		t.Errorf("expected 1 DKG start, got %d", m.started[CeremonyDKG])
+	}
+	if m.failed[CeremonyDKG] != 1 || m.completed[CeremonyDKG] != 0 {
+		t.Errorf("expected 1 DKG failure, got failed=%d completed=%d",
+			m.failed[CeremonyDKG], m.completed[CeremonyDKG])
+	}
+	if m.latencies[RoundDKG1] != 1 || m.latencies[RoundDKG2] != 1 {
+		t.Errorf("expected one latency sample per round, got %v", m.latencies)
+	}
+	if m.badShares[2] != 1 {
+		t.Errorf("expected verification failure attributed to participant 2, got %v", m.badShares)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2, 3}
+
+	participants := make([]*Participant, 3)
+	r1Outputs := make([]*Round1Output, 3)
+	for i := range participants {
+		participants[i], _ = NewParticipant(g, 2, 3, i+1)
+	}
+
+	if st := participants[0].Status(); st.Ceremony != 0 || st.Done {
+		t.Errorf("expected no ceremony before round 1, got %+v", st)
+	}
+
+	for i, p := range participants {
+		r1Outputs[i], _ = p.GenerateRound1(rand.Reader, allIDs)
+	}
+
+	p1 := participants[0]
+	if err := p1.ReceiveBroadcast(r1Outputs[1].Broadcast); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceivePrivateShare(r1Outputs[1].PrivateShares[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceiveBroadcast(r1Outputs[2].Broadcast); err != nil {
+		t.Fatal(err)
+	}
+
+	st := p1.Status()
+	if st.Round != RoundDKG1 || st.Done {
+		t.Errorf("expected round 1 in progress, got %+v", st)
+	}
+	if !slices.Equal(st.Contributed, []int{2}) || !slices.Equal(st.Pending, []int{3}) {
+		t.Errorf("expected contributed [2] pending [3], got %v %v", st.Contributed, st.Pending)
+	}
+	if st.Progress() != 0.5 {
+		t.Errorf("expected progress 0.5, got %v", st.Progress())
+	}
+
+	// Conflicting broadcast from participant 2 is rejected
+	if err := p1.ReceiveBroadcast(r1Outputs[2].Broadcast); err != nil {
+		t.Errorf("redelivery of identical broadcast should be accepted: %v", err)
+	}
+	forged := &frost.Round1Data{ID: r1Outputs[1].Broadcast.ID, Commitments: r1Outputs[2].Broadcast.Commitments}
+	if err := p1.ReceiveBroadcast(forged); err == nil {
+		t.Error("conflicting broadcast should be rejected")
+	}
+
+	if err := p1.ReceivePrivateShare(r1Outputs[2].PrivateShares[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p1.ProcessRound1(nil); err != nil {
+		t.Fatal(err)
+	}
+	if st := p1.Status(); !st.Done || st.Err != nil || st.Progress() != 1 {
+		t.Errorf("expected successful completion, got %+v", st)
+	}
+}
+
+func TestProcessRound1Incomplete(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2, 3}
+	participants := make([]*Participant, 3)
+	outputs := make([]*Round1Output, 3)
+	for i := range participants {
+		participants[i], _ = NewParticipant(g, 2, 3, i+1)
+		outputs[i], _ = participants[i].GenerateRound1(rand.Reader, allIDs)
+	}
+
+	// Participant 3 has delivered its broadcast but not its share yet
+	p1 := participants[0]
+	for _, from := range []int{2, 3} {
+		if err := p1.ReceiveBroadcast(outputs[from-1].Broadcast); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p1.ReceivePrivateShare(outputs[1].PrivateShares[1]); err != nil {
+		t.Fatal(err)
+	}
+	_, err := p1.ProcessRound1(nil)
+	if err == nil || !strings.Contains(err.Error(), "private shares from [3]") {
+		t.Fatalf("err = %v, want an error naming participant 3", err)
+	}
+	if st := p1.Status(); st.Done {
+		t.Errorf("incomplete round 1 ended the DKG: %+v", st)
+	}
+
+	// The DKG completes once the share arrives
+	if err := p1.ReceivePrivateShare(outputs[2].PrivateShares[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p1.ProcessRound1(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A missing broadcast is named too
+	p2 := participants[1]
+	if _, err := p2.ProcessRound1(&Round1Input{
+		Broadcasts:    []*frost.Round1Data{outputs[0].Broadcast},
+		PrivateShares: []*frost.Round1PrivateData{outputs[0].PrivateShares[2], outputs[2].PrivateShares[2]},
+	}); err == nil || !strings.Contains(err.Error(), "broadcasts from [3]") {
+		t.Errorf("err = %v, want an error naming participant 3", err)
+	}
+}
+
+func TestCoordinatorThreshold(t *testing.T) {
+	f, _ := frost.New(&bjj.BJJ{}, 3, 4)
+	var few *frost.InsufficientSignersError
+	if _, err := NewCoordinator(f, []byte("quorum"), []int{1, 2}); !errors.As(err, &few) {
+		t.Errorf("two signers for threshold 3: err = %v, want *frost.InsufficientSignersError", err)
+	}
+	if _, err := NewCoordinator(f, []byte("quorum"), []int{1, 2, 4}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMisroutedPrivateShare(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2, 3}
+	participants := make([]*Participant, 3)
+	outputs := make([]*Round1Output, 3)
+	for i := range participants {
+		participants[i], _ = NewParticipant(g, 2, 3, i+1)
+		outputs[i], _ = participants[i].GenerateRound1(rand.Reader, allIDs)
+	}
+	p1 := participants[0]
+
+	// The share participant 2 sent to participant 3
+	if err := p1.ReceivePrivateShare(outputs[1].PrivateShares[3]); err == nil {
+		t.Error("accepted a share addressed to another participant")
+	}
+	// A share claiming to come from the receiver itself
+	self := outputs[1].PrivateShares[1]
+	spoofed := &frost.Round1PrivateData{FromID: g.NewScalar().SetUint64(1), ToID: self.ToID, Share: self.Share}
+	if err := p1.ReceivePrivateShare(spoofed); err == nil {
+		t.Error("accepted a share from the receiver's own ID")
+	}
+	if err := p1.ReceivePrivateShare(&frost.Round1PrivateData{FromID: self.FromID}); err == nil {
+		t.Error("accepted an incomplete share")
+	}
+	if err := p1.ReceivePrivateShare(nil); err == nil {
+		t.Error("accepted a nil share")
+	}
+	if err := p1.ReceiveBroadcast(nil); err == nil {
+		t.Error("accepted a nil broadcast")
+	}
+	if st := p1.Status(); len(st.Contributed) != 0 {
+		t.Errorf("rejected messages were recorded: %v", st.Contributed)
+	}
+}
+
+func TestCoordinator(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, _ := runTestDKG(t, g, 2, 3)
+	f := participants[0].FROST()
+	message := []byte("coordinated signing")
+
+	c, err := NewCoordinator(f, message, []int{1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess1, _ := participants[0].NewSigningSession(rand.Reader, message)
+	sess3, _ := participants[2].NewSigningSession(rand.Reader, message)
+
+	if err := c.AddCommitment(sess1.Commitment()); err != nil {
+		t.Fatal(err)
+	}
+	if st := c.Status(); st.Round != RoundSign1 || !slices.Equal(st.Pending, []int{3}) {
+		t.Errorf("expected participant 3 pending in round 1, got %+v", st)
+	}
+	if _, err := c.Commitments(); err == nil {
+		t.Error("commitment list should not be available yet")
+	}
+
+	if err := c.AddCommitment(&frost.SigningCommitment{ID: sess3.Commitment().ID}); err == nil {
+		t.Error("incomplete commitment should be rejected")
+	}
+
+	// Participant 2 is not part of this session
+	sess2, _ := participants[1].NewSigningSession(rand.Reader, message)
+	if err := c.AddCommitment(sess2.Commitment()); err == nil {
+		t.Error("commitment from non-signer should be rejected")
+	}
+
+	if err := c.AddCommitment(sess3.Commitment()); err != nil {
+		t.Fatal(err)
+	}
+	commitments, err := c.Commitments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	share1, _ := sess1.Sign(commitments)
+	if err := c.AddShare(share1); err != nil {
+		t.Fatal(err)
+	}
+	if st := c.Status(); st.Round != RoundSign2 || !slices.Equal(st.Pending, []int{3}) {
+		t.Errorf("expected participant 3 pending in round 2, got %+v", st)
+	}
+	if _, err := c.Aggregate(); err == nil {
+		t.Error("aggregate should fail while shares are missing")
+	}
+
+	share3, _ := sess3.Sign(commitments)
+	if err := c.AddShare(share3); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := c.Aggregate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(f, message, sig, participants[0].KeyShare().GroupKey); err != nil {
+		t.Error(err)
+	}
+	if st := c.Status(); !st.Done || st.Err != nil {
+		t.Errorf("expected completed ceremony, got %+v", st)
+	}
+}
+
+// runTestDKG runs a complete DKG between total participants and returns
+// them along with their results.
+func runTestDKG(t *testing.T, g *bjj.BJJ, threshold, total int) ([]*Participant, []*DKGResult) {
+	t.Helper()
+
+	allIDs := make([]int, total)
+	participants := make([]*Participant, total)
+	r1Outputs := make([]*Round1Output, total)
+	for i := range participants {
+		allIDs[i] = i + 1
+	}
+	for i := range participants {
+		p, err := NewParticipant(g, threshold, total, i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		participants[i] = p
+		r1Outputs[i], err = p.GenerateRound1(rand.Reader, allIDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	broadcasts := make([]*frost.Round1Data, total)
+	for i, r1 := range r1Outputs {
+		broadcasts[i] = r1.Broadcast
+	}
+
+	results := make([]*DKGResult, total)
+	for i, p := range participants {
+		var privateShares []*frost.Round1PrivateData
+		for j, r1 := range r1Outputs {
+			if i != j {
+				privateShares = append(privateShares, r1.PrivateShares[p.ID()])
+			}
+		}
+		result, err := p.ProcessRound1(&Round1Input{
+			Broadcasts:    broadcasts,
+			PrivateShares: privateShares,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results[i] = result
+	}
+	return participants, results
+}
+
+func TestQuickDKG(t *testing.T) {
+	g := &bjj.BJJ{}
+
+	keyShares, roster, err := QuickDKG(g, 3, 5, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyShares) != 5 || roster.Total() != 5 || roster.Threshold != 3 {
+		t.Fatalf("unexpected result sizes: %d shares, roster %+v", len(keyShares), roster)
+	}
+
+	for i, ks := range keyShares {
+		id := i + 1
+		if !ks.GroupKey.Equal(roster.GroupKey) {
+			t.Errorf("participant %d has a different group key", id)
+		}
+		expected := g.NewPoint().ScalarMult(ks.SecretKey, g.Generator())
+		if !roster.PublicKeys[id].Equal(expected) {
+			t.Errorf("roster public key for participant %d does not match its secret share", id)
+		}
+	}
+
+	f, _ := frost.New(g, 3, 5)
+	message := []byte("quick dkg")
+	sig, err := QuickSign(f, rand.Reader, []*frost.KeyShare{keyShares[0], keyShares[2], keyShares[4]}, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(f, message, sig, roster.GroupKey); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDKGResultPublicKeys(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, results := runTestDKG(t, g, 3, 4)
+
+	for _, result := range results {
+		if len(result.AllPublicKeys) != len(participants) {
+			t.Fatalf("expected %d public keys, got %d", len(participants), len(result.AllPublicKeys))
+		}
+		for _, p := range participants {
+			if !result.AllPublicKeys[p.ID()].Equal(p.KeyShare().PublicKey) {
+				t.Errorf("public key for participant %d does not match its key share", p.ID())
+			}
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2}
+	obs := newRecordingObserver()
+	cfg := &Config{
+		Hasher:      frost.NewBlake2bHasher(),
+		Context:     "example.com/payments",
+		NoncePolicy: NonceHedged,
+		Observer:    obs,
+	}
+
+	p1, err := NewParticipantWithConfig(g, 2, 2, 1, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, _ := NewParticipantWithConfig(g, 2, 2, 2, cfg)
+
+	r1_1, _ := p1.GenerateRound1(rand.Reader, allIDs)
+	r1_2, _ := p2.GenerateRound1(rand.Reader, allIDs)
+	broadcasts := []*frost.Round1Data{r1_1.Broadcast, r1_2.Broadcast}
+	result, err := p1.ProcessRound1(&Round1Input{
+		Broadcasts:    broadcasts,
+		PrivateShares: []*frost.Round1PrivateData{r1_2.PrivateShares[1]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p2.ProcessRound1(&Round1Input{
+		Broadcasts:    broadcasts,
+		PrivateShares: []*frost.Round1PrivateData{r1_1.PrivateShares[2]},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obs.completed[CeremonyDKG]; !ok {
+		t.Error("observer from config was not notified")
+	}
+
+	message := []byte("context-bound message")
+	sess1, _ := p1.NewSigningSession(rand.Reader, message)
+	sess2, _ := p2.NewSigningSession(rand.Reader, message)
+	commitments := []*frost.SigningCommitment{sess1.Commitment(), sess2.Commitment()}
+	share1, _ := sess1.Sign(commitments)
+	share2, _ := sess2.Sign(commitments)
+
+	f, _ := cfg.NewFROST(g, 2, 2)
+	sig, err := Aggregate(f, message, commitments, []*frost.SignatureShare{share1, share2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(f, message, sig, result.GroupKey); err != nil {
+		t.Error(err)
+	}
+
+	// A verifier with a different context must reject the signature
+	other := &Config{Hasher: frost.NewBlake2bHasher(), Context: "example.com/other"}
+	fOther, _ := other.NewFROST(g, 2, 2)
+	if err := Verify(fOther, message, sig, result.GroupKey); err == nil {
+		t.Error("signature should not verify under a different context")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2, 3}
+
+	t.Run("DKG", func(t *testing.T) {
+		obs := newRecordingObserver()
+		cfg := &Config{Timeout: time.Millisecond, Observer: obs}
+
+		p1, _ := NewParticipantWithConfig(g, 2, 3, 1, cfg)
+		p2, _ := NewParticipant(g, 2, 3, 2)
+		r1_1, _ := p1.GenerateRound1(rand.Reader, allIDs)
+		r1_2, _ := p2.GenerateRound1(rand.Reader, allIDs)
+		_ = r1_1
+
+		if _, ok := p1.Deadline(); !ok {
+			t.Fatal("expected a deadline for round 1")
+		}
+		if err := p1.ReceiveBroadcast(r1_2.Broadcast); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		// Participant 2's share arrives in the input, participant 3 never shows up
+		_, err := p1.ProcessRound1(&Round1Input{
+			PrivateShares: []*frost.Round1PrivateData{r1_2.PrivateShares[1]},
+		})
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("expected TimeoutError, got %v", err)
+		}
+		if timeoutErr.Round != RoundDKG1 {
+			t.Errorf("expected round 1 timeout, got %v", timeoutErr.Round)
+		}
+		if !slices.Equal(timeoutErr.MissingBroadcasts, []int{3}) || !slices.Equal(timeoutErr.MissingShares, []int{3}) {
+			t.Errorf("expected participant 3 missing, got %v", timeoutErr)
+		}
+		if !slices.Equal(obs.timeouts[RoundDKG1], []int{3}) {
+			t.Errorf("expected observer timeout for participant 3, got %v", obs.timeouts)
+		}
+		if st := p1.Status(); !st.Done || st.Err == nil {
+			t.Errorf("expected failed ceremony, got %+v", st)
+		}
+		if err := p1.ReceiveBroadcast(r1_2.Broadcast); err == nil {
+			t.Error("messages should be rejected after a timeout")
+		}
+	})
+
+	t.Run("Coordinator", func(t *testing.T) {
+		participants, _ := runTestDKG(t, g, 2, 3)
+		f := participants[0].FROST()
+		message := []byte("timeout")
+
+		sess, _ := participants[1].NewSigningSession(rand.Reader, message)
+		c, _ := NewCoordinatorWithConfig(f, message, []int{1, 2, 3}, &Config{Timeout: 50 * time.Millisecond})
+		if err := c.AddCommitment(sess.Commitment()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.CheckTimeout(); err != nil {
+			t.Fatalf("should not time out before the deadline: %v", err)
+		}
+		time.Sleep(60 * time.Millisecond)
+
+		_, err := c.Commitments()
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("expected TimeoutError, got %v", err)
+		}
+		if !slices.Equal(timeoutErr.MissingCommitments, []int{1, 3}) {
+			t.Errorf("expected signers 1 and 3 missing, got %v", timeoutErr.MissingCommitments)
+		}
+		if !slices.Equal(timeoutErr.Missing(), []int{1, 3}) {
+			t.Errorf("Missing() = %v", timeoutErr.Missing())
+		}
+
+		// Late commitments cannot complete the expired round
+		for _, p := range []*Participant{participants[0], participants[2]} {
+			late, _ := p.NewSigningSession(rand.Reader, message)
+			if err := c.AddCommitment(late.Commitment()); !errors.As(err, &timeoutErr) {
+				t.Errorf("AddCommitment after a timeout: got %v, want the TimeoutError", err)
+			}
+		}
+		if _, err := c.Commitments(); !errors.As(err, &timeoutErr) {
+			t.Errorf("Commitments after a timeout: got %v, want the TimeoutError", err)
+		}
+	})
+}
+
+// stallingSigner never answers until its context is done.
+type stallingSigner struct{ id int }
+
+func (s *stallingSigner) ID() int { return s.id }
+
+func (s *stallingSigner) Commit(ctx context.Context, message []byte) (*frost.SigningCommitment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *stallingSigner) Sign(ctx context.Context, message []byte, commitments []*frost.SigningCommitment) (*frost.SignatureShare, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// corruptSigner tampers with the shares of an honest signer.
+type corruptSigner struct{ Signer }
+
+func (s *corruptSigner) Sign(ctx context.Context, message []byte, commitments []*frost.SigningCommitment) (*frost.SignatureShare, error) {
+	share, err := s.Signer.Sign(ctx, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	g := &bjj.BJJ{}
+	one := g.NewScalar()
+	one.SetBytes([]byte{1})
+	share.Z = g.NewScalar().Add(share.Z, one)
+	return share, nil
+}
+
+// silentSigner answers without an error but also without a result, as a
+// faulty remote signer might. Commit or Sign stays silent as configured.
+type silentSigner struct {
+	Signer
+	silentCommit bool
+}
+
+func (s *silentSigner) Commit(ctx context.Context, message []byte) (*frost.SigningCommitment, error) {
+	if s.silentCommit {
+		return nil, nil
+	}
+	return s.Signer.Commit(ctx, message)
+}
+
+func (s *silentSigner) Sign(ctx context.Context, message []byte, commitments []*frost.SigningCommitment) (*frost.SignatureShare, error) {
+	return nil, nil
+}
+
+func TestRetryCoordinator(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, results := runTestDKG(t, g, 2, 4)
+	f := participants[0].FROST()
+	roster := &Roster{
+		Threshold:  2,
+		IDs:        []int{1, 2, 3, 4},
+		GroupKey:   results[0].GroupKey,
+		PublicKeys: results[0].AllPublicKeys,
+	}
+	message := []byte("retry")
+
+	signers := []Signer{
+		&stallingSigner{id: 1},
+		&corruptSigner{NewLocalSigner(participants[1], rand.Reader)},
+		NewLocalSigner(participants[2], rand.Reader),
+		NewLocalSigner(participants[3], rand.Reader),
+	}
+	metrics := newCountingMetrics()
+	cfg := &Config{Timeout: 50 * time.Millisecond, Metrics: metrics}
+
+	rc, err := NewRetryCoordinator(f, roster, signers, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := rc.Sign(context.Background(), message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(f, message, sig, roster.GroupKey); err != nil {
+		t.Error(err)
+	}
+	if metrics.badShares[2] != 1 {
+		t.Errorf("expected a verification failure for signer 2, got %v", metrics.badShares)
+	}
+	if metrics.failed[CeremonySigning] != 2 || metrics.completed[CeremonySigning] != 1 {
+		t.Errorf("expected two failed attempts and one success, got %d and %d",
+			metrics.failed[CeremonySigning], metrics.completed[CeremonySigning])
+	}
+
+	t.Run("Exhausted", func(t *testing.T) {
+		rc, err := NewRetryCoordinator(f, roster, signers[:3], cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = rc.Sign(context.Background(), message)
+		var exhausted *SignersExhaustedError
+		if !errors.As(err, &exhausted) {
+			t.Fatalf("expected SignersExhaustedError, got %v", err)
+		}
+		if !errors.Is(exhausted.Faulty[1], context.DeadlineExceeded) {
+			t.Errorf("expected signer 1 to be excluded for stalling, got %v", exhausted.Faulty[1])
+		}
+		if _, ok := exhausted.Faulty[2]; !ok {
+			t.Error("expected signer 2 to be excluded")
+		}
+	})
+
+	t.Run("NilResults", func(t *testing.T) {
+		signers := []Signer{
+			&silentSigner{Signer: NewLocalSigner(participants[0], rand.Reader), silentCommit: true},
+			&silentSigner{Signer: NewLocalSigner(participants[1], rand.Reader)},
+			NewLocalSigner(participants[2], rand.Reader),
+			NewLocalSigner(participants[3], rand.Reader),
+		}
+		rc, err := NewRetryCoordinator(f, roster, signers, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := rc.Sign(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(f, message, sig, roster.GroupKey); err != nil {
+			t.Error(err)
+		}
+
+		rc, err = NewRetryCoordinator(f, roster, signers[:3], cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = rc.Sign(context.Background(), message)
+		var exhausted *SignersExhaustedError
+		if !errors.As(err, &exhausted) {
+			t.Fatalf("expected SignersExhaustedError, got %v", err)
+		}
+		for _, id := range []int{1, 2} {
+			if _, ok := exhausted.Faulty[id]; !ok {
+				t.Errorf("expected signer %d to be excluded", id)
+			}
+		}
+	})
+
+	t.Run("AbandonedSessions", func(t *testing.T) {
+		locals := []*localSigner{
+			NewLocalSigner(participants[1], rand.Reader).(*localSigner),
+			NewLocalSigner(participants[2], rand.Reader).(*localSigner),
+			NewLocalSigner(participants[3], rand.Reader).(*localSigner),
+		}
+		signers := []Signer{&stallingSigner{id: 1}, locals[0], locals[1], locals[2]}
+		rc, err := NewRetryCoordinator(f, roster, signers, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Signer 2 commits in the first attempt, which fails on signer 1
+		if _, err := rc.Sign(context.Background(), message); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range locals {
+			s.mu.Lock()
+			if n := len(s.sessions); n != 0 {
+				t.Errorf("signer %d kept %d signing sessions", s.ID(), n)
+			}
+			s.mu.Unlock()
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if _, err := NewRetryCoordinator(f, roster, signers[:1], nil); err == nil {
+			t.Error("expected error for fewer signers than the threshold")
+		}
+		dup := []Signer{signers[2], NewLocalSigner(participants[2], rand.Reader)}
+		if _, err := NewRetryCoordinator(f, roster, dup, nil); err == nil {
+			t.Error("expected error for duplicate signers")
+		}
+	})
+}
+
+func TestBatchSigning(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, results := runTestDKG(t, g, 2, 3)
+	f := participants[0].FROST()
+	messages := [][]byte{[]byte("first"), []byte("second"), []byte("third")}
+
+	signers := participants[:2]
+	sessions := make([]*BatchSigningSession, len(signers))
+	for i, p := range signers {
+		sess, err := p.NewBatchSigningSession(rand.Reader, messages)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sess.Len() != len(messages) {
+			t.Fatalf("expected %d commitments, got %d", len(messages), sess.Len())
+		}
+		sessions[i] = sess
+	}
+
+	// Regroup commitments by message
+	commitments := make([][]*frost.SigningCommitment, len(messages))
+	for i := range messages {
+		for _, sess := range sessions {
+			commitments[i] = append(commitments[i], sess.Commitments()[i])
+		}
+	}
+
+	shares := make([][]*frost.SignatureShare, len(messages))
+	for _, sess := range sessions {
+		batchShares, err := sess.Sign(commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, share := range batchShares {
+			shares[i] = append(shares[i], share)
+		}
+	}
+
+	sigs, err := AggregateBatch(f, messages, commitments, shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sig := range sigs {
+		if err := Verify(f, messages[i], sig, results[0].GroupKey); err != nil {
+			t.Errorf("message %d: %v", i, err)
+		}
+	}
+
+	if _, err := sessions[0].Sign(commitments); err == nil {
+		t.Error("expected error when reusing a batch session")
+	}
+
+	t.Run("MismatchedCommitments", func(t *testing.T) {
+		sess, _ := participants[0].NewBatchSigningSession(rand.Reader, messages)
+		other, _ := participants[1].NewBatchSigningSession(rand.Reader, messages)
+
+		// Swap this signer's commitments for two messages
+		swapped := make([][]*frost.SigningCommitment, len(messages))
+		for i := range messages {
+			swapped[i] = []*frost.SigningCommitment{sess.Commitments()[i], other.Commitments()[i]}
+		}
+		swapped[0][0], swapped[1][0] = swapped[1][0], swapped[0][0]
+
+		if _, err := sess.Sign(swapped); err == nil {
+			t.Error("expected error for commitments in the wrong order")
+		}
+		if !sess.IsConsumed() {
+			t.Error("session should be consumed after a failed Sign")
+		}
+	})
+}
+
+func TestEchoBroadcast(t *testing.T) {
+	g := &bjj.BJJ{}
+	allIDs := []int{1, 2, 3}
+	cfg := &Config{EchoBroadcast: true}
+
+	setup := func(t *testing.T) ([]*Participant, []*Round1Output) {
+		t.Helper()
+		participants := make([]*Participant, len(allIDs))
+		outputs := make([]*Round1Output, len(allIDs))
+		for i, id := range allIDs {
+			p, err := NewParticipantWithConfig(g, 2, 3, id, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			participants[i] = p
+			outputs[i], err = p.GenerateRound1(rand.Reader, allIDs)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return participants, outputs
+	}
+	deliver := func(t *testing.T, p *Participant, broadcasts []*frost.Round1Data, outputs []*Round1Output) {
+		t.Helper()
+		for _, b := range broadcasts {
+			if err := p.ReceiveBroadcast(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, r1 := range outputs {
+			if share, ok := r1.PrivateShares[p.ID()]; ok {
+				if err := p.ReceivePrivateShare(share); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	t.Run("Consistent", func(t *testing.T) {
+		participants, outputs := setup(t)
+		var broadcasts []*frost.Round1Data
+		for _, r1 := range outputs {
+			broadcasts = append(broadcasts, r1.Broadcast)
+		}
+		digests := make(map[int][]byte)
+		for _, p := range participants {
+			deliver(t, p, broadcasts, outputs)
+			d, err := p.EchoDigest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			digests[p.ID()] = d
+		}
+
+		// Participant 1 insists on echoes from everyone
+		if _, err := participants[0].ProcessRound1(nil); err == nil {
+			t.Fatal("expected error for missing echoes")
+		}
+
+		for _, p := range participants[1:] {
+			echoes := make(map[int][]byte)
+			for id, d := range digests {
+				if id != p.ID() {
+					echoes[id] = d
+				}
+			}
+			if _, err := p.ProcessRound1(&Round1Input{Echoes: echoes}); err != nil {
+				t.Fatalf("participant %d: %v", p.ID(), err)
+			}
+		}
+	})
+
+	t.Run("Equivocation", func(t *testing.T) {
+		participants, outputs := setup(t)
+
+		// Participant 3 sends participant 2 a different broadcast
+		forked, _ := NewParticipant(g, 2, 3, 3)
+		forkedR1, _ := forked.GenerateRound1(rand.Reader, allIDs)
+
+		deliver(t, participants[0], []*frost.Round1Data{outputs[1].Broadcast, outputs[2].Broadcast}, outputs)
+		deliver(t, participants[1], []*frost.Round1Data{outputs[0].Broadcast, forkedR1.Broadcast}, nil)
+		deliver(t, participants[2], []*frost.Round1Data{outputs[0].Broadcast, outputs[1].Broadcast}, nil)
+
+		d1, _ := participants[0].EchoDigest()
+		d2, _ := participants[1].EchoDigest()
+		if slices.Equal(d1, d2) {
+			t.Fatal("digests of different broadcasts should differ")
+		}
+
+		if err := participants[0].ReceiveEcho(2, d2); err != nil {
+			t.Fatal(err)
+		}
+		if err := participants[0].ReceiveEcho(2, d1); err == nil {
+			t.Error("expected error for a conflicting echo")
+		}
+		d3, _ := participants[2].EchoDigest()
+		_, err := participants[0].ProcessRound1(&Round1Input{Echoes: map[int][]byte{3: d3}})
+		var mismatch *EchoMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("expected EchoMismatchError, got %v", err)
+		}
+		if !slices.Equal(mismatch.Mismatched, []int{2}) {
+			t.Errorf("expected participant 2 to disagree, got %v", mismatch.Mismatched)
+		}
+	})
+}
+
+func TestExportImportSigningSession(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, results := runTestDKG(t, g, 2, 3)
+	f := participants[0].FROST()
+	message := []byte("handed over")
+
+	// Signers that restore participants from their key shares and share
+	// one nonce store
+	store := NewMemoryNonceStore()
+	newSigner := func(id int) *Participant {
+		p, _ := NewParticipantWithConfig(g, 2, 3, id, &Config{NonceStore: store})
+		if err := p.SetKeyShare(participants[id-1].KeyShare()); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	sess1, _ := newSigner(1).NewSigningSession(rand.Reader, message)
+	sess2, _ := participants[1].NewSigningSession(rand.Reader, message)
+
+	data, err := sess1.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess1.IsConsumed() {
+		t.Error("exported session should be consumed locally")
+	}
+	if _, err := sess1.Export(); err == nil {
+		t.Error("expected error exporting a consumed session")
+	}
+
+	// Another process restores participant 1 from its key share
+	imported, err := newSigner(1).ImportSigningSession(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID() != sess1.ID() {
+		t.Error("session ID changed across export")
+	}
+	if string(imported.Message()) != string(message) {
+		t.Error("message changed across export")
+	}
+	if _, err := newSigner(1).ImportSigningSession(data); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused importing a session twice, got %v", err)
+	}
+
+	commitments := []*frost.SigningCommitment{imported.Commitment(), sess2.Commitment()}
+	share1, err := imported.Sign(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	share2, _ := sess2.Sign(commitments)
+	sig, err := Aggregate(f, message, commitments, []*frost.SignatureShare{share1, share2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(f, message, sig, results[0].GroupKey); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("NoNonceStore", func(t *testing.T) {
+		sess, _ := participants[0].NewSigningSession(rand.Reader, message)
+		if _, err := sess.Export(); err == nil {
+			t.Error("expected error exporting without a nonce store")
+		}
+		if sess.IsConsumed() {
+			t.Error("a refused export should leave the session usable")
+		}
+		data := exportTestSession(t, newSigner(1), message)
+		if _, err := participants[0].ImportSigningSession(data); err == nil {
+			t.Error("expected error importing without a nonce store")
+		}
+	})
+
+	t.Run("Rejects", func(t *testing.T) {
+		data := exportTestSession(t, newSigner(1), message)
+		p1, p2 := newSigner(1), newSigner(2)
+
+		if _, err := p2.ImportSigningSession(data); err == nil {
+			t.Error("expected error importing another participant's session")
+		}
+		tampered := slices.Clone(data)
+		tampered[len(tampered)-1] ^= 1
+		if _, err := p1.ImportSigningSession(tampered); err == nil {
+			t.Error("expected error importing a tampered session")
+		}
+		if _, err := p1.ImportSigningSession(data[:len(data)-1]); err == nil {
+			t.Error("expected error importing a truncated session")
+		}
+		// A zero-padded ID decodes to the same scalar but has the wrong size
+		padded := append([]byte{data[0]}, binary.BigEndian.AppendUint32(nil, uint32(g.ScalarSize()+1))...)
+		padded = append(padded, 0)
+		padded = append(padded, data[5:]...)
+		if _, err := p1.ImportSigningSession(padded); err == nil {
+			t.Error("expected error importing a session with an oversized field")
+		}
+		// ID + order also decodes to the same scalar, at the right size
+		aliased := slices.Clone(data)
+		id := aliased[5 : 5+g.ScalarSize()]
+		v := new(big.Int).SetBytes(id)
+		v.Add(v, new(big.Int).SetBytes(g.Order())).FillBytes(id)
+		if _, err := p1.ImportSigningSession(aliased); err == nil {
+			t.Error("expected error importing a session with a non-canonical ID")
+		}
+		// Rejected imports are not recorded
+		if _, err := p1.ImportSigningSession(data); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// exportTestSession creates a signing session of p for message and
+// exports it.
+func exportTestSession(t *testing.T, p *Participant, message []byte) []byte {
+	t.Helper()
+	sess, err := p.NewSigningSession(rand.Reader, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := sess.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestNonceStore(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, _ := runTestDKG(t, g, 2, 3)
+	message := []byte("snapshot")
+	path := t.TempDir() + "/nonces"
+
+	store, err := OpenFileNonceStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSigner := func(store NonceStore) *Participant {
+		p, _ := NewParticipantWithConfig(g, 2, 3, 1, &Config{NonceStore: store})
+		if err := p.SetKeyShare(participants[0].KeyShare()); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// Take a snapshot of an in-flight session, then sign with it
+	sess, _ := newSigner(store).NewSigningSession(rand.Reader, message)
+	other, _ := participants[1].NewSigningSession(rand.Reader, message)
+	commitments := []*frost.SigningCommitment{sess.Commitment(), other.Commitment()}
+	snapshot, _ := sess.Export()
+
+	restored, err := newSigner(store).ImportSigningSession(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restored.Sign(commitments); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a restart, the same snapshot cannot be restored again
+	store, err = OpenFileNonceStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, err := newSigner(store).ImportSigningSession(snapshot); !errors.Is(err, ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused after restart, got %v", err)
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		store := NewMemoryNonceStore()
+		if err := store.MarkUsed("a"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.MarkUsed("a"); !errors.Is(err, ErrNonceReused) {
+			t.Errorf("expected ErrNonceReused, got %v", err)
+		}
+		if err := store.MarkUsed("b"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSignerSelector(t *testing.T) {
+	available := []int{1, 2, 3, 4, 5}
+
+	t.Run("LowestID", func(t *testing.T) {
+		if got := (LowestIDSelector{}).Select(available, 3); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("RoundRobin", func(t *testing.T) {
+		s := &RoundRobinSelector{}
+		want := [][]int{{1, 2, 3}, {4, 5, 1}, {2, 3, 4}}
+		for i, w := range want {
+			if got := s.Select(available, 3); !slices.Equal(got, w) {
+				t.Errorf("request %d: got %v, want %v", i, got, w)
+			}
+		}
+		// Signer 5 dropped out; rotation continues after it
+		if got := s.Select([]int{1, 2, 3, 4}, 2); !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		s := &LatencySelector{}
+		for _, id := range available {
+			s.Report(id, time.Duration(10-id)*time.Millisecond, nil)
+		}
+		s.Report(5, time.Millisecond, errors.New("invalid share"))
+		if got := s.Select(available, 2); !slices.Equal(got, []int{3, 4}) {
+			t.Errorf("got %v", got)
+		}
+		// Unmeasured signers are tried first
+		if got := s.Select([]int{1, 6}, 1); !slices.Equal(got, []int{6}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("Reliability", func(t *testing.T) {
+		s := &ReliabilitySelector{}
+		s.Report(1, 0, context.DeadlineExceeded)
+		s.Report(2, 0, nil)
+		s.Report(4, 0, nil)
+		if got := s.Select(available, 3); !slices.Equal(got, []int{2, 3, 4}) {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("RetryCoordinator", func(t *testing.T) {
+		g := &bjj.BJJ{}
+		participants, results := runTestDKG(t, g, 2, 3)
+		roster := &Roster{
+			Threshold:  2,
+			IDs:        []int{1, 2, 3},
+			GroupKey:   results[0].GroupKey,
+			PublicKeys: results[0].AllPublicKeys,
+		}
+		signers := []Signer{
+			&stallingSigner{id: 1},
+			NewLocalSigner(participants[1], rand.Reader),
+			NewLocalSigner(participants[2], rand.Reader),
+		}
+		selector := &ReliabilitySelector{}
+		cfg := &Config{Timeout: 20 * time.Millisecond, Selector: selector}
+		rc, err := NewRetryCoordinator(participants[0].FROST(), roster, signers, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rc.Sign(context.Background(), []byte("first")); err != nil {
+			t.Fatal(err)
+		}
+
+		// The stalled signer is no longer invited first
+		if got := selector.Select([]int{1, 2, 3}, 2); !slices.Equal(got, []int{2, 3}) {
+			t.Errorf("got %v", got)
+		}
+	})
+}
+
+func TestDecodeBroadcast(t *testing.T) {
+	g := &bjj.BJJ{}
+	p, _ := NewParticipant(g, 2, 3, 1)
+	r1, err := p.GenerateRound1(rand.Reader, []int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := make([][]byte, len(r1.Broadcast.Commitments))
+	for i, c := range r1.Broadcast.Commitments {
+		encoded[i] = c.Bytes()
+	}
+	decoded, err := p.DecodeBroadcast(1, encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameBroadcast(decoded, r1.Broadcast) {
+		t.Error("decoded broadcast differs from the original")
+	}
+
+	encoded[1] = encoded[1][:16]
+	if _, err := p.DecodeBroadcast(1, encoded); err == nil {
+		t.Error("expected error decoding a truncated commitment")
+	}
+}
+
+func TestStreamSigningSession(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, results := runTestDKG(t, g, 2, 3)
+	payload := func() io.Reader {
+		return io.LimitReader(mrand.NewChaCha8([32]byte{1}), 1<<20)
+	}
+
+	sess1, err := participants[0].NewStreamSigningSession(rand.Reader, payload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess2, err := participants[1].NewStreamSigningSession(rand.Reader, payload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sess1.Message(), sess2.Message()) {
+		t.Fatal("signers computed different prehashes")
+	}
+	commitments := []*frost.SigningCommitment{sess1.Commitment(), sess2.Commitment()}
+	share1, _ := sess1.Sign(commitments)
+	share2, _ := sess2.Sign(commitments)
+
+	f, _ := frost.New(g, 2, 3)
+	sig, err := Aggregate(f, sess1.Message(), commitments, []*frost.SignatureShare{share1, share2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReader(f, payload(), sig, results[0].GroupKey); err != nil {
+		t.Error(err)
+	}
+	tampered := io.MultiReader(payload(), bytes.NewReader([]byte{0}))
+	if err := VerifyReader(f, tampered, sig, results[0].GroupKey); err == nil {
+		t.Error("signature verified for a different payload")
+	}
+
+	// The context is bound into the streamed H4 as into H4
+	cfg := &Config{Context: "example.com/artifacts"}
+	h := cfg.hasher().(frost.StreamHasher)
+	d, err := h.NewH4(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Write([]byte("artifact"))
+	if !bytes.Equal(d.Sum(nil), h.H4(g, []byte("artifact"))) {
+		t.Error("streamed H4 differs from H4 with a context")
+	}
+	cfg.Hasher = frost.NewPoseidonHasher()
+	fp, _ := cfg.NewFROST(g, 2, 3)
+	if _, err := fp.PrehashMessage(payload()); err == nil {
+		t.Error("PrehashMessage accepted a hasher that cannot stream")
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	g := &bjj.BJJ{}
+	participants, _ := runTestDKG(t, g, 2, 3)
+	for _, share := range participants[0].privateShares {
+		if !share.Share.IsZero() {
+			t.Error("private share was not wiped after the DKG")
+		}
+	}
+
+	sess, err := participants[0].NewSigningSession(rand.Reader, []byte("abandoned"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := sess.nonce.D
+	sess.Zeroize()
+	sess.Zeroize()
+	if !d.IsZero() {
+		t.Error("Zeroize did not wipe the nonce")
+	}
+	if !sess.IsConsumed() {
+		t.Error("Zeroize did not consume the session")
+	}
+	if _, err := sess.Sign([]*frost.SigningCommitment{sess.Commitment()}); err == nil {
+		t.Error("zeroized session signed")
+	}
+}
+
+func TestGuardedMemory(t *testing.T) {
+	g := &bjj.BJJ{}
+	cfg := &Config{GuardedMemory: true, NoncePolicy: NonceHedged, NonceStore: NewMemoryNonceStore()}
+	p1, _ := NewParticipantWithConfig(g, 2, 2, 1, cfg)
+	p2, _ := NewParticipantWithConfig(g, 2, 2, 2, cfg)
+	r1_1, _ := p1.GenerateRound1(rand.Reader, []int{1, 2})
+	r1_2, _ := p2.GenerateRound1(rand.Reader, []int{1, 2})
+	broadcasts := []*frost.Round1Data{r1_1.Broadcast, r1_2.Broadcast}
+	result1, err := p1.ProcessRound1(&Round1Input{
+		Broadcasts:    broadcasts,
+		PrivateShares: []*frost.Round1PrivateData{r1_2.PrivateShares[1]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result2, err := p2.ProcessRound1(&Round1Input{
+		Broadcasts:    broadcasts,
+		PrivateShares: []*frost.Round1PrivateData{r1_1.PrivateShares[2]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wiping the stored result leaves the sealed copy usable
+	result2.KeyShare.Zeroize()
+	if p1.KeyShare().SecretKey != nil {
+		t.Error("participant keeps its secret on the heap")
+	}
+	if result1.KeyShare.SecretKey == nil {
+		t.Fatal("DKG result lost the secret")
+	}
+	f, _ := cfg.NewFROST(g, 2, 2)
+
+	message := []byte("guarded")
+	sess1, err := p1.NewSigningSession(rand.Reader, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess2, _ := p2.NewSigningSession(rand.Reader, message)
+	if sess1.nonce.D != nil {
+		t.Error("session keeps its nonce on the heap")
+	}
+	commitments := []*frost.SigningCommitment{sess1.Commitment(), sess2.Commitment()}
+	share1, err := sess1.Sign(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	share2, _ := sess2.Sign(commitments)
+	sig, _ := Aggregate(f, message, commitments, []*frost.SignatureShare{share1, share2})
+	if err := Verify(f, message, sig, result1.GroupKey); err != nil {
+		t.Error(err)
+	}
+
+	messages := [][]byte{[]byte("first"), []byte("second")}
+	b1, err := p1.NewBatchSigningSession(rand.Reader, messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2, _ := p2.NewBatchSigningSession(rand.Reader, messages)
+	batchCommitments := make([][]*frost.SigningCommitment, len(messages))
+	for i := range messages {
+		batchCommitments[i] = []*frost.SigningCommitment{b1.Commitments()[i], b2.Commitments()[i]}
+	}
+	shares1, err := b1.Sign(batchCommitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares2, _ := b2.Sign(batchCommitments)
+	batchShares := [][]*frost.SignatureShare{
+		{shares1[0], shares2[0]},
+		{shares1[1], shares2[1]},
+	}
+	sigs, err := AggregateBatch(f, messages, batchCommitments, batchShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sig := range sigs {
+		if err := Verify(f, messages[i], sig, result1.GroupKey); err != nil {
+			t.Errorf("message %d: %v", i, err)
+		}
+	}
+
+	// A restored participant seals the secret of the saved key share
+	sess1, _ = p1.NewSigningSession(rand.Reader, message)
+	sess2, _ = p2.NewSigningSession(rand.Reader, message)
+	data, err := sess1.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewParticipantWithConfig(g, 2, 2, 1, cfg)
+	if err := restored.SetKeyShare(p1.KeyShare()); err == nil {
+		t.Error("SetKeyShare accepted a key share without secret")
+	}
+	if err := restored.SetKeyShare(result1.KeyShare); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := restored.ImportSigningSession(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitments = []*frost.SigningCommitment{imported.Commitment(), sess2.Commitment()}
+	share1, err = imported.Sign(commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	share2, _ = sess2.Sign(commitments)
+	sig, _ = Aggregate(f, message, commitments, []*frost.SignatureShare{share1, share2})
+	if err := Verify(f, message, sig, result1.GroupKey); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRequireConstantTime(t *testing.T) {
+	g := &bjj.BJJ{}
+	cfg := &Config{RequireConstantTime: true, Context: "app"}
+	if _, err := NewParticipantWithConfig(g, 2, 3, 1, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Hasher = struct{ frost.Hasher }{&frost.SHA256Hasher{}}
+	if _, err := NewParticipantWithConfig(g, 2, 3, 1, cfg); err == nil {
+		t.Error("accepted a hasher without constant-time nonces")
+	}
+}
+
+func TestConfigInvalidHasher(t *testing.T) {
+	cfg := &Config{Hasher: &frost.SHAKE256Hasher{ScalarLength: 32}, Context: "app"}
+	if _, err := NewParticipantWithConfig(&bjj.BJJ{}, 2, 3, 1, cfg); err == nil {
+		t.Error("accepted a SHAKE256Hasher with a 32-byte ScalarLength")
+	}
+}
+
+func TestConfigContextIden3(t *testing.T) {
+	hash := func([]*big.Int) (*big.Int, error) { return big.NewInt(1), nil }
+	for _, h := range []frost.Hasher{
+		&bjj.Iden3Hasher{Hash: hash},
+		frost.NewHKDFNonceHasher(&bjj.Iden3Hasher{Hash: hash}, nil),
+	} {
+		cfg := &Config{Hasher: h, Context: "app"}
+		if _, err := NewParticipantWithConfig(&bjj.BJJ{}, 2, 3, 1, cfg); err == nil {
+			t.Errorf("%T: accepted a Context with Iden3Hasher", h)
+		}
+		cfg.Context = ""
+		if _, err := NewParticipantWithConfig(&bjj.BJJ{}, 2, 3, 1, cfg); err != nil {
+			t.Errorf("%T: %v", h, err)
+		}
+	}
+}
+
+func TestCheckTranscriptDigests(t *testing.T) {
+	participants, results := runTestDKG(t, &bjj.BJJ{}, 2, 3)
+	if len(results[0].TranscriptDigest) == 0 {
+		t.Fatal("DKG result has no transcript digest")
+	}
+	digests := make(map[int][]byte)
+	for i, r := range results {
+		digests[participants[i].ID()] = r.TranscriptDigest
+	}
+	if err := CheckTranscriptDigests(results[0], digests); err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(participants[1].KeyShare().TranscriptDigest, results[0].TranscriptDigest) {
+		t.Error("key share and DKG result digests differ")
+	}
+
+	digests[3] = []byte("skewed")
+	var mismatch *EchoMismatchError
+	if err := CheckTranscriptDigests(results[0], digests); !errors.As(err, &mismatch) || !slices.Equal(mismatch.Mismatched, []int{3}) {
+		t.Errorf("err = %v, want *EchoMismatchError for participant 3", err)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	participants, _ := runTestDKG(t, &bjj.BJJ{}, 2, 3)
+	p := participants[0]
+	sess, _ := p.NewSigningSession(rand.Reader, []byte("log me"))
+	batch, _ := p.NewBatchSigningSession(rand.Reader, [][]byte{[]byte("a"), []byte("b")})
+	secrets := [][]byte{p.KeyShare().SecretKey.Bytes(), sess.nonce.D.Bytes(), sess.nonce.E.Bytes(), batch.nonces[0].D.Bytes()}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	var out []string
+	for _, v := range []any{p, sess, batch} {
+		for _, verb := range []string{"%v", "%+v", "%#v"} {
+			out = append(out, fmt.Sprintf(verb, v))
+		}
+		logger.Info("value", "v", v)
+	}
+	out = append(out, logs.String())
+	for _, s := range out {
+		if !strings.Contains(s, "[REDACTED]") {
+			t.Errorf("output does not redact secrets: %s", s)
+		}
+		for _, secret := range secrets {
+			if strings.Contains(s, hex.EncodeToString(secret)) {
+				t.Errorf("output leaks a secret: %s", s)
+			}
+		}
+	}
+	if got := sess.String(); !strings.Contains(got, sess.ID()) {
+		t.Errorf("SigningSession.String() = %s, want the session ID", got)
+	}
+	sess.Zeroize()
+	if got := sess.String(); !strings.Contains(got, "<wiped>") {
+		t.Errorf("consumed SigningSession.String() = %s", got)
+	}
+}
+
+func TestSignWithProof(t *testing.T) {
+	participants, _ := runTestDKG(t, &bjj.BJJ{}, 2, 3)
+	message := []byte("attributable")
+	sessions := make([]*SigningSession, 2)
+	commitments := make([]*frost.SigningCommitment, 2)
+	for i := range sessions {
+		sessions[i], _ = participants[i].NewSigningSession(rand.Reader, message)
+		commitments[i] = sessions[i].Commitment()
+	}
+
+	share, proof, err := sessions[0].SignWithProof(rand.Reader, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := participants[0].frost
+	groupKey := participants[0].KeyShare().GroupKey
+	if !f.VerifySignatureShareProof(share, proof, groupKey, message, commitments) {
+		t.Error("proof from SignWithProof rejected")
+	}
+	if !proof.PublicKey.Equal(participants[0].KeyShare().PublicKey) {
+		t.Error("proof is not for the signer's public key")
+	}
+	if _, _, err := sessions[0].SignWithProof(rand.Reader, commitments); err == nil {
+		t.Error("SignWithProof reused a consumed session")
+	}
+}
+
+func FuzzDecodeBroadcast(f *testing.F) {
+	g := &bjj.BJJ{}
+	p, err := NewParticipant(g, 2, 3, 1)
+	if err != nil {
+		f.Fatal(err)
+	}
+	out, err := p.GenerateRound1(rand.Reader, []int{1, 2, 3})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(2, bytes.Join(g.EncodePoints(out.Broadcast.Commitments), nil))
+	f.Add(2, []byte{})
+	f.Add(0, make([]byte, 31))
+	f.Fuzz(func(t *testing.T, from int, data []byte) {
+		// Split data into encodings of the group's size, as a transport would
+		size := g.ElementSize()
+		if len(data) > 16*size {
+			return
+		}
+		var commitments [][]byte
+		for len(data) > 0 {
+			n := min(size, len(data))
+			commitments = append(commitments, data[:n])
+			data = data[n:]
+		}
+		b, err := p.DecodeBroadcast(from, commitments)
+		if err != nil {
+			return
+		}
+		for i, c := range b.Commitments {
+			if !bytes.Equal(c.Bytes(), commitments[i]) {
+				t.Fatalf("commitment %d: %x decodes to a point encoded as %x", i, commitments[i], c.Bytes())
+			}
+		}
+		// Receiving it must fail cleanly or succeed, never panic
+		q, _ := NewParticipant(g, 2, 3, 1)
+		if _, err := q.GenerateRound1(rand.Reader, []int{1, 2, 3}); err != nil {
+			t.Fatal(err)
+		}
+		q.ReceiveBroadcast(b)
+	})
+}
+
+func FuzzImportSigningSession(f *testing.F) {
+	g := &bjj.BJJ{}
+	keyShares, _, err := QuickDKG(g, 2, 3, rand.Reader)
+	if err != nil {
+		f.Fatal(err)
+	}
+	// Every input is imported by a participant with an empty nonce store,
+	// so that repeated inputs are not refused as reimports
+	newImporter := func(tb testing.TB) *Participant {
+		p, err := NewParticipantWithConfig(g, 2, 3, 1, &Config{NonceStore: NewMemoryNonceStore()})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if err := p.SetKeyShare(keyShares[0]); err != nil {
+			tb.Fatal(err)
+		}
+		return p
+	}
+	sess, err := newImporter(f).NewSigningSession(rand.Reader, []byte("message"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	data, err := sess.Export()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add(data[:len(data)-1])
+	f.Add([]byte{exportVersion})
+	f.Add([]byte{exportVersion, 0xff, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := newImporter(t).ImportSigningSession(data)
+		if err != nil {
+			return
+		}
+		again, err := s.Export()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(again, data) {
+			t.Fatalf("import of %x re-exports as %x", data, again)
+		}
+	})
+}
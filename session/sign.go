@@ -17,10 +17,14 @@ type SigningSession struct {
 	mu         sync.Mutex
 	frost      *frost.FROST
 	keyShare   *frost.KeyShare
+	secret     *sealed // the key share's secret, with GuardedMemory
 	message    []byte
 	nonce      *frost.SigningNonce
+	sealed     *sealed // the nonce's scalars, with GuardedMemory
 	commitment *frost.SigningCommitment
 	consumed   bool
+	progress   *tracker
+	nonceStore NonceStore
 }
 
 // NewSigningSession creates a new signing session for the given message.
@@ -30,14 +34,26 @@ type SigningSession struct {
 //
 // The participant must have completed DKG before creating signing sessions.
 func (p *Participant) NewSigningSession(rng io.Reader, message []byte) (*SigningSession, error) {
-	if p.keyShare == nil {
-		return nil, errors.New("DKG not complete: no key share available")
+	keyShare, secret, err := p.signingKey()
+	if err != nil {
+		return nil, err
 	}
 
-	nonce, commitment, err := p.frost.SignRound1(rng, p.keyShare)
+	progress := newTracker(p.config)
+	progress.startRound(RoundSign1)
+
+	nonce, commitment, err := p.signRound1(rng, keyShare, secret, message)
 	if err != nil {
+		progress.complete(err)
 		return nil, err
 	}
+	var sealedNonce *sealed
+	if p.config.GuardedMemory {
+		if sealedNonce, err = sealNonces(p.group, nonce); err != nil {
+			progress.complete(err)
+			return nil, err
+		}
+	}
 
 	// Copy message to prevent external modification
 	msgCopy := make([]byte, len(message))
@@ -45,13 +61,49 @@ func (p *Participant) NewSigningSession(rng io.Reader, message []byte) (*Signing
 
 	return &SigningSession{
 		frost:      p.frost,
-		keyShare:   p.keyShare,
+		keyShare:   keyShare,
+		secret:     secret,
 		message:    msgCopy,
 		nonce:      nonce,
+		sealed:     sealedNonce,
 		commitment: commitment,
+		progress:   progress,
+		nonceStore: p.config.NonceStore,
 	}, nil
 }
 
+// NewStreamSigningSession is like [Participant.NewSigningSession] for a
+// message read from r, which may be too large to hold in memory. The
+// session signs the prehash of the message computed by
+// [frost.FROST.PrehashMessage], and that digest is what Message returns
+// and what the coordinator aggregates; verifiers check the signature with
+// [VerifyReader]. The configured hasher must implement
+// [frost.StreamHasher].
+func (p *Participant) NewStreamSigningSession(rng, r io.Reader) (*SigningSession, error) {
+	if _, _, err := p.signingKey(); err != nil {
+		return nil, err
+	}
+	digest, err := p.frost.PrehashMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	return p.NewSigningSession(rng, digest)
+}
+
+// signRound1 generates signing nonces for message with keyShare and its
+// sealed secret according to the configured nonce policy.
+func (p *Participant) signRound1(rng io.Reader, keyShare *frost.KeyShare, secret *sealed, message []byte) (*frost.SigningNonce, *frost.SigningCommitment, error) {
+	if p.config.NoncePolicy == NonceHedged {
+		opened, release, err := openKeyShare(keyShare, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer release()
+		return p.frost.SignRound1Hedged(rng, opened, message)
+	}
+	return p.frost.SignRound1(rng, keyShare)
+}
+
 // Commitment returns the public commitment that must be broadcast to other signers.
 func (s *SigningSession) Commitment() *frost.SigningCommitment {
 	return s.commitment
@@ -68,7 +120,9 @@ func (s *SigningSession) Message() []byte {
 // signers, including this participant's own commitment.
 //
 // This method consumes the session. Calling Sign a second time returns
-// an error to prevent nonce reuse, which would compromise security.
+// an error to prevent nonce reuse, which would compromise security. If a
+// [NonceStore] is configured, the session is also recorded there, and Sign
+// returns [ErrNonceReused] if it was recorded before.
 //
 // After Sign returns (successfully or not), the internal nonces are zeroed.
 func (s *SigningSession) Sign(allCommitments []*frost.SigningCommitment) (*frost.SignatureShare, error) {
@@ -85,31 +139,103 @@ func (s *SigningSession) Sign(allCommitments []*frost.SigningCommitment) (*frost
 	// Ensure nonces are zeroed after this call, regardless of success
 	defer s.zeroNonces()
 
+	s.progress.startRound(RoundSign2)
+	share, _, err := s.sign(allCommitments, nil)
+	s.progress.complete(err)
+	return share, err
+}
+
+// SignWithProof is like [SigningSession.Sign] but also returns a proof
+// that the share is correct, drawing its randomness from rng. Anyone with
+// the session's public inputs can check it with
+// [frost.FROST.VerifySignatureShareProof], so a bad share can be blamed on
+// its signer without trusting the coordinator that relayed it.
+func (s *SigningSession) SignWithProof(rng io.Reader, allCommitments []*frost.SigningCommitment) (*frost.SignatureShare, *frost.SignatureShareProof, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.consumed {
+		return nil, nil, errors.New("session already consumed: nonce reuse prevented")
+	}
+	s.consumed = true
+	defer s.zeroNonces()
+
+	s.progress.startRound(RoundSign2)
+	share, proof, err := s.sign(allCommitments, rng)
+	s.progress.complete(err)
+	return share, proof, err
+}
+
+// sign checks the commitment list and computes the signature share, and
+// a proof for it if proofRNG is not nil.
+func (s *SigningSession) sign(allCommitments []*frost.SigningCommitment, proofRNG io.Reader) (*frost.SignatureShare, *frost.SignatureShareProof, error) {
+	if err := s.frost.CheckCommitments(allCommitments); err != nil {
+		return nil, nil, err
+	}
+
 	// Verify our commitment is in the list
 	found := false
 	for _, c := range allCommitments {
 		if c.ID.Equal(s.commitment.ID) {
 			found = true
-			break
+			continue
 		}
+		s.progress.received(RoundSign1, scalarToInt(c.ID))
 	}
 	if !found {
-		return nil, errors.New("own commitment not found in commitment list")
+		return nil, nil, errors.New("own commitment not found in commitment list")
+	}
+
+	// Record the nonces as used before the share can leave this process
+	if s.nonceStore != nil {
+		if err := s.nonceStore.MarkUsed(s.ID()); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return s.frost.SignRound2(s.keyShare, s.nonce, s.message, allCommitments)
+	keyShare, release, err := openKeyShare(s.keyShare, s.secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	closeNonce, err := openNonces(s.sealed, s.nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeNonce()
+	share, err := s.frost.SignRound2(keyShare, s.nonce, s.message, allCommitments)
+	if err != nil || proofRNG == nil {
+		return share, nil, err
+	}
+	proof, err := s.frost.ProveSignatureShare(proofRNG, keyShare, share, s.message, allCommitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return share, proof, nil
 }
 
 // zeroNonces zeroes out the secret nonce values to prevent accidental reuse.
 func (s *SigningSession) zeroNonces() {
+	s.sealed.destroy()
+	s.sealed = nil
 	if s.nonce == nil {
 		return
 	}
-	// Zero the nonce scalars by setting them to a new zero scalar
-	// This is a best-effort cleanup; Go doesn't guarantee memory zeroing
+	// This is a best-effort cleanup; see the memwipe package
+	s.nonce.Zeroize()
 	s.nonce = nil
 }
 
+// Zeroize wipes the session's secret nonces and consumes it, so that a
+// session abandoned before signing keeps no secrets in memory. It is safe
+// to call more than once, and a consumed session cannot sign.
+func (s *SigningSession) Zeroize() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumed = true
+	s.zeroNonces()
+}
+
 // IsConsumed returns true if this session has already been used for signing.
 func (s *SigningSession) IsConsumed() bool {
 	s.mu.Lock()
@@ -156,6 +282,16 @@ func Verify(f *frost.FROST, message []byte, sig *frost.Signature, groupKey group
 	return nil
 }
 
+// VerifyReader is like [Verify] for a message read from r, as signed by
+// sessions created with [Participant.NewStreamSigningSession].
+func VerifyReader(f *frost.FROST, r io.Reader, sig *frost.Signature, groupKey group.Point) error {
+	digest, err := f.PrehashMessage(r)
+	if err != nil {
+		return err
+	}
+	return Verify(f, digest, sig, groupKey)
+}
+
 // QuickSign performs a complete signing operation when all key shares are local.
 //
 // This is useful for testing or single-machine threshold setups where all
@@ -186,11 +322,15 @@ func QuickSign(
 		commitments[i] = commitment
 	}
 
-	// Round 2: Generate signature shares
+	// Round 2: Generate signature shares in one prepared session
+	prepared, err := f.Prepare(signerShares[0].GroupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
 	shares := make([]*frost.SignatureShare, len(signerShares))
 
 	for i, keyShare := range signerShares {
-		share, err := f.SignRound2(keyShare, nonces[i], message, commitments)
+		share, err := prepared.Sign(keyShare, nonces[i])
 		if err != nil {
 			return nil, err
 		}
@@ -198,5 +338,5 @@ func QuickSign(
 	}
 
 	// Aggregate
-	return f.Aggregate(message, commitments, shares)
+	return prepared.Aggregate(shares)
 }
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"slices"
+	"time"
+)
+
+// Status is a snapshot of a ceremony's progress, as reported by
+// [Participant.Status] and [Coordinator.Status]. It is intended for
+// operators diagnosing a stalled ceremony.
+type Status struct {
+	// Ceremony is the kind of ceremony in progress. It is zero if no
+	// ceremony has been started.
+	Ceremony Ceremony
+
+	// Round is the current round, or the last round if Done is set.
+	Round Round
+
+	// Done reports whether the ceremony has finished.
+	Done bool
+
+	// Err is the error the ceremony failed with, if Done is set and the
+	// ceremony was unsuccessful.
+	Err error
+
+	// Contributed lists the IDs of peers whose messages for the current
+	// round have been received, in ascending order.
+	Contributed []int
+
+	// Pending lists the IDs of peers whose messages for the current round
+	// are still outstanding, in ascending order.
+	Pending []int
+
+	// Started is when the ceremony began.
+	Started time.Time
+
+	// Elapsed is the time since the ceremony began, or its total duration
+	// if Done is set.
+	Elapsed time.Duration
+
+	// Deadline is when the current round times out. It is zero if no
+	// timeout is configured or the ceremony is done.
+	Deadline time.Time
+}
+
+// Progress returns the fraction of expected contributions received in the
+// current round, in the range [0, 1]. A finished ceremony reports 1.
+func (s Status) Progress() float64 {
+	if s.Done {
+		return 1
+	}
+	expected := len(s.Contributed) + len(s.Pending)
+	if expected == 0 {
+		return 0
+	}
+	return float64(len(s.Contributed)) / float64(expected)
+}
+
+// Status reports the progress of this participant's DKG ceremony.
+//
+// While waiting for round 1 messages, Contributed and Pending partition
+// the other participants by whether both their broadcast and their private
+// share have been received.
+func (p *Participant) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dkg == nil {
+		return Status{Done: p.finalized}
+	}
+
+	var contributed, pending []int
+	if !p.dkg.done {
+		for _, id := range p.roster {
+			if id == p.id {
+				continue
+			}
+			_, gotBroadcast := p.broadcasts[id]
+			_, gotShare := p.privateShares[id]
+			if gotBroadcast && gotShare {
+				contributed = append(contributed, id)
+			} else {
+				pending = append(pending, id)
+			}
+		}
+		slices.Sort(contributed)
+		slices.Sort(pending)
+	}
+	return p.dkg.status(contributed, pending)
+}
+
+// Progress returns the fraction of round 1 messages received so far.
+// See [Status.Progress].
+func (p *Participant) Progress() float64 {
+	return p.Status().Progress()
+}
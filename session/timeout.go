@@ -0,0 +1,184 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TimeoutError is returned when a round's deadline passes before every
+// expected message has arrived. It lists exactly which participants failed
+// to deliver, so that operators can follow up with the right people.
+type TimeoutError struct {
+	// Round is the round that timed out.
+	Round Round
+
+	// Deadline is when the round timed out.
+	Deadline time.Time
+
+	// MissingBroadcasts lists participants whose DKG broadcast was not received.
+	MissingBroadcasts []int
+
+	// MissingShares lists participants whose DKG private share or signature
+	// share was not received.
+	MissingShares []int
+
+	// MissingCommitments lists signers whose signing commitment was not received.
+	MissingCommitments []int
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	var parts []string
+	if len(e.MissingBroadcasts) > 0 {
+		parts = append(parts, fmt.Sprintf("broadcasts from %v", e.MissingBroadcasts))
+	}
+	if len(e.MissingShares) > 0 {
+		parts = append(parts, fmt.Sprintf("shares from %v", e.MissingShares))
+	}
+	if len(e.MissingCommitments) > 0 {
+		parts = append(parts, fmt.Sprintf("commitments from %v", e.MissingCommitments))
+	}
+	return fmt.Sprintf("%s timed out waiting for %s", e.Round, strings.Join(parts, ", "))
+}
+
+// Missing returns the IDs of all participants that failed to deliver a
+// message, in ascending order and without duplicates.
+func (e *TimeoutError) Missing() []int {
+	var ids []int
+	ids = append(ids, e.MissingBroadcasts...)
+	ids = append(ids, e.MissingShares...)
+	ids = append(ids, e.MissingCommitments...)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// expire fails the ceremony tracked by t with err, notifying the observer
+// of every missing participant first.
+func (t *tracker) expire(err *TimeoutError) {
+	for _, id := range err.Missing() {
+		t.observer.OnParticipantTimeout(err.Round, id)
+	}
+	t.complete(err)
+}
+
+// deadline returns when the current round times out. It returns false if
+// no timeout is configured or the ceremony is not in progress.
+func (t *tracker) deadline() (time.Time, bool) {
+	if t.timeout <= 0 || t.round == 0 || t.done {
+		return time.Time{}, false
+	}
+	return t.roundStarted.Add(t.timeout), true
+}
+
+// expired reports whether the current round's deadline has passed.
+func (t *tracker) expired() bool {
+	deadline, ok := t.deadline()
+	return ok && !time.Now().Before(deadline)
+}
+
+// Deadline returns when the current DKG round times out. It returns false
+// if no timeout is configured or no DKG round is in progress.
+func (p *Participant) Deadline() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dkg == nil {
+		return time.Time{}, false
+	}
+	return p.dkg.deadline()
+}
+
+// CheckTimeout returns a [*TimeoutError] if the current DKG round's
+// deadline has passed while messages are still outstanding. The ceremony
+// is then failed and the observer is notified of every missing
+// participant. It returns nil if the round has not timed out.
+//
+// Applications typically call CheckTimeout from a timer set to the time
+// returned by [Participant.Deadline].
+func (p *Participant) CheckTimeout() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.checkTimeout()
+}
+
+// checkTimeout implements CheckTimeout with p.mu held.
+func (p *Participant) checkTimeout() error {
+	if p.dkg == nil || !p.dkg.expired() {
+		return nil
+	}
+	missingBroadcasts, missingShares := p.missingMessages()
+	if len(missingBroadcasts) == 0 && len(missingShares) == 0 {
+		return nil
+	}
+	deadline, _ := p.dkg.deadline()
+	err := &TimeoutError{
+		Round:             p.dkg.round,
+		Deadline:          deadline,
+		MissingBroadcasts: missingBroadcasts,
+		MissingShares:     missingShares,
+	}
+	p.dkg.expire(err)
+	return err
+}
+
+// missingMessages returns the peers whose broadcast or private share has
+// not been received.
+func (p *Participant) missingMessages() (broadcasts, shares []int) {
+	for _, id := range p.roster {
+		if id == p.id {
+			continue
+		}
+		if _, ok := p.broadcasts[id]; !ok {
+			broadcasts = append(broadcasts, id)
+		}
+		if _, ok := p.privateShares[id]; !ok {
+			shares = append(shares, id)
+		}
+	}
+	slices.Sort(broadcasts)
+	slices.Sort(shares)
+	return broadcasts, shares
+}
+
+// Deadline returns when the current signing round times out. It returns
+// false if no timeout is configured or the ceremony is complete.
+func (c *Coordinator) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.progress.deadline()
+}
+
+// CheckTimeout returns a [*TimeoutError] if the current round's deadline
+// has passed while commitments or shares are still outstanding. The
+// ceremony is then failed and the observer is notified of every missing
+// signer. It returns nil if the round has not timed out.
+func (c *Coordinator) CheckTimeout() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.checkTimeout()
+}
+
+// checkTimeout implements CheckTimeout with c.mu held.
+func (c *Coordinator) checkTimeout() error {
+	if !c.progress.expired() {
+		return nil
+	}
+	missing := c.missing(c.progress.round)
+	if len(missing) == 0 {
+		return nil
+	}
+	deadline, _ := c.progress.deadline()
+	err := &TimeoutError{Round: c.progress.round, Deadline: deadline}
+	if c.progress.round == RoundSign1 {
+		err.MissingCommitments = missing
+	} else {
+		err.MissingShares = missing
+	}
+	c.progress.expire(err)
+	return err
+}
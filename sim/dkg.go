@@ -0,0 +1,250 @@
+package sim
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/session"
+)
+
+// DKGOutcome is the result of a simulated DKG.
+type DKGOutcome struct {
+	// Results holds the result of every honest participant that completed
+	// the DKG.
+	Results map[int]*session.DKGResult
+
+	// Errors holds the error of every honest participant that did not.
+	// Participants still waiting for messages at the end have a
+	// [*StalledError].
+	Errors map[int]error
+
+	// Trace lists every message sent, in the order it was sent.
+	Trace []Event
+
+	// Ticks is the tick at which the last message was delivered.
+	Ticks int
+}
+
+// KeyShares returns the key shares of the participants that completed
+// the DKG, ordered by ID.
+func (o *DKGOutcome) KeyShares() []*frost.KeyShare {
+	var shares []*frost.KeyShare
+	for _, id := range slices.Sorted(maps.Keys(o.Results)) {
+		shares = append(shares, o.Results[id].KeyShare)
+	}
+	return shares
+}
+
+// Agreed reports whether every participant that completed the DKG
+// obtained the same group key.
+func (o *DKGOutcome) Agreed() bool {
+	var first group.Point
+	for _, r := range o.Results {
+		if first == nil {
+			first = r.GroupKey
+		} else if !first.Equal(r.GroupKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// dkgNode is a participant of a simulated DKG.
+type dkgNode struct {
+	id       int
+	p        *session.Participant
+	echoes   map[int]bool
+	echoSent bool
+	done     bool
+}
+
+// RunDKG simulates a DKG with the participants 1 to cfg.Total. It returns
+// an error only if cfg is invalid; the outcome of each participant is
+// reported in the DKGOutcome.
+func RunDKG(cfg *Config) (*DKGOutcome, error) {
+	c, err := cfg.check()
+	if err != nil {
+		return nil, err
+	}
+	ids := c.ids()
+	net := newNetwork(c)
+	out := &DKGOutcome{Results: make(map[int]*session.DKGResult), Errors: make(map[int]error)}
+
+	nodes := make(map[int]*dkgNode, len(ids))
+	for _, id := range ids {
+		p, err := session.NewParticipantWithConfig(c.Group, c.Threshold, c.Total, id, c.sessionConfig())
+		if err != nil {
+			return nil, err
+		}
+		nodes[id] = &dkgNode{id: id, p: p, echoes: make(map[int]bool)}
+	}
+
+	// Round 1: every dealer sends its broadcast and shares at tick 0
+	for _, id := range ids {
+		n := nodes[id]
+		r1, err := n.p.GenerateRound1(c.reader("dkg", id), ids)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.sendRound1(net, id, ids, r1); err != nil {
+			return nil, err
+		}
+	}
+
+	fail := func(n *dkgNode, err error) {
+		n.done = true
+		if c.behavior(n.id) == Honest {
+			out.Errors[n.id] = err
+		}
+	}
+	for {
+		m, ok := net.next()
+		if !ok {
+			break
+		}
+		n := nodes[m.To]
+		if n.done {
+			continue
+		}
+		var err error
+		switch m.Kind {
+		case KindBroadcast:
+			err = n.p.ReceiveBroadcast(m.Payload.(*frost.Round1Data))
+		case KindShare:
+			err = n.p.ReceivePrivateShare(m.Payload.(*frost.Round1PrivateData))
+		case KindEcho:
+			if err = n.p.ReceiveEcho(m.From, m.Payload.([]byte)); err == nil {
+				n.echoes[m.From] = true
+			}
+		}
+		if err != nil {
+			fail(n, err)
+			continue
+		}
+		c.advance(net, n, ids, out, fail)
+	}
+
+	for _, id := range ids {
+		n := nodes[id]
+		if n.done || c.behavior(id) != Honest {
+			continue
+		}
+		out.Errors[id] = &StalledError{Tick: net.now, Pending: c.pendingDKG(n, ids)}
+	}
+	out.Trace = net.trace
+	out.Ticks = net.now
+	return out, nil
+}
+
+// advance moves node n forward once it holds every message it needs:
+// it sends its echo digest and completes the DKG.
+func (c *Config) advance(net *network, n *dkgNode, ids []int, out *DKGOutcome, fail func(*dkgNode, error)) {
+	if len(n.p.Status().Pending) > 0 {
+		return
+	}
+	if c.Echo && !n.echoSent {
+		n.echoSent = true
+		digest, err := n.p.EchoDigest()
+		if err != nil {
+			fail(n, err)
+			return
+		}
+		if c.behavior(n.id) != Silent {
+			for _, to := range ids {
+				if to != n.id {
+					net.send(Message{From: n.id, To: to, Kind: KindEcho, Payload: bytes.Clone(digest)})
+				}
+			}
+		}
+	}
+	if c.Echo && len(n.echoes) < len(ids)-1 {
+		return
+	}
+	result, err := n.p.ProcessRound1(nil)
+	if err != nil {
+		fail(n, err)
+		return
+	}
+	n.done = true
+	if c.behavior(n.id) == Honest {
+		out.Results[n.id] = result
+	}
+}
+
+// pendingDKG returns the participants n is still waiting for.
+func (c *Config) pendingDKG(n *dkgNode, ids []int) []int {
+	pending := n.p.Status().Pending
+	if len(pending) == 0 && c.Echo {
+		for _, id := range ids {
+			if id != n.id && !n.echoes[id] {
+				pending = append(pending, id)
+			}
+		}
+	}
+	return pending
+}
+
+// sendRound1 sends dealer id's round 1 messages, tampered with according
+// to its behavior.
+func (c *Config) sendRound1(net *network, id int, ids []int, r1 *session.Round1Output) error {
+	g := c.Group
+	broadcast, shares := r1.Broadcast, r1.PrivateShares
+	others := slices.DeleteFunc(slices.Clone(ids), func(to int) bool { return to == id })
+
+	switch c.behavior(id) {
+	case Silent:
+		return nil
+
+	case BadShares:
+		bad := make(map[int]*frost.Round1PrivateData, len(shares))
+		for to, s := range shares {
+			bad[to] = &frost.Round1PrivateData{
+				FromID: s.FromID,
+				ToID:   s.ToID,
+				Share:  g.NewScalar().Add(s.Share, g.NewScalar().SetUint64(1)),
+			}
+		}
+		shares = bad
+
+	case BadCommitments:
+		commitments := slices.Clone(broadcast.Commitments)
+		last := len(commitments) - 1
+		commitments[last] = g.NewPoint().Add(commitments[last], g.Generator())
+		broadcast = &frost.Round1Data{ID: broadcast.ID, Commitments: commitments}
+
+	case Equivocate:
+		// A second dealer with the same ID deals to the upper half
+		shadow, err := session.NewParticipant(g, c.Threshold, c.Total, id)
+		if err != nil {
+			return err
+		}
+		r1b, err := shadow.GenerateRound1(c.reader("dkg-equivocate", id), ids)
+		if err != nil {
+			return err
+		}
+		half := len(others) / 2
+		for i, to := range others {
+			b, s := broadcast, shares[to]
+			if i >= half {
+				b, s = r1b.Broadcast, r1b.PrivateShares[to]
+			}
+			net.send(Message{From: id, To: to, Kind: KindBroadcast, Payload: b})
+			net.send(Message{From: id, To: to, Kind: KindShare, Payload: s})
+		}
+		return nil
+
+	case Honest, BadSignatureShare:
+	default:
+		return fmt.Errorf("sim: unknown behavior %d", c.behavior(id))
+	}
+
+	for _, to := range others {
+		net.send(Message{From: id, To: to, Kind: KindBroadcast, Payload: broadcast})
+		net.send(Message{From: id, To: to, Kind: KindShare, Payload: shares[to]})
+	}
+	return nil
+}
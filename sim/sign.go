@@ -0,0 +1,160 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/session"
+)
+
+// SignOutcome is the result of a simulated signing ceremony.
+type SignOutcome struct {
+	// Signature is the aggregated signature. It is nil if signing failed.
+	Signature *frost.Signature
+
+	// Err is the error signing failed with. The coordinator reports a
+	// [*StalledError] if it was still waiting for messages at the end.
+	Err error
+
+	// Faulty lists the signers whose signature shares failed
+	// verification, in ascending order.
+	Faulty []int
+
+	// Trace lists every message sent, in the order it was sent.
+	Trace []Event
+
+	// Ticks is the tick at which the last message was delivered.
+	Ticks int
+}
+
+// RunSigning simulates signing message with the key shares from a DKG,
+// such as [DKGOutcome.KeyShares]. The signers are cfg.Signers, which must
+// all hold one of the key shares, and node [Coordinator] collects and
+// aggregates their messages. RunSigning returns an error only if the
+// configuration or key shares are invalid.
+func RunSigning(cfg *Config, keyShares []*frost.KeyShare, message []byte) (*SignOutcome, error) {
+	c, err := cfg.check()
+	if err != nil {
+		return nil, err
+	}
+	if len(keyShares) == 0 {
+		return nil, errors.New("sim: no key shares")
+	}
+	groupKey := keyShares[0].GroupKey
+
+	f, err := c.sessionConfig().NewFROST(c.Group, c.Threshold, c.Total)
+	if err != nil {
+		return nil, err
+	}
+	coord, err := session.NewCoordinatorWithConfig(f, message, c.Signers, c.sessionConfig())
+	if err != nil {
+		return nil, err
+	}
+	net := newNetwork(c)
+	out := &SignOutcome{}
+
+	// Round 1: every signer sends its commitment at tick 0
+	sessions := make(map[int]*session.SigningSession, len(c.Signers))
+	publicKeys := make(map[int]group.Point, len(c.Signers))
+	for _, id := range c.Signers {
+		ks := findKeyShare(c.Group, keyShares, id)
+		if ks == nil {
+			return nil, fmt.Errorf("sim: no key share for signer %d", id)
+		}
+		publicKeys[id] = ks.PublicKey
+		p, err := session.NewParticipantWithConfig(c.Group, c.Threshold, c.Total, id, c.sessionConfig())
+		if err != nil {
+			return nil, err
+		}
+		if err := p.SetKeyShare(ks); err != nil {
+			return nil, err
+		}
+		s, err := p.NewSigningSession(c.reader("sign", id), message)
+		if err != nil {
+			return nil, err
+		}
+		sessions[id] = s
+		if c.behavior(id) != Silent {
+			net.send(Message{From: id, To: Coordinator, Kind: KindCommitment, Payload: s.Commitment()})
+		}
+	}
+
+	var commitments []*frost.SigningCommitment
+	shares := make(map[int]*frost.SignatureShare)
+	for out.Signature == nil && out.Err == nil {
+		m, ok := net.next()
+		if !ok {
+			break
+		}
+		switch m.Kind {
+		case KindCommitment:
+			if err := coord.AddCommitment(m.Payload.(*frost.SigningCommitment)); err != nil {
+				out.Err = err
+				break
+			}
+			if list, err := coord.Commitments(); err == nil {
+				commitments = list
+				for _, id := range c.Signers {
+					net.send(Message{From: Coordinator, To: id, Kind: KindCommitmentList, Payload: list})
+				}
+			}
+
+		case KindCommitmentList:
+			share, err := sessions[m.To].Sign(m.Payload.([]*frost.SigningCommitment))
+			if err != nil {
+				// A signer that refuses to sign simply sends nothing
+				continue
+			}
+			if c.behavior(m.To) == BadSignatureShare {
+				one := c.Group.NewScalar().SetUint64(1)
+				share = &frost.SignatureShare{ID: share.ID, Z: c.Group.NewScalar().Add(share.Z, one)}
+			}
+			net.send(Message{From: m.To, To: Coordinator, Kind: KindSignatureShare, Payload: share})
+
+		case KindSignatureShare:
+			share := m.Payload.(*frost.SignatureShare)
+			if err := coord.AddShare(share); err != nil {
+				out.Err = err
+				break
+			}
+			shares[m.From] = share
+			if len(coord.Status().Pending) > 0 {
+				continue
+			}
+			sig, err := coord.Aggregate()
+			if err == nil && !f.Verify(message, sig, groupKey) {
+				err = errors.New("aggregate signature is invalid")
+				for _, id := range c.Signers {
+					if !f.VerifySignatureShare(shares[id], publicKeys[id], groupKey, message, commitments) {
+						out.Faulty = append(out.Faulty, id)
+					}
+				}
+			}
+			if err != nil {
+				out.Err = err
+				break
+			}
+			out.Signature = sig
+		}
+	}
+
+	if out.Signature == nil && out.Err == nil {
+		out.Err = &StalledError{Tick: net.now, Pending: coord.Status().Pending}
+	}
+	out.Trace = net.trace
+	out.Ticks = net.now
+	return out, nil
+}
+
+// findKeyShare returns the key share of participant id, or nil.
+func findKeyShare(g group.Group, keyShares []*frost.KeyShare, id int) *frost.KeyShare {
+	want := g.NewScalar().SetUint64(uint64(id))
+	for _, ks := range keyShares {
+		if ks.ID.Equal(want) {
+			return ks
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+// Package sim runs FROST ceremonies over a simulated network, so that the
+// behavior of the session package under message loss, delays, reordering
+// and misbehaving participants can be tested deterministically.
+//
+// A simulation is driven entirely by [Config].Seed: the participants'
+// randomness, the message delays and the dropped messages all come from
+// streams derived from it, so a failing run can be replayed exactly.
+// Time is measured in ticks of a virtual clock; nothing sleeps.
+//
+//	out, err := sim.RunDKG(&sim.Config{
+//		Threshold: 2,
+//		Total:     4,
+//		Seed:      1,
+//		Faults:    sim.Faults{MaxDelay: 5},
+//		Behaviors: map[int]sim.Behavior{3: sim.Equivocate},
+//		Echo:      true,
+//	})
+//
+// Every node runs the unmodified [session.Participant] and
+// [session.Coordinator] code. Misbehaving participants are simulated by
+// tampering with the messages they send, as listed under [Behavior].
+package sim
+
+import (
+	"container/heap"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/group"
+	"github.com/f3rmion/fy/session"
+)
+
+// DefaultDeadline is the number of ticks a simulation runs for when
+// [Config].Deadline is zero.
+const DefaultDeadline = 1000
+
+// Coordinator is the node ID of the signing coordinator.
+const Coordinator = 0
+
+// Config describes a simulated ceremony.
+type Config struct {
+	// Group is the group to use. It defaults to baby-jubjub.
+	Group group.Group
+
+	// Threshold and Total are the threshold parameters of the group.
+	Threshold int
+	Total     int
+
+	// Seed determines all randomness of the simulation.
+	Seed uint64
+
+	// Faults configures the network.
+	Faults Faults
+
+	// Behaviors maps participant IDs to their behavior. Participants that
+	// are not listed are honest.
+	Behaviors map[int]Behavior
+
+	// Echo enables echo broadcast in the DKG, see
+	// [session.Config].EchoBroadcast.
+	Echo bool
+
+	// Signers lists the participants that take part in signing. It
+	// defaults to all participants.
+	Signers []int
+
+	// Deadline is the tick at which the simulation stops delivering
+	// messages. It defaults to DefaultDeadline.
+	Deadline int
+}
+
+// Faults configures the simulated network.
+type Faults struct {
+	// DropRate is the probability that any one message is lost.
+	DropRate float64
+
+	// MaxDelay is the largest extra delay of a message, in ticks. Each
+	// message takes between 1 and 1+MaxDelay ticks, drawn uniformly, so
+	// messages sent at the same time can arrive in any order.
+	MaxDelay int
+}
+
+// Behavior is the way a simulated participant misbehaves.
+type Behavior int
+
+const (
+	// Honest participants follow the protocol.
+	Honest Behavior = iota
+
+	// Silent participants send nothing, as if they had crashed.
+	Silent
+
+	// BadShares dealers send every other participant a private share that
+	// does not match their commitments.
+	BadShares
+
+	// BadCommitments dealers broadcast commitments that do not match the
+	// shares they send.
+	BadCommitments
+
+	// Equivocate dealers run two DKGs at once: the lower half of the
+	// other participants receives the broadcast and shares of one, the
+	// upper half those of the other. Every share verifies, but without
+	// echo broadcast the two halves end up with different group keys.
+	Equivocate
+
+	// BadSignatureShare signers send the coordinator an invalid
+	// signature share.
+	BadSignatureShare
+)
+
+// String returns the name of the behavior.
+func (b Behavior) String() string {
+	switch b {
+	case Honest:
+		return "honest"
+	case Silent:
+		return "silent"
+	case BadShares:
+		return "bad-shares"
+	case BadCommitments:
+		return "bad-commitments"
+	case Equivocate:
+		return "equivocate"
+	case BadSignatureShare:
+		return "bad-signature-share"
+	default:
+		return "unknown"
+	}
+}
+
+// Kind is the type of a simulated message.
+type Kind int
+
+const (
+	// KindBroadcast carries a DKG round 1 broadcast.
+	KindBroadcast Kind = iota + 1
+
+	// KindShare carries a DKG private share.
+	KindShare
+
+	// KindEcho carries a DKG echo digest.
+	KindEcho
+
+	// KindCommitment carries a signer's commitment to the coordinator.
+	KindCommitment
+
+	// KindCommitmentList carries the coordinator's list of commitments to
+	// a signer.
+	KindCommitmentList
+
+	// KindSignatureShare carries a signer's signature share to the
+	// coordinator.
+	KindSignatureShare
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindBroadcast:
+		return "broadcast"
+	case KindShare:
+		return "share"
+	case KindEcho:
+		return "echo"
+	case KindCommitment:
+		return "commitment"
+	case KindCommitmentList:
+		return "commitment-list"
+	case KindSignatureShare:
+		return "signature-share"
+	default:
+		return "unknown"
+	}
+}
+
+// Message is a message between two simulated nodes.
+type Message struct {
+	From, To int
+	Kind     Kind
+	Payload  any
+}
+
+// Event records the fate of one message.
+type Event struct {
+	// Sent and Delivered are the ticks at which the message was sent and
+	// delivered. Delivered is zero if the message was dropped or was
+	// still in flight at the deadline.
+	Sent, Delivered int
+
+	From, To int
+	Kind     Kind
+	Dropped  bool
+}
+
+// StalledError is the error of a participant that was still waiting for
+// messages when the simulation ended.
+type StalledError struct {
+	// Tick is the tick at which the simulation ended.
+	Tick int
+
+	// Pending lists the nodes whose messages were still missing.
+	Pending []int
+}
+
+// Error implements the error interface.
+func (e *StalledError) Error() string {
+	return fmt.Sprintf("stalled at tick %d waiting for %v", e.Tick, e.Pending)
+}
+
+// check validates the configuration and fills in defaults.
+func (c *Config) check() (*Config, error) {
+	if c == nil {
+		return nil, errors.New("sim: nil config")
+	}
+	out := *c
+	if out.Group == nil {
+		out.Group = &bjj.BJJ{}
+	}
+	if out.Deadline == 0 {
+		out.Deadline = DefaultDeadline
+	}
+	if out.Faults.DropRate < 0 || out.Faults.DropRate > 1 || out.Faults.MaxDelay < 0 || out.Deadline < 0 {
+		return nil, errors.New("sim: invalid faults or deadline")
+	}
+	for id := range out.Behaviors {
+		if id < 1 || id > out.Total {
+			return nil, fmt.Errorf("sim: behavior for unknown participant %d", id)
+		}
+	}
+	if out.Signers == nil {
+		out.Signers = out.ids()
+	}
+	out.Signers = slices.Sorted(slices.Values(out.Signers))
+	return &out, nil
+}
+
+// ids returns the participant IDs 1 to Total.
+func (c *Config) ids() []int {
+	ids := make([]int, c.Total)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// behavior returns the behavior of participant id.
+func (c *Config) behavior(id int) Behavior {
+	return c.Behaviors[id]
+}
+
+// sessionConfig returns the session configuration of every participant.
+func (c *Config) sessionConfig() *session.Config {
+	return &session.Config{EchoBroadcast: c.Echo}
+}
+
+// rand returns the random stream for the given purpose, derived from the
+// seed.
+func (c *Config) rand(purpose string, id int) *rand.ChaCha8 {
+	h := sha256.New()
+	h.Write([]byte("fy/sim\x00" + purpose + "\x00"))
+	h.Write(binary.BigEndian.AppendUint64(nil, c.Seed))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(id)))
+	var seed [32]byte
+	h.Sum(seed[:0])
+	return rand.NewChaCha8(seed)
+}
+
+// reader returns the randomness of node id for the given purpose.
+func (c *Config) reader(purpose string, id int) io.Reader {
+	return c.rand(purpose, id)
+}
+
+// network delivers messages between nodes in the order of a virtual
+// clock.
+type network struct {
+	rng      *rand.Rand
+	faults   Faults
+	deadline int
+	now      int
+	queue    queue
+	seq      int
+	trace    []Event
+}
+
+// newNetwork returns the network of a simulation.
+func newNetwork(c *Config) *network {
+	return &network{
+		rng:      rand.New(c.rand("network", 0)),
+		faults:   c.Faults,
+		deadline: c.Deadline,
+	}
+}
+
+// send puts m in flight, unless the network drops it.
+func (n *network) send(m Message) {
+	ev := Event{Sent: n.now, From: m.From, To: m.To, Kind: m.Kind}
+	if n.faults.DropRate > 0 && n.rng.Float64() < n.faults.DropRate {
+		ev.Dropped = true
+		n.trace = append(n.trace, ev)
+		return
+	}
+	n.trace = append(n.trace, ev)
+	at := n.now + 1 + n.rng.IntN(n.faults.MaxDelay+1)
+	heap.Push(&n.queue, &flight{at: at, seq: n.seq, event: len(n.trace) - 1, msg: m})
+	n.seq++
+}
+
+// next advances the clock to the next delivery and returns its message.
+// It returns false once no message can be delivered before the deadline.
+func (n *network) next() (Message, bool) {
+	if n.queue.Len() == 0 || n.queue[0].at > n.deadline {
+		return Message{}, false
+	}
+	f := heap.Pop(&n.queue).(*flight)
+	n.now = f.at
+	n.trace[f.event].Delivered = f.at
+	return f.msg, true
+}
+
+// flight is a message in flight.
+type flight struct {
+	at, seq int
+	event   int
+	msg     Message
+}
+
+// queue is a min-heap of messages ordered by delivery time, then by the
+// order they were sent in.
+type queue []*flight
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q queue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *queue) Push(x any) { *q = append(*q, x.(*flight)) }
+
+func (q *queue) Pop() any {
+	old := *q
+	f := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return f
+}
@@ -0,0 +1,221 @@
+package sim
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/f3rmion/fy/bjj"
+	"github.com/f3rmion/fy/session"
+)
+
+func TestHonestDKGAndSigning(t *testing.T) {
+	cfg := &Config{Threshold: 3, Total: 5, Seed: 1, Faults: Faults{MaxDelay: 10}, Echo: true}
+	out, err := RunDKG(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Errors) != 0 || len(out.Results) != 5 {
+		t.Fatalf("results %d, errors %v", len(out.Results), out.Errors)
+	}
+	if !out.Agreed() {
+		t.Fatal("participants disagree on the group key")
+	}
+
+	cfg.Signers = []int{1, 3, 5}
+	sign, err := RunSigning(cfg, out.KeyShares(), []byte("simulated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sign.Err != nil {
+		t.Fatal(sign.Err)
+	}
+	f, _ := (&session.Config{}).NewFROST(&bjj.BJJ{}, 3, 5)
+	if err := session.Verify(f, []byte("simulated"), sign.Signature, out.Results[1].GroupKey); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	run := func(seed uint64) *DKGOutcome {
+		out, err := RunDKG(&Config{Threshold: 2, Total: 4, Seed: seed, Faults: Faults{MaxDelay: 20, DropRate: 0.05}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	a, b := run(7), run(7)
+	if !reflect.DeepEqual(a.Trace, b.Trace) || a.Ticks != b.Ticks {
+		t.Fatal("same seed produced different traces")
+	}
+	for id, r := range a.Results {
+		if !r.KeyShare.SecretKey.Equal(b.Results[id].KeyShare.SecretKey) {
+			t.Fatalf("same seed produced different key shares for participant %d", id)
+		}
+	}
+	if reflect.DeepEqual(a.Trace, run(8).Trace) {
+		t.Error("different seeds produced the same trace")
+	}
+}
+
+func TestReordering(t *testing.T) {
+	out, err := RunDKG(&Config{Threshold: 2, Total: 4, Seed: 3, Faults: Faults{MaxDelay: 50}, Echo: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered := false
+	for i := 1; i < len(out.Trace); i++ {
+		if out.Trace[i].Delivered < out.Trace[i-1].Delivered {
+			reordered = true
+		}
+	}
+	if !reordered {
+		t.Fatal("no messages were reordered")
+	}
+	if len(out.Errors) != 0 || !out.Agreed() {
+		t.Fatalf("errors %v, agreed %v", out.Errors, out.Agreed())
+	}
+}
+
+func TestDroppedMessages(t *testing.T) {
+	out, err := RunDKG(&Config{Threshold: 2, Total: 4, Seed: 1, Faults: Faults{DropRate: 0.3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Errors) == 0 {
+		t.Fatal("DKG completed despite dropped messages")
+	}
+	for id, err := range out.Errors {
+		var stalled *StalledError
+		if !errors.As(err, &stalled) || len(stalled.Pending) == 0 {
+			t.Errorf("participant %d: err = %v, want a StalledError", id, err)
+		}
+	}
+}
+
+func TestByzantineDKG(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior Behavior
+		echo     bool
+		check    func(t *testing.T, out *DKGOutcome)
+	}{
+		{"silent", Silent, false, func(t *testing.T, out *DKGOutcome) {
+			for id, err := range out.Errors {
+				var stalled *StalledError
+				if !errors.As(err, &stalled) || !reflect.DeepEqual(stalled.Pending, []int{4}) {
+					t.Errorf("participant %d: err = %v, want stalled on 4", id, err)
+				}
+			}
+		}},
+		{"bad shares", BadShares, false, func(t *testing.T, out *DKGOutcome) {
+			for id, err := range out.Errors {
+				if !strings.Contains(err.Error(), "invalid share") {
+					t.Errorf("participant %d: err = %v", id, err)
+				}
+			}
+		}},
+		{"bad commitments", BadCommitments, false, func(t *testing.T, out *DKGOutcome) {
+			for id, err := range out.Errors {
+				if !strings.Contains(err.Error(), "invalid share") {
+					t.Errorf("participant %d: err = %v", id, err)
+				}
+			}
+		}},
+		{"equivocate with echo", Equivocate, true, func(t *testing.T, out *DKGOutcome) {
+			for id, err := range out.Errors {
+				var mismatch *session.EchoMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Errorf("participant %d: err = %v, want EchoMismatchError", id, err)
+				}
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := RunDKG(&Config{
+				Threshold: 2, Total: 4, Seed: 5,
+				Faults:    Faults{MaxDelay: 5},
+				Behaviors: map[int]Behavior{4: tt.behavior},
+				Echo:      tt.echo,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out.Results) != 0 || len(out.Errors) != 3 {
+				t.Fatalf("results %d, errors %v; want every honest participant to fail", len(out.Results), out.Errors)
+			}
+			tt.check(t, out)
+		})
+	}
+}
+
+func TestEquivocationWithoutEcho(t *testing.T) {
+	out, err := RunDKG(&Config{Threshold: 2, Total: 5, Seed: 2, Behaviors: map[int]Behavior{5: Equivocate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Errors) != 0 || len(out.Results) != 4 {
+		t.Fatalf("results %d, errors %v", len(out.Results), out.Errors)
+	}
+	if out.Agreed() {
+		t.Fatal("equivocation did not split the group key")
+	}
+	if err := session.CheckTranscriptDigests(out.Results[1], map[int][]byte{
+		4: out.Results[4].TranscriptDigest,
+	}); err == nil {
+		t.Error("transcript digests agree despite equivocation")
+	}
+}
+
+func TestByzantineSigning(t *testing.T) {
+	cfg := &Config{Threshold: 2, Total: 3, Seed: 9, Faults: Faults{MaxDelay: 3}}
+	dkg, err := RunDKG(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Behaviors = map[int]Behavior{2: BadSignatureShare}
+	out, err := RunSigning(cfg, dkg.KeyShares(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Err == nil || !reflect.DeepEqual(out.Faulty, []int{2}) {
+		t.Fatalf("err = %v, faulty = %v; want signer 2 blamed", out.Err, out.Faulty)
+	}
+
+	cfg.Behaviors = map[int]Behavior{3: Silent}
+	out, err = RunSigning(cfg, dkg.KeyShares(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stalled *StalledError
+	if !errors.As(out.Err, &stalled) || !reflect.DeepEqual(stalled.Pending, []int{3}) {
+		t.Fatalf("err = %v, want stalled on 3", out.Err)
+	}
+
+	// Without the silent signer, the remaining quorum succeeds
+	cfg.Signers = []int{1, 2}
+	cfg.Behaviors = nil
+	out, err = RunSigning(cfg, dkg.KeyShares(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Err != nil {
+		t.Fatal(out.Err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, cfg := range []*Config{
+		nil,
+		{Threshold: 2, Total: 3, Behaviors: map[int]Behavior{4: Silent}},
+		{Threshold: 2, Total: 3, Faults: Faults{DropRate: 2}},
+		{Threshold: 4, Total: 3},
+	} {
+		if _, err := RunDKG(cfg); err == nil {
+			t.Errorf("RunDKG(%+v) succeeded", cfg)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+)
+
+// ErrMismatch is wrapped by the errors [Vector.Check] returns when a
+// recorded value differs from the recomputed one.
+var ErrMismatch = errors.New("testvectors: mismatch")
+
+// Load reads a vector from the JSON file at path.
+func Load(path string) (*Vector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	v, err := Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+// Decode reads a vector in JSON from r. Unknown fields are rejected.
+func Decode(r io.Reader) (*Vector, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var v Vector
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if v.Version != Version {
+		return nil, fmt.Errorf("unsupported vector version %d", v.Version)
+	}
+	return &v, nil
+}
+
+// Check recomputes every derived value of v from its inputs and returns
+// an error if any recorded value differs. The inputs are the dealers'
+// commitments and shares, the signers' nonces and the message; the seed
+// is not used. The signature is also checked with [FROST.Verify].
+func (v *Vector) Check() error {
+	f, g, err := newFROST(v.Group, v.Hasher, v.Threshold, v.Total)
+	if err != nil {
+		return err
+	}
+	d := decoder{g: g}
+	keyShares, groupKey := v.checkDKG(f, &d)
+	if d.err != nil {
+		return d.err
+	}
+	v.checkSigning(f, &d, keyShares, groupKey)
+	return d.err
+}
+
+// checkDKG checks the DKG section and returns the secret key shares,
+// indexed by ID - 1, and the group key.
+func (v *Vector) checkDKG(f *frost.FROST, d *decoder) ([]group.Scalar, group.Point) {
+	g := d.g
+	if len(v.DKG.Dealers) != v.Total {
+		d.fail("dkg.dealers: have %d, want %d", len(v.DKG.Dealers), v.Total)
+		return nil, nil
+	}
+	if len(v.DKG.KeyShares) != v.Total {
+		d.fail("dkg.keyShares: have %d, want %d", len(v.DKG.KeyShares), v.Total)
+		return nil, nil
+	}
+
+	broadcasts := make([]*frost.Round1Data, v.Total)
+	secretKeys := make([]group.Scalar, v.Total)
+	for j := range secretKeys {
+		secretKeys[j] = g.NewScalar()
+	}
+	groupKey := g.NewPoint()
+	for i, dealer := range v.DKG.Dealers {
+		name := fmt.Sprintf("dkg.dealers[%d]", i)
+		if dealer.ID != i+1 {
+			d.fail("%s.id: have %d, want %d", name, dealer.ID, i+1)
+			return nil, nil
+		}
+		if len(dealer.Commitments) != v.Threshold || len(dealer.Shares) != v.Total {
+			d.fail("%s: have %d commitments and %d shares, want %d and %d",
+				name, len(dealer.Commitments), len(dealer.Shares), v.Threshold, v.Total)
+			return nil, nil
+		}
+		commitments := d.points(name+".commitments", dealer.Commitments)
+		shares := d.scalars(name+".shares", dealer.Shares)
+		if d.err != nil {
+			return nil, nil
+		}
+		broadcasts[i] = &frost.Round1Data{ID: id(g, dealer.ID), Commitments: commitments}
+		groupKey.Add(groupKey, commitments[0])
+
+		// s_ij*G must equal sum_k C_ik * j^k.
+		for j, share := range shares {
+			x := id(g, j+1)
+			xk := g.NewScalar().SetUint64(1)
+			powers := make([]group.Scalar, len(commitments))
+			for k := range powers {
+				powers[k] = xk.Clone()
+				xk.Mul(xk, x)
+			}
+			if !g.ScalarBaseMult(share).Equal(g.MultiScalarMult(powers, commitments)) {
+				d.fail("%s.shares[%d]: does not match the commitments", name, j)
+				return nil, nil
+			}
+			secretKeys[j].Add(secretKeys[j], share)
+		}
+	}
+
+	for j, ks := range v.DKG.KeyShares {
+		name := fmt.Sprintf("dkg.keyShares[%d]", j)
+		if ks.ID != j+1 {
+			d.fail("%s.id: have %d, want %d", name, ks.ID, j+1)
+			return nil, nil
+		}
+		d.scalar(name+".secretKey", ks.SecretKey, secretKeys[j])
+		d.point(name+".publicKey", ks.PublicKey, g.ScalarBaseMult(secretKeys[j]))
+	}
+	d.point("dkg.groupKey", v.DKG.GroupKey, groupKey)
+
+	digest, err := f.TranscriptDigest(broadcasts)
+	if err != nil {
+		d.fail("dkg: %v", err)
+		return nil, nil
+	}
+	d.bytes("dkg.transcriptDigest", v.DKG.TranscriptDigest, digest)
+	return secretKeys, groupKey
+}
+
+// checkSigning checks the signing section against the key shares and
+// group key recomputed by checkDKG.
+func (v *Vector) checkSigning(f *frost.FROST, d *decoder, secretKeys []group.Scalar, groupKey group.Point) {
+	g := d.g
+	s := &v.Signing
+	n := len(s.Signers)
+	if n < v.Threshold || n > v.Total {
+		d.fail("signing.signers: have %d, want between %d and %d", n, v.Threshold, v.Total)
+		return
+	}
+	for name, list := range map[string][]string{
+		"hidingNonces": s.HidingNonces, "bindingNonces": s.BindingNonces,
+		"hidingPoints": s.HidingPoints, "bindingPoints": s.BindingPoints,
+		"bindingFactors": s.BindingFactors, "lagrange": s.Lagrange, "shares": s.Shares,
+	} {
+		if len(list) != n {
+			d.fail("signing.%s: have %d entries, want %d", name, len(list), n)
+			return
+		}
+	}
+	message, err := hex.DecodeString(s.Message)
+	if err != nil {
+		d.fail("signing.message: %v", err)
+		return
+	}
+
+	hiding := d.scalars("signing.hidingNonces", s.HidingNonces)
+	binding := d.scalars("signing.bindingNonces", s.BindingNonces)
+	if d.err != nil {
+		return
+	}
+	commitments := make([]*frost.SigningCommitment, n)
+	for i, signer := range s.Signers {
+		if signer < 1 || signer > v.Total {
+			d.fail("signing.signers[%d]: invalid participant ID %d", i, signer)
+			return
+		}
+		commitments[i] = &frost.SigningCommitment{
+			ID:           id(g, signer),
+			HidingPoint:  g.ScalarBaseMult(hiding[i]),
+			BindingPoint: g.ScalarBaseMult(binding[i]),
+		}
+		d.point(fmt.Sprintf("signing.hidingPoints[%d]", i), s.HidingPoints[i], commitments[i].HidingPoint)
+		d.point(fmt.Sprintf("signing.bindingPoints[%d]", i), s.BindingPoints[i], commitments[i].BindingPoint)
+	}
+	if d.err != nil {
+		return
+	}
+
+	inter, err := f.Intermediates(groupKey, message, commitments)
+	if err != nil {
+		d.fail("signing: %v", err)
+		return
+	}
+	for i := range n {
+		d.scalar(fmt.Sprintf("signing.bindingFactors[%d]", i), s.BindingFactors[i], inter.BindingFactors[i])
+		d.scalar(fmt.Sprintf("signing.lagrange[%d]", i), s.Lagrange[i], inter.Lagrange[i])
+	}
+	d.point("signing.groupCommitment", s.GroupCommitment, inter.GroupCommitment)
+	d.scalar("signing.challenge", s.Challenge, inter.Challenge)
+
+	// z_i = d_i + e_i*rho_i + lambda_i*s_i*c
+	z := g.NewScalar()
+	for i, signer := range s.Signers {
+		zi := g.NewScalar().Mul(binding[i], inter.BindingFactors[i])
+		zi.Add(zi, hiding[i])
+		t := g.NewScalar().Mul(inter.Lagrange[i], secretKeys[signer-1])
+		t.Mul(t, inter.Challenge)
+		zi.Add(zi, t)
+		d.scalar(fmt.Sprintf("signing.shares[%d]", i), s.Shares[i], zi)
+		z.Add(z, zi)
+	}
+	sig := &frost.Signature{R: inter.GroupCommitment, Z: z}
+	d.bytes("signing.signature", s.Signature, sig.Bytes())
+	if d.err == nil && !f.Verify(message, sig, groupKey) {
+		d.fail("signing.signature: does not verify")
+	}
+}
+
+// decoder decodes and compares the hex fields of a vector, keeping the
+// first error.
+type decoder struct {
+	g   group.Group
+	err error
+}
+
+// fail records an error wrapping ErrMismatch, unless one is recorded.
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrMismatch, fmt.Sprintf(format, args...))
+	}
+}
+
+// scalars decodes a list of canonical scalars.
+func (d *decoder) scalars(name string, list []string) []group.Scalar {
+	out := make([]group.Scalar, len(list))
+	for i, s := range list {
+		b, err := hex.DecodeString(s)
+		if err == nil {
+			out[i], err = d.g.NewScalar().SetCanonicalBytes(b)
+		}
+		if err != nil {
+			d.fail("%s[%d]: %v", name, i, err)
+			return nil
+		}
+	}
+	return out
+}
+
+// points decodes a list of points.
+func (d *decoder) points(name string, list []string) []group.Point {
+	out := make([]group.Point, len(list))
+	for i, s := range list {
+		b, err := hex.DecodeString(s)
+		if err == nil {
+			out[i], err = d.g.NewPoint().SetBytes(b)
+		}
+		if err != nil {
+			d.fail("%s[%d]: %v", name, i, err)
+			return nil
+		}
+	}
+	return out
+}
+
+// scalar compares a recorded scalar with the recomputed one.
+func (d *decoder) scalar(name, have string, want group.Scalar) {
+	d.bytes(name, have, want.Bytes())
+}
+
+// point compares a recorded point with the recomputed one.
+func (d *decoder) point(name, have string, want group.Point) {
+	d.bytes(name, have, want.Bytes())
+}
+
+// bytes compares a recorded hex string with the recomputed bytes.
+func (d *decoder) bytes(name, have string, want []byte) {
+	b, err := hex.DecodeString(have)
+	if err != nil {
+		d.fail("%s: %v", name, err)
+		return
+	}
+	if !bytes.Equal(b, want) {
+		d.fail("%s: have %s, want %x", name, have, want)
+	}
+}
+
+// id returns the scalar identifier of participant i.
+func id(g group.Group, i int) group.Scalar {
+	return g.NewScalar().SetUint64(uint64(i))
+}
@@ -0,0 +1,271 @@
+// Package testvectors generates and checks deterministic test vectors for
+// FROST key generation and signing, so that other implementations can be
+// validated against this one.
+//
+// A [Vector] records one complete ceremony for a group and hasher: the
+// commitments and shares of a DKG with their resulting key shares, and a
+// signing session with its nonces, binding factors, group commitment,
+// challenge, Lagrange coefficients, signature shares and signature. All
+// randomness comes from a seeded stream, so the same seed always yields
+// the same vector. [Vector.Check] recomputes every derived value from the
+// recorded inputs, independently of the seed, and can therefore validate
+// vectors produced by any implementation.
+//
+// Scalars and points are hex-encoded with the group's Bytes encoding.
+package testvectors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+
+	"github.com/f3rmion/fy/frost"
+	"github.com/f3rmion/fy/group"
+
+	_ "github.com/f3rmion/fy/bjj" // register the baby-jubjub group
+)
+
+// Version is the format version of the vectors.
+const Version = 1
+
+// DefaultMessage is the message signed by generated vectors.
+const DefaultMessage = "test"
+
+// Vector is a test vector for one group and hasher.
+type Vector struct {
+	Version   int    `json:"version"`
+	Group     string `json:"group"`
+	Hasher    string `json:"hasher"`
+	Threshold int    `json:"threshold"`
+	Total     int    `json:"total"`
+	Seed      string `json:"seed"`
+	DKG       DKG    `json:"dkg"`
+	Signing   Sign   `json:"signing"`
+}
+
+// DKG records a distributed key generation.
+type DKG struct {
+	// Dealers holds every participant's round 1 messages.
+	Dealers []Dealer `json:"dealers"`
+
+	// KeyShares holds the resulting key shares, in ID order.
+	KeyShares []KeyShare `json:"keyShares"`
+
+	GroupKey         string `json:"groupKey"`
+	TranscriptDigest string `json:"transcriptDigest"`
+}
+
+// Dealer holds a participant's round 1 broadcast and the shares it sent.
+type Dealer struct {
+	ID          int      `json:"id"`
+	Commitments []string `json:"commitments"`
+
+	// Shares holds f_i(j) for every participant j, including i itself,
+	// in ID order.
+	Shares []string `json:"shares"`
+}
+
+// KeyShare is a participant's key share.
+type KeyShare struct {
+	ID        int    `json:"id"`
+	SecretKey string `json:"secretKey"`
+	PublicKey string `json:"publicKey"`
+}
+
+// Sign records a signing session of the first Threshold participants.
+type Sign struct {
+	Message         string   `json:"message"`
+	Signers         []int    `json:"signers"`
+	HidingNonces    []string `json:"hidingNonces"`
+	BindingNonces   []string `json:"bindingNonces"`
+	HidingPoints    []string `json:"hidingPoints"`
+	BindingPoints   []string `json:"bindingPoints"`
+	BindingFactors  []string `json:"bindingFactors"`
+	GroupCommitment string   `json:"groupCommitment"`
+	Challenge       string   `json:"challenge"`
+	Lagrange        []string `json:"lagrange"`
+	Shares          []string `json:"shares"`
+	Signature       string   `json:"signature"`
+}
+
+// NewRand returns the deterministic random stream used by [Generate]: the
+// ChaCha20 keystream under the key SHA-256(seed) and an all-zero nonce.
+func NewRand(seed []byte) io.Reader {
+	key := sha256.Sum256(seed)
+	c, err := chacha20.NewUnauthenticatedCipher(key[:], make([]byte, chacha20.NonceSize))
+	if err != nil {
+		panic(err) // the key and nonce sizes are fixed
+	}
+	return &stream{c}
+}
+
+// stream reads a ChaCha20 keystream.
+type stream struct {
+	c *chacha20.Cipher
+}
+
+// Read implements io.Reader.
+func (s *stream) Read(p []byte) (int, error) {
+	clear(p)
+	s.c.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+// Generate produces the vector for the named group and hasher with the
+// given threshold parameters, drawing all randomness from NewRand(seed).
+func Generate(groupName, hasherName string, threshold, total int, seed []byte) (*Vector, error) {
+	f, _, err := newFROST(groupName, hasherName, threshold, total)
+	if err != nil {
+		return nil, err
+	}
+	rng := NewRand(seed)
+	v := &Vector{
+		Version:   Version,
+		Group:     groupName,
+		Hasher:    hasherName,
+		Threshold: threshold,
+		Total:     total,
+		Seed:      hex.EncodeToString(seed),
+	}
+
+	// DKG
+	participants := make([]*frost.Participant, total)
+	broadcasts := make([]*frost.Round1Data, total)
+	for i := range participants {
+		if participants[i], err = f.NewParticipant(rng, i+1); err != nil {
+			return nil, err
+		}
+		broadcasts[i] = participants[i].Round1Broadcast()
+		d := Dealer{ID: i + 1, Commitments: encodeAll(broadcasts[i].Commitments)}
+		for j := 1; j <= total; j++ {
+			d.Shares = append(d.Shares, encode(f.Round1PrivateSend(participants[i], j).Share))
+		}
+		v.DKG.Dealers = append(v.DKG.Dealers, d)
+	}
+	for i, sender := range participants {
+		for j, receiver := range participants {
+			if i == j {
+				continue
+			}
+			if err := f.Round2ReceiveShare(receiver, f.Round1PrivateSend(sender, j+1), broadcasts[i].Commitments); err != nil {
+				return nil, err
+			}
+		}
+	}
+	keyShares := make([]*frost.KeyShare, total)
+	for i, p := range participants {
+		if keyShares[i], err = f.Finalize(p, broadcasts); err != nil {
+			return nil, err
+		}
+		v.DKG.KeyShares = append(v.DKG.KeyShares, KeyShare{
+			ID:        i + 1,
+			SecretKey: encode(keyShares[i].SecretKey),
+			PublicKey: encode(keyShares[i].PublicKey),
+		})
+	}
+	groupKey := keyShares[0].GroupKey
+	v.DKG.GroupKey = encode(groupKey)
+	v.DKG.TranscriptDigest = hex.EncodeToString(keyShares[0].TranscriptDigest)
+
+	// Signing by the first threshold participants
+	message := []byte(DefaultMessage)
+	s := &v.Signing
+	s.Message = hex.EncodeToString(message)
+	nonces := make([]*frost.SigningNonce, threshold)
+	commitments := make([]*frost.SigningCommitment, threshold)
+	for i := range threshold {
+		if nonces[i], commitments[i], err = f.SignRound1(rng, keyShares[i]); err != nil {
+			return nil, err
+		}
+		s.Signers = append(s.Signers, i+1)
+		s.HidingNonces = append(s.HidingNonces, encode(nonces[i].D))
+		s.BindingNonces = append(s.BindingNonces, encode(nonces[i].E))
+		s.HidingPoints = append(s.HidingPoints, encode(commitments[i].HidingPoint))
+		s.BindingPoints = append(s.BindingPoints, encode(commitments[i].BindingPoint))
+	}
+	inter, err := f.Intermediates(groupKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	s.BindingFactors = encodeAll(inter.BindingFactors)
+	s.GroupCommitment = encode(inter.GroupCommitment)
+	s.Challenge = encode(inter.Challenge)
+	s.Lagrange = encodeAll(inter.Lagrange)
+
+	shares := make([]*frost.SignatureShare, threshold)
+	for i := range threshold {
+		if shares[i], err = f.SignRound2(keyShares[i], nonces[i], message, commitments); err != nil {
+			return nil, err
+		}
+		s.Shares = append(s.Shares, encode(shares[i].Z))
+	}
+	sig, err := f.Aggregate(message, commitments, shares)
+	if err != nil {
+		return nil, err
+	}
+	s.Signature = hex.EncodeToString(sig.Bytes())
+	return v, nil
+}
+
+// GenerateAll produces one vector for every registered group and hasher.
+// Combinations that the frost package rejects are skipped.
+func GenerateAll(threshold, total int, seed []byte) ([]*Vector, error) {
+	var vectors []*Vector
+	for _, g := range group.Names() {
+		for _, h := range frost.HasherNames() {
+			if _, _, err := newFROST(g, h, threshold, total); err != nil {
+				continue
+			}
+			v, err := Generate(g, h, threshold, total, seed)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", g, h, err)
+			}
+			vectors = append(vectors, v)
+		}
+	}
+	if len(vectors) == 0 {
+		return nil, errors.New("testvectors: no usable group and hasher")
+	}
+	return vectors, nil
+}
+
+// Name returns a file name for the vector, such as
+// "baby-jubjub_sha256.json".
+func (v *Vector) Name() string {
+	return v.Group + "_" + v.Hasher + ".json"
+}
+
+// newFROST creates the FROST instance for a vector.
+func newFROST(groupName, hasherName string, threshold, total int) (*frost.FROST, group.Group, error) {
+	g, err := group.New(groupName)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, err := frost.NewHasher(hasherName)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := frost.NewWithHasher(g, threshold, total, h)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, g, nil
+}
+
+// encode hex-encodes a scalar or point.
+func encode(v interface{ Bytes() []byte }) string {
+	return hex.EncodeToString(v.Bytes())
+}
+
+// encodeAll hex-encodes a list of scalars or points.
+func encodeAll[T interface{ Bytes() []byte }](list []T) []string {
+	out := make([]string, len(list))
+	for i, v := range list {
+		out[i] = encode(v)
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the vectors in testdata")
+
+// testSeed is the seed of the vectors in testdata.
+var testSeed = []byte("fy test vectors")
+
+func TestGenerateAll(t *testing.T) {
+	vectors, err := GenerateAll(2, 3, testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vectors {
+		t.Run(v.Group+"/"+v.Hasher, func(t *testing.T) {
+			if err := v.Check(); err != nil {
+				t.Fatal(err)
+			}
+			again, err := Generate(v.Group, v.Hasher, v.Threshold, v.Total, testSeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(marshal(t, v), marshal(t, again)) {
+				t.Fatal("vector is not deterministic")
+			}
+
+			path := filepath.Join("testdata", v.Name())
+			if *update {
+				if err := os.WriteFile(path, marshal(t, v), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(marshal(t, v), want) {
+				t.Fatalf("%s is out of date; run go test -update", path)
+			}
+		})
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no vectors in testdata")
+	}
+	for _, path := range paths {
+		v, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := v.Check(); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+func TestCheckDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(v *Vector)
+	}{
+		{"dealer share", func(v *Vector) { v.DKG.Dealers[0].Shares[1] = v.DKG.Dealers[0].Shares[2] }},
+		{"secret key", func(v *Vector) { v.DKG.KeyShares[0].SecretKey = v.DKG.KeyShares[1].SecretKey }},
+		{"group key", func(v *Vector) { v.DKG.GroupKey = v.DKG.KeyShares[0].PublicKey }},
+		{"transcript digest", func(v *Vector) { v.DKG.TranscriptDigest = "00" + v.DKG.TranscriptDigest[2:] }},
+		{"message", func(v *Vector) { v.Signing.Message = "00" }},
+		{"nonce", func(v *Vector) { v.Signing.HidingNonces[0] = v.Signing.BindingNonces[0] }},
+		{"binding factor", func(v *Vector) { v.Signing.BindingFactors[0] = v.Signing.BindingFactors[1] }},
+		{"challenge", func(v *Vector) { v.Signing.Challenge = v.Signing.Shares[0] }},
+		{"lagrange", func(v *Vector) { v.Signing.Lagrange[0] = v.Signing.Lagrange[1] }},
+		{"signature share", func(v *Vector) { v.Signing.Shares[0] = v.Signing.Shares[1] }},
+		{"signers", func(v *Vector) {
+			v.Signing.Signers[0], v.Signing.Signers[1] = v.Signing.Signers[1], v.Signing.Signers[0]
+		}},
+		{"signature", func(v *Vector) { v.Signing.Signature = v.Signing.GroupCommitment }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Generate("baby-jubjub", "sha256", 2, 3, testSeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(v)
+			if err := v.Check(); !errors.Is(err, ErrMismatch) {
+				t.Fatalf("Check() = %v, want ErrMismatch", err)
+			}
+		})
+	}
+}
+
+func TestGenerateSeeds(t *testing.T) {
+	a, err := Generate("baby-jubjub", "sha256", 2, 3, []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate("baby-jubjub", "sha256", 2, 3, []byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.DKG.GroupKey == b.DKG.GroupKey {
+		t.Fatal("different seeds produced the same group key")
+	}
+}
+
+func marshal(t *testing.T, v *Vector) []byte {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(data, '\n')
+}
+
+func FuzzCheck(f *testing.F) {
+	v, err := Generate("baby-jubjub", "sha256", 2, 3, testSeed)
+	if err != nil {
+		f.Fatal(err)
+	}
+	// Without the signing section the seed stays small enough for the
+	// fuzzer to mutate efficiently; Check still reaches the DKG checks.
+	v.Signing = Sign{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add([]byte(`{"version":1,"group":"baby-jubjub","hasher":"sha256","threshold":2,"total":3}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := Decode(bytes.NewReader(data))
+		if err != nil || v.Total > 16 {
+			return
+		}
+		v.Check()
+	})
+}